@@ -17,12 +17,22 @@ import (
 
 // BuilderResult contains the result of a builder run
 type BuilderResult struct {
-	Signals     []llm.Signal
-	TotalTokens int
-	Output      string
-	Error       error
+	Signals         []llm.Signal
+	TotalTokens     int
+	Output          string
+	Discrepancy     string // Set when PRD_COMPLETE was claimed without a new commit
+	MissingEvidence string // Set when requireEvidence rejected a PRD_COMPLETE claim
+	FileMismatch    string // Evidence files that disagree with git since the baseline
+	Error           error
+	RateLimited     bool   // The API rate-limited the builder
+	Summary         string // One-line recap of the agent's work
+	WriteDeclined   bool   // The user declined the builder's first change (confirmBeforeWrite)
+	GateUnreached   bool   // The builder ended without trying to change anything (confirmBeforeWrite)
 }
 
+// ReadOnlyTools are the only tools available to the builder in preview mode
+var ReadOnlyTools = []string{"Read", "Glob", "Grep"}
+
 // Run executes the builder agent to implement the active PRD's plan
 func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, cfg *config.Config) (*BuilderResult, error) {
 	// Nil guard - use default config if none provided
@@ -31,17 +41,69 @@ func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, cfg *co
 	}
 
 	// Get the active PRD
-	activePRDs := prdFile.GetActivePRDs()
-	if len(activePRDs) == 0 {
+	activePRD := SelectActive(prdFile, cfg.Run.ResumePRD, cfg.Run.Scope().Work())
+	if activePRD == nil {
 		return &BuilderResult{}, fmt.Errorf("no active PRD found")
 	}
 
-	activePRD := activePRDs[0]
-	prompt := buildBuilderPrompt(basePath, &activePRD, cfg)
+	prompt := buildBuilderPrompt(basePath, activePRD, cfg)
+
+	if cfg.Run.BuilderReadOnly {
+		display.AgentHeader("builder", "previewing plan for "+activePRD.ID)
+	} else {
+		display.AgentHeader("builder", "executing plan for "+activePRD.ID)
+	}
+
+	// Capture HEAD so a completion claim can be checked for a new commit
+	repo := gitRepo{basePath: basePath}
+	baseline, _ := repo.Head()
+
+	model := cfg.ModelForPRD("builder", activePRD.ID)
+	if model != cfg.GetPhaseConfig("builder").Model {
+		display.Info(fmt.Sprintf("Using model %s for %s (prdModels)", model, activePRD.ID))
+	}
+	if activePRD.ModelOverride != "" {
+		model = activePRD.ModelOverride
+		display.Info(fmt.Sprintf("Using escalated model %s for %s", model, activePRD.ID))
+	}
+
+	// Ask before the first change when step-through mode is on and someone
+	// is there to answer
+	var gate llm.WriteGate
+	if cfg.GetPhaseConfig("builder").ConfirmBeforeWrite && cfg.Run.ConfirmWrite != nil {
+		id := activePRD.ID
+		gate = func(tool string) bool { return cfg.Run.ConfirmWrite(id, tool) }
+	}
+
+	result, err := runClaude(ctx, basePath, prompt, model, cfg, gate)
+	if err != nil {
+		return result, err
+	}
+	if result.WriteDeclined || result.GateUnreached {
+		return result, nil
+	}
+
+	if !cfg.Run.BuilderReadOnly {
+		if _, err := applySubtaskSignals(basePath, result.Signals); err != nil {
+			return result, err
+		}
+	}
 
-	display.AgentHeader("builder", "executing plan for "+activePRD.ID)
+	if err := flagUnbackedCompletion(basePath, result, activePRD.ID, repo, baseline); err != nil {
+		return result, err
+	}
 
-	return runClaude(ctx, basePath, prompt, cfg)
+	if cfg.RequireEvidence && !cfg.Run.BuilderReadOnly {
+		if err := requireEvidence(basePath, result, activePRD.ID); err != nil {
+			return result, err
+		}
+	}
+
+	if err := crossCheckEvidenceFiles(basePath, result, activePRD.ID, repo, baseline); err != nil {
+		return result, err
+	}
+
+	return result, nil
 }
 
 // RunChat runs an interactive Claude session
@@ -52,26 +114,54 @@ func RunChat(ctx context.Context, basePath string, prdFile *prd.PRDFileData, cfg
 	return runClaudeInteractive(ctx, basePath, prompt, cfg)
 }
 
+// SelectActive returns the active PRD the builder should work on: resumeID
+// when it names an active PRD, otherwise the first active one within scope
+func SelectActive(prdFile *prd.PRDFileData, resumeID string, scope prd.Scope) *prd.PRD {
+	if resumeID != "" {
+		if p := prdFile.FindByID(resumeID); p != nil && p.Passes.IsActive() {
+			return p
+		}
+	}
+	active := prdFile.GetActivePRDsIn(scope)
+	if len(active) == 0 {
+		return nil
+	}
+	return prdFile.FindByID(active[0].ID)
+}
+
 // ShouldRunBuilder determines if the builder should run
-// It should run if there's an active PRD with a plan
-func ShouldRunBuilder(prdFile *prd.PRDFileData) bool {
-	return len(prdFile.GetActivePRDs()) > 0
+// It should run if there's an active PRD with a plan within scope
+func ShouldRunBuilder(prdFile *prd.PRDFileData, scope prd.Scope) bool {
+	run, _ := ShouldRunBuilderWithReason(prdFile, scope)
+	return run
 }
 
-func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config) (*BuilderResult, error) {
-	result := &BuilderResult{}
+// ShouldRunBuilderWithReason is ShouldRunBuilder plus the rationale for the
+// decision
+func ShouldRunBuilderWithReason(prdFile *prd.PRDFileData, scope prd.Scope) (bool, string) {
+	active := prdFile.GetActivePRDsIn(scope)
+	if len(active) == 0 {
+		if scope.Limited() {
+			return false, fmt.Sprintf("no active PRD within %s", scope)
+		}
+		return false, "no active PRD"
+	}
+	return true, fmt.Sprintf("active PRD %s has a plan to execute", active[0].ID)
+}
 
+func runClaude(ctx context.Context, basePath string, prompt prompts.PhasePrompt, model string, cfg *config.Config, gate llm.WriteGate) (*BuilderResult, error) {
 	phaseConfig := cfg.GetPhaseConfig("builder")
 
-	claude := llm.NewClaude("")
-
-	// Create a cancellable context for this execution
-	execCtx, cancelExec := context.WithCancel(ctx)
-	defer cancelExec()
+	apiKey, err := cfg.Auth.APIKey()
+	if err != nil {
+		return nil, err
+	}
+	claude := llm.NewClaude("", apiKey)
 
 	opts := llm.ExecuteOptions{
-		Prompt:       prompt,
-		Model:        phaseConfig.Model,
+		SystemPrompt: prompt.System,
+		Prompt:       prompt.User,
+		Model:        model,
 		AllowedTools: []string{
 			"Read", "Write", "Edit", "Bash", "Glob", "Grep",
 			"Task", "TodoWrite", "WebSearch", "WebFetch",
@@ -81,45 +171,116 @@ func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config)
 			prd.GetMillhousePath(basePath, prd.ProgressFile),
 			prd.GetMillhousePath(basePath, prd.PromptFile),
 		},
-		WorkDir: basePath,
+		WorkDir:    basePath,
+		AddDirs:    prd.ExternalArtifactDirs(),
+		ExtraArgs:  phaseConfig.ExtraArgs,
+		MCPServers: phaseConfig.MCPServers,
 	}
 
-	reader, err := claude.Execute(execCtx, opts)
+	// Read-only preview: restrict to inspection tools and deny anything that writes
+	if cfg.Run.BuilderReadOnly {
+		opts.AllowedTools = ReadOnlyTools
+		opts.DisallowedTools = []string{"Write", "Edit", "MultiEdit", "NotebookEdit", "Bash", "Task"}
+		opts.MCPServers = nil // MCP tools could write
+		gate = nil
+	}
+
+	// Step-through mode keeps every tool; claude's PreToolUse hook waits on
+	// the gate before the first mutating one, so nothing lands before the answer
+	handler, err := streamClaude(ctx, claude, opts, phaseConfig, gate)
 	if err != nil {
 		return nil, err
 	}
 
+	// Convert handler results to BuilderResult
+	result := &BuilderResult{
+		TotalTokens: handler.GetTokenStats().TotalTokens,
+		Output:      handler.GetOutput(),
+		Signals:     handler.GetSignals(),
+		RateLimited: handler.RateLimited(),
+		Summary:     handler.PhaseSummary("builder"),
+	}
+	if gate != nil {
+		switch {
+		case !handler.WriteGated():
+			result.GateUnreached = true
+		case !handler.WriteApproved():
+			result.WriteDeclined = true
+		}
+	}
+
+	return result, nil
+}
+
+// streamClaude runs one claude execution, displaying its stream, and returns
+// the handler holding what it produced
+func streamClaude(ctx context.Context, claude *llm.Claude, opts llm.ExecuteOptions, phaseConfig config.PhaseConfig, gate llm.WriteGate) (*llm.ConsoleHandler, error) {
+	// Create a cancellable context for this execution
+	execCtx, cancelExec := context.WithCancel(ctx)
+	defer cancelExec()
+
 	// Create handler with termination support
-	handler := llm.NewConsoleHandlerWithTerminate(phaseConfig.MaxTokens, cancelExec)
+	handler := llm.NewConsoleHandlerWithLimits(phaseConfig.MaxTokens, phaseConfig.MaxOutputTokens, cancelExec)
+	handler.SetMaxRetainedOutput(phaseConfig.RetainedOutput())
+	defer handler.Flush() // Flush buffered output on every exit path
+
+	// claude asks the gate through its PreToolUse hook, a mil subprocess
+	if gate != nil {
+		handler.SetWriteGate(gate)
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate mil for the write gate hook: %w", err)
+		}
+		hook, err := llm.StartWriteGateHook(exe, handler.AskWriteGate)
+		if err != nil {
+			return nil, err
+		}
+		defer hook.Close()
+		opts.Settings = hook.SettingsPath()
+	}
+
+	reader, err := claude.Execute(execCtx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancel the phase if the agent goes quiet for too long
+	stopWatchdog := handler.WatchIdle(phaseConfig.IdleTimeoutDuration(), cancelExec)
+	defer stopWatchdog()
 
 	// Parse the stream
 	if err := llm.ParseStream(reader, handler, cancelExec); err != nil {
 		reader.Close()
 		return nil, fmt.Errorf("stream parsing failed: %w", err)
 	}
+	stopWatchdog()
 
 	// Close reader and check for process exit errors (e.g., Claude CLI failure)
 	// Note: "signal: killed" is expected when we intentionally terminate after a signal
 	closeErr := reader.Close()
 	if closeErr != nil && !handler.ShouldTerminate() {
+		if handler.RateLimited() {
+			return nil, fmt.Errorf("claude execution failed: %w: %w", closeErr, llm.ErrRateLimited)
+		}
 		return nil, fmt.Errorf("claude execution failed: %w", closeErr)
 	}
 
-	// Convert handler results to BuilderResult
-	result.Output = handler.GetOutput()
-	result.TotalTokens = handler.GetTokenStats().TotalTokens
-	result.Signals = handler.GetSignals()
-
+	handler.Flush()
 	fmt.Println() // Ensure newline after output
 	handler.DisplayFinalTokenUsage()
+	handler.DisplayToolSummary()
 
-	return result, nil
+	return handler, nil
 }
 
 func runClaudeInteractive(ctx context.Context, basePath, prompt string, cfg *config.Config) error {
 	phaseConfig := cfg.GetPhaseConfig("chat")
 
-	claude := llm.NewClaude("")
+	apiKey, err := cfg.Auth.APIKey()
+	if err != nil {
+		return err
+	}
+	claude := llm.NewClaude("", apiKey)
 
 	opts := llm.ExecuteOptions{
 		SystemPrompt: prompt,
@@ -129,20 +290,27 @@ func runClaudeInteractive(ctx context.Context, basePath, prompt string, cfg *con
 			prd.GetMillhousePath(basePath, prd.ProgressFile),
 			prd.GetMillhousePath(basePath, prd.PromptFile),
 		},
-		WorkDir: basePath,
+		WorkDir:    basePath,
+		AddDirs:    prd.ExternalArtifactDirs(),
+		SessionID:  cfg.Run.ChatSessionID,
+		ExtraArgs:  phaseConfig.ExtraArgs,
+		MCPServers: phaseConfig.MCPServers,
 	}
 
 	return claude.ExecuteInteractive(ctx, opts)
 }
 
-func buildBuilderPrompt(basePath string, activePRD *prd.PRD, cfg *config.Config) string {
+func buildBuilderPrompt(basePath string, activePRD *prd.PRD, cfg *config.Config) prompts.PhasePrompt {
 	phaseConfig := cfg.GetPhaseConfig("builder")
 
 	promptMD := readFileContent(prd.GetMillhousePath(basePath, prd.PromptFile))
 	activePRDJSON, _ := json.MarshalIndent(activePRD, "", "  ")
 	progressContent := readLastLines(prd.GetMillhousePath(basePath, prd.ProgressFile), phaseConfig.ProgressLines)
 	planContent := readFileContent(prd.GetPlanPath(basePath, activePRD.ID))
-	builderAugmentation := prompts.LoadAugmentation(basePath, "builder")
+	builderAugmentation := ""
+	if !cfg.Run.NoAugmentation {
+		builderAugmentation = prompts.LoadAugmentation(basePath, "builder")
+	}
 
 	return prompts.BuildBuilderPrompt(prompts.BuilderData{
 		PromptMD:            promptMD,
@@ -151,14 +319,17 @@ func buildBuilderPrompt(basePath string, activePRD *prd.PRD, cfg *config.Config)
 		ProgressContent:     progressContent,
 		Timestamp:           time.Now().Format("2006-01-02 15:04"),
 		BuilderAugmentation: builderAugmentation,
+		CriteriaChecklist:   prompts.FormatCriteria(activePRD.AcceptanceCriteria),
+		SubtaskChecklist:    prompts.FormatSubtasks(activePRD.Subtasks),
+		ReadOnly:            cfg.Run.BuilderReadOnly,
+		Resuming:            cfg.Run.ResumePRD == activePRD.ID,
+		RunDirective:        cfg.Run.RunDirective,
 	})
 }
 
 func buildChatPrompt(basePath string, prdFile *prd.PRDFileData) string {
-	open := prdFile.GetOpenPRDs()
-	active := prdFile.GetActivePRDs()
-	pending := prdFile.GetPendingPRDs()
-	complete := prdFile.GetCompletePRDs()
+	groups := prdFile.Group()
+	open, active, pending, complete := groups.Open, groups.Active, groups.Pending, groups.Complete
 
 	promptContent := readFileContent(prd.GetMillhousePath(basePath, prd.PromptFile))
 	hasPromptContent := len(promptContent) > 200
@@ -181,22 +352,22 @@ func buildChatPrompt(basePath string, prdFile *prd.PRDFileData) string {
 }
 
 func readFileContent(path string) string {
-	content, err := os.ReadFile(path)
+	content, err := prd.ReadTextFile(path)
 	if err != nil {
 		return ""
 	}
-	return string(content)
+	return content
 }
 
 func readLastLines(path string, n int) string {
-	content, err := os.ReadFile(path)
+	content, err := prd.ReadTextFile(path)
 	if err != nil {
 		return ""
 	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(content, "\n")
 	if len(lines) <= n {
-		return string(content)
+		return content
 	}
 
 	return strings.Join(lines[len(lines)-n:], "\n")
@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestSelectActive(t *testing.T) {
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "first", Passes: prd.PassesStatus{Value: "active"}},
+		{ID: "second", Passes: prd.PassesStatus{Value: "active"}},
+		{ID: "done", Passes: prd.PassesStatus{Value: true}},
+	}}
+
+	if got := SelectActive(prdFile, "", prd.Scope{}); got == nil || got.ID != "first" {
+		t.Errorf("default = %v, want first", got)
+	}
+	if got := SelectActive(prdFile, "second", prd.Scope{}); got == nil || got.ID != "second" {
+		t.Errorf("resume = %v, want second", got)
+	}
+	if got := SelectActive(prdFile, "done", prd.Scope{}); got == nil || got.ID != "first" {
+		t.Errorf("inactive resume target should fall back, got %v", got)
+	}
+	if got := SelectActive(&prd.PRDFileData{}, "first", prd.Scope{}); got != nil {
+		t.Errorf("no active PRDs should return nil, got %v", got)
+	}
+
+	prdFile.PRDs[0].Owner = "bob"
+	if got := SelectActive(prdFile, "", prd.Scope{Owner: "alice"}); got == nil || got.ID != "second" {
+		t.Errorf("scoped = %v, want second", got)
+	}
+}
+
+func TestShouldRunBuilderWithReason(t *testing.T) {
+	run, reason := ShouldRunBuilderWithReason(&prd.PRDFileData{PRDs: []prd.PRD{{ID: "open-1"}}}, prd.Scope{})
+	if run || reason != "no active PRD" {
+		t.Errorf("no active PRD: got %v, %q", run, reason)
+	}
+
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "auth-1", Owner: "bob", Passes: prd.PassesStatus{Value: "active"}},
+	}}
+	run, reason = ShouldRunBuilderWithReason(prdFile, prd.Scope{})
+	if !run || reason != "active PRD auth-1 has a plan to execute" {
+		t.Errorf("active PRD: got %v, %q", run, reason)
+	}
+
+	// Another owner's active PRD is left to their run
+	run, reason = ShouldRunBuilderWithReason(prdFile, prd.Scope{Owner: "alice"})
+	if run || reason != "no active PRD within owner alice" {
+		t.Errorf("active PRD outside scope: got %v, %q", run, reason)
+	}
+}
@@ -0,0 +1,249 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// repoInspector abstracts the git queries used to check completion claims
+type repoInspector interface {
+	Head() (string, error)
+	WorkingTreeClean() (bool, []string, error)
+	ChangedFilesSince(baseline string) ([]string, error)
+}
+
+// gitRepo inspects the repository at basePath with the git CLI
+type gitRepo struct {
+	basePath string
+}
+
+func (g gitRepo) Head() (string, error) {
+	return git.RevParse(g.basePath, "HEAD")
+}
+
+func (g gitRepo) WorkingTreeClean() (bool, []string, error) {
+	return git.CheckWorkingTreeClean(g.basePath)
+}
+
+func (g gitRepo) ChangedFilesSince(baseline string) ([]string, error) {
+	return git.GetChangedFilesSince(g.basePath, baseline)
+}
+
+// checkCompletionCommit verifies that a PRD_COMPLETE claim is backed by a
+// new commit since baseline. Returns a description of the discrepancy, or
+// "" if the claim holds or could not be checked.
+func checkCompletionCommit(repo repoInspector, baseline string) string {
+	if baseline == "" {
+		return ""
+	}
+
+	head, err := repo.Head()
+	if err != nil || head != baseline {
+		return ""
+	}
+
+	clean, changes, err := repo.WorkingTreeClean()
+	if err == nil && !clean {
+		return fmt.Sprintf("claimed PRD_COMPLETE with no new commit and %d uncommitted change(s)", len(changes))
+	}
+	return "claimed PRD_COMPLETE with no new commit"
+}
+
+// flagUnbackedCompletion downgrades a PRD_COMPLETE signal that has no commit
+// behind it: the signal is dropped from the result and the discrepancy is
+// recorded in the PRD's notes so the reviewer sees it.
+func flagUnbackedCompletion(basePath string, result *BuilderResult, prdID string, repo repoInspector, baseline string) error {
+	claimed := false
+	var kept []llm.Signal
+	for _, s := range result.Signals {
+		if s.Type == llm.SignalPRDComplete {
+			claimed = true
+			continue
+		}
+		kept = append(kept, s)
+	}
+	if !claimed {
+		return nil
+	}
+
+	result.Discrepancy = checkCompletionCommit(repo, baseline)
+	if result.Discrepancy == "" {
+		return nil
+	}
+	result.Signals = kept
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+	if p := prdFile.FindByID(prdID); p != nil {
+		p.AppendNote(fmt.Sprintf("[milhouse] Builder %s (baseline %.7s)", result.Discrepancy, baseline))
+	}
+	return prd.Save(basePath, prdFile)
+}
+
+// requireEvidence refuses a PRD_COMPLETE claim without a loadable evidence
+// file: the signal is dropped and the PRD is put back to active with a note
+// telling the builder to write evidence before signaling again.
+func requireEvidence(basePath string, result *BuilderResult, prdID string) error {
+	claimed := false
+	var kept []llm.Signal
+	for _, s := range result.Signals {
+		if s.Type == llm.SignalPRDComplete {
+			claimed = true
+			continue
+		}
+		kept = append(kept, s)
+	}
+	if !claimed {
+		return nil
+	}
+
+	_, evErr := prd.LoadEvidence(basePath, prdID)
+	if evErr == nil {
+		return nil
+	}
+	result.MissingEvidence = evErr.Error()
+	result.Signals = kept
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+	if p := prdFile.FindByID(prdID); p != nil {
+		if p.Passes.IsPending() {
+			p.Passes.SetActive()
+		}
+		p.AppendNote(fmt.Sprintf("[milhouse] Completion refused: %s. Write %s before signaling PRD_COMPLETE.",
+			evErr, prd.AgentArtifactDir(prd.EvidenceDir)+prdID+"-evidence.md"))
+	}
+	return prd.Save(basePath, prdFile)
+}
+
+// crossCheckEvidenceFiles compares the evidence's files list with what git
+// says changed since baseline. An empty list is filled in from git; a
+// mismatch is noted on the PRD so the reviewer can tell bookkeeping errors
+// from work that is genuinely missing.
+func crossCheckEvidenceFiles(basePath string, result *BuilderResult, prdID string, repo repoInspector, baseline string) error {
+	if baseline == "" || !hasSignal(result.Signals, llm.SignalPRDComplete) {
+		return nil
+	}
+	ev, _ := prd.LoadEvidence(basePath, prdID)
+	if ev == nil {
+		return nil
+	}
+	changed, err := repo.ChangedFilesSince(baseline)
+	if err != nil {
+		return nil
+	}
+	changed = withoutMillhouseFiles(changed)
+
+	var note string
+	if len(ev.Files) == 0 {
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := appendChangedFiles(ev.Path, changed); err != nil {
+			return err
+		}
+		note = fmt.Sprintf("[milhouse] Evidence listed no files; added %d changed since %.7s from git", len(changed), baseline)
+	} else {
+		unchanged, unlisted := compareEvidenceFiles(ev.Files, changed)
+		if len(unchanged) == 0 && len(unlisted) == 0 {
+			return nil
+		}
+		result.FileMismatch = describeFileMismatch(unchanged, unlisted)
+		note = fmt.Sprintf("[milhouse] Evidence files differ from git since %.7s: %s", baseline, result.FileMismatch)
+	}
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+	if p := prdFile.FindByID(prdID); p != nil {
+		p.AppendNote(note)
+	}
+	return prd.Save(basePath, prdFile)
+}
+
+// compareEvidenceFiles splits claimed files git did not see change from
+// changed files the evidence did not list
+func compareEvidenceFiles(claimed, changed []string) (unchanged, unlisted []string) {
+	changedSet := make(map[string]bool, len(changed))
+	for _, f := range changed {
+		changedSet[f] = true
+	}
+	claimedSet := make(map[string]bool, len(claimed))
+	for _, f := range claimed {
+		f = filepath.ToSlash(filepath.Clean(f))
+		claimedSet[f] = true
+		if !changedSet[f] {
+			unchanged = append(unchanged, f)
+		}
+	}
+	for _, f := range changed {
+		if !claimedSet[f] {
+			unlisted = append(unlisted, f)
+		}
+	}
+	return unchanged, unlisted
+}
+
+// describeFileMismatch summarizes compareEvidenceFiles output for notes
+func describeFileMismatch(unchanged, unlisted []string) string {
+	var parts []string
+	if len(unchanged) > 0 {
+		parts = append(parts, "listed but unchanged: "+strings.Join(unchanged, ", "))
+	}
+	if len(unlisted) > 0 {
+		parts = append(parts, "changed but unlisted: "+strings.Join(unlisted, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// withoutMillhouseFiles drops Millhouse's own files, which the builder
+// commits alongside its work but does not list as evidence
+func withoutMillhouseFiles(files []string) []string {
+	var kept []string
+	for _, f := range files {
+		if !strings.HasPrefix(f, prd.MillhouseDir+"/") {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// appendChangedFiles adds a git-derived files section to an evidence file
+func appendChangedFiles(path string, files []string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to update evidence: %w", err)
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	b.WriteString("\n## Files Changed (from git)\n")
+	for _, file := range files {
+		fmt.Fprintf(&b, "- `%s`\n", file)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		return fmt.Errorf("failed to update evidence: %w", err)
+	}
+	return nil
+}
+
+// hasSignal reports whether signals contains one of type t
+func hasSignal(signals []llm.Signal, t string) bool {
+	for _, s := range signals {
+		if s.Type == t {
+			return true
+		}
+	}
+	return false
+}
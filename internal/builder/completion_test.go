@@ -0,0 +1,261 @@
+package builder
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// fakeRepo is a repoInspector with canned answers
+type fakeRepo struct {
+	head    string
+	headErr error
+	changes []string
+	changed []string
+}
+
+func (f fakeRepo) Head() (string, error) {
+	return f.head, f.headErr
+}
+
+func (f fakeRepo) WorkingTreeClean() (bool, []string, error) {
+	return len(f.changes) == 0, f.changes, nil
+}
+
+func (f fakeRepo) ChangedFilesSince(baseline string) ([]string, error) {
+	return f.changed, nil
+}
+
+func TestCheckCompletionCommit(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     fakeRepo
+		baseline string
+		want     string
+	}{
+		{
+			name:     "New commit landed",
+			repo:     fakeRepo{head: "bbb"},
+			baseline: "aaa",
+			want:     "",
+		},
+		{
+			name:     "No commit and clean tree",
+			repo:     fakeRepo{head: "aaa"},
+			baseline: "aaa",
+			want:     "claimed PRD_COMPLETE with no new commit",
+		},
+		{
+			name:     "No commit with uncommitted changes",
+			repo:     fakeRepo{head: "aaa", changes: []string{"M main.go", "?? new.go"}},
+			baseline: "aaa",
+			want:     "claimed PRD_COMPLETE with no new commit and 2 uncommitted change(s)",
+		},
+		{
+			name:     "No baseline skips the check",
+			repo:     fakeRepo{head: "aaa"},
+			baseline: "",
+			want:     "",
+		},
+		{
+			name:     "Unreadable HEAD skips the check",
+			repo:     fakeRepo{headErr: errors.New("not a git repository")},
+			baseline: "aaa",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkCompletionCommit(tt.repo, tt.baseline); got != tt.want {
+				t.Errorf("checkCompletionCommit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagUnbackedCompletion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "prd-1", Passes: prd.PassesStatus{Value: "pending"}},
+	}}
+	if err := prd.Save(dir, prdFile); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &BuilderResult{Signals: []llm.Signal{{Type: llm.SignalPRDComplete}}}
+	if err := flagUnbackedCompletion(dir, result, "prd-1", fakeRepo{head: "aaa"}, "aaa"); err != nil {
+		t.Fatalf("flagUnbackedCompletion() error = %v", err)
+	}
+
+	if result.Discrepancy == "" {
+		t.Error("Discrepancy should be set when no commit landed")
+	}
+	if len(result.Signals) != 0 {
+		t.Errorf("PRD_COMPLETE should be dropped, got %v", result.Signals)
+	}
+
+	reloaded, err := prd.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := reloaded.FindByID("prd-1")
+	if !p.Passes.IsPending() {
+		t.Errorf("PRD should stay pending, got %v", p.Passes.Value)
+	}
+	if !strings.Contains(p.Notes, "no new commit") {
+		t.Errorf("Notes should record the discrepancy, got %q", p.Notes)
+	}
+}
+
+func TestRequireEvidence(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir, prd.EvidenceDir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+			{ID: "prd-1", Passes: prd.PassesStatus{Value: "pending"}},
+		}}
+		if err := prd.Save(dir, prdFile); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	t.Run("Missing evidence keeps the PRD active", func(t *testing.T) {
+		dir := setup(t)
+		result := &BuilderResult{Signals: []llm.Signal{{Type: llm.SignalPRDComplete}}}
+		if err := requireEvidence(dir, result, "prd-1"); err != nil {
+			t.Fatalf("requireEvidence() error = %v", err)
+		}
+		if result.MissingEvidence == "" || len(result.Signals) != 0 {
+			t.Errorf("claim should be refused, got %+v", result)
+		}
+
+		reloaded, err := prd.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := reloaded.FindByID("prd-1")
+		if !p.Passes.IsActive() {
+			t.Errorf("PRD should be back to active, got %v", p.Passes.Value)
+		}
+		if !strings.Contains(p.Notes, "prd-1-evidence.md") {
+			t.Errorf("Notes should ask for evidence, got %q", p.Notes)
+		}
+	})
+
+	t.Run("Evidence present accepts the claim", func(t *testing.T) {
+		dir := setup(t)
+		evidence := "# Evidence\n## Commits\n- abc1234 Implement prd-1\n"
+		if err := os.WriteFile(prd.GetEvidencePath(dir, "prd-1"), []byte(evidence), 0644); err != nil {
+			t.Fatal(err)
+		}
+		result := &BuilderResult{Signals: []llm.Signal{{Type: llm.SignalPRDComplete}}}
+		if err := requireEvidence(dir, result, "prd-1"); err != nil {
+			t.Fatalf("requireEvidence() error = %v", err)
+		}
+		if result.MissingEvidence != "" || len(result.Signals) != 1 {
+			t.Errorf("claim should stand, got %+v", result)
+		}
+
+		reloaded, err := prd.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := reloaded.FindByID("prd-1"); !p.Passes.IsPending() {
+			t.Errorf("PRD should stay pending, got %v", p.Passes.Value)
+		}
+	})
+}
+
+func TestCompareEvidenceFiles(t *testing.T) {
+	unchanged, unlisted := compareEvidenceFiles(
+		[]string{"./main.go", "docs/README.md"},
+		[]string{"main.go", "main_test.go"},
+	)
+	if strings.Join(unchanged, ",") != "docs/README.md" {
+		t.Errorf("unchanged = %v, want [docs/README.md]", unchanged)
+	}
+	if strings.Join(unlisted, ",") != "main_test.go" {
+		t.Errorf("unlisted = %v, want [main_test.go]", unlisted)
+	}
+}
+
+func TestCrossCheckEvidenceFiles(t *testing.T) {
+	setup := func(t *testing.T, evidence string) string {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir, prd.EvidenceDir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+			{ID: "prd-1", Passes: prd.PassesStatus{Value: "pending"}},
+		}}
+		if err := prd.Save(dir, prdFile); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(prd.GetEvidencePath(dir, "prd-1"), []byte(evidence), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	repo := fakeRepo{head: "bbb", changed: []string{".milhouse/prd.json", "main.go", "main_test.go"}}
+	claim := func() *BuilderResult {
+		return &BuilderResult{Signals: []llm.Signal{{Type: llm.SignalPRDComplete}}}
+	}
+
+	t.Run("Mismatch is noted", func(t *testing.T) {
+		dir := setup(t, "## Files Changed\n- main.go\n- other.go\n")
+		result := claim()
+		if err := crossCheckEvidenceFiles(dir, result, "prd-1", repo, "aaa"); err != nil {
+			t.Fatal(err)
+		}
+		want := "listed but unchanged: other.go; changed but unlisted: main_test.go"
+		if result.FileMismatch != want {
+			t.Errorf("FileMismatch = %q, want %q", result.FileMismatch, want)
+		}
+		reloaded, _ := prd.Load(dir)
+		if !strings.Contains(reloaded.FindByID("prd-1").Notes, want) {
+			t.Errorf("Notes should record the mismatch, got %q", reloaded.FindByID("prd-1").Notes)
+		}
+	})
+
+	t.Run("Empty list is filled from git", func(t *testing.T) {
+		dir := setup(t, "## Commits\n- bbb1234\n")
+		result := claim()
+		if err := crossCheckEvidenceFiles(dir, result, "prd-1", repo, "aaa"); err != nil {
+			t.Fatal(err)
+		}
+		ev, err := prd.LoadEvidence(dir, "prd-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(ev.Files, ",") != "main.go,main_test.go" {
+			t.Errorf("Files = %v, want git's changes without .milhouse/", ev.Files)
+		}
+		if result.FileMismatch != "" {
+			t.Errorf("FileMismatch = %q, want none", result.FileMismatch)
+		}
+	})
+
+	t.Run("Matching list is left alone", func(t *testing.T) {
+		dir := setup(t, "## Files Changed\n- main.go\n- main_test.go\n")
+		result := claim()
+		if err := crossCheckEvidenceFiles(dir, result, "prd-1", repo, "aaa"); err != nil {
+			t.Fatal(err)
+		}
+		reloaded, _ := prd.Load(dir)
+		if result.FileMismatch != "" || reloaded.FindByID("prd-1").Notes != "" {
+			t.Errorf("expected no mismatch, got %q / %q", result.FileMismatch, reloaded.FindByID("prd-1").Notes)
+		}
+	})
+}
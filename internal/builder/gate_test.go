@@ -0,0 +1,157 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prompts"
+)
+
+// fakeClaudeEnv makes the test binary act as the claude CLI
+const fakeClaudeEnv = "MIL_TEST_FAKE_CLAUDE_TOOL"
+
+func TestMain(m *testing.M) {
+	switch {
+	case len(os.Args) == 3 && os.Args[1] == llm.WriteGateHookCommand:
+		// Run as the hook, the way claude runs mil
+		os.Exit(llm.RunWriteGateHook(os.Args[2], os.Stdin, os.Stderr))
+	case os.Getenv(fakeClaudeEnv) != "":
+		os.Exit(fakeClaude(os.Args[1:], os.Getenv(fakeClaudeEnv)))
+	}
+	os.Exit(m.Run())
+}
+
+// fakeClaude stands in for the claude CLI: it announces one tool use, runs
+// the PreToolUse hooks from --settings the way claude does, and only uses the
+// tool (writing changed.txt) when every hook allows it
+func fakeClaude(args []string, tool string) int {
+	emit := func(event map[string]any) {
+		data, _ := json.Marshal(event)
+		fmt.Println(string(data))
+	}
+	content := func(block map[string]any) map[string]any {
+		return map[string]any{"type": "assistant", "message": map[string]any{"content": []any{block}}}
+	}
+
+	emit(content(map[string]any{"type": "text", "text": "Starting on the plan."}))
+	emit(content(map[string]any{"type": "tool_use", "name": tool, "input": map[string]any{}}))
+
+	allowed := true
+	for i, arg := range args {
+		if arg != "--settings" || i+1 == len(args) {
+			continue
+		}
+		var settings struct {
+			Hooks struct {
+				PreToolUse []struct {
+					Matcher string
+					Hooks   []struct{ Command string }
+				}
+			}
+		}
+		data, err := os.ReadFile(args[i+1])
+		if err != nil || json.Unmarshal(data, &settings) != nil {
+			fmt.Fprintln(os.Stderr, "fake claude: bad --settings")
+			return 1
+		}
+		for _, entry := range settings.Hooks.PreToolUse {
+			if !regexp.MustCompile("^(" + entry.Matcher + ")$").MatchString(tool) {
+				continue
+			}
+			for _, hook := range entry.Hooks {
+				cmd := exec.Command("sh", "-c", hook.Command)
+				cmd.Stdin = strings.NewReader(`{"hook_event_name":"PreToolUse","tool_name":"` + tool + `","tool_input":{}}`)
+				var exitErr *exec.ExitError
+				if err := cmd.Run(); errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+					allowed = false
+				} else if err != nil {
+					fmt.Fprintln(os.Stderr, "fake claude: hook failed:", err)
+					return 1
+				}
+			}
+		}
+	}
+
+	if allowed && llm.IsMutatingTool(tool) {
+		os.WriteFile("changed.txt", []byte("changed"), 0644)
+	}
+	emit(content(map[string]any{"type": "text", "text": "###PRD_COMPLETE###"}))
+	emit(map[string]any{"type": "result", "result": "done"})
+	return 0
+}
+
+// useFakeClaude puts the test binary on PATH as claude, using tool once
+func useFakeClaude(t *testing.T, tool string) {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	if err := os.Symlink(exe, filepath.Join(bin, "claude")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(fakeClaudeEnv, tool)
+}
+
+func TestRunClaudeWriteGate(t *testing.T) {
+	tests := []struct {
+		name        string
+		tool        string
+		approve     bool
+		wantAsked   bool
+		wantChanged bool
+		wantResult  BuilderResult
+	}{
+		{name: "approved", tool: "Edit", approve: true, wantAsked: true, wantChanged: true},
+		{name: "declined", tool: "Bash", approve: false, wantAsked: true, wantResult: BuilderResult{WriteDeclined: true}},
+		{name: "never reached", tool: "Read", wantResult: BuilderResult{GateUnreached: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeClaude(t, tt.tool)
+			dir := t.TempDir()
+			changed := filepath.Join(dir, "changed.txt")
+
+			var asked []string
+			gate := func(tool string) bool {
+				asked = append(asked, tool)
+				if _, err := os.Stat(changed); err == nil {
+					t.Error("the tool ran before the gate answered")
+				}
+				return tt.approve
+			}
+
+			result, err := runClaude(context.Background(), dir, prompts.PhasePrompt{User: "build"}, "", config.DefaultConfig(), gate)
+			if err != nil {
+				t.Fatalf("runClaude() error = %v", err)
+			}
+
+			if got := len(asked) == 1 && asked[0] == tt.tool; got != tt.wantAsked {
+				t.Errorf("gate asked for %v, want asked=%v", asked, tt.wantAsked)
+			}
+			if _, err := os.Stat(changed); (err == nil) != tt.wantChanged {
+				t.Errorf("changed.txt exists = %v, want %v", err == nil, tt.wantChanged)
+			}
+			if result.WriteDeclined != tt.wantResult.WriteDeclined || result.GateUnreached != tt.wantResult.GateUnreached {
+				t.Errorf("declined=%v unreached=%v, want %v/%v", result.WriteDeclined, result.GateUnreached,
+					tt.wantResult.WriteDeclined, tt.wantResult.GateUnreached)
+			}
+			if tt.approve && !bytes.Contains([]byte(result.Output), []byte("PRD_COMPLETE")) {
+				t.Errorf("approved run did not continue past the gate: %q", result.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// applySubtaskSignals marks subtasks done for each SUBTASK_DONE signal and
+// returns how many changed. Unknown PRDs or indexes are reported and skipped.
+func applySubtaskSignals(basePath string, signals []llm.Signal) (int, error) {
+	var done []llm.Signal
+	for _, s := range signals {
+		if s.Type == llm.SignalSubtaskDone {
+			done = append(done, s)
+		}
+	}
+	if len(done) == 0 {
+		return 0, nil
+	}
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	changed := 0
+	for _, s := range done {
+		p := prdFile.FindByID(s.PRDID)
+		if p == nil {
+			display.Warning(fmt.Sprintf("SUBTASK_DONE for unknown PRD %s", s.PRDID))
+			continue
+		}
+		n, _ := strconv.Atoi(s.Details)
+		ok, err := p.CompleteSubtask(n)
+		if err != nil {
+			display.Warning(err.Error())
+			continue
+		}
+		if ok {
+			changed++
+			finished, total := p.SubtaskProgress()
+			display.Info(fmt.Sprintf("%s subtask %d done (%d/%d)", p.ID, n, finished, total))
+		}
+	}
+
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, prd.Save(basePath, prdFile)
+}
@@ -0,0 +1,47 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestApplySubtaskSignals(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{{ID: "big-1", Subtasks: []prd.Subtask{
+		{Description: "schema"}, {Description: "api"}, {Description: "ui", Done: true},
+	}}}}
+	if err := prd.Save(dir, prdFile); err != nil {
+		t.Fatal(err)
+	}
+
+	signals := []llm.Signal{
+		{Type: llm.SignalSubtaskDone, PRDID: "big-1", Details: "2"},
+		{Type: llm.SignalSubtaskDone, PRDID: "big-1", Details: "3"}, // Already done
+		{Type: llm.SignalSubtaskDone, PRDID: "big-1", Details: "9"}, // Out of range
+		{Type: llm.SignalSubtaskDone, PRDID: "missing", Details: "1"},
+		{Type: llm.SignalBailout, Details: "context"},
+	}
+	changed, err := applySubtaskSignals(dir, signals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 1 {
+		t.Errorf("changed = %d, want 1", changed)
+	}
+
+	reloaded, err := prd.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := reloaded.FindByID("big-1")
+	if done, total := p.SubtaskProgress(); done != 2 || total != 3 || !p.Subtasks[1].Done {
+		t.Errorf("progress = %d/%d, subtasks %+v", done, total, p.Subtasks)
+	}
+}
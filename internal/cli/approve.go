@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/prd"
+	"github.com/daydemir/milhouse/internal/reviewer"
+)
+
+// maxEvidenceLines caps how much of an evidence file mil approve prints
+const maxEvidenceLines = 40
+
+var approveCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Approve or reject pending PRDs by hand",
+	Long: `Step through pending PRDs one at a time and decide each yourself,
+instead of (or after) the LLM reviewer.
+
+For every pending PRD Milhouse shows the PRD, its evidence, a diff stat of the
+commits the evidence names, git verification of those commits and the
+completion policy checks. Then choose:
+  a  approve - promote to complete and delete the plan
+  r  reject  - revert to open with your reason in its notes
+  s  skip    - leave it pending
+  q  quit    - stop; earlier decisions are kept
+
+Each decision is saved immediately.`,
+	Args: cobra.NoArgs,
+	RunE: runApprove,
+}
+
+func init() {
+	rootCmd.AddCommand(approveCmd)
+}
+
+// approvalTally counts the decisions made in one mil approve session
+type approvalTally struct {
+	approved, rejected, skipped int
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	d := display.NewWithOptions(GetNoColor())
+
+	if !prd.MillhouseExists(cwd) {
+		d.Error(".milhouse/ directory not found")
+		d.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		d.Warning(fmt.Sprintf("Failed to load config: %v, using defaults", err))
+		cfg = config.DefaultConfig()
+	}
+
+	applyDisplayConfig(d, cfg)
+
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	pending := prdFile.GetPendingPRDs()
+	if len(pending) == 0 {
+		d.Info("No pending PRDs to approve")
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		d.Error("mil approve needs a terminal to ask on")
+		d.Info("Use 'mil review' for unattended verification")
+		return fmt.Errorf("no terminal")
+	}
+
+	d.Header(fmt.Sprintf("Milhouse Approve (%d pending)", len(pending)))
+
+	tally, err := approveLoop(bufio.NewReader(os.Stdin), d, cwd, cfg, pending)
+	if err != nil {
+		return err
+	}
+
+	d.SectionBreak()
+	d.Info(fmt.Sprintf("Approved %d, rejected %d, skipped %d", tally.approved, tally.rejected, tally.skipped))
+	return nil
+}
+
+// approveLoop shows each pending PRD and applies the user's decision
+func approveLoop(in *bufio.Reader, d *display.Display, cwd string, cfg *config.Config, pending []prd.PRD) (approvalTally, error) {
+	var tally approvalTally
+	for i, p := range pending {
+		d.SectionBreak()
+		d.Header(fmt.Sprintf("PRD %d of %d: %s", i+1, len(pending), p.ID))
+		showForApproval(d, cwd, cfg, p)
+
+		decision, reason := askDecision(in, d.Out())
+		switch decision {
+		case "approve":
+			result := &reviewer.ReviewerResult{Verified: []string{p.ID}}
+			if err := reviewer.ApplyVerdicts(cwd, result, 0); err != nil {
+				return tally, fmt.Errorf("failed to approve %s: %w", p.ID, err)
+			}
+			d.Success(fmt.Sprintf("Approved: %s", p.ID))
+			tally.approved++
+		case "reject":
+			result := &reviewer.ReviewerResult{Rejected: []string{p.ID}}
+			if err := reviewer.ApplyVerdicts(cwd, result, 0); err != nil {
+				return tally, fmt.Errorf("failed to reject %s: %w", p.ID, err)
+			}
+			rejection := []reviewer.RejectionDetail{{ID: p.ID, Reason: reason}}
+			if _, err := reviewer.RecordRejections(cwd, rejection, 0); err != nil {
+				d.Warning(fmt.Sprintf("Failed to record rejection reason: %v", err))
+			}
+			d.Warning(fmt.Sprintf("Rejected: %s", p.ID))
+			tally.rejected++
+		case "skip":
+			d.Info(fmt.Sprintf("Skipped: %s", p.ID))
+			tally.skipped++
+		default: // quit
+			tally.skipped += len(pending) - i
+			return tally, nil
+		}
+	}
+	return tally, nil
+}
+
+// askDecision prompts until the user picks approve, reject (with a reason),
+// skip or quit; end of input counts as quit
+func askDecision(in *bufio.Reader, out io.Writer) (decision, reason string) {
+	decision = askChoice(in, out, []string{"approve", "reject", "skip", "quit"}, "")
+	if decision != "reject" {
+		return decision, ""
+	}
+	for {
+		fmt.Fprint(out, "Reason: ")
+		reason, err := in.ReadString('\n')
+		if reason = strings.TrimSpace(reason); reason != "" {
+			return "reject", reason
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(out)
+			return "quit", ""
+		}
+		fmt.Fprintln(out, "A reason is required so the builder knows what to fix")
+	}
+}
+
+// showForApproval prints what a human needs to judge a pending PRD: the PRD,
+// its evidence and diff stat, git verification and the completion policy
+func showForApproval(d *display.Display, cwd string, cfg *config.Config, p prd.PRD) {
+	ev, evErr := prd.LoadEvidence(cwd, p.ID)
+	d.PRDDetail(p, prd.PlanExists(cwd, p.ID), ev != nil)
+
+	d.SubHeader("Evidence")
+	if ev == nil {
+		d.Warning(evErr.Error())
+	} else {
+		lines := strings.Split(strings.TrimRight(ev.Content, "\n"), "\n")
+		for _, line := range lines[:min(len(lines), maxEvidenceLines)] {
+			fmt.Fprintf(d.Out(), "  %s\n", line)
+		}
+		if len(lines) > maxEvidenceLines {
+			d.Info(fmt.Sprintf("  ... %d more lines in %s", len(lines)-maxEvidenceLines, ev.Path))
+		}
+	}
+
+	var commits []string
+	if ev != nil {
+		commits = ev.Commits
+	}
+	d.SubHeader("Changes")
+	if stat, err := git.DiffStat(cwd, commits...); err != nil {
+		d.Warning(err.Error())
+	} else if stat == "" {
+		d.Info("  No changes")
+	} else {
+		for _, line := range strings.Split(stat, "\n") {
+			fmt.Fprintf(d.Out(), "  %s\n", line)
+		}
+	}
+
+	d.SubHeader("Verification")
+	for _, sha := range commits {
+		result, err := git.VerifyEvidence(cwd, sha, ev.Files)
+		switch {
+		case err != nil:
+			d.Warning(fmt.Sprintf("%s: %v", sha, err))
+		case result.IsVerified():
+			d.Success(fmt.Sprintf("%s: commit exists with the claimed files", sha))
+		default:
+			d.Warning(fmt.Sprintf("%s: %s", sha, result.GetErrorSummary()))
+		}
+	}
+	for _, r := range reviewer.CurrentChecks(cwd, p.ID) {
+		if r.Passed() {
+			d.Success(fmt.Sprintf("%s: %s", r.Name, r.Status()))
+		} else {
+			d.Warning(fmt.Sprintf("%s: %s", r.Name, r.Status()))
+		}
+	}
+	if ok, reasons := reviewer.EvaluateCompletion(&p, cwd, cfg); ok {
+		d.Success("Definition of done met")
+	} else {
+		for _, reason := range reasons {
+			d.Warning(fmt.Sprintf("Definition of done: %s", reason))
+		}
+	}
+}
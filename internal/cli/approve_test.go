@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestApproveLoop(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := &prd.PRDFileData{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		p := prd.PRD{ID: id, Description: "PRD " + id, AcceptanceCriteria: []string{"works"}}
+		p.Passes.SetPending()
+		data.PRDs = append(data.PRDs, p)
+	}
+	if err := prd.Save(dir, data); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+
+	// Approve a, reject b (after an empty reason), skip c, quit before d
+	in := bufio.NewReader(strings.NewReader("a\nr\n\nmissing tests\nmaybe\ns\nq\n"))
+	tally, err := approveLoop(in, d, dir, config.DefaultConfig(), data.GetPendingPRDs())
+	if err != nil {
+		t.Fatalf("approveLoop() error = %v", err)
+	}
+	if tally != (approvalTally{approved: 1, rejected: 1, skipped: 2}) {
+		t.Errorf("tally = %+v, want 1 approved, 1 rejected, 2 skipped", tally)
+	}
+	if !strings.Contains(out.String(), "A reason is required") {
+		t.Errorf("empty reason was not refused:\n%s", out.String())
+	}
+
+	saved, err := prd.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := saved.FindByID("a"); !p.Passes.IsTrue() {
+		t.Errorf("a = %s, want complete", p.Passes.String())
+	}
+	if p := saved.FindByID("b"); p.Passes.IsTrue() || p.Passes.IsPending() || !strings.Contains(p.Notes, "missing tests") {
+		t.Errorf("b = %s with notes %q, want open with the reason", p.Passes.String(), p.Notes)
+	}
+	for _, id := range []string{"c", "d"} {
+		if p := saved.FindByID(id); !p.Passes.IsPending() {
+			t.Errorf("%s = %s, want still pending", id, p.Passes.String())
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/events"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// batchLogFile receives a project's mil run output when projects run in parallel
+const batchLogFile = "batch.log"
+
+var (
+	batchReposFlag       string
+	batchIterationsFlag  int
+	batchConcurrencyFlag int
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run Millhouse across several projects",
+}
+
+var batchRunCmd = &cobra.Command{
+	Use:   "run --repos FILE [-- run flags]",
+	Short: "Run N iterations in each listed project",
+	Long: `Run 'mil run N' in every project directory listed in the --repos file, one
+per line. Blank lines and lines starting with # are skipped, and relative
+paths are taken from the file's directory.
+
+Projects run one at a time unless --concurrency allows more, up to
+batch.maxConcurrency in config.yaml (default 2) to stay under API rate limits.
+Sequential runs stream their output; parallel runs write it to batch.log in
+each project's artifacts directory. A project that fails does not stop the
+others.
+
+Flags after -- are passed to every 'mil run', e.g.
+  mil batch run --repos repos.txt -n 3 -- --review-all
+
+A table of completed and remaining PRDs and tokens per project ends the batch.`,
+	RunE: runBatchRun,
+}
+
+func init() {
+	batchRunCmd.Flags().StringVar(&batchReposFlag, "repos", "", "`File` listing one project directory per line")
+	batchRunCmd.Flags().IntVarP(&batchIterationsFlag, "iterations", "n", 1, "Iterations to run in each project")
+	batchRunCmd.Flags().IntVarP(&batchConcurrencyFlag, "concurrency", "j", 1, "Projects to run at once (up to batch.maxConcurrency)")
+	batchRunCmd.MarkFlagRequired("repos")
+	batchCmd.AddCommand(batchRunCmd)
+	rootCmd.AddCommand(batchCmd)
+}
+
+// batchResult is how the run in one project went
+type batchResult struct {
+	repo      string
+	err       error
+	completed int    // PRDs completed during the run
+	remaining int    // PRDs not complete afterwards
+	tokens    int    // Tokens the run's phases logged
+	log       string // File the run's output went to; empty when streamed
+}
+
+// batchRunner runs mil in one project, writing its output to out
+type batchRunner func(ctx context.Context, repo string, out io.Writer) error
+
+func runBatchRun(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && cmd.ArgsLenAtDash() != 0 {
+		return fmt.Errorf("unexpected arguments %v: put mil run flags after --", args)
+	}
+	if batchIterationsFlag < 1 {
+		return fmt.Errorf("--iterations must be a positive integer")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	d := display.NewWithOptions(GetNoColor())
+
+	// The batch directory need not be a project; its config only sets the limit
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		d.Warning(fmt.Sprintf("Failed to load config: %v, using defaults", err))
+		cfg = config.DefaultConfig()
+	}
+	if limit := cfg.Batch.MaxConcurrency; batchConcurrencyFlag < 1 || batchConcurrencyFlag > limit {
+		d.Error(fmt.Sprintf("--concurrency must be between 1 and %d (batch.maxConcurrency)", limit))
+		return fmt.Errorf("invalid --concurrency: %d", batchConcurrencyFlag)
+	}
+
+	repos, err := readRepoList(batchReposFlag)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no projects listed in %s", batchReposFlag)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the mil binary: %w", err)
+	}
+	runArgs := batchRunArgs(batchIterationsFlag, batchConcurrencyFlag > 1, args)
+	runner := func(ctx context.Context, repo string, out io.Writer) error {
+		c := osexec.CommandContext(ctx, exe, runArgs...)
+		c.Dir = repo
+		c.Stdout = out
+		c.Stderr = out
+		return c.Run()
+	}
+
+	d.Header(fmt.Sprintf("Milhouse Batch (%d projects, %d iterations each)", len(repos), batchIterationsFlag))
+	results := runBatch(context.Background(), d, repos, batchConcurrencyFlag, runner)
+	showBatchSummary(d, results)
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d project(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+// readRepoList reads project directories from path, one per line
+// Blank lines and # comments are skipped, relative paths are resolved against
+// the file's directory, and repeats are dropped.
+func readRepoList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project list: %w", err)
+	}
+	defer f.Close()
+
+	base, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project list directory: %w", err)
+	}
+
+	var repos []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(base, line)
+		}
+		line = filepath.Clean(line)
+		if !slices.Contains(repos, line) {
+			repos = append(repos, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read project list: %w", err)
+	}
+	return repos, nil
+}
+
+// batchRunArgs builds the mil run command line for each project
+// Output bound for log files is uncolored; --yes carries over so prompts
+// cannot stall an unattended project, and --trace-events carries over too.
+func batchRunArgs(iterations int, toLogs bool, runFlags []string) []string {
+	args := []string{"run", strconv.Itoa(iterations)}
+	if noColor || toLogs {
+		args = append(args, "--no-color")
+	}
+	if assumeYes {
+		args = append(args, "--yes")
+	}
+	if traceEvents {
+		args = append(args, "--trace-events")
+	}
+	return append(args, runFlags...)
+}
+
+// runBatch runs every project with at most concurrency at a time and returns
+// their results in list order
+// Sequential runs stream to the display; parallel ones write batchLogFile.
+func runBatch(ctx context.Context, d *display.Display, repos []string, concurrency int, run batchRunner) []batchResult {
+	results := make([]batchResult, len(repos))
+	var mu sync.Mutex // Serializes display output from the workers
+	report := func(f func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		f()
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runBatchRepo(ctx, d, repo, concurrency > 1, run, report)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runBatchRepo runs one project and measures what the run did from its
+// prd.json and event log
+func runBatchRepo(ctx context.Context, d *display.Display, repo string, toLog bool, run batchRunner, report func(func())) batchResult {
+	result := batchResult{repo: repo}
+
+	var before *prd.PRDFileData
+	err := fmt.Errorf("not initialized (no .milhouse/)")
+	if prd.MillhouseExists(repo) {
+		before, err = prd.Load(repo)
+	}
+	if err != nil {
+		result.err = err
+		report(func() { d.Error(fmt.Sprintf("%s: %v", repo, err)) })
+		return result
+	}
+	startComplete := len(before.GetCompletePRDs())
+	started := time.Now()
+
+	out := d.Out()
+	if toLog {
+		result.log = filepath.Join(repoArtifactsDir(repo), batchLogFile)
+		f, err := createBatchLog(result.log)
+		if err != nil {
+			result.err = err
+			report(func() { d.Error(fmt.Sprintf("%s: %v", repo, err)) })
+			return result
+		}
+		defer f.Close()
+		out = f
+		report(func() { d.Info(fmt.Sprintf("Started %s (log: %s)", repo, result.log)) })
+	} else {
+		d.SubHeader(repo)
+	}
+
+	result.err = run(ctx, repo, out)
+
+	if after, err := prd.Load(repo); err == nil {
+		result.completed = max(len(after.GetCompletePRDs())-startComplete, 0)
+		result.remaining = len(after.PRDs) - len(after.GetCompletePRDs())
+	} else if result.err == nil {
+		result.err = err
+	}
+	result.tokens = tokensSince(repo, started)
+
+	report(func() {
+		if result.err != nil {
+			d.Error(fmt.Sprintf("%s failed: %v", repo, result.err))
+		} else {
+			d.Success(fmt.Sprintf("%s finished: %d PRD(s) completed, %d remaining", repo, result.completed, result.remaining))
+		}
+	})
+	return result
+}
+
+// createBatchLog opens a fresh log file, creating its directory
+func createBatchLog(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch log: %w", err)
+	}
+	return f, nil
+}
+
+// repoArtifactsDir returns where a project keeps its generated artifacts,
+// following its own outputDir setting rather than this process's
+func repoArtifactsDir(repo string) string {
+	if cfg, err := config.Load(repo); err == nil && cfg.OutputDir != "" {
+		if dir, err := resolveOutputDir(repo, cfg.OutputDir); err == nil {
+			return dir
+		}
+	}
+	return filepath.Join(repo, prd.MillhouseDir)
+}
+
+// tokensSince totals the tokens a project's event log records from start on
+func tokensSince(repo string, start time.Time) int {
+	evts, _, err := events.ReadFile(filepath.Join(repoArtifactsDir(repo), prd.EventsFile))
+	if err != nil {
+		return 0
+	}
+	total := 0
+	for _, e := range evts {
+		if !e.Time.Before(start) {
+			total += e.Tokens
+		}
+	}
+	return total
+}
+
+// showBatchSummary prints one row per project and the totals
+func showBatchSummary(d *display.Display, results []batchResult) {
+	d.SubHeader("Batch Summary")
+
+	w := tabwriter.NewWriter(d.Out(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tCOMPLETED\tREMAINING\tTOKENS\tRESULT")
+	completed, tokens, failed := 0, 0, 0
+	for _, r := range results {
+		outcome := "ok"
+		if r.err != nil {
+			outcome = "failed: " + r.err.Error()
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1fK\t%s\n", r.repo, r.completed, r.remaining, float64(r.tokens)/1000, outcome)
+		completed += r.completed
+		tokens += r.tokens
+	}
+	w.Flush()
+
+	fmt.Fprintln(d.Out())
+	d.Stat("Projects", fmt.Sprintf("%d (%d failed)", len(results), failed))
+	d.Stat("PRDs completed", fmt.Sprintf("%d", completed))
+	d.Stat("Tokens", fmt.Sprintf("%.1fK", float64(tokens)/1000))
+}
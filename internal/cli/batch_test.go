@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/events"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestReadRepoList(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "repos.txt")
+	content := "# fleet\napi\n\n  web  \n/abs/path\napi\n"
+	if err := os.WriteFile(list, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readRepoList(list)
+	if err != nil {
+		t.Fatalf("readRepoList() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "api"), filepath.Join(dir, "web"), "/abs/path"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readRepoList() = %v, want %v", got, want)
+	}
+}
+
+// newBatchProject creates a project with open PRDs a-1 and a-2
+func newBatchProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := &prd.PRDFileData{PRDs: []prd.PRD{{ID: "a-1"}, {ID: "a-2"}}}
+	if err := prd.Save(dir, data); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRunBatch(t *testing.T) {
+	done, failing, missing := newBatchProject(t), newBatchProject(t), t.TempDir()
+	others := []string{newBatchProject(t), newBatchProject(t)}
+	repos := append([]string{done, failing, missing}, others...)
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	run := func(ctx context.Context, repo string, out io.Writer) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprintln(out, "running", repo)
+
+		if repo == failing {
+			return errors.New("exit status 1")
+		}
+		if repo == done {
+			data, err := prd.Load(repo)
+			if err != nil {
+				return err
+			}
+			data.PRDs[0].Passes.SetTrue()
+			if err := prd.Save(repo, data); err != nil {
+				return err
+			}
+			log := events.NewLog(repo)
+			log.Append(events.Event{Type: events.TypePhase, Phase: "builder", Tokens: 1500})
+			log.Append(events.Event{Type: events.TypePhase, Phase: "reviewer", Tokens: 500})
+		}
+		return nil
+	}
+
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+	results := runBatch(context.Background(), d, repos, 2, run)
+
+	if peak > 2 {
+		t.Errorf("%d projects ran at once, want at most 2", peak)
+	}
+	if len(results) != len(repos) {
+		t.Fatalf("got %d results, want %d", len(results), len(repos))
+	}
+	for i, r := range results {
+		if r.repo != repos[i] {
+			t.Errorf("result %d is for %s, want %s", i, r.repo, repos[i])
+		}
+	}
+
+	if r := results[0]; r.err != nil || r.completed != 1 || r.remaining != 1 || r.tokens != 2000 {
+		t.Errorf("completed project = %+v, want 1 completed, 1 remaining, 2000 tokens", r)
+	}
+	if results[1].err == nil {
+		t.Error("failing project has no error")
+	}
+	if r := results[2]; r.err == nil || !strings.Contains(r.err.Error(), "not initialized") {
+		t.Errorf("uninitialized project error = %v", r.err)
+	}
+	for _, r := range results[3:] {
+		if r.err != nil || r.remaining != 2 {
+			t.Errorf("project after a failure = %+v, want it run with 2 remaining", r)
+		}
+		log, err := os.ReadFile(r.log)
+		if err != nil || !strings.Contains(string(log), "running "+r.repo) {
+			t.Errorf("parallel run output not in %s: %q (%v)", r.log, log, err)
+		}
+	}
+
+	showBatchSummary(d, results)
+	if !strings.Contains(out.String(), "2.0K") || !strings.Contains(out.String(), "failed: exit status 1") {
+		t.Errorf("summary missing tokens or failure:\n%s", out.String())
+	}
+}
+
+func TestBatchRunArgs(t *testing.T) {
+	got := batchRunArgs(3, true, []string{"--review-all"})
+	want := []string{"run", "3", "--no-color", "--review-all"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batchRunArgs() = %v, want %v", got, want)
+	}
+}
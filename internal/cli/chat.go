@@ -4,16 +4,24 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/daydemir/milhouse/internal/builder"
 	"github.com/daydemir/milhouse/internal/config"
 	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
 	"github.com/daydemir/milhouse/internal/prd"
 )
 
-var chatModelFlag string
+var (
+	chatModelFlag  string
+	chatSaveFlag   bool
+	chatNoSaveFlag bool
+)
 
 var chatCmd = &cobra.Command{
 	Use:   "chat",
@@ -31,6 +39,9 @@ This command launches an interactive session - no arguments needed.`,
 
 func init() {
 	chatCmd.Flags().StringVar(&chatModelFlag, "model", "", "Override chat model (haiku, sonnet, opus)")
+	chatCmd.Flags().BoolVar(&chatSaveFlag, "save", false, "Save the session transcript to .milhouse/chat-history/")
+	chatCmd.Flags().BoolVar(&chatNoSaveFlag, "no-save", false, "Do not save the transcript, even if phases.chat.saveHistory is set")
+	chatCmd.MarkFlagsMutuallyExclusive("save", "no-save")
 	rootCmd.AddCommand(chatCmd)
 }
 
@@ -75,6 +86,9 @@ func runChat(cmd *cobra.Command, args []string) error {
 		display.Error(fmt.Sprintf("Invalid configuration: %v", err))
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
+	if err := checkAPIKey(display.NewWithOptions(GetNoColor()), cfg); err != nil {
+		return err
+	}
 
 	// Create context for the session
 	ctx := context.Background()
@@ -83,10 +97,80 @@ func runChat(cmd *cobra.Command, args []string) error {
 	display.Info("Starting interactive session...")
 	display.Divider()
 
+	save := (chatSaveFlag || cfg.GetPhaseConfig("chat").SaveHistory) && !chatNoSaveFlag
+	if save {
+		cfg.Run.ChatSessionID = llm.NewSessionID()
+	}
+	started := time.Now()
+
 	// Run interactive Claude session
-	if err := builder.RunChat(ctx, cwd, prdFile, cfg); err != nil {
-		return fmt.Errorf("chat session error: %w", err)
+	chatErr := builder.RunChat(ctx, cwd, prdFile, cfg)
+
+	// Stamp PRDs added during the session and record status changes made in it
+	if _, err := reloadWithHistory(cwd, prdFile, 0); err != nil {
+		display.Warning(fmt.Sprintf("Failed to record PRD changes: %v", err))
 	}
 
+	// Save whatever was said, even if the session ended with an error
+	if save {
+		redactor, _ := cfg.Redact.Redactor() // Validated above
+		if path, err := saveChatHistory(cwd, cfg.Run.ChatSessionID, started, redactor); err != nil {
+			display.Warning(fmt.Sprintf("Chat transcript not saved: %v", err))
+		} else {
+			display.Info(fmt.Sprintf("Transcript saved to %s", path))
+		}
+	}
+
+	if chatErr != nil {
+		return fmt.Errorf("chat session error: %w", chatErr)
+	}
 	return nil
 }
+
+// saveChatHistory converts the claude CLI's record of a chat session into
+// markdown under chat-history/, masking secrets, and returns the file written
+// The interactive session owns the terminal, so its I/O cannot be teed;
+// the transcript claude keeps for the session is read afterwards instead.
+func saveChatHistory(cwd, sessionID string, started time.Time, redactor *llm.Redactor) (string, error) {
+	source, err := llm.SessionTranscriptPath(cwd, sessionID)
+	if err != nil {
+		return "", err
+	}
+	turns, err := llm.ReadTranscript(source)
+	if err != nil {
+		return "", err
+	}
+	if len(turns) == 0 {
+		return "", fmt.Errorf("session %s has no messages", sessionID)
+	}
+
+	path := prd.GetArtifactPath(cwd, prd.ChatHistoryDir, started.Format("20060102-150405")+".md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create chat history directory: %w", err)
+	}
+	text := redactor.Redact(formatTranscript(turns, started))
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return "", fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return path, nil
+}
+
+// formatTranscript renders chat turns as markdown
+func formatTranscript(turns []llm.TranscriptTurn, started time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Chat %s\n", started.Format("2006-01-02 15:04"))
+	for _, t := range turns {
+		speaker := "You"
+		if t.Role == "assistant" {
+			speaker = "Claude"
+		}
+		fmt.Fprintf(&b, "\n**%s:**\n", speaker)
+		if t.Text != "" {
+			fmt.Fprintf(&b, "\n%s\n", t.Text)
+		}
+		if len(t.Tools) > 0 {
+			fmt.Fprintf(&b, "\n_Tools: %s_\n", strings.Join(t.Tools, ", "))
+		}
+	}
+	return b.String()
+}
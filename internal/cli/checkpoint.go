@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// checkpointCommit commits the working tree after the reviewer completes PRDs
+// and records the commit SHA in each completed PRD's history. Failures are
+// reported as warnings; a checkpoint never stops the run.
+func checkpointCommit(d *display.Display, cwd string, before, after *prd.PRDFileData) {
+	completed := newlyCompleted(before, after)
+	if len(completed) == 0 {
+		return
+	}
+
+	sha, committed, err := git.Commit(cwd, checkpointMessage(completed))
+	if err != nil {
+		d.Warning(fmt.Sprintf("Checkpoint commit failed: %v", err))
+		return
+	}
+	if !committed {
+		d.Info("Checkpoint skipped: nothing to commit")
+		return
+	}
+
+	for _, p := range completed {
+		if found := after.FindByID(p.ID); found != nil {
+			found.RecordCommit(sha)
+		}
+	}
+	if err := prd.Save(cwd, after); err != nil {
+		d.Warning(fmt.Sprintf("Failed to record checkpoint commit: %v", err))
+	}
+	d.Success(fmt.Sprintf("Checkpoint commit %s", sha[:min(7, len(sha))]))
+}
+
+// newlyCompleted returns PRDs that are complete in after but were not in before
+func newlyCompleted(before, after *prd.PRDFileData) []prd.PRD {
+	var completed []prd.PRD
+	for _, p := range after.PRDs {
+		if !p.Passes.IsTrue() {
+			continue
+		}
+		if old := before.FindByID(p.ID); old != nil && old.Passes.IsTrue() {
+			continue
+		}
+		completed = append(completed, p)
+	}
+	return completed
+}
+
+// checkpointMessage builds "millhouse: <id> — <description>", listing every
+// PRD in the body when several completed in the same review
+func checkpointMessage(completed []prd.PRD) string {
+	if len(completed) == 1 {
+		return fmt.Sprintf("millhouse: %s — %s", completed[0].ID, completed[0].Description)
+	}
+
+	ids := make([]string, len(completed))
+	lines := make([]string, len(completed))
+	for i, p := range completed {
+		ids[i] = p.ID
+		lines[i] = fmt.Sprintf("- %s — %s", p.ID, p.Description)
+	}
+	return fmt.Sprintf("millhouse: %s\n\n%s", strings.Join(ids, ", "), strings.Join(lines, "\n"))
+}
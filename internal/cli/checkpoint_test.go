@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestNewlyCompleted(t *testing.T) {
+	before := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "done", Passes: prd.PassesStatus{Value: true}},
+		{ID: "verified", Passes: prd.PassesStatus{Value: "pending"}},
+		{ID: "rejected", Passes: prd.PassesStatus{Value: "pending"}},
+	}}
+	after := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "done", Passes: prd.PassesStatus{Value: true}},
+		{ID: "verified", Description: "Add login", Passes: prd.PassesStatus{Value: true}},
+		{ID: "rejected", Passes: prd.PassesStatus{Value: false}},
+		{ID: "added", Description: "Added by hand", Passes: prd.PassesStatus{Value: true}},
+	}}
+
+	completed := newlyCompleted(before, after)
+	if len(completed) != 2 || completed[0].ID != "verified" || completed[1].ID != "added" {
+		t.Fatalf("newlyCompleted = %v", completed)
+	}
+
+	if got, want := checkpointMessage(completed[:1]), "millhouse: verified — Add login"; got != want {
+		t.Errorf("single message = %q, want %q", got, want)
+	}
+	want := "millhouse: verified, added\n\n- verified — Add login\n- added — Added by hand"
+	if got := checkpointMessage(completed); got != want {
+		t.Errorf("multi message = %q, want %q", got, want)
+	}
+}
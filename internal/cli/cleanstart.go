@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// Start policies for a working tree that already has uncommitted changes
+const (
+	startWarn   = "warn"   // Say so and run anyway (default)
+	startRefuse = "refuse" // --require-clean-start
+	startStash  = "stash"  // --stash: stash now, restore when the run ends
+)
+
+// stashMessage labels the stash in 'git stash list'
+const stashMessage = "millhouse: changes from before mil run"
+
+// startPolicy picks the start policy from the run's flags
+// A checkpoint commit stages the whole tree, so with --checkpoint-commit a
+// dirty start is refused rather than folded into the first checkpoint.
+func startPolicy(requireClean, stash, checkpoint bool) string {
+	switch {
+	case requireClean:
+		return startRefuse
+	case stash:
+		return startStash
+	case checkpoint:
+		return startRefuse
+	}
+	return startWarn
+}
+
+// guardStartTree applies the start policy to changes that were in the working
+// tree before the run, ignoring .milhouse/ which the run itself writes
+// It returns the guard holding stashed changes (nil when nothing was stashed)
+// for the caller to restore, or an error when the run must not start.
+func guardStartTree(d *display.Display, cwd, policy string) (*git.StashGuard, error) {
+	changes, err := git.UncommittedChanges(cwd, prd.MillhouseDir)
+	if err != nil {
+		if policy == startWarn {
+			return nil, nil // Not a git repo: nothing to protect
+		}
+		d.Error(fmt.Sprintf("Cannot check for uncommitted changes: %v", err))
+		return nil, fmt.Errorf("--%s needs a git repository: %w", policyFlag(policy), err)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	switch policy {
+	case startRefuse:
+		d.Error(fmt.Sprintf("Working tree has %d uncommitted change(s):", len(changes)))
+		for _, c := range changes[:min(len(changes), 10)] {
+			d.Info("  " + c)
+		}
+		d.Info("Commit or stash them first, or rerun with --stash")
+		return nil, fmt.Errorf("working tree not clean")
+
+	case startStash:
+		guard, err := git.GuardStash(cwd, stashMessage, prd.MillhouseDir)
+		if err != nil {
+			d.Error(fmt.Sprintf("Failed to stash uncommitted changes: %v", err))
+			return nil, fmt.Errorf("failed to stash: %w", err)
+		}
+		d.Info(fmt.Sprintf("Stashed %d uncommitted change(s); they are restored when the run ends", guard.Stashed()))
+		return guard, nil
+	}
+
+	d.Warning(fmt.Sprintf("Working tree has %d uncommitted change(s) from before this run", len(changes)))
+	d.Info("The agent will build on them and the reviewer may mistake them for its work; use --stash or --require-clean-start")
+	return nil, nil
+}
+
+// restoreStartTree puts stashed pre-run changes back, telling the user how to
+// recover them by hand when they no longer apply cleanly
+func restoreStartTree(d *display.Display, guard *git.StashGuard) {
+	sha := guard.SHA()
+	if err := guard.Restore(); err != nil {
+		d.Warning(fmt.Sprintf("Could not restore your stashed changes: %v", err))
+		d.Info(fmt.Sprintf("They are safe in the stash (%s); resolve with 'git stash list' and 'git stash pop'", sha[:min(7, len(sha))]))
+		return
+	}
+	d.Success("Restored changes stashed before the run")
+}
+
+func policyFlag(policy string) string {
+	if policy == startRefuse {
+		return "require-clean-start"
+	}
+	return policy
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestGuardStartTree(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-m", "initial")
+
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+
+	// Run state in .milhouse/ never counts as a pre-existing change
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, prd.MillhouseDir, prd.PRDFile), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if guard, err := guardStartTree(d, dir, startRefuse); err != nil || guard != nil {
+		t.Fatalf("guardStartTree() with only .milhouse/ changes = %v, %v", guard, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := guardStartTree(d, dir, startWarn); err != nil {
+		t.Errorf("warn policy error = %v", err)
+	}
+	if !strings.Contains(out.String(), "1 uncommitted change(s)") {
+		t.Errorf("warn policy did not warn:\n%s", out.String())
+	}
+	if _, err := guardStartTree(d, dir, startRefuse); err == nil {
+		t.Error("refuse policy started on a dirty tree")
+	}
+
+	guard, err := guardStartTree(d, dir, startStash)
+	if err != nil || guard == nil {
+		t.Fatalf("stash policy = %v, %v; want a guard", guard, err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "main.go")); string(content) != "package main" {
+		t.Errorf("main.go = %q after stashing, want the committed version", content)
+	}
+	restoreStartTree(d, guard)
+	if content, _ := os.ReadFile(filepath.Join(dir, "main.go")); string(content) != "package main // edited" {
+		t.Errorf("main.go = %q after restore, want the user's edit", content)
+	}
+}
+
+func TestStartPolicy(t *testing.T) {
+	tests := []struct {
+		requireClean, stash, checkpoint bool
+		want                            string
+	}{
+		{want: startWarn},
+		{requireClean: true, want: startRefuse},
+		{stash: true, want: startStash},
+		{checkpoint: true, want: startRefuse},
+		{stash: true, checkpoint: true, want: startStash},
+	}
+	for _, tt := range tests {
+		if got := startPolicy(tt.requireClean, tt.stash, tt.checkpoint); got != tt.want {
+			t.Errorf("startPolicy(%v, %v, %v) = %s, want %s", tt.requireClean, tt.stash, tt.checkpoint, got, tt.want)
+		}
+	}
+}
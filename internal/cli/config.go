@@ -40,7 +40,7 @@ var configShowCmd = &cobra.Command{
 var configInitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize configuration with defaults",
-	Long:  "Create a .milhouse/config.yaml file with default settings in the current project.",
+	Long:  "Create a .milhouse/config.yaml file with default settings in the current project.\nAn existing file is only replaced after confirmation (or with --yes).",
 	RunE:  runConfigInit,
 }
 
@@ -123,7 +123,10 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	configPath := config.MillhouseDir + "/" + config.ConfigFile
 	if _, err := os.Stat(configPath); err == nil {
 		display.Warning(fmt.Sprintf("Configuration file already exists: %s", configPath))
-		return nil
+		if !confirm("Overwrite it with defaults?") {
+			display.Info("Left existing configuration unchanged")
+			return nil
+		}
 	}
 
 	// Create default config and save
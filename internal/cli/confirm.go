@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// confirm asks the user a yes/no question before a destructive operation
+// --yes answers yes without asking. Without a terminal on stdin the answer is
+// no, so scripts fail safe instead of hanging on a prompt nobody can see.
+func confirm(prompt string) bool {
+	isTTY := term.IsTerminal(int(os.Stdin.Fd()))
+	if !assumeYes && !isTTY {
+		display.Warning("No terminal to confirm on - pass --yes to proceed non-interactively")
+	}
+	return confirmWith(os.Stdin, os.Stdout, isTTY, assumeYes, prompt)
+}
+
+// confirmWith implements confirm over explicit streams
+func confirmWith(in io.Reader, out io.Writer, isTTY, yes bool, prompt string) bool {
+	if yes {
+		return true
+	}
+	if !isTTY {
+		return false
+	}
+
+	fmt.Fprintf(out, "%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// askChoice prompts until the user picks one of options by name or first
+// letter, e.g. "[c]ontinue  [a]bort: "
+// An empty answer picks def, or asks again when def is empty. End of input
+// picks the last option, which should be the way out.
+func askChoice(in *bufio.Reader, out io.Writer, options []string, def string) string {
+	labels := make([]string, len(options))
+	for i, option := range options {
+		labels[i] = "[" + option[:1] + "]" + option[1:]
+	}
+	for {
+		fmt.Fprintf(out, "%s: ", strings.Join(labels, "  "))
+		answer, err := in.ReadString('\n')
+		if err != nil && answer == "" {
+			fmt.Fprintln(out)
+			return options[len(options)-1]
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer == "" && def != "" {
+			return def
+		}
+		for _, option := range options {
+			if answer != "" && (answer == option || answer == option[:1]) {
+				return option
+			}
+		}
+	}
+}
+
+// writeConfirmer returns the confirmBeforeWrite prompt shown when the
+// builder first tries to change files for a PRD, or nil without a terminal
+func writeConfirmer(cwd string) func(prdID, tool string) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil
+	}
+	return func(prdID, tool string) bool {
+		display.Warning(fmt.Sprintf("Builder wants to use %s on %s - nothing has been changed yet", tool, prdID))
+		display.Info(fmt.Sprintf("Plan: %s", prd.GetPlanPath(cwd, prdID)))
+		return confirm("Allow the builder to make changes?")
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConfirmWith(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		isTTY bool
+		yes   bool
+		want  bool
+	}{
+		{"yes flag skips prompt", "", false, true, true},
+		{"no tty refuses", "y\n", false, false, false},
+		{"answer y", "y\n", true, false, true},
+		{"answer yes", "YES\n", true, false, true},
+		{"answer n", "n\n", true, false, false},
+		{"empty answer defaults to no", "\n", true, false, false},
+		{"eof refuses", "", true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got := confirmWith(strings.NewReader(tt.input), &out, tt.isTTY, tt.yes, "Delete it?")
+			if got != tt.want {
+				t.Errorf("confirmWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAskChoice(t *testing.T) {
+	step := []string{"continue", "inspect", "abort"}
+	decide := []string{"approve", "reject", "skip", "quit"}
+	tests := []struct {
+		options []string
+		def     string
+		input   string
+		want    string
+	}{
+		{step, "continue", "\n", "continue"},
+		{step, "continue", "C\n", "continue"},
+		{step, "continue", "inspect\n", "inspect"},
+		{step, "continue", "huh\na\n", "abort"},
+		{step, "continue", "", "abort"},
+		{decide, "", "\nr\n", "reject"}, // No default: an empty answer asks again
+		{decide, "", " Skip \n", "skip"},
+		{decide, "", "x\n", "quit"}, // End of input after a bad answer
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := askChoice(bufio.NewReader(strings.NewReader(tt.input)), &out, tt.options, tt.def); got != tt.want {
+			t.Errorf("askChoice(%v, %q) = %s, want %s", tt.options, tt.input, got, tt.want)
+		}
+	}
+
+	var out bytes.Buffer
+	askChoice(bufio.NewReader(strings.NewReader("a\n")), &out, decide, "")
+	if got := out.String(); got != "[a]pprove  [r]eject  [s]kip  [q]uit: " {
+		t.Errorf("prompt = %q", got)
+	}
+}
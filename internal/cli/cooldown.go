@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// DefaultRejectCooldown is how many iterations the planner passes over a PRD
+// the reviewer just rejected while other work is open
+const DefaultRejectCooldown = 1
+
+// rejectionCooldown keeps the planner off PRDs rejected in the last few
+// iterations of one mil run, so their rejection notes can shape a different
+// approach instead of the same one being replanned straight away
+type rejectionCooldown struct {
+	iterations int            // Iterations a rejected PRD sits out; 0 disables
+	rejectedAt map[string]int // Iteration of each PRD's latest rejection
+}
+
+func newRejectionCooldown(iterations int) *rejectionCooldown {
+	return &rejectionCooldown{iterations: iterations, rejectedAt: make(map[string]int)}
+}
+
+// record notes the PRDs one review rejected in iteration
+func (c *rejectionCooldown) record(signals []llm.Signal, iteration int) {
+	for _, s := range signals {
+		if s.Type == llm.SignalRejected && s.PRDID != "" {
+			c.rejectedAt[s.PRDID] = iteration
+		}
+	}
+}
+
+// remaining returns how many more iterations id sits out after iteration,
+// or 0 when it is free to be planned
+func (c *rejectionCooldown) remaining(id string, iteration int) int {
+	at, ok := c.rejectedAt[id]
+	if !ok || c.iterations <= 0 {
+		return 0
+	}
+	return max(at+c.iterations-iteration+1, 0)
+}
+
+// skip returns the open PRDs within scope that are cooling down in iteration,
+// and whether the planner should pass over them: it should not when they are
+// all the open work there is
+func (c *rejectionCooldown) skip(prdFile *prd.PRDFileData, scope prd.Scope, iteration int) (cooling []string, ok bool) {
+	open := prdFile.GetOpenPRDsIn(scope)
+	for _, p := range open {
+		if c.remaining(p.ID, iteration) > 0 {
+			cooling = append(cooling, p.ID)
+		}
+	}
+	return cooling, len(cooling) > 0 && len(cooling) < len(open)
+}
+
+// applyCooldown sets the PRDs the next planner run passes over and says why;
+// callers clear cfg.Run.Cooldown once the planner has run
+func applyCooldown(d *display.Display, c *rejectionCooldown, prdFile *prd.PRDFileData, scope prd.Scope, iteration int) []string {
+	cooling, ok := c.skip(prdFile, scope, iteration)
+	if len(cooling) == 0 {
+		return nil
+	}
+	if !ok {
+		d.Info(fmt.Sprintf("Rejection cooldown: %s is all the open work, planning it anyway", strings.Join(cooling, ", ")))
+		return nil
+	}
+	for _, id := range cooling {
+		d.Info(fmt.Sprintf("Rejection cooldown: skipping %s, just rejected (%d iteration(s) left)", id, c.remaining(id, iteration)))
+	}
+	return cooling
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestRejectionCooldown(t *testing.T) {
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "top", Priority: 1},
+		{ID: "next", Priority: 2},
+	}}
+	for i := range prdFile.PRDs {
+		prdFile.PRDs[i].Passes.SetFalse()
+	}
+	cooldown := newRejectionCooldown(2)
+
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+
+	// The reviewer rejects top in iteration 1; it goes back to open
+	cooldown.record([]llm.Signal{{Type: llm.SignalRejected, PRDID: "top", Details: "tests fail"}}, 1)
+
+	var order []string
+	for i := 2; i <= 4; i++ {
+		skip := applyCooldown(d, cooldown, prdFile, prd.Scope{}, i)
+		order = append(order, prd.SelectNext(prdFile, prd.Scope{Skip: skip}).ID)
+	}
+	if want := []string{"next", "next", "top"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("planner picks after a rejection = %v, want %v", order, want)
+	}
+	if !strings.Contains(out.String(), "skipping top, just rejected (2 iteration(s) left)") {
+		t.Errorf("cooldown not shown:\n%s", out.String())
+	}
+
+	// With no other open work the rejected PRD is planned anyway
+	cooldown.record([]llm.Signal{{Type: llm.SignalRejected, PRDID: "top"}}, 4)
+	prdFile.PRDs[1].Passes.SetTrue()
+	out.Reset()
+	if skip := applyCooldown(d, cooldown, prdFile, prd.Scope{}, 5); skip != nil {
+		t.Errorf("skipped %v with nothing else open", skip)
+	}
+	if !strings.Contains(out.String(), "planning it anyway") {
+		t.Errorf("forced planning not shown:\n%s", out.String())
+	}
+}
+
+func TestRejectionCooldownDisabled(t *testing.T) {
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{{ID: "top", Priority: 1}, {ID: "next", Priority: 2}}}
+	for i := range prdFile.PRDs {
+		prdFile.PRDs[i].Passes.SetFalse()
+	}
+	cooldown := newRejectionCooldown(0)
+	cooldown.record([]llm.Signal{{Type: llm.SignalRejected, PRDID: "top"}}, 1)
+	if cooling, ok := cooldown.skip(prdFile, prd.Scope{}, 2); cooling != nil || ok {
+		t.Errorf("disabled cooldown skipped %v", cooling)
+	}
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/custom"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+var customModelFlag string
+
+var customCmd = &cobra.Command{
+	Use:   "x <name> [args...]",
+	Short: "Run a custom agent prompt from .milhouse/prompts/custom/",
+	Long: `Run a user-defined prompt as a single agent pass, outside the PRD loop.
+
+Prompts live in .milhouse/prompts/custom/<name>.md. The agent gets the prompt
+plus prd.json, progress.md and prompt.md as context. Arguments after the name
+are substituted into the prompt:
+  $ARGUMENTS  all arguments, space-separated
+  $1, ${2}    a single argument
+  $$          a literal $
+A prompt without placeholders gets the arguments appended.
+
+The agent is read-only unless phases.custom.allowWrites is set in config.yaml;
+phases.custom also sets its model, token limits, extraArgs and mcpServers.
+Run 'mil x' without a name to list the available prompts.`,
+	Example: `  mil x security-audit
+  mil x explain-package internal/llm`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeCustomPrompts,
+	RunE:              runCustom,
+}
+
+func init() {
+	customCmd.Flags().StringVar(&customModelFlag, "model", "", "Override custom prompt model (haiku, sonnet, opus)")
+	customCmd.Flags().SetInterspersed(false) // Flags after the name belong to the prompt
+	rootCmd.AddCommand(customCmd)
+}
+
+func runCustom(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	d := display.NewWithOptions(GetNoColor())
+
+	if !prd.MillhouseExists(cwd) {
+		d.Error(".milhouse/ directory not found")
+		d.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	if len(args) == 0 {
+		return listCustomPrompts(d, cwd)
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		d.Warning(fmt.Sprintf("Failed to load config: %v, using defaults", err))
+		cfg = config.DefaultConfig()
+	}
+
+	applyDisplayConfig(d, cfg)
+
+	if customModelFlag != "" {
+		cfg.Phases.Custom.Model = customModelFlag
+	}
+	if err := cfg.Validate(); err != nil {
+		d.Error(fmt.Sprintf("Invalid configuration from CLI flags: %v", err))
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := checkAPIKey(d, cfg); err != nil {
+		return err
+	}
+
+	name := args[0]
+	d.Header("Milhouse x " + name)
+
+	result, err := custom.Run(context.Background(), cwd, name, args[1:], cfg)
+	if err != nil {
+		d.Error(fmt.Sprintf("Custom prompt error: %v", err))
+		return fmt.Errorf("custom prompt failed: %w", err)
+	}
+
+	if result.Summary != "" {
+		d.Info(result.Summary)
+	}
+	for _, sig := range result.Signals {
+		if sig.Type == llm.SignalBlocked {
+			d.Warning(fmt.Sprintf("Agent could not complete '%s': %s", name, sig.Details))
+			return fmt.Errorf("custom prompt blocked")
+		}
+	}
+	return nil
+}
+
+// listCustomPrompts shows the prompts mil x can run
+func listCustomPrompts(d *display.Display, cwd string) error {
+	names, err := custom.List(cwd)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		d.Info("No custom prompts yet")
+		d.Info("Create .milhouse/prompts/custom/<name>.md and run 'mil x <name>'")
+		return nil
+	}
+	d.Header("Custom prompts")
+	for _, name := range names {
+		fmt.Printf("  %s\n", name)
+	}
+	return nil
+}
+
+// completeCustomPrompts offers custom prompt names for the first argument
+func completeCustomPrompts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, _ := custom.List(cwd)
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			matches = append(matches, name)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
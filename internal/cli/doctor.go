@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check .milhouse/ for common problems",
+	Long: `Check the project's .milhouse/ folder for problems that confuse the agents:
+
+  - prd.json that fails to load
+  - config.yaml that fails to load or validate, or whose auth source has no key
+  - progress.md or prompt.md with invalid UTF-8 or CRLF line endings
+  - PRD integrity problems reported by 'mil prd validate', including plan
+    files whose PRD is gone or no longer active (see 'mil plan prune')
+
+Agents read text files with invalid bytes replaced and line endings
+normalized, but fixing the files keeps what you see and what they see the same.
+
+With --fix, a prd.json that is empty or fails to parse is restored from prd.json.bak (see
+'mil prd restore').`,
+	RunE: runDoctor,
+}
+
+var doctorFixFlag bool
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFixFlag, "fix", false, "Repair what can be repaired automatically")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if !prd.MillhouseExists(cwd) {
+		display.Error(".milhouse/ directory not found")
+		display.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	display.Header("Milhouse Doctor")
+	problems := 0
+
+	prdFile, err := prd.Load(cwd)
+	if doctorFixFlag && !prd.Intact(cwd) && prd.BackupExists(cwd) {
+		if _, err = prd.Restore(cwd); err == nil {
+			display.Success(fmt.Sprintf("Restored %s from %s", prd.PRDFile, prd.BackupFile))
+			prdFile, err = prd.Load(cwd)
+		}
+	}
+	if err != nil {
+		display.Error(fmt.Sprintf("%s: %v", prd.PRDFile, err)) // Names the restore commands when there is a backup
+		problems++
+	} else {
+		display.Success(fmt.Sprintf("%s loads", prd.PRDFile))
+	}
+
+	if cfg, err := config.Load(cwd); err != nil {
+		display.Error(fmt.Sprintf("config: %v", err))
+		problems++
+	} else {
+		display.Success("config loads and validates")
+		if cfg.Auth != (config.AuthConfig{}) {
+			if _, err := cfg.Auth.APIKey(); err != nil {
+				display.Error(fmt.Sprintf("auth: %v", err))
+				problems++
+			} else {
+				display.Success("API key found")
+			}
+		}
+	}
+
+	for _, name := range []string{prd.ProgressFile, prd.PromptFile} {
+		issues, err := prd.TextFileIssues(prd.GetMillhousePath(cwd, name))
+		if os.IsNotExist(err) {
+			display.Warning(fmt.Sprintf("%s: missing", name))
+			continue
+		}
+		if err != nil {
+			display.Error(fmt.Sprintf("%s: %v", name, err))
+			problems++
+			continue
+		}
+		if len(issues) > 0 {
+			display.Warning(fmt.Sprintf("%s: %s", name, strings.Join(issues, ", ")))
+			problems++
+			continue
+		}
+		display.Success(fmt.Sprintf("%s is valid UTF-8", name))
+	}
+
+	if prdFile != nil {
+		issues := prd.Lint(prdFile, cwd)
+		for _, issue := range issues {
+			if issue.Severity == prd.SeverityError {
+				display.Error(issue.String())
+			} else {
+				display.Warning(issue.String())
+			}
+		}
+		if len(issues) > 0 {
+			problems += len(issues)
+		} else {
+			display.Success("PRDs pass integrity checks")
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) found", problems)
+	}
+	display.Success("No problems found")
+	return nil
+}
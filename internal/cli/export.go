@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+var (
+	exportFormatFlag string
+	exportOutputFlag string
+	exportOwnerFlag  string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export PRD status as HTML or CSV",
+	Long: `Write the PRD status for use outside the terminal.
+
+Formats:
+  html  a self-contained, styled status report for a browser or email
+  csv   one row per PRD (id, status, priority, description, owner, dependsOn)
+        for spreadsheets
+
+The export goes to stdout unless --output names a file.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormatFlag, "format", prd.ExportHTML, "Output format: "+strings.Join(prd.ExportFormats, ", "))
+	exportCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "", "Write to `file` instead of stdout")
+	exportCmd.Flags().StringVar(&exportOwnerFlag, "owner", "", "Only export PRDs owned by this person (or unowned)")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(prd.ExportFormats, exportFormatFlag) {
+		return fmt.Errorf("unknown --format %q (valid: %s)", exportFormatFlag, strings.Join(prd.ExportFormats, ", "))
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if !prd.MillhouseExists(cwd) {
+		display.Error(".milhouse/ directory not found")
+		display.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+	if exportOwnerFlag != "" {
+		prdFile.PRDs = prdFile.GetPRDsByOwner(exportOwnerFlag)
+	}
+
+	if exportOutputFlag == "" {
+		return prd.Export(os.Stdout, exportFormatFlag, prdFile, time.Now())
+	}
+
+	f, err := os.Create(exportOutputFlag)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := prd.Export(f, exportFormatFlag, prdFile, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	display.Success(fmt.Sprintf("Exported %d PRD(s) to %s", len(prdFile.PRDs), exportOutputFlag))
+	return nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// DefaultFairThreshold is the iteration lead --fair allows the top-priority
+// PRD over the least-served open PRD before rotating
+const DefaultFairThreshold = 3
+
+// fairScheduler counts the iterations each PRD consumes over one mil run and
+// rotates planning away from PRDs that have taken a disproportionate share
+type fairScheduler struct {
+	threshold int            // Iteration lead that triggers a rotation; 0 disables
+	used      map[string]int // Iterations consumed by PRD ID
+}
+
+func newFairScheduler(threshold int) *fairScheduler {
+	return &fairScheduler{threshold: threshold, used: make(map[string]int)}
+}
+
+// record charges one iteration to the PRD it worked on
+func (f *fairScheduler) record(id string) {
+	if id != "" {
+		f.used[id]++
+	}
+}
+
+// pick returns the PRD the planner should take instead of the priority
+// choice, along with that choice, or "" to keep priority order
+// It rotates once the priority choice has used threshold iterations more
+// than the least-served open PRD, preferring higher priority among those.
+func (f *fairScheduler) pick(prdFile *prd.PRDFileData, scope prd.Scope) (rotateTo, top string) {
+	if f.threshold <= 0 {
+		return "", ""
+	}
+	next := prd.SelectNext(prdFile, scope)
+	if next == nil {
+		return "", ""
+	}
+
+	var least *prd.PRD
+	for _, p := range prdFile.GetOpenPRDsIn(scope) {
+		if least == nil || f.used[p.ID] < f.used[least.ID] ||
+			(f.used[p.ID] == f.used[least.ID] && p.Priority < least.Priority) {
+			least = &p
+		}
+	}
+	if least.ID == next.ID || f.used[next.ID]-f.used[least.ID] < f.threshold {
+		return "", ""
+	}
+	return least.ID, next.ID
+}
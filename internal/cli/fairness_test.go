@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestFairSchedulerRotates(t *testing.T) {
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "hog", Priority: 1},
+		{ID: "mid", Priority: 2},
+		{ID: "low", Priority: 3},
+	}}
+	for i := range prdFile.PRDs {
+		prdFile.PRDs[i].Passes.SetFalse()
+	}
+	fair := newFairScheduler(2)
+
+	// Simulate a run where the planner always takes what it is given, or the
+	// priority choice when not rotated
+	var order []string
+	for i := 0; i < 6; i++ {
+		id, top := fair.pick(prdFile, prd.Scope{})
+		if id == "" {
+			id = prd.SelectNext(prdFile, prd.Scope{}).ID
+		} else if top != "hog" {
+			t.Errorf("rotated away from %s, want hog", top)
+		}
+		order = append(order, id)
+		fair.record(id)
+	}
+
+	want := []string{"hog", "hog", "mid", "low", "hog", "mid"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestFairSchedulerDisabled(t *testing.T) {
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{{ID: "hog", Priority: 1}, {ID: "other", Priority: 2}}}
+	for i := range prdFile.PRDs {
+		prdFile.PRDs[i].Passes.SetFalse()
+	}
+	fair := newFairScheduler(0)
+	for i := 0; i < 10; i++ {
+		fair.record("hog")
+	}
+	if id, _ := fair.pick(prdFile, prd.Scope{}); id != "" {
+		t.Errorf("disabled scheduler rotated to %s", id)
+	}
+}
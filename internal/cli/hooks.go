@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/events"
+	"github.com/daydemir/milhouse/internal/exec"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// buildFailureTailLines is how much build output is copied into PRD notes
+const buildFailureTailLines = 15
+
+// runPostBuildHooks runs the configured hook commands after the builder and
+// saves the results as the PRD's check evidence for the reviewer
+// A failing build command stops the remaining hooks and is returned as failed.
+func runPostBuildHooks(ctx context.Context, d *display.Display, cwd string, cfg *config.Config, prdID string) (results []*exec.Result, failed *exec.Result, err error) {
+	if cfg.Hooks.BuildCommand == "" && cfg.Hooks.TestCommand == "" {
+		return nil, nil, nil
+	}
+
+	// Note the code under test so the results go stale once it changes
+	var tree string
+	if state, err := git.CaptureTree(cwd, prd.MillhouseDir); err == nil {
+		tree, _ = state.Fingerprint()
+	}
+
+	if cfg.Hooks.BuildCommand != "" {
+		r, err := runHook(ctx, d, cwd, "build", cfg.Hooks.BuildCommand, cfg.Hooks.BuildTimeout, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, r)
+		if !r.Passed() {
+			failed = r
+		}
+	}
+
+	if failed == nil && cfg.Hooks.TestCommand != "" {
+		r, err := runHook(ctx, d, cwd, "test", cfg.Hooks.TestCommand, cfg.Hooks.TestTimeout, nil)
+		if err != nil {
+			return results, nil, err
+		}
+		results = append(results, r)
+	}
+
+	for _, r := range results {
+		r.Tree = tree
+	}
+	path := prd.GetChecksPath(cwd, prdID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return results, failed, fmt.Errorf("failed to create evidence directory: %w", err)
+	}
+	if err := exec.SaveResults(path, results); err != nil {
+		return results, failed, err
+	}
+
+	return results, failed, nil
+}
+
+// runHook runs a single hook command, streaming its output through the display
+// env adds KEY=value variables to the command's environment.
+func runHook(ctx context.Context, d *display.Display, cwd, name, command string, timeout int, env []string) (*exec.Result, error) {
+	d.Info(fmt.Sprintf("Running %s command: %s", name, command))
+
+	r, err := exec.Run(ctx, name, command, exec.Options{
+		Dir:     cwd,
+		Timeout: time.Duration(config.HookTimeout(timeout)) * time.Second,
+		Stream:  d.Out(),
+		Env:     env,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Passed() {
+		d.Success(fmt.Sprintf("%s command passed (%s)", name, r.Duration.Round(100*time.Millisecond)))
+	} else {
+		d.Error(fmt.Sprintf("%s command %s (exit %d)", name, strings.ToLower(r.Status()), r.ExitCode))
+	}
+
+	return r, nil
+}
+
+// shouldGateBuild reports whether the post-build hooks should check prdID
+// Only a PRD the builder handed to review is checked: a blocked PRD or one
+// still active after a bailout has nothing to gate, and an interrupted run
+// would record the killed command as a failure.
+func shouldGateBuild(ctx context.Context, prdFile *prd.PRDFileData, prdID string) bool {
+	if ctx.Err() != nil || prdID == "" {
+		return false
+	}
+	p := prdFile.FindByID(prdID)
+	return p != nil && p.Passes.IsPending()
+}
+
+// recordBuildFailure moves a PRD whose build failed out of review and notes why
+// With requeue the PRD stays active for another builder pass against the same
+// plan, otherwise it returns to open and its plan is deleted so the planner
+// starts over.
+func recordBuildFailure(cwd, prdID string, r *exec.Result, requeue bool, iteration int) (string, error) {
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		return "", fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	p := prdFile.FindByID(prdID)
+	if p == nil {
+		return "", fmt.Errorf("PRD %s not found", prdID)
+	}
+	if !p.Passes.IsPending() {
+		return "", fmt.Errorf("PRD %s is %s, not awaiting review", prdID, p.Passes.String())
+	}
+
+	to := prd.StatusOpen
+	if requeue {
+		to = prd.StatusActive
+	}
+	if err := p.Transition(to, iteration); err != nil {
+		return "", err
+	}
+
+	tail := r.Tail
+	if lines := strings.Split(tail, "\n"); len(lines) > buildFailureTailLines {
+		tail = strings.Join(lines[len(lines)-buildFailureTailLines:], "\n")
+	}
+	p.AppendNote(fmt.Sprintf("[iteration %d] Build failed (%s): %s\n%s", iteration, r.Status(), r.Command, tail))
+
+	if !requeue {
+		p.ActivePlan = ""
+		if err := prd.DeletePlan(cwd, prdID); err != nil {
+			return "", err
+		}
+	}
+
+	if err := prd.Save(cwd, prdFile); err != nil {
+		return "", err
+	}
+
+	return to, nil
+}
+
+// registerSignalHooks wires the configured onSignal commands into the bus
+// Each command receives the signal as JSON on stdin. Failures are reported
+// but never stop the run.
+func registerSignalHooks(ctx context.Context, d *display.Display, cwd string, bus *events.Bus, hooks []config.SignalHookConfig) {
+	for _, h := range hooks {
+		run := func(msg events.SignalMessage) {
+			runSignalHook(ctx, d, cwd, h, msg)
+		}
+
+		if len(h.Signals) == 0 {
+			bus.On("", run)
+			continue
+		}
+		for _, signalType := range h.Signals {
+			bus.On(signalType, run)
+		}
+	}
+}
+
+// runSignalHook pipes one signal to a hook command
+func runSignalHook(ctx context.Context, d *display.Display, cwd string, h config.SignalHookConfig, msg events.SignalMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		d.Warning(fmt.Sprintf("Signal hook: failed to encode %s: %v", msg.Type, err))
+		return
+	}
+
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = config.DefaultSignalHookTimeout
+	}
+
+	r, err := exec.Run(ctx, "signal", h.Command, exec.Options{
+		Dir:     cwd,
+		Timeout: time.Duration(timeout) * time.Second,
+		Stdin:   bytes.NewReader(data),
+	})
+	if err != nil {
+		d.Warning(fmt.Sprintf("Signal hook for %s could not start: %v", msg.Type, err))
+		return
+	}
+	if !r.Passed() {
+		d.Warning(fmt.Sprintf("Signal hook for %s %s (exit %d): %s", msg.Type, strings.ToLower(r.Status()), r.ExitCode, h.Command))
+	}
+}
+
+// publishSignals hands a phase's signals to the bus
+func publishSignals(bus *events.Bus, runID string, iteration int, phase string, signals []llm.Signal) {
+	for _, s := range signals {
+		bus.Publish(events.SignalMessage{
+			RunID:     runID,
+			Iteration: iteration,
+			Phase:     phase,
+			Type:      s.Type,
+			PRDID:     s.PRDID,
+			Details:   s.Details,
+			Payload:   s.Payload,
+		})
+	}
+}
+
+// How a mil run ended, passed to the onComplete hook as MIL_EXIT_REASON
+const (
+	exitIterations  = "iterations"  // Every requested iteration ran
+	exitNoWork      = "no-work"     // Nothing left to plan, build or review
+	exitIdle        = "idle"        // Early exit after consecutive idle iterations
+	exitDeclined    = "declined"    // The user declined the builder's first change
+	exitPreview     = "preview"     // A --builder-readonly preview finished
+	exitAborted     = "aborted"     // The user aborted at a --step pause
+	exitInterrupted = "interrupted" // Ctrl-C or SIGTERM stopped the run
+	exitError       = "error"       // The run stopped on an error
+)
+
+// runOutcome records how a mil run ended for the onComplete hook
+type runOutcome struct {
+	reason        string
+	runID         string
+	iterations    int // Iterations started
+	startComplete int // Complete PRDs before the run
+}
+
+// env renders the outcome as MIL_* environment variables, with PRD counts
+// taken from prdFile; runErr is included when the run failed
+func (o runOutcome) env(prdFile *prd.PRDFileData, runErr error) []string {
+	reason := o.reason
+	if runErr != nil && reason != exitInterrupted {
+		reason = exitError
+	}
+	groups := prdFile.Group()
+	complete := len(groups.Complete)
+	env := []string{
+		"MIL_EXIT_REASON=" + reason,
+		"MIL_RUN_ID=" + o.runID,
+		fmt.Sprintf("MIL_ITERATIONS=%d", o.iterations),
+		fmt.Sprintf("MIL_PRDS_COMPLETED=%d", max(complete-o.startComplete, 0)),
+		fmt.Sprintf("MIL_PRDS_COMPLETE=%d", complete),
+		fmt.Sprintf("MIL_PRDS_OPEN=%d", len(groups.Open)),
+		fmt.Sprintf("MIL_PRDS_ACTIVE=%d", len(groups.Active)),
+		fmt.Sprintf("MIL_PRDS_PENDING=%d", len(groups.Pending)),
+		fmt.Sprintf("MIL_PRDS_BLOCKED=%d", len(groups.Blocked)),
+	}
+	if runErr != nil {
+		env = append(env, "MIL_ERROR="+runErr.Error())
+	}
+	return env
+}
+
+// runCompletionHook runs hooks.onComplete once mil run ends
+// A failed run skips it unless onCompleteAlways is set. A failing command is
+// returned as an error so the exit status of mil run reflects it.
+func runCompletionHook(ctx context.Context, d *display.Display, cwd string, cfg *config.Config, outcome runOutcome, runErr error) error {
+	if cfg.Hooks.OnComplete == "" {
+		return nil
+	}
+	if runErr != nil && !cfg.Hooks.OnCompleteAlways {
+		d.Info("Skipping on-complete command: the run failed (set onCompleteAlways or pass --on-complete-always to run it anyway)")
+		return nil
+	}
+
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		d.Warning(fmt.Sprintf("On-complete command gets no PRD counts: %v", err))
+		prdFile = &prd.PRDFileData{}
+	}
+
+	r, err := runHook(ctx, d, cwd, "on-complete", cfg.Hooks.OnComplete, cfg.Hooks.OnCompleteTimeout, outcome.env(prdFile, runErr))
+	if err != nil {
+		return err
+	}
+	if !r.Passed() {
+		return fmt.Errorf("on-complete command %s (exit %d)", strings.ToLower(r.Status()), r.ExitCode)
+	}
+	return nil
+}
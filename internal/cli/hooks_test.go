@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/exec"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestRunCompletionHook(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := prd.Save(dir, &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "a", Passes: prd.PassesStatus{Value: true}},
+		{ID: "b", Passes: prd.PassesStatus{Value: true}},
+		{ID: "c", Passes: prd.PassesStatus{Value: false}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+	cfg := config.DefaultConfig()
+	cfg.Hooks.OnComplete = `echo "$MIL_EXIT_REASON $MIL_ITERATIONS $MIL_PRDS_COMPLETED/$MIL_PRDS_COMPLETE open=$MIL_PRDS_OPEN"`
+	outcome := runOutcome{reason: exitNoWork, iterations: 3, startComplete: 1}
+
+	if err := runCompletionHook(context.Background(), d, dir, cfg, outcome, nil); err != nil {
+		t.Fatalf("runCompletionHook() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "no-work 3 1/2 open=1") {
+		t.Errorf("hook did not see the outcome:\n%s", out.String())
+	}
+
+	// A failed run skips the hook unless onCompleteAlways is set
+	out.Reset()
+	cfg.Hooks.OnComplete = `echo "reason=$MIL_EXIT_REASON error=$MIL_ERROR"`
+	if err := runCompletionHook(context.Background(), d, dir, cfg, outcome, errors.New("boom")); err != nil {
+		t.Fatalf("runCompletionHook() error = %v", err)
+	}
+	if strings.Contains(out.String(), "reason=") {
+		t.Errorf("hook ran after a failed run:\n%s", out.String())
+	}
+	cfg.Hooks.OnCompleteAlways = true
+	if err := runCompletionHook(context.Background(), d, dir, cfg, outcome, errors.New("boom")); err != nil {
+		t.Fatalf("runCompletionHook() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "reason=error error=boom") {
+		t.Errorf("hook did not see the failure:\n%s", out.String())
+	}
+
+	// A failing command surfaces its exit code
+	cfg.Hooks.OnComplete = "exit 4"
+	err := runCompletionHook(context.Background(), d, dir, cfg, outcome, nil)
+	if err == nil || !strings.Contains(err.Error(), "exit 4") {
+		t.Errorf("runCompletionHook() error = %v, want one with exit 4", err)
+	}
+}
+
+func TestRunCompletionHookInterrupted(t *testing.T) {
+	ctx, stop := interruptibleContext()
+	defer stop()
+	self, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := self.Signal(os.Interrupt); err != nil {
+		t.Skipf("cannot interrupt the test process: %v", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGINT did not cancel the run context")
+	}
+
+	// The hook still runs, and sees why the run ended
+	dir := t.TempDir()
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+	cfg := config.DefaultConfig()
+	cfg.Hooks.OnComplete = `echo "reason=$MIL_EXIT_REASON error=$MIL_ERROR"`
+	cfg.Hooks.OnCompleteAlways = true
+	outcome := runOutcome{reason: exitInterrupted}
+	if err := runCompletionHook(context.WithoutCancel(ctx), d, dir, cfg, outcome, errRunInterrupted); err != nil {
+		t.Fatalf("runCompletionHook() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "reason=interrupted error=run interrupted") {
+		t.Errorf("hook did not see the interruption:\n%s", out.String())
+	}
+}
+
+func TestRecordBuildFailure(t *testing.T) {
+	for _, requeue := range []bool{false, true} {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := prd.Save(dir, &prd.PRDFileData{PRDs: []prd.PRD{
+			{ID: "feat", ActivePlan: "feat-plan.md", Passes: prd.PassesStatus{Value: "pending"}},
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := prd.EnsurePlansDir(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(prd.GetPlanPath(dir, "feat"), []byte("plan"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		r := &exec.Result{Name: "test", Command: "go test ./...", ExitCode: 1, Tail: "FAIL"}
+		to, err := recordBuildFailure(dir, "feat", r, requeue, 2)
+		if err != nil {
+			t.Fatalf("recordBuildFailure(requeue=%v) error = %v", requeue, err)
+		}
+		prdFile, err := prd.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := prdFile.FindByID("feat")
+		if !strings.Contains(p.Notes, "[iteration 2] Build failed") {
+			t.Errorf("requeue=%v: notes = %q, want the failure", requeue, p.Notes)
+		}
+
+		// A requeued PRD keeps its plan for another builder pass; otherwise
+		// the planner starts over
+		_, statErr := os.Stat(prd.GetPlanPath(dir, "feat"))
+		if requeue {
+			if to != prd.StatusActive || p.ActivePlan == "" || statErr != nil {
+				t.Errorf("requeued: moved to %s with plan %q (%v), want active with its plan", to, p.ActivePlan, statErr)
+			}
+		} else if to != prd.StatusOpen || p.ActivePlan != "" || !os.IsNotExist(statErr) {
+			t.Errorf("not requeued: moved to %s with plan %q (%v), want open without a plan", to, p.ActivePlan, statErr)
+		}
+	}
+}
+
+func TestShouldGateBuild(t *testing.T) {
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "built", Passes: prd.PassesStatus{Value: "pending"}},
+		{ID: "bailed", Passes: prd.PassesStatus{Value: "active"}},
+		{ID: "stuck", Passes: prd.PassesStatus{Value: "blocked"}},
+	}}
+	interrupted, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		id   string
+		want bool
+	}{
+		{name: "pending", ctx: context.Background(), id: "built", want: true},
+		{name: "still active after a bailout", ctx: context.Background(), id: "bailed"},
+		{name: "blocked", ctx: context.Background(), id: "stuck"},
+		{name: "interrupted", ctx: interrupted, id: "built"},
+		{name: "no PRD", ctx: context.Background(), id: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldGateBuild(tt.ctx, prdFile, tt.id); got != tt.want {
+				t.Errorf("shouldGateBuild(%s) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordBuildFailureLeavesOtherStates(t *testing.T) {
+	for _, status := range []string{prd.StatusActive, prd.StatusBlocked} {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := prd.Save(dir, &prd.PRDFileData{PRDs: []prd.PRD{
+			{ID: "feat", ActivePlan: "feat-plan.md", Passes: prd.PassesStatus{Value: status}},
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := prd.EnsurePlansDir(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(prd.GetPlanPath(dir, "feat"), []byte("plan"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		r := &exec.Result{Name: "build", Command: "make", ExitCode: 2}
+		if _, err := recordBuildFailure(dir, "feat", r, false, 2); err == nil {
+			t.Errorf("%s: recordBuildFailure() should refuse a PRD that is not pending", status)
+		}
+		prdFile, err := prd.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := prdFile.FindByID("feat"); p.Passes.String() != status || p.ActivePlan == "" {
+			t.Errorf("%s: PRD moved to %s with plan %q", status, p.Passes.String(), p.ActivePlan)
+		}
+		if _, err := os.Stat(prd.GetPlanPath(dir, "feat")); err != nil {
+			t.Errorf("%s: plan removed: %v", status, err)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package cli
 
 import (
+	"slices"
+	"sort"
+
+	"github.com/daydemir/milhouse/internal/display"
 	"github.com/daydemir/milhouse/internal/llm"
 	"github.com/daydemir/milhouse/internal/prd"
 )
@@ -11,7 +15,28 @@ type IterationState struct {
 	ActiveCount   int
 	PendingCount  int
 	CompleteCount int
-	SignalTypes   []string // e.g., ["VERIFIED", "PLAN_UPDATED"]
+	BlockedCount  int
+	SignalTypes   []string          // e.g., ["VERIFIED", "PLAN_UPDATED"]
+	Statuses      map[string]string // PRD ID -> status
+	Plans         []string          // PRD IDs with a plan file, sorted
+}
+
+// Kinds of Change between two iteration states
+const (
+	ChangeStatus      = "status"       // The PRD moved from one status to another
+	ChangeAdded       = "added"        // The PRD appeared in prd.json
+	ChangeRemoved     = "removed"      // The PRD left prd.json
+	ChangePlanCreated = "plan-created" // A plan file appeared for the PRD
+	ChangePlanDeleted = "plan-deleted" // The PRD's plan file was removed
+)
+
+// Change is one difference between two iteration states
+// From and To are statuses; From is empty for added PRDs, To for removed ones.
+type Change struct {
+	PRDID string
+	Kind  string
+	From  string
+	To    string
 }
 
 // Equals compares two iteration states for equality
@@ -23,7 +48,8 @@ func (s *IterationState) Equals(other *IterationState) bool {
 	if s.OpenCount != other.OpenCount ||
 		s.ActiveCount != other.ActiveCount ||
 		s.PendingCount != other.PendingCount ||
-		s.CompleteCount != other.CompleteCount {
+		s.CompleteCount != other.CompleteCount ||
+		s.BlockedCount != other.BlockedCount {
 		return false
 	}
 
@@ -47,40 +73,14 @@ func (s *IterationState) Equals(other *IterationState) bool {
 	return true
 }
 
-// IsIdle returns true if the iteration produced no productive signals
+// IsIdle returns true if the iteration produced only signals known to do no
+// work
 func (s *IterationState) IsIdle() bool {
-	// Productive signals that indicate actual work being done
-	productiveSignals := map[string]bool{
-		llm.SignalVerified:     true,
-		llm.SignalRejected:     true,
-		llm.SignalPlanComplete: true,
-		llm.SignalPlanUpdated:  true,
-		llm.SignalPRDComplete:  true,
-	}
-
 	for _, sig := range s.SignalTypes {
-		if productiveSignals[sig] {
-			return false
-		}
-	}
-
-	return len(s.SignalTypes) == 0 || allNonProductive(s.SignalTypes)
-}
-
-// allNonProductive checks if all signals are non-productive
-func allNonProductive(signals []string) bool {
-	nonProductiveSignals := map[string]bool{
-		llm.SignalLoopRisk:         true,
-		llm.SignalAnalysisComplete: true,
-		llm.SignalBlocked:          true,
-	}
-
-	for _, sig := range signals {
-		if !nonProductiveSignals[sig] {
+		if !llm.IsIdleSignal(sig) {
 			return false
 		}
 	}
-
 	return true
 }
 
@@ -91,14 +91,19 @@ func CaptureIterationState(prdFile *prd.PRDFileData, signals []llm.Signal) *Iter
 	}
 
 	// Count PRDs by state
+	state.Statuses = make(map[string]string, len(prdFile.PRDs))
 	for _, p := range prdFile.PRDs {
-		if p.Passes.IsTrue() {
+		state.Statuses[p.ID] = p.Passes.String()
+		switch p.Passes.String() {
+		case prd.StatusComplete:
 			state.CompleteCount++
-		} else if p.Passes.IsPending() {
+		case prd.StatusPending:
 			state.PendingCount++
-		} else if p.Passes.IsActive() {
+		case prd.StatusActive:
 			state.ActiveCount++
-		} else {
+		case prd.StatusBlocked:
+			state.BlockedCount++
+		default:
 			state.OpenCount++
 		}
 	}
@@ -114,3 +119,70 @@ func CaptureIterationState(prdFile *prd.PRDFileData, signals []llm.Signal) *Iter
 
 	return state
 }
+
+// captureRunState captures the PRD state and signals along with which PRDs
+// have plans; an unreadable plans directory counts as no plans
+func captureRunState(cwd string, prdFile *prd.PRDFileData, signals []llm.Signal) *IterationState {
+	state := CaptureIterationState(prdFile, signals)
+	state.Plans, _ = prd.ListPlans(cwd)
+	return state
+}
+
+// showIterationChanges prints the changes an iteration made
+func showIterationChanges(d *display.Display, changes []Change) {
+	d.SubHeader("Changes this iteration")
+	if len(changes) == 0 {
+		d.Info("No PRD changes")
+		return
+	}
+	for _, c := range changes {
+		switch c.Kind {
+		case ChangePlanCreated, ChangePlanDeleted:
+			d.PlanChange(c.PRDID, c.Kind == ChangePlanCreated)
+		default:
+			d.StatusChange(c.PRDID, c.From, c.To)
+		}
+	}
+}
+
+// Diff lists what changed since prev, ordered by PRD ID with status changes
+// before plan changes. A nil prev yields no changes.
+func (s *IterationState) Diff(prev *IterationState) []Change {
+	if s == nil || prev == nil {
+		return nil
+	}
+
+	var changes []Change
+	for id, to := range s.Statuses {
+		switch from, ok := prev.Statuses[id]; {
+		case !ok:
+			changes = append(changes, Change{PRDID: id, Kind: ChangeAdded, To: to})
+		case from != to:
+			changes = append(changes, Change{PRDID: id, Kind: ChangeStatus, From: from, To: to})
+		}
+	}
+	for id, from := range prev.Statuses {
+		if _, ok := s.Statuses[id]; !ok {
+			changes = append(changes, Change{PRDID: id, Kind: ChangeRemoved, From: from})
+		}
+	}
+	for _, id := range s.Plans {
+		if !slices.Contains(prev.Plans, id) {
+			changes = append(changes, Change{PRDID: id, Kind: ChangePlanCreated})
+		}
+	}
+	for _, id := range prev.Plans {
+		if !slices.Contains(s.Plans, id) {
+			changes = append(changes, Change{PRDID: id, Kind: ChangePlanDeleted})
+		}
+	}
+
+	isPlan := func(c Change) bool { return c.Kind == ChangePlanCreated || c.Kind == ChangePlanDeleted }
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].PRDID != changes[j].PRDID {
+			return changes[i].PRDID < changes[j].PRDID
+		}
+		return !isPlan(changes[i]) && isPlan(changes[j])
+	})
+	return changes
+}
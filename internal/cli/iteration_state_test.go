@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestIterationStateDiff(t *testing.T) {
+	state := func(plans []string, statuses ...string) *IterationState {
+		data := &prd.PRDFileData{}
+		for i := 0; i < len(statuses); i += 2 {
+			p := prd.PRD{ID: statuses[i]}
+			if statuses[i+1] == prd.StatusComplete {
+				p.Passes.SetTrue()
+			} else {
+				p.Passes.Value = statuses[i+1]
+			}
+			data.PRDs = append(data.PRDs, p)
+		}
+		s := CaptureIterationState(data, nil)
+		s.Plans = plans
+		return s
+	}
+
+	start := state([]string{"b", "d"}, "a", "open", "b", "active", "c", "complete", "d", "pending")
+	end := state([]string{"a", "d"}, "a", "active", "b", "pending", "d", "pending", "e", "open")
+
+	want := []Change{
+		{PRDID: "a", Kind: ChangeStatus, From: "open", To: "active"},
+		{PRDID: "a", Kind: ChangePlanCreated},
+		{PRDID: "b", Kind: ChangeStatus, From: "active", To: "pending"},
+		{PRDID: "b", Kind: ChangePlanDeleted},
+		{PRDID: "c", Kind: ChangeRemoved, From: "complete"},
+		{PRDID: "e", Kind: ChangeAdded, To: "open"},
+	}
+	if got := end.Diff(start); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := end.Diff(end); len(got) != 0 {
+		t.Errorf("Diff() of identical states = %+v, want none", got)
+	}
+	if got := end.Diff(nil); got != nil {
+		t.Errorf("Diff(nil) = %+v, want nil", got)
+	}
+
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+	showIterationChanges(d, want)
+	for _, line := range []string{"a: open → active", "a: plan created", "c: removed (was complete)", "e: added as open"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("change list missing %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestIterationStateIsIdle(t *testing.T) {
+	tests := []struct {
+		signals []string
+		want    bool
+	}{
+		{nil, true},
+		{[]string{llm.SignalAnalysisComplete, llm.SignalLoopRisk}, true},
+		{[]string{llm.SignalAnalysisComplete, llm.SignalVerified}, false},
+		{[]string{"DEPLOYED"}, false}, // Custom signals may have done anything
+	}
+	for _, tt := range tests {
+		s := &IterationState{SignalTypes: tt.signals}
+		if got := s.IsIdle(); got != tt.want {
+			t.Errorf("IsIdle(%v) = %v, want %v", tt.signals, got, tt.want)
+		}
+	}
+}
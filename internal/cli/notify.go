@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/daydemir/milhouse/internal/events"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/notify"
+)
+
+// Events mil run --notify can fire on
+const (
+	notifyComplete = "complete" // The run ended, successfully or not
+	notifyBailout  = "bailout"  // The builder bailed out of a PRD
+	notifyBlocked  = "blocked"  // A PRD was blocked
+	notifyInput    = "input"    // The run waits on a human (refinement, write confirmation)
+	notifyAll      = "all"
+)
+
+// notifyEvents lists every --notify event in display order
+var notifyEvents = []string{notifyComplete, notifyBailout, notifyBlocked, notifyInput}
+
+// notifySignals maps the signals that trigger a notification to their event
+var notifySignals = map[string]string{
+	llm.SignalBailout:     notifyBailout,
+	llm.SignalBlocked:     notifyBlocked,
+	llm.SignalNeedsRefine: notifyInput,
+}
+
+// runNotifier sends notifications for the events chosen with --notify
+type runNotifier struct {
+	events  map[string]bool
+	deliver func(title, message string)
+}
+
+// newRunNotifier parses the --notify events; it returns nil when the flag was
+// not given, and every method is a no-op on a nil runNotifier
+func newRunNotifier(selected []string) (*runNotifier, error) {
+	if len(selected) == 0 {
+		return nil, nil
+	}
+	chosen := make(map[string]bool)
+	for _, event := range selected {
+		event = strings.ToLower(strings.TrimSpace(event))
+		switch {
+		case event == notifyAll:
+			for _, e := range notifyEvents {
+				chosen[e] = true
+			}
+		case slices.Contains(notifyEvents, event):
+			chosen[event] = true
+		default:
+			return nil, fmt.Errorf("unknown --notify event %q (valid: %s, %s)", event, strings.Join(notifyEvents, ", "), notifyAll)
+		}
+	}
+	return &runNotifier{events: chosen, deliver: notify.New().Send}, nil
+}
+
+// send notifies about event if it was chosen
+func (n *runNotifier) send(event, message string) {
+	if n == nil || !n.events[event] {
+		return
+	}
+	n.deliver("Milhouse", message)
+}
+
+// subscribe registers the notifier on the bus for the signals it cares about
+func (n *runNotifier) subscribe(bus *events.Bus) {
+	if n == nil {
+		return
+	}
+	for signalType, event := range notifySignals {
+		if !n.events[event] {
+			continue
+		}
+		bus.On(signalType, func(msg events.SignalMessage) {
+			n.send(event, signalNotification(msg))
+		})
+	}
+}
+
+// wrapConfirm notifies before a confirm-before-write prompt waits for an answer
+func (n *runNotifier) wrapConfirm(confirm func(prdID, tool string) bool) func(prdID, tool string) bool {
+	if n == nil || confirm == nil {
+		return confirm
+	}
+	return func(prdID, tool string) bool {
+		n.send(notifyInput, fmt.Sprintf("Builder is waiting for approval to change %s", prdID))
+		return confirm(prdID, tool)
+	}
+}
+
+// runEnded notifies that the run finished, with how it ended
+func (n *runNotifier) runEnded(outcome runOutcome, runErr error) {
+	if runErr != nil {
+		n.send(notifyComplete, fmt.Sprintf("Run failed after %d iteration(s): %v", outcome.iterations, runErr))
+		return
+	}
+	n.send(notifyComplete, fmt.Sprintf("Run finished after %d iteration(s) (%s)", outcome.iterations, outcome.reason))
+}
+
+// signalNotification renders a signal as a one-line notification
+func signalNotification(msg events.SignalMessage) string {
+	text := msg.Type
+	if msg.PRDID != "" {
+		text += " on " + msg.PRDID
+	}
+	if msg.Details != "" {
+		text += ": " + msg.Details
+	}
+	return text
+}
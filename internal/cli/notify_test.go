@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/events"
+	"github.com/daydemir/milhouse/internal/llm"
+)
+
+func TestNewRunNotifier(t *testing.T) {
+	if n, err := newRunNotifier(nil); n != nil || err != nil {
+		t.Errorf("no --notify = %v, %v; want nil notifier", n, err)
+	}
+	n, err := newRunNotifier([]string{notifyAll})
+	if err != nil || len(n.events) != len(notifyEvents) {
+		t.Errorf("--notify=all = %v, %v; want every event", n, err)
+	}
+	if _, err := newRunNotifier([]string{"bailout", "done"}); err == nil || !strings.Contains(err.Error(), `"done"`) {
+		t.Errorf("unknown event error = %v", err)
+	}
+}
+
+func TestRunNotifierEvents(t *testing.T) {
+	n, err := newRunNotifier([]string{"bailout", "Input"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent []string
+	n.deliver = func(title, message string) { sent = append(sent, message) }
+
+	bus := events.NewBus()
+	n.subscribe(bus)
+	bus.Publish(events.SignalMessage{Type: llm.SignalBailout, PRDID: "auth-1", Details: "context full"})
+	bus.Publish(events.SignalMessage{Type: llm.SignalBlocked, PRDID: "auth-2"}) // Not chosen
+	bus.Publish(events.SignalMessage{Type: llm.SignalNeedsRefine, PRDID: "auth-3"})
+
+	confirm := n.wrapConfirm(func(prdID, tool string) bool { return true })
+	if !confirm("auth-4", "Edit") {
+		t.Error("wrapped confirm lost the answer")
+	}
+	n.runEnded(runOutcome{reason: exitNoWork, iterations: 2}, errors.New("boom")) // Not chosen
+
+	want := []string{
+		"BAILOUT on auth-1: context full",
+		"NEEDS_REFINEMENT on auth-3",
+		"Builder is waiting for approval to change auth-4",
+	}
+	if strings.Join(sent, "\n") != strings.Join(want, "\n") {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+
+	// A nil notifier (no --notify) does nothing
+	var none *runNotifier
+	none.subscribe(bus)
+	none.runEnded(runOutcome{}, nil)
+	if none.wrapConfirm(nil) != nil {
+		t.Error("nil notifier should leave a nil confirmer alone")
+	}
+}
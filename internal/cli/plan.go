@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Manage plan files",
+	Long:  `Commands for working with plan files in .milhouse/plans/.`,
+}
+
+var planPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete plans whose PRD is gone or no longer active",
+	Long: `List plan files whose PRD no longer exists in prd.json or is no longer
+active or pending, and delete them.
+
+Stale plans are left behind when a PRD is reset to open or removed by hand,
+and can mislead the reviewer. Asks for confirmation; pass --yes to skip it.`,
+	Args: cobra.NoArgs,
+	RunE: runPlanPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.AddCommand(planPruneCmd)
+}
+
+func runPlanPrune(cmd *cobra.Command, args []string) error {
+	cwd, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	stale, err := prdFile.StalePlans(cwd)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		display.Success("No stale plans")
+		return nil
+	}
+
+	display.Info(fmt.Sprintf("%d stale plan(s):", len(stale)))
+	for _, id := range stale {
+		fmt.Printf("  %s\n", prd.GetPlanPath(cwd, id))
+	}
+
+	if !confirm(fmt.Sprintf("Delete %d stale plan(s)?", len(stale))) {
+		display.Info("Nothing deleted")
+		return nil
+	}
+
+	for _, id := range stale {
+		if err := prd.DeletePlan(cwd, id); err != nil {
+			return err
+		}
+	}
+	display.Success(fmt.Sprintf("Deleted %d stale plan(s)", len(stale)))
+	return nil
+}
@@ -0,0 +1,503 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+var prdCmd = &cobra.Command{
+	Use:   "prd",
+	Short: "Inspect and manage individual PRDs",
+	Long:  `Commands for working with a single PRD in .milhouse/prd.json.`,
+}
+
+var prdHistoryCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show a PRD's status transition timeline",
+	Long: `Show every recorded status change for a PRD with timestamps and iterations.
+
+Frequent bounces between pending and open are a strong signal that the
+agents are struggling with the PRD.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDHistory,
+}
+
+var prdShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a single PRD in detail",
+	Long: `Show a PRD's status, priority, description, acceptance criteria, notes,
+history, and whether a plan and evidence exist.
+
+Use --json to print the raw PRD as stored in prd.json.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDShow,
+}
+
+var prdRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove a PRD and its plan",
+	Long: `Remove a PRD from prd.json and delete its plan file.
+
+Asks for confirmation; pass --yes to skip the prompt in scripts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDRm,
+}
+
+var prdReorderCmd = &cobra.Command{
+	Use:   "reorder",
+	Short: "Interactively reorder open PRDs by priority",
+	Long: `Open a TUI listing open PRDs in priority order. Move PRDs up and down,
+then save to rewrite their priorities as 1, 2, 3, ...`,
+	Args: cobra.NoArgs,
+	RunE: runPRDReorder,
+}
+
+var prdNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create a PRD with an interactive form",
+	Long: `Open a form that walks through the description, priority, acceptance
+criteria and owner of a new PRD, then adds it to prd.json as open.
+
+No agent is involved, so no tokens are spent. The ID is generated from the
+description (e.g. add-caching-layer-a1b2). Press ESC to leave without saving.`,
+	Args: cobra.NoArgs,
+	RunE: runPRDNew,
+}
+
+var prdValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Report every integrity problem in prd.json",
+	Long: `Run all PRD integrity checks and report every problem at once, grouped
+by severity:
+
+  errors:   missing or malformed IDs, duplicate IDs, empty descriptions,
+            unknown or cyclic dependencies
+  warnings: missing acceptance criteria, orphaned plan files,
+            pending PRDs without an evidence file
+
+Exits non-zero if any errors are found, so it can gate CI.`,
+	Args: cobra.NoArgs,
+	RunE: runPRDValidate,
+}
+
+var prdGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Print the PRD dependency graph as DOT or Mermaid",
+	Long: `Print the dependency graph of all PRDs (from their dependsOn lists) as
+Graphviz DOT or Mermaid source. Nodes are colored by status and dependency
+cycles are drawn in red. Pipe the output into a renderer, e.g.:
+
+  mil prd graph | dot -Tsvg > prds.svg`,
+	Args: cobra.NoArgs,
+	RunE: runPRDGraph,
+}
+
+var prdSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find PRDs by keyword",
+	Long: `List PRDs whose ID, description, acceptance criteria or notes contain the
+query, ignoring case.
+
+Use --fuzzy to also match words a typo or two away, --status to only search
+PRDs with one status, and --json for machine-readable output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDSearch,
+}
+
+var prdLinkCmd = &cobra.Command{
+	Use:   "link <id> <url>",
+	Short: "Link a PRD to an external ticket or issue",
+	Long: `Record a link from a PRD to a ticket in an external tracker, such as a
+Jira issue or a GitHub issue. Links are stored in the PRD's metadata and
+shown by 'mil prd show' and 'mil status -v'.
+
+The type is guessed from the host (jira, github, gitlab, linear, shortcut,
+or link); set it with --type.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPRDLink,
+}
+
+var prdUnlinkCmd = &cobra.Command{
+	Use:   "unlink <id> <url|type>",
+	Short: "Remove a PRD's external links",
+	Long: `Remove the link with the given URL from a PRD, or every link of the
+given type (e.g. jira).`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPRDUnlink,
+}
+
+var prdRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore prd.json from its backup",
+	Long: `Replace prd.json with prd.json.bak, the last copy that parsed. Every save
+keeps this backup, so a truncated or hand-broken prd.json loses at most the
+last change.
+
+Restoring over a prd.json that still parses requires --force.`,
+	Args: cobra.NoArgs,
+	RunE: runPRDRestore,
+}
+
+var (
+	prdLinkTypeFlag     string
+	prdShowJSONFlag     bool
+	prdGraphFormatFlag  string
+	prdSearchStatusFlag string
+	prdSearchFuzzyFlag  bool
+	prdSearchJSONFlag   bool
+	prdRestoreForceFlag bool
+)
+
+func init() {
+	rootCmd.AddCommand(prdCmd)
+	prdCmd.AddCommand(prdHistoryCmd)
+	prdCmd.AddCommand(prdShowCmd)
+	prdCmd.AddCommand(prdRmCmd)
+	prdCmd.AddCommand(prdReorderCmd)
+	prdCmd.AddCommand(prdValidateCmd)
+	prdCmd.AddCommand(prdNewCmd)
+	prdCmd.AddCommand(prdGraphCmd)
+	prdCmd.AddCommand(prdSearchCmd)
+	prdCmd.AddCommand(prdLinkCmd)
+	prdCmd.AddCommand(prdUnlinkCmd)
+	prdCmd.AddCommand(prdRestoreCmd)
+
+	prdLinkCmd.Flags().StringVar(&prdLinkTypeFlag, "type", "", "Link type, e.g. jira or github (default: guessed from the URL)")
+	prdShowCmd.Flags().BoolVar(&prdShowJSONFlag, "json", false, "Print the raw PRD as JSON")
+	prdGraphCmd.Flags().StringVar(&prdGraphFormatFlag, "format", prd.GraphDOT, "Output format: dot or mermaid")
+	prdSearchCmd.Flags().StringVar(&prdSearchStatusFlag, "status", "", "Only search PRDs with this status (open, active, pending, complete, blocked)")
+	prdSearchCmd.Flags().BoolVar(&prdSearchFuzzyFlag, "fuzzy", false, "Also match words with small typos")
+	prdSearchCmd.Flags().BoolVar(&prdSearchJSONFlag, "json", false, "Print matches as JSON")
+	prdRestoreCmd.Flags().BoolVar(&prdRestoreForceFlag, "force", false, "Restore even if prd.json is valid")
+}
+
+// loadPRDFile loads prd.json from the current directory, reporting a missing .milhouse/
+func loadPRDFile() (string, *prd.PRDFileData, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if !prd.MillhouseExists(cwd) {
+		display.Error(".milhouse/ directory not found")
+		display.Info("Run 'mil init' to initialize")
+		return "", nil, fmt.Errorf("not initialized")
+	}
+
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	return cwd, prdFile, nil
+}
+
+// findPRD looks up a PRD by ID, reporting an unknown ID
+func findPRD(prdFile *prd.PRDFileData, id string) (*prd.PRD, error) {
+	p := prdFile.FindByID(id)
+	if p == nil {
+		display.Error(fmt.Sprintf("PRD not found: %s", id))
+		return nil, fmt.Errorf("unknown PRD: %s", id)
+	}
+	return p, nil
+}
+
+func runPRDShow(cmd *cobra.Command, args []string) error {
+	cwd, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	p, err := findPRD(prdFile, args[0])
+	if err != nil {
+		return err
+	}
+
+	if prdShowJSONFlag {
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode PRD: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	_, err = os.Stat(prd.GetEvidencePath(cwd, p.ID))
+	evidenceExists := err == nil
+
+	display.Header(fmt.Sprintf("PRD: %s", p.ID))
+	display.PRDDetail(*p, prd.PlanExists(cwd, p.ID), evidenceExists)
+
+	return nil
+}
+
+func runPRDHistory(cmd *cobra.Command, args []string) error {
+	_, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	p, err := findPRD(prdFile, args[0])
+	if err != nil {
+		return err
+	}
+
+	display.Header(fmt.Sprintf("History: %s", p.ID))
+	display.PRDHistory(*p)
+
+	return nil
+}
+
+func runPRDRm(cmd *cobra.Command, args []string) error {
+	cwd, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	p, err := findPRD(prdFile, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !confirm(fmt.Sprintf("Remove PRD %s (%s)?", p.ID, display.Truncate(p.Description, 50))) {
+		display.Info("Nothing removed")
+		return nil
+	}
+
+	id := p.ID
+	kept := prdFile.PRDs[:0]
+	for _, existing := range prdFile.PRDs {
+		if existing.ID != id {
+			kept = append(kept, existing)
+		}
+	}
+	prdFile.PRDs = kept
+
+	if err := prd.Save(cwd, prdFile); err != nil {
+		return fmt.Errorf("failed to save PRDs: %w", err)
+	}
+	if err := prd.DeletePlan(cwd, id); err != nil {
+		display.Warning(fmt.Sprintf("Failed to delete plan: %v", err))
+	}
+
+	display.Success(fmt.Sprintf("Removed PRD %s", id))
+	return nil
+}
+
+func runPRDLink(cmd *cobra.Command, args []string) error {
+	url := args[1]
+	if err := prd.ValidateLinkURL(url); err != nil {
+		return err
+	}
+
+	cwd, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	p, err := findPRD(prdFile, args[0])
+	if err != nil {
+		return err
+	}
+
+	linkType := strings.ToLower(strings.TrimSpace(prdLinkTypeFlag))
+	if linkType == "" {
+		linkType = prd.LinkType(url)
+	}
+	if !p.AddLink(prd.Link{Type: linkType, URL: url}) {
+		display.Info(fmt.Sprintf("%s is already linked to %s", p.ID, url))
+		return nil
+	}
+
+	if err := prd.Save(cwd, prdFile); err != nil {
+		return fmt.Errorf("failed to save PRDs: %w", err)
+	}
+	display.Success(fmt.Sprintf("Linked %s to %s (%s)", p.ID, url, linkType))
+	return nil
+}
+
+func runPRDUnlink(cmd *cobra.Command, args []string) error {
+	cwd, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	p, err := findPRD(prdFile, args[0])
+	if err != nil {
+		return err
+	}
+
+	removed := p.RemoveLinks(args[1])
+	if removed == 0 {
+		display.Error(fmt.Sprintf("%s has no link matching %s", p.ID, args[1]))
+		return fmt.Errorf("no matching link")
+	}
+
+	if err := prd.Save(cwd, prdFile); err != nil {
+		return fmt.Errorf("failed to save PRDs: %w", err)
+	}
+	display.Success(fmt.Sprintf("Removed %d link(s) from %s", removed, p.ID))
+	return nil
+}
+
+func runPRDRestore(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if !prd.MillhouseExists(cwd) {
+		display.Error(".milhouse/ directory not found")
+		display.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	if !prd.BackupExists(cwd) {
+		display.Error(fmt.Sprintf("No backup found (%s)", prd.BackupFile))
+		return fmt.Errorf("no backup")
+	}
+
+	if prd.Intact(cwd) && !prdRestoreForceFlag {
+		display.Info(fmt.Sprintf("%s is valid; use --force to replace it with the backup", prd.PRDFile))
+		return nil
+	}
+
+	restored, err := prd.Restore(cwd)
+	if err != nil {
+		return err
+	}
+	display.Success(fmt.Sprintf("Restored %s from %s (%d PRDs)", prd.PRDFile, prd.BackupFile, len(restored.PRDs)))
+	return nil
+}
+
+func runPRDReorder(cmd *cobra.Command, args []string) error {
+	cwd, _, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	if err := prd.RunReorder(cwd); err != nil {
+		return fmt.Errorf("reorder failed: %w", err)
+	}
+
+	return nil
+}
+
+func runPRDNew(cmd *cobra.Command, args []string) error {
+	cwd, _, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	created, err := prd.RunWizard(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create PRD: %w", err)
+	}
+	if created == nil {
+		display.Info("Cancelled; no PRD created")
+		return nil
+	}
+
+	display.Success(fmt.Sprintf("Created %s (priority %d, %d criteria)", created.ID, created.Priority, len(created.AcceptanceCriteria)))
+	return nil
+}
+
+func runPRDValidate(cmd *cobra.Command, args []string) error {
+	cwd, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	issues := prd.Lint(prdFile, cwd)
+	if len(issues) == 0 {
+		display.Success(fmt.Sprintf("No problems found in %d PRD(s)", len(prdFile.PRDs)))
+		return nil
+	}
+
+	var errs, warnings []prd.LintIssue
+	for _, issue := range issues {
+		if issue.Severity == prd.SeverityError {
+			errs = append(errs, issue)
+		} else {
+			warnings = append(warnings, issue)
+		}
+	}
+
+	if len(errs) > 0 {
+		display.Header(fmt.Sprintf("Errors (%d)", len(errs)))
+		for _, issue := range errs {
+			display.Error(issue.String())
+		}
+	}
+	if len(warnings) > 0 {
+		display.Header(fmt.Sprintf("Warnings (%d)", len(warnings)))
+		for _, issue := range warnings {
+			display.Warning(issue.String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d error(s) found", len(errs))
+	}
+	return nil
+}
+
+func runPRDGraph(cmd *cobra.Command, args []string) error {
+	_, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	graph, err := prd.Graph(prdFile, prdGraphFormatFlag)
+	if err != nil {
+		return err
+	}
+	fmt.Print(graph)
+
+	for _, cycle := range prd.DependencyCycles(prdFile) {
+		display.Warning(fmt.Sprintf("Dependency cycle: %s", strings.Join(cycle, ", ")))
+	}
+	return nil
+}
+
+func runPRDSearch(cmd *cobra.Command, args []string) error {
+	_, prdFile, err := loadPRDFile()
+	if err != nil {
+		return err
+	}
+
+	results, err := prd.Search(prdFile, args[0], prd.SearchOptions{Status: prdSearchStatusFlag, Fuzzy: prdSearchFuzzyFlag})
+	if err != nil {
+		display.Error(err.Error())
+		return err
+	}
+
+	if prdSearchJSONFlag {
+		if results == nil {
+			results = []prd.SearchResult{} // Print [] rather than null
+		}
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		display.Info(fmt.Sprintf("No PRDs match %q", args[0]))
+		return nil
+	}
+	display.Header(fmt.Sprintf("Matches for %q (%d)", args[0], len(results)))
+	for _, r := range results {
+		display.PRDStatus(r.PRD)
+	}
+	return nil
+}
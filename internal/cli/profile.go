@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+const (
+	cpuProfileFile  = "cpu.pprof"
+	heapProfileFile = "heap.pprof"
+)
+
+// startProfiling begins a CPU profile of the Millhouse process in dir
+// The returned stop function ends the CPU profile and writes a heap profile.
+func startProfiling(dir string) (func() error, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	cpuFile, err := os.Create(filepath.Join(dir, cpuProfileFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		cpuFile.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+
+	stop := func() error {
+		pprof.StopCPUProfile()
+		if err := cpuFile.Close(); err != nil {
+			return fmt.Errorf("failed to write CPU profile: %w", err)
+		}
+
+		heapFile, err := os.Create(filepath.Join(dir, heapProfileFile))
+		if err != nil {
+			return fmt.Errorf("failed to create heap profile: %w", err)
+		}
+		defer heapFile.Close()
+
+		runtime.GC() // Up-to-date allocation statistics
+		if err := pprof.WriteHeapProfile(heapFile); err != nil {
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		return nil
+	}
+
+	return stop, nil
+}
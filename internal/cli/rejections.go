@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// rejectionTracker counts reviewer rejections per PRD over one mil run
+type rejectionTracker struct {
+	max     int                 // Rejections before a PRD is blocked; 0 disables
+	reasons map[string][]string // Rejection reasons by PRD ID, oldest first
+}
+
+func newRejectionTracker(max int) *rejectionTracker {
+	return &rejectionTracker{max: max, reasons: make(map[string][]string)}
+}
+
+// record counts the REJECTED signals from one review and returns the PRDs
+// that just reached the cap
+func (t *rejectionTracker) record(signals []llm.Signal) []string {
+	if t.max <= 0 {
+		return nil
+	}
+	var capped []string
+	for _, s := range signals {
+		if s.Type != llm.SignalRejected || s.PRDID == "" {
+			continue
+		}
+		reason := s.Details
+		if reason == "" {
+			reason = "no reason given"
+		}
+		t.reasons[s.PRDID] = append(t.reasons[s.PRDID], reason)
+		if len(t.reasons[s.PRDID]) == t.max {
+			capped = append(capped, s.PRDID)
+		}
+	}
+	return capped
+}
+
+// block moves capped PRDs to blocked with a note listing why each rejection
+// happened, returning the IDs it changed
+func (t *rejectionTracker) block(prdFile *prd.PRDFileData, ids []string, iteration int) []string {
+	var blocked []string
+	for _, id := range ids {
+		p := prdFile.FindByID(id)
+		if p == nil || p.Passes.IsTrue() || p.Passes.IsBlocked() {
+			continue
+		}
+		if err := p.Transition(prd.StatusBlocked, iteration); err != nil {
+			continue
+		}
+		reasons := t.reasons[id]
+		p.AppendNote(fmt.Sprintf("[max rejections] rejected %d times this run: %s", len(reasons), strings.Join(reasons, "; ")))
+		blocked = append(blocked, id)
+	}
+	return blocked
+}
+
+// capRejections blocks PRDs the reviewer has rejected --max-rejections times
+// this run so the loop stops retrying them
+func capRejections(d *display.Display, cwd string, prdFile *prd.PRDFileData, tracker *rejectionTracker, signals []llm.Signal, iteration int) {
+	blocked := tracker.block(prdFile, tracker.record(signals), iteration)
+	if len(blocked) == 0 {
+		return
+	}
+	if err := prd.Save(cwd, prdFile); err != nil {
+		d.Warning(fmt.Sprintf("Failed to block rejected PRDs: %v", err))
+		return
+	}
+	for _, id := range blocked {
+		d.Warning(fmt.Sprintf("PRD %s rejected %d times this run - blocked for human review", id, tracker.max))
+	}
+}
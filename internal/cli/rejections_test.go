@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestRejectionTracker(t *testing.T) {
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "flaky", Passes: prd.PassesStatus{Value: false}},
+		{ID: "fine", Passes: prd.PassesStatus{Value: false}},
+	}}
+	tracker := newRejectionTracker(3)
+
+	// Simulate the reviewer rejecting flaky every iteration and fine once
+	reviews := [][]llm.Signal{
+		{{Type: llm.SignalRejected, PRDID: "flaky", Details: "tests fail"}, {Type: llm.SignalRejected, PRDID: "fine"}},
+		{{Type: llm.SignalRejected, PRDID: "flaky", Details: "lint errors"}, {Type: llm.SignalVerified, PRDID: "fine"}},
+		{{Type: llm.SignalRejected, PRDID: "flaky", Details: "tests fail"}},
+	}
+	var blocked []string
+	for i, signals := range reviews {
+		blocked = append(blocked, tracker.block(prdFile, tracker.record(signals), i+1)...)
+	}
+
+	if len(blocked) != 1 || blocked[0] != "flaky" {
+		t.Fatalf("blocked = %v, want [flaky]", blocked)
+	}
+	flaky := prdFile.FindByID("flaky")
+	if !flaky.Passes.IsBlocked() {
+		t.Errorf("flaky status = %s, want blocked", flaky.Passes)
+	}
+	if want := "[max rejections] rejected 3 times this run: tests fail; lint errors; tests fail"; flaky.Notes != want {
+		t.Errorf("notes = %q, want %q", flaky.Notes, want)
+	}
+	if len(flaky.History) != 1 || flaky.History[0].Iteration != 3 {
+		t.Errorf("history = %+v", flaky.History)
+	}
+	if fine := prdFile.FindByID("fine"); fine.Passes.IsBlocked() || strings.Contains(fine.Notes, "max rejections") {
+		t.Errorf("fine should not be blocked: %+v", fine)
+	}
+
+	// Further rejections past the cap do not block again
+	if more := tracker.record(reviews[0]); len(more) != 0 {
+		t.Errorf("record past the cap = %v, want none", more)
+	}
+}
+
+func TestRejectionTrackerDisabled(t *testing.T) {
+	tracker := newRejectionTracker(0)
+	for i := 0; i < 5; i++ {
+		if capped := tracker.record([]llm.Signal{{Type: llm.SignalRejected, PRDID: "a"}}); len(capped) != 0 {
+			t.Fatalf("disabled tracker capped %v", capped)
+		}
+	}
+}
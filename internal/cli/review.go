@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+	"github.com/daydemir/milhouse/internal/reviewer"
+)
+
+var reviewModelFlag string
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Verify all pending PRDs in one pass",
+	Long: `Run a dedicated reviewer sweep over every pending PRD.
+
+The reviewer emits a VERIFIED or REJECTED verdict for each pending PRD and
+Milhouse applies the state transitions:
+  - Verified PRDs are promoted to complete
+  - Rejected PRDs are reverted to open
+  - Plan files are deleted in both cases`,
+	Args: cobra.NoArgs,
+	RunE: runReview,
+}
+
+func init() {
+	reviewCmd.Flags().StringVar(&reviewModelFlag, "model", "", "Override reviewer model (haiku, sonnet, opus)")
+	rootCmd.AddCommand(reviewCmd)
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	d := display.NewWithOptions(GetNoColor())
+
+	if !prd.MillhouseExists(cwd) {
+		d.Error(".milhouse/ directory not found")
+		d.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		d.Warning(fmt.Sprintf("Failed to load config: %v, using defaults", err))
+		cfg = config.DefaultConfig()
+	}
+
+	applyDisplayConfig(d, cfg)
+
+	cfg.ApplyOverrides("", "", reviewModelFlag, "", 0, 0, 0, 0, 0, 0)
+	if err := cfg.Validate(); err != nil {
+		d.Error(fmt.Sprintf("Invalid configuration from CLI flags: %v", err))
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := checkAPIKey(d, cfg); err != nil {
+		return err
+	}
+
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	pending := prdFile.GetPendingPRDs()
+	if len(pending) == 0 {
+		d.Info("No pending PRDs to review")
+		return nil
+	}
+
+	d.Header(fmt.Sprintf("Milhouse Review (%d pending)", len(pending)))
+
+	result, err := reviewer.RunBatch(context.Background(), cwd, prdFile, 0, cfg)
+	if err != nil {
+		d.Error(fmt.Sprintf("Reviewer error: %v", err))
+		return fmt.Errorf("review failed: %w", err)
+	}
+
+	showReviewResult(d, result)
+	if _, err := reviewer.RecordRejections(cwd, result.Rejections, 0); err != nil {
+		d.Warning(fmt.Sprintf("Failed to record rejection reasons: %v", err))
+	}
+
+	// Show resulting state
+	prdFile, err = prd.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to reload PRDs: %w", err)
+	}
+	groups := prdFile.Group()
+	d.SummaryExtended(len(groups.Open), len(groups.Active), len(groups.Pending), len(groups.Complete))
+
+	return nil
+}
+
+// showReviewResult displays reviewer outcomes and returns them as signals
+func showReviewResult(d *display.Display, result *reviewer.ReviewerResult) []llm.Signal {
+	var signals []llm.Signal
+
+	for _, id := range result.Verified {
+		signals = append(signals, llm.Signal{Type: llm.SignalVerified, PRDID: id})
+		d.Signal("VERIFIED", id)
+	}
+	for _, r := range result.Rejections {
+		signals = append(signals, llm.Signal{Type: llm.SignalRejected, PRDID: r.ID, Details: r.Reason})
+		d.Signal("REJECTED", r.ID)
+	}
+	for _, id := range result.PlanUpdated {
+		signals = append(signals, llm.Signal{Type: llm.SignalPlanUpdated, PRDID: id})
+		d.Signal("PLAN_UPDATED", id)
+	}
+	for _, id := range result.LoopRisk {
+		signals = append(signals, llm.Signal{Type: llm.SignalLoopRisk, PRDID: id})
+		d.Warning(fmt.Sprintf("Loop risk detected for PRD: %s", id))
+	}
+	for _, phase := range result.PromptUpdated {
+		d.Info(fmt.Sprintf("📝 Updated prompt guidance: %s.md", phase))
+	}
+	for _, id := range result.Held {
+		d.Warning(fmt.Sprintf("Verdict held, PRD stays pending (definition of done not met): %s", id))
+		for _, reason := range result.HeldReasons[id] {
+			d.Info("  " + reason)
+		}
+	}
+	for _, id := range result.NoCriteria {
+		d.Warning(fmt.Sprintf("Verdict refused, PRD has no acceptance criteria - blocked for refinement: %s", id))
+	}
+	for _, id := range result.Unreviewed {
+		d.Warning(fmt.Sprintf("No verdict for pending PRD: %s", id))
+	}
+
+	return signals
+}
@@ -1,11 +1,30 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
 )
 
-var noColor bool
+var (
+	noColor       bool
+	assumeYes     bool
+	outputDirFlag string
+	compactFlag   bool
+	fullFlag      bool
+	markdownFlag  bool
+	themeFlag     string
+	traceEvents   bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "mil",
@@ -17,20 +36,135 @@ Claude to implement them iteratively, with a reviewer agent ensuring
 quality and progress between iterations.
 
 Commands:
-  init     Create .milhouse/ folder with starter files
-  chat     Interactive Claude session for PRD management
-  status   Show PRD status summary
-  run N    Execute N iterations autonomously`,
+  init      Create .milhouse/ folder with starter files
+  chat      Interactive Claude session for PRD management
+  status    Show PRD status summary
+  run N     Execute N iterations autonomously
+  batch     Run N iterations in each of several projects
+  review    Verify all pending PRDs in one pass
+  approve   Approve or reject pending PRDs by hand
+  stats     Summarize metrics from past runs
+  export    Write PRD status as HTML or CSV
+  summarize Condense progress.md into patterns and learnings
+  prd       Inspect and manage individual PRDs (new, show, history, rm, reorder, validate, graph, link)
+  plan      Manage plan files (prune)
+  doctor    Check .milhouse/ for common problems
+  x         Run a custom prompt from .milhouse/prompts/custom/`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Disable colors globally if --no-color flag is set
 		if noColor {
 			color.NoColor = true
 		}
+
+		cwd, cfg, err := loadProjectConfig()
+		if err == nil {
+			err = applyOutputDir(cwd, cfg)
+		}
+		if err != nil {
+			display.Warning(fmt.Sprintf("Ignoring output directory: %v", err))
+		}
+		applyTheme(cfg)
+		applyPRDLimits(cfg)
+		llm.SetTraceEventsDefault(traceEvents)
 	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Answer yes to confirmation prompts (for scripts and CI)")
+	rootCmd.PersistentFlags().BoolVar(&compactFlag, "compact", false, "Show each agent message on one line (full text is still captured)")
+	rootCmd.PersistentFlags().BoolVar(&fullFlag, "full", false, "Show complete agent messages wrapped to the terminal width")
+	rootCmd.MarkFlagsMutuallyExclusive("compact", "full")
+	rootCmd.PersistentFlags().BoolVar(&markdownFlag, "markdown", false, "Render headers, lists and code blocks in agent messages (implies --full)")
+	rootCmd.MarkFlagsMutuallyExclusive("compact", "markdown")
+	rootCmd.PersistentFlags().StringVar(&themeFlag, "theme", "", "Color theme: "+strings.Join(display.ThemeNames(), ", "))
+	rootCmd.PersistentFlags().BoolVar(&traceEvents, "trace-events", false, "Report Claude stream event types Milhouse does not recognize")
+	rootCmd.PersistentFlags().StringVar(&outputDirFlag, "output-dir", "", "Keep plans, evidence and logs in `dir` instead of .milhouse/ (\"cache\" for the user cache)")
+}
+
+// loadProjectConfig loads the project config once for the settings applied
+// before every command
+// The config is nil outside a Millhouse project or when it does not load;
+// config errors are reported by the commands that load it.
+func loadProjectConfig() (string, *config.Config, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if !prd.MillhouseExists(cwd) {
+		return cwd, nil, nil
+	}
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return cwd, nil, nil
+	}
+	return cwd, cfg, nil
+}
+
+// applyOutputDir relocates generated artifacts per --output-dir or the
+// outputDir config setting; prd.json and the other inputs stay in .milhouse/
+func applyOutputDir(cwd string, cfg *config.Config) error {
+	dir := outputDirFlag
+	if dir == "" && cfg != nil {
+		dir = cfg.OutputDir
+	}
+
+	resolved, err := resolveOutputDir(cwd, dir)
+	if err != nil {
+		return err
+	}
+	prd.SetArtifactsDir(resolved)
+	return nil
+}
+
+// applyPRDLimits caps the PRD descriptions and notes prd.Save writes per the
+// prdLimits config setting; without one, text is never cut
+func applyPRDLimits(cfg *config.Config) {
+	if cfg == nil {
+		prd.SetTextLimits(0, 0)
+		return
+	}
+	prd.SetTextLimits(cfg.PRDLimits.MaxDescription, cfg.PRDLimits.MaxNotes)
+}
+
+// applyTheme selects the color preset from --theme or the display.theme
+// config setting, warning and keeping the default for unknown names
+func applyTheme(cfg *config.Config) {
+	name := themeFlag
+	if name == "" && cfg != nil {
+		name = cfg.Display.Theme
+	}
+	if name == "" {
+		return
+	}
+
+	if !display.IsTheme(name) {
+		display.Warning(fmt.Sprintf("Unknown theme %q, using %s (available: %s)",
+			name, display.ThemeDefault, strings.Join(display.ThemeNames(), ", ")))
+	}
+	display.SetThemeDefault(name)
+}
+
+// resolveOutputDir turns an output directory setting into an absolute path
+// "cache" maps to a per-project directory in the user cache, a leading ~/
+// to the home directory, and relative paths are taken from cwd.
+func resolveOutputDir(cwd, dir string) (string, error) {
+	switch {
+	case dir == "":
+		return "", nil
+	case dir == "cache":
+		return prd.CacheArtifactsDir(cwd)
+	case dir == "~" || strings.HasPrefix(dir, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		return filepath.Join(home, strings.TrimPrefix(dir, "~")), nil
+	case filepath.IsAbs(dir):
+		return filepath.Clean(dir), nil
+	default:
+		return filepath.Join(cwd, dir), nil
+	}
 }
 
 // GetNoColor returns the no-color flag value
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveOutputDir(t *testing.T) {
+	cwd := t.TempDir()
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{name: "unset", dir: "", want: ""},
+		{name: "absolute", dir: "/tmp/artifacts/", want: "/tmp/artifacts"},
+		{name: "relative", dir: "out", want: filepath.Join(cwd, "out")},
+		{name: "home", dir: "~/scratch", want: filepath.Join(home, "scratch")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveOutputDir(cwd, tt.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveOutputDir(%q) = %q, want %q", tt.dir, got, tt.want)
+			}
+		})
+	}
+
+	got, err := resolveOutputDir(cwd, "cache")
+	if err != nil {
+		t.Skipf("no cache directory: %v", err)
+	}
+	if !strings.Contains(got, "milhouse") || strings.HasPrefix(got, cwd) {
+		t.Errorf("resolveOutputDir(cache) = %q, want a directory in the user cache", got)
+	}
+}
@@ -2,19 +2,29 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/daydemir/milhouse/internal/builder"
 	"github.com/daydemir/milhouse/internal/config"
 	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/events"
+	"github.com/daydemir/milhouse/internal/exec"
+	"github.com/daydemir/milhouse/internal/git"
 	"github.com/daydemir/milhouse/internal/llm"
 	"github.com/daydemir/milhouse/internal/planner"
 	"github.com/daydemir/milhouse/internal/prd"
 	"github.com/daydemir/milhouse/internal/reviewer"
+	"github.com/daydemir/milhouse/internal/summarizer"
 )
 
 var (
@@ -27,6 +37,54 @@ var (
 	plannerTokensFlag  int
 	builderTokensFlag  int
 	reviewerTokensFlag int
+
+	// Progress context override flags
+	progressLinesFlag         int
+	plannerProgressLinesFlag  int
+	builderProgressLinesFlag  int
+	reviewerProgressLinesFlag int
+
+	// Reviewer mode flags
+	reviewAllFlag     bool
+	maxRejectionsFlag int
+
+	// Builder mode flags
+	builderReadOnlyFlag    bool
+	confirmBeforeWriteFlag bool
+
+	// Selection override flags
+	selectFlag      string
+	selectOnceFlag  bool
+	ownerFlag       string
+	maxPriorityFlag int
+	prdFilterFlag   string
+	resumePRDFlag   string
+
+	// Scheduling flags
+	fairFlag           bool
+	fairThresholdFlag  int
+	rejectCooldownFlag int
+	stepFlag           bool
+
+	// Git flags
+	checkpointCommitFlag  bool
+	stashFlag             bool
+	requireCleanStartFlag bool
+
+	// Hook flags
+	onCompleteFlag       string
+	onCompleteAlwaysFlag bool
+	notifyFlag           []string
+
+	// Prompt flags
+	noAugmentationFlag bool
+	seedPromptFlag     string
+
+	// Profiling flags
+	profileDirFlag string
+
+	// Diagnostics flags
+	explainFlag bool
 )
 
 var runCmd = &cobra.Command{
@@ -56,14 +114,87 @@ func init() {
 	runCmd.Flags().IntVar(&plannerTokensFlag, "planner-max-tokens", 0, "Override planner token limit (10000-200000)")
 	runCmd.Flags().IntVar(&builderTokensFlag, "builder-max-tokens", 0, "Override builder token limit (10000-200000)")
 	runCmd.Flags().IntVar(&reviewerTokensFlag, "reviewer-max-tokens", 0, "Override reviewer token limit (10000-200000)")
+
+	// Progress context override flags
+	runCmd.Flags().IntVar(&progressLinesFlag, "progress-lines", 0, "Override how many progress.md lines every phase sees (10-1000)")
+	runCmd.Flags().IntVar(&plannerProgressLinesFlag, "planner-progress-lines", 0, "Override planner progress.md lines (10-1000)")
+	runCmd.Flags().IntVar(&builderProgressLinesFlag, "builder-progress-lines", 0, "Override builder progress.md lines (10-1000)")
+	runCmd.Flags().IntVar(&reviewerProgressLinesFlag, "reviewer-progress-lines", 0, "Override reviewer progress.md lines (10-1000)")
+
+	// Reviewer mode flags
+	runCmd.Flags().BoolVar(&reviewAllFlag, "review-all", false, "Verify every pending PRD in one reviewer pass")
+	runCmd.Flags().IntVar(&maxRejectionsFlag, "max-rejections", 0, "Block a PRD once the reviewer rejects it N times in this run (0 = no cap)")
+
+	// Builder mode flags
+	runCmd.Flags().BoolVar(&builderReadOnlyFlag, "builder-readonly", false, "Preview builder changes with read-only tools, then stop")
+	runCmd.Flags().BoolVar(&confirmBeforeWriteFlag, "confirm-before-write", false, "Ask before the builder's first change to each PRD (interactive only)")
+
+	// Selection override flags
+	runCmd.Flags().StringVar(&selectFlag, "select", "", "Force the planner to select this open PRD")
+	runCmd.Flags().BoolVar(&selectOnceFlag, "select-once", false, "Apply --select only to the first planner run")
+	runCmd.Flags().StringVar(&ownerFlag, "owner", "", "Only plan PRDs owned by this person (or unowned)")
+	runCmd.Flags().IntVar(&maxPriorityFlag, "max-priority", 0, "Only plan PRDs with priority <= N (1 = most important)")
+	runCmd.Flags().StringVar(&prdFilterFlag, "prd-filter", "", "Only plan PRDs matching this `expression`, e.g. 'priority<=2 && tag=backend'")
+	runCmd.Flags().StringVar(&resumePRDFlag, "resume-prd", "", "Continue interrupted work on this active PRD in the first builder run")
+
+	// Scheduling flags
+	runCmd.Flags().BoolVar(&fairFlag, "fair", false, "Rotate to less-served open PRDs when one keeps consuming iterations")
+	runCmd.Flags().IntVar(&fairThresholdFlag, "fair-threshold", DefaultFairThreshold, "With --fair, rotate once a PRD has used N more iterations than the least-served one")
+	runCmd.Flags().BoolVar(&stepFlag, "step", false, "Pause after each phase to continue, inspect or abort (interactive only)")
+	runCmd.Flags().IntVar(&rejectCooldownFlag, "reject-cooldown", DefaultRejectCooldown, "Iterations the planner passes over a just-rejected PRD while other work is open (0 = off)")
+
+	// Git flags
+	runCmd.Flags().BoolVar(&checkpointCommitFlag, "checkpoint-commit", false, "Commit the working tree after each PRD is verified complete")
+	runCmd.Flags().BoolVar(&stashFlag, "stash", false, "Stash uncommitted changes before the run and restore them when it ends")
+	runCmd.Flags().BoolVar(&requireCleanStartFlag, "require-clean-start", false, "Refuse to start when the working tree has uncommitted changes")
+	runCmd.MarkFlagsMutuallyExclusive("stash", "require-clean-start")
+
+	// Hook flags
+	runCmd.Flags().StringVar(&onCompleteFlag, "on-complete", "", "Run this shell command when the run ends, with its outcome in MIL_* variables")
+	runCmd.Flags().BoolVar(&onCompleteAlwaysFlag, "on-complete-always", false, "Run the --on-complete command even when the run fails")
+	runCmd.Flags().StringSliceVar(&notifyFlag, "notify", nil, "Desktop notification or terminal bell on these events: complete, bailout, blocked, input (bare --notify = all)")
+	runCmd.Flags().Lookup("notify").NoOptDefVal = notifyAll
+
+	// Prompt flags
+	runCmd.Flags().BoolVar(&noAugmentationFlag, "no-augmentation", false, "Ignore .milhouse/prompts/ and run with stock prompts only")
+	runCmd.Flags().StringVar(&seedPromptFlag, "seed-prompt", "", "One-time instruction for every phase of this run (text or path to a file)")
+
+	// Diagnostics flags
+	runCmd.Flags().BoolVar(&explainFlag, "explain", false, "Print why each phase ran or was skipped")
+
+	// Profiling flags (for investigating Millhouse itself, not Claude)
+	runCmd.Flags().StringVar(&profileDirFlag, "profile", "", "Write CPU and heap pprof profiles of this process to `dir`")
+	runCmd.Flags().MarkHidden("profile")
+}
+
+// errRunInterrupted ends a run stopped by Ctrl-C or SIGTERM
+var errRunInterrupted = errors.New("run interrupted")
+
+// interruptibleContext returns a context cancelled by the first SIGINT or
+// SIGTERM, which also stops the agents running under it
+// Later signals get the default behaviour, so a second Ctrl-C ends mil at
+// once even while the on-complete hook runs.
+func interruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
 }
 
-func runRun(cmd *cobra.Command, args []string) error {
+func runRun(cmd *cobra.Command, args []string) (runErr error) {
 	iterations, err := strconv.Atoi(args[0])
 	if err != nil || iterations < 1 {
 		return fmt.Errorf("N must be a positive integer")
 	}
 
+	// --notify gets the user's attention on events that need it
+	notifier, err := newRunNotifier(notifyFlag)
+	if err != nil {
+		return err
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
@@ -85,63 +216,353 @@ func runRun(cmd *cobra.Command, args []string) error {
 		cfg = config.DefaultConfig()
 	}
 
-	// Apply CLI flag overrides
+	applyDisplayConfig(d, cfg)
+
+	for _, f := range []struct {
+		name  string
+		lines int
+	}{
+		{"progress-lines", progressLinesFlag},
+		{"planner-progress-lines", plannerProgressLinesFlag},
+		{"builder-progress-lines", builderProgressLinesFlag},
+		{"reviewer-progress-lines", reviewerProgressLinesFlag},
+	} {
+		if f.lines != 0 && (f.lines < config.MinProgressLines || f.lines > config.MaxProgressLines) {
+			d.Error(fmt.Sprintf("--%s must be between %d and %d", f.name, config.MinProgressLines, config.MaxProgressLines))
+			return fmt.Errorf("invalid --%s: %d", f.name, f.lines)
+		}
+	}
+
+	// Apply CLI flag overrides; a phase's own --*-progress-lines beats --progress-lines
 	cfg.ApplyOverrides(plannerModelFlag, builderModelFlag, reviewerModelFlag, "",
-		plannerTokensFlag, builderTokensFlag, reviewerTokensFlag)
+		plannerTokensFlag, builderTokensFlag, reviewerTokensFlag,
+		phaseLines(plannerProgressLinesFlag), phaseLines(builderProgressLinesFlag), phaseLines(reviewerProgressLinesFlag))
+
+	if onCompleteFlag != "" {
+		cfg.Hooks.OnComplete = onCompleteFlag
+	}
+	if onCompleteAlwaysFlag {
+		cfg.Hooks.OnCompleteAlways = true
+	}
+
+	cfg.Run.BuilderReadOnly = builderReadOnlyFlag
+	cfg.Run.NoAugmentation = noAugmentationFlag
+	cfg.Run.Owner = ownerFlag
+
+	if maxPriorityFlag < 0 {
+		d.Error("--max-priority must not be negative")
+		return fmt.Errorf("invalid --max-priority: %d", maxPriorityFlag)
+	}
+	cfg.Run.MaxPriority = maxPriorityFlag
+
+	if prdFilterFlag != "" {
+		filter, err := prd.ParseFilter(prdFilterFlag)
+		if err != nil {
+			d.Error(err.Error())
+			return fmt.Errorf("invalid --prd-filter: %w", err)
+		}
+		cfg.Run.Filter = filter
+	}
+
+	if maxRejectionsFlag < 0 {
+		d.Error("--max-rejections must not be negative")
+		return fmt.Errorf("invalid --max-rejections: %d", maxRejectionsFlag)
+	}
+
+	if rejectCooldownFlag < 0 {
+		d.Error("--reject-cooldown must not be negative")
+		return fmt.Errorf("invalid --reject-cooldown: %d", rejectCooldownFlag)
+	}
+
+	if fairThresholdFlag < 1 {
+		d.Error("--fair-threshold must be at least 1")
+		return fmt.Errorf("invalid --fair-threshold: %d", fairThresholdFlag)
+	}
+
+	if seedPromptFlag != "" {
+		directive, err := loadSeedPrompt(seedPromptFlag)
+		if err != nil {
+			return err
+		}
+		cfg.Run.RunDirective = directive
+		d.Info(fmt.Sprintf("Run directive: %s", display.Truncate(strings.ReplaceAll(directive, "\n", " "), 60)))
+	}
+
+	// Validate the selection override against the current PRD state
+	if selectFlag != "" {
+		prdFile, err := prd.Load(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to load PRDs: %w", err)
+		}
+		if err := prd.ValidateSelection(prdFile, selectFlag); err == nil {
+			err = prd.ValidateScope(prdFile, selectFlag, cfg.Run.Scope())
+		}
+		if err != nil {
+			d.Error(fmt.Sprintf("Invalid --select: %v", err))
+			return fmt.Errorf("invalid selection: %w", err)
+		}
+		cfg.Run.Select = selectFlag
+	}
+
+	// Validate that a resumed PRD was left active with its plan
+	if resumePRDFlag != "" {
+		prdFile, err := prd.Load(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to load PRDs: %w", err)
+		}
+		if err := prd.ValidateResume(cwd, prdFile, resumePRDFlag); err != nil {
+			d.Error(fmt.Sprintf("Invalid --resume-prd: %v", err))
+			return fmt.Errorf("invalid resume: %w", err)
+		}
+		cfg.Run.ResumePRD = resumePRDFlag
+		d.Info(fmt.Sprintf("Resuming interrupted work on %s", resumePRDFlag))
+	}
 
 	// Validate configuration after applying overrides
 	if err := cfg.Validate(); err != nil {
 		d.Error(fmt.Sprintf("Invalid configuration from CLI flags: %v", err))
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
+	if err := checkAPIKey(d, cfg); err != nil {
+		return err
+	}
+	warnUnmatchedPRDModels(d, cwd, cfg)
+
+	// Step-through mode needs someone at a terminal; --yes approves up front
+	if confirmBeforeWriteFlag {
+		cfg.Phases.Builder.ConfirmBeforeWrite = true
+	}
+	if cfg.Phases.Builder.ConfirmBeforeWrite && !assumeYes {
+		if cfg.Run.ConfirmWrite = writeConfirmer(cwd); cfg.Run.ConfirmWrite == nil {
+			d.Warning("confirmBeforeWrite ignored: no terminal to confirm on")
+		}
+	}
+
+	if profileDirFlag != "" {
+		stopProfiling, err := startProfiling(profileDirFlag)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := stopProfiling(); err != nil {
+				d.Warning(fmt.Sprintf("Profiling: %v", err))
+				return
+			}
+			d.Info(fmt.Sprintf("Profiles written to %s (open with 'go tool pprof')", profileDirFlag))
+		}()
+	}
 
-	// Create context for the run
-	ctx := context.Background()
+	// Ctrl-C cancels the run instead of killing mil, so the on-complete hook
+	// and stash restore still run
+	ctx, stopSignals := interruptibleContext()
+	defer stopSignals()
 
 	d.Header(fmt.Sprintf("Milhouse Run (%d iterations)", iterations))
 
+	// Changes already in the tree are not the agent's: warn, refuse or stash them
+	policy := startPolicy(requireCleanStartFlag, stashFlag, checkpointCommitFlag)
+	guard, err := guardStartTree(d, cwd, policy)
+	if err != nil {
+		if checkpointCommitFlag && !requireCleanStartFlag {
+			d.Info("--checkpoint-commit commits the whole tree, so it only starts from a clean one")
+		}
+		return err
+	}
+	if guard != nil {
+		defer restoreStartTree(d, guard) // Runs after the on-complete hook
+	}
+
+	if cfg.Run.NoAugmentation {
+		d.Warning("Prompt augmentations disabled - using stock prompts only")
+	}
+
+	// Structured event log for 'mil stats'
+	evlog := events.NewLog(cwd)
+	logEvent(d, evlog, events.Event{Type: events.TypeRunStart})
+	runStarted := time.Now()
+
+	// Signal hooks let external tools react to signals (notifications, paging)
+	bus := events.NewBus()
+	registerSignalHooks(ctx, d, cwd, bus, cfg.Hooks.OnSignal)
+	notifier.subscribe(bus)
+	cfg.Run.ConfirmWrite = notifier.wrapConfirm(cfg.Run.ConfirmWrite)
+
+	// Adaptive backoff between phases after API rate limits
+	limiter := newRateLimiter(cfg.RateLimit)
+
+	// Per-PRD rejection counts for --max-rejections
+	rejections := newRejectionTracker(maxRejectionsFlag)
+
+	// Pauses between phases for --step
+	step := newStepper(d, cwd, stepFlag)
+
+	// Recently rejected PRDs for --reject-cooldown
+	cooldown := newRejectionCooldown(rejectCooldownFlag)
+
+	// Per-PRD iteration counts for --fair
+	fair := newFairScheduler(0)
+	if fairFlag {
+		fair = newFairScheduler(fairThresholdFlag)
+	}
+
 	// Early exit tracking
 	var prevState *IterationState
 	idleCount := 0
+	completed := 0
+
+	// The on-complete hook learns how the run ended, failures included
+	outcome := runOutcome{reason: exitIterations, runID: evlog.RunID()}
+	if prdFile, err := prd.Load(cwd); err == nil {
+		outcome.startComplete = len(prdFile.GetCompletePRDs())
+	}
+	defer func() {
+		outcome.iterations = completed
+		if ctx.Err() != nil {
+			outcome.reason = exitInterrupted
+			runErr = errRunInterrupted
+			d.Warning("Run interrupted")
+		}
+		// The run context may be cancelled; the hook gets its own timeout
+		hookCtx := context.WithoutCancel(ctx)
+		if err := runCompletionHook(hookCtx, d, cwd, cfg, outcome, runErr); err != nil && runErr == nil {
+			runErr = err
+		}
+		notifier.runEnded(outcome, runErr)
+	}()
 
 	for i := 1; i <= iterations; i++ {
-		d.IterationHeader(i, iterations)
+		if ctx.Err() != nil {
+			return errRunInterrupted
+		}
+		completed = i
+		iterStarted := time.Now()
 
 		// Track all signals for this iteration
 		var allSignals []llm.Signal
+		var workedOn string // PRD this iteration is charged to under --fair
+		step.startIteration()
 
 		// Load fresh PRD state at start of each iteration
 		prdFile, err := prd.Load(cwd)
 		if err != nil {
 			return fmt.Errorf("failed to load PRDs: %w", err)
 		}
+		trackActivePRD(d, prdFile, cfg.Run.Scope())
+		d.IterationHeader(i, iterations)
+		startState := captureRunState(cwd, prdFile, nil)
 
-		// Check if there's work to do
-		openPRDs := prdFile.GetOpenPRDs()
-		activePRDs := prdFile.GetActivePRDs()
-		pendingPRDs := prdFile.GetPendingPRDs()
+		// Check if there's work to do within the run's scope
+		groups := prdFile.Group()
+		openPRDs := groups.OpenIn(cfg.Run.Scope())
+		activePRDs := groups.ActiveIn(cfg.Run.Scope().Work())
+		pendingPRDs := groups.PendingIn(cfg.Run.Scope().Work())
 
 		if len(openPRDs) == 0 && len(activePRDs) == 0 && len(pendingPRDs) == 0 {
-			d.Success("All PRDs complete! Nothing to do.")
+			if blocked := groups.Blocked; len(blocked) > 0 {
+				d.Warning(fmt.Sprintf("Nothing to do: %d PRD(s) blocked awaiting refinement", len(blocked)))
+			} else if others := len(groups.Open) + len(groups.Active) + len(groups.Pending); others > 0 {
+				d.Info(fmt.Sprintf("Nothing to do within %s: %d unfinished PRD(s) left untouched", cfg.Run.Scope(), others))
+			} else {
+				d.Success("All PRDs complete! Nothing to do.")
+			}
+			outcome.reason = exitNoWork
 			break
 		}
 
+		// Keep progress.md within budget before the agents read it
+		if summarizer.ShouldSummarize(cwd, cfg) && !cfg.Run.BuilderReadOnly {
+			d.PhaseHeader("Summarizing progress.md")
+			if err := limiter.Wait(ctx, d); err != nil {
+				return err
+			}
+			sumResult, err := summarizeProgress(ctx, d, cwd, cfg)
+			observePhase(d, limiter, sumResult != nil && sumResult.RateLimited, err)
+			if err == nil && sumResult.Summarized == 0 {
+				d.Warning(fmt.Sprintf("progress.md exceeds summarize.maxBytes (%d) but has too few entries to condense", cfg.Summarize.MaxBytes))
+			}
+		}
+
 		// ========================================
 		// PHASE 1: PLANNER
 		// ========================================
-		if planner.ShouldRunPlanner(prdFile) {
-			d.SubHeader("Phase 1: Planner")
+		runPlanner, reason := planner.ShouldRunPlannerWithReason(prdFile, cfg.Run.Scope())
+		explainPhase(d, "planner", runPlanner, reason)
+		if runPlanner {
+			d.PhaseHeader("Phase 1: Planner")
 
+			// Cooldown and fair scheduling steer this one planner run; --select wins
+			rotated := false
+			if cfg.Run.Select == "" {
+				cfg.Run.Cooldown = applyCooldown(d, cooldown, prdFile, cfg.Run.Scope(), i)
+				if id, top := fair.pick(prdFile, cfg.Run.Scope()); id != "" {
+					d.Info(fmt.Sprintf("Fair scheduling: %s has used %d iterations this run, rotating to %s", top, fair.used[top], id))
+					cfg.Run.Select = id
+					rotated = true
+				}
+			}
+
+			if err := limiter.Wait(ctx, d); err != nil {
+				return err
+			}
+			phaseStarted := time.Now()
 			planResult, err := planner.Run(ctx, cwd, prdFile, cfg)
+			planTime := time.Since(phaseStarted)
+			observePhase(d, limiter, planResult != nil && planResult.RateLimited, err)
+			d.PhaseDuration("Planner", planTime)
+			cfg.Run.Cooldown = nil
+			if rotated {
+				cfg.Run.Select = ""
+			}
 			if err != nil {
 				d.Error(fmt.Sprintf("Planner error: %v", err))
+				logEvent(d, evlog, events.Event{Type: events.TypePhase, Iteration: i, Phase: "planner", Error: err.Error(), DurationMs: planTime.Milliseconds()})
 				continue
 			}
 
+			logEvent(d, evlog, events.Event{
+				Type:       events.TypePhase,
+				Iteration:  i,
+				Phase:      "planner",
+				PRDID:      planResult.PRDID,
+				Tokens:     planResult.TotalTokens,
+				Signals:    signalTypes(planResult.Signals),
+				DurationMs: planTime.Milliseconds(),
+			})
+
+			showPhaseSummary(d, cfg, planResult.Summary)
 			if planResult.Skipped {
 				d.Info(fmt.Sprintf("Planner skipped: %s", planResult.SkipReason))
 			} else if planResult.PRDID != "" {
+				workedOn = planResult.PRDID
 				d.Signal("PLAN_COMPLETE", planResult.PRDID)
+				if a := planResult.Approach; a != nil && a.Risk != "" {
+					d.Info(fmt.Sprintf("Approach (%s risk, %d file(s)): %s", a.Risk, len(a.Files), a.Summary))
+				} else if a != nil {
+					d.Info(fmt.Sprintf("Approach (%d file(s)): %s", len(a.Files), a.Summary))
+				}
+				if cfg.Run.Select != "" && planResult.PRDID != cfg.Run.Select {
+					d.Warning(fmt.Sprintf("Planner ignored --select %s and planned %s", cfg.Run.Select, planResult.PRDID))
+				}
+			}
+
+			for _, id := range planResult.CriteriaProposed {
+				d.Info(fmt.Sprintf("Planner proposed acceptance criteria for %s - review them with 'mil prd show %s'", id, id))
+			}
+			for _, id := range planResult.NeedsRefinement {
+				d.Warning(fmt.Sprintf("PRD %s needs refinement - blocked until updated via 'mil chat'", id))
+			}
+			for _, proposal := range planResult.Splits {
+				applySplit(d, cwd, cfg, proposal, i)
+			}
+
+			// Clear a one-shot selection, or one whose target is done
+			if cfg.Run.Select != "" {
+				if selectOnceFlag {
+					cfg.Run.Select = ""
+				} else if p := prdFile.FindByID(cfg.Run.Select); p == nil || p.Passes.IsTrue() {
+					d.Info(fmt.Sprintf("Selection %s complete, resuming priority order", cfg.Run.Select))
+					cfg.Run.Select = ""
+				}
 			}
 
 			// Handle planner signals
@@ -151,12 +572,19 @@ func runRun(cmd *cobra.Command, args []string) error {
 					d.Signal(signal.Type, signal.Details)
 				}
 			}
+			publishSignals(bus, evlog.RunID(), i, "planner", planResult.Signals)
 
 			// Reload PRD state after planner
-			prdFile, err = prd.Load(cwd)
+			prdFile, err = reloadWithHistory(cwd, prdFile, i)
 			if err != nil {
 				return fmt.Errorf("failed to reload PRDs: %w", err)
 			}
+			trackActivePRD(d, prdFile, cfg.Run.Scope())
+
+			if !step.pause(d, "planner", plannerStepSummary(planResult), planResult.PRDID) {
+				outcome.reason = exitAborted
+				break
+			}
 		} else if len(activePRDs) > 0 {
 			d.Info(fmt.Sprintf("Planner skipped: active PRD exists (%s)", activePRDs[0].ID))
 		} else if len(openPRDs) == 0 {
@@ -166,83 +594,220 @@ func runRun(cmd *cobra.Command, args []string) error {
 		// ========================================
 		// PHASE 2: BUILDER
 		// ========================================
-		if builder.ShouldRunBuilder(prdFile) {
-			d.SubHeader("Phase 2: Builder")
+		var buildFailure *exec.Result
+		writeDeclined := false
+		runBuilder, reason := builder.ShouldRunBuilderWithReason(prdFile, cfg.Run.Scope().Work())
+		explainPhase(d, "builder", runBuilder, reason)
+		if runBuilder {
+			d.PhaseHeader("Phase 2: Builder")
+
+			var activeID string
+			if active := builder.SelectActive(prdFile, cfg.Run.ResumePRD, cfg.Run.Scope().Work()); active != nil {
+				activeID = active.ID
+				workedOn = activeID
+				d.Info(fmt.Sprintf("Executing plan for PRD: %s", activeID))
+			}
 
-			activePRDs = prdFile.GetActivePRDs()
-			if len(activePRDs) > 0 {
-				d.Info(fmt.Sprintf("Executing plan for PRD: %s", activePRDs[0].ID))
+			// Snapshot the working tree so a read-only preview can be checked afterward
+			var treeBefore *git.TreeState
+			if cfg.Run.BuilderReadOnly {
+				if treeBefore, err = git.CaptureTree(cwd, prd.MillhouseDir); err != nil {
+					return fmt.Errorf("read-only mode requires a git repository: %w", err)
+				}
 			}
 
+			if err := limiter.Wait(ctx, d); err != nil {
+				return err
+			}
+			phaseStarted := time.Now()
 			buildResult, err := builder.Run(ctx, cwd, prdFile, cfg)
+			buildTime := time.Since(phaseStarted)
+			observePhase(d, limiter, buildResult != nil && buildResult.RateLimited, err)
+			d.PhaseDuration("Builder", buildTime)
+			cfg.Run.ResumePRD = "" // Only the first builder run resumes
 			if err != nil {
 				d.Error(fmt.Sprintf("Builder error: %v", err))
+				logEvent(d, evlog, events.Event{Type: events.TypePhase, Iteration: i, Phase: "builder", PRDID: activeID, Error: err.Error(), DurationMs: buildTime.Milliseconds()})
 			} else {
+				showPhaseSummary(d, cfg, buildResult.Summary)
+				writeDeclined = buildResult.WriteDeclined
+				if buildResult.GateUnreached {
+					d.Warning(fmt.Sprintf("Builder ended without trying to change anything - %s kept active", activeID))
+				}
+
 				// Handle builder signals
 				for _, signal := range buildResult.Signals {
 					allSignals = append(allSignals, signal)
 					d.Signal(signal.Type, signal.Details)
 				}
+				publishSignals(bus, evlog.RunID(), i, "builder", buildResult.Signals)
+				if buildResult.Discrepancy != "" {
+					d.Warning(fmt.Sprintf("Builder %s - left pending with a note for the reviewer", buildResult.Discrepancy))
+				}
+				if buildResult.FileMismatch != "" {
+					d.Warning(fmt.Sprintf("Evidence files differ from git (%s) - noted for the reviewer", buildResult.FileMismatch))
+				}
+				if buildResult.MissingEvidence != "" {
+					d.Warning(fmt.Sprintf("Completion refused: %s - kept active so the builder writes evidence", buildResult.MissingEvidence))
+				}
+
+				logEvent(d, evlog, events.Event{
+					Type:       events.TypePhase,
+					Iteration:  i,
+					Phase:      "builder",
+					PRDID:      activeID,
+					Tokens:     buildResult.TotalTokens,
+					Signals:    signalTypes(buildResult.Signals),
+					DurationMs: buildTime.Milliseconds(),
+				})
+			}
+
+			if cfg.Run.BuilderReadOnly {
+				changed, err := treeBefore.Changed()
+				if err != nil {
+					return err
+				}
+				if len(changed) > 0 {
+					d.Error("Builder modified files in read-only mode:")
+					for _, c := range changed {
+						d.Info("  " + c)
+					}
+					return fmt.Errorf("read-only builder changed %d file(s)", len(changed))
+				}
+				d.Success("Builder preview complete - working tree unchanged")
+				d.Info("Run without --builder-readonly to apply the plan")
+				outcome.reason = exitPreview
+				break
 			}
 
 			// Reload PRD state after builder
-			prdFile, err = prd.Load(cwd)
+			prdFile, err = reloadWithHistory(cwd, prdFile, i)
 			if err != nil {
 				return fmt.Errorf("failed to reload PRDs: %w", err)
 			}
+
+			// Post-build hooks ground the reviewer in real command results
+			if shouldGateBuild(ctx, prdFile, activeID) {
+				if _, buildFailure, err = runPostBuildHooks(ctx, d, cwd, cfg, activeID); err != nil {
+					d.Warning(fmt.Sprintf("Hook error: %v", err))
+				}
+			}
+
+			// A failed build is not worth reviewer tokens
+			if buildFailure != nil && shouldGateBuild(ctx, prdFile, activeID) {
+				to, err := recordBuildFailure(cwd, activeID, buildFailure, cfg.Hooks.RequeueOnBuildFailure, i)
+				if err != nil {
+					d.Warning(fmt.Sprintf("Failed to record build failure: %v", err))
+				} else {
+					d.Warning(fmt.Sprintf("Build failed for %s - moved to %s with the error in its notes", activeID, to))
+				}
+				if prdFile, err = prd.Load(cwd); err != nil {
+					return fmt.Errorf("failed to reload PRDs: %w", err)
+				}
+			}
+
+			if !step.pause(d, "builder", statusStepSummary(prdFile, activeID), activeID) {
+				outcome.reason = exitAborted
+				break
+			}
 		} else {
 			d.Info("Builder skipped: no active PRD")
 		}
+		fair.record(workedOn)
+
+		if writeDeclined {
+			d.Warning(fmt.Sprintf("Changes declined - stopping the run with %s still active", workedOn))
+			d.Info("Edit its plan or PRD, then run again")
+			outcome.reason = exitDeclined
+			break
+		}
 
 		// ========================================
 		// PHASE 3: REVIEWER
 		// ========================================
-		if reviewer.ShouldRunReviewer(prdFile) {
-			d.SubHeader("Phase 3: Reviewer")
+		runReviewer, reason := reviewer.ShouldRunReviewerWithReason(prdFile, cfg.Run.Scope())
+		if buildFailure != nil {
+			runReviewer, reason = false, "the build failed"
+		}
+		explainPhase(d, "reviewer", runReviewer, reason)
+		if buildFailure != nil {
+			d.Info("Reviewer skipped: build failed")
+		} else if runReviewer {
+			d.PhaseHeader("Phase 3: Reviewer")
 			d.AnalysisStart()
 
-			reviewResult, err := reviewer.Run(ctx, cwd, prdFile, i, cfg)
+			if err := limiter.Wait(ctx, d); err != nil {
+				return err
+			}
+			var reviewResult *reviewer.ReviewerResult
+			var reviewSignals []llm.Signal
+			phaseStarted := time.Now()
+			if reviewAllFlag && len(prdFile.GetPendingPRDsIn(cfg.Run.Scope().Work())) > 0 {
+				reviewResult, err = reviewer.RunBatch(ctx, cwd, prdFile, i, cfg)
+			} else {
+				reviewResult, err = reviewer.Run(ctx, cwd, prdFile, i, cfg)
+			}
+			reviewTime := time.Since(phaseStarted)
+			observePhase(d, limiter, reviewResult != nil && reviewResult.RateLimited, err)
+			d.PhaseDuration("Reviewer", reviewTime)
 			if err != nil {
 				d.Warning(fmt.Sprintf("Reviewer error: %v", err))
+				logEvent(d, evlog, events.Event{Type: events.TypePhase, Iteration: i, Phase: "reviewer", Error: err.Error(), DurationMs: reviewTime.Milliseconds()})
 			} else {
-				// Handle reviewer signals
-				for _, id := range reviewResult.Verified {
-					allSignals = append(allSignals, llm.Signal{Type: llm.SignalVerified, PRDID: id})
-					d.Signal("VERIFIED", id)
+				showPhaseSummary(d, cfg, reviewResult.Summary)
+				reviewSignals = showReviewResult(d, reviewResult)
+				cooldown.record(reviewSignals, i)
+				if _, err := reviewer.RecordRejections(cwd, reviewResult.Rejections, i); err != nil {
+					d.Warning(fmt.Sprintf("Failed to record rejection reasons: %v", err))
 				}
-				for _, id := range reviewResult.Rejected {
-					allSignals = append(allSignals, llm.Signal{Type: llm.SignalRejected, PRDID: id})
-					d.Signal("REJECTED", id)
-				}
-				for _, id := range reviewResult.PlanUpdated {
-					allSignals = append(allSignals, llm.Signal{Type: llm.SignalPlanUpdated, PRDID: id})
-					d.Signal("PLAN_UPDATED", id)
-				}
-				for _, id := range reviewResult.LoopRisk {
-					allSignals = append(allSignals, llm.Signal{Type: llm.SignalLoopRisk, PRDID: id})
-					d.Warning(fmt.Sprintf("Loop risk detected for PRD: %s", id))
-				}
-				for _, phase := range reviewResult.PromptUpdated {
-					d.Info(fmt.Sprintf("📝 Updated prompt guidance: %s.md", phase))
+				allSignals = append(allSignals, reviewSignals...)
+				publishSignals(bus, evlog.RunID(), i, "reviewer", reviewSignals)
+				logEvent(d, evlog, events.Event{
+					Type:       events.TypePhase,
+					Iteration:  i,
+					Phase:      "reviewer",
+					Tokens:     reviewResult.TotalTokens,
+					Signals:    signalTypes(reviewSignals),
+					Verified:   reviewResult.Verified,
+					Rejected:   reviewResult.Rejected,
+					DurationMs: reviewTime.Milliseconds(),
+				})
+			}
+
+			// Record reviewer transitions in PRD history
+			if reviewed, err := reloadWithHistory(cwd, prdFile, i); err != nil {
+				d.Warning(fmt.Sprintf("Failed to record PRD history: %v", err))
+			} else {
+				trackActivePRD(d, reviewed, cfg.Run.Scope())
+				if checkpointCommitFlag {
+					checkpointCommit(d, cwd, prdFile, reviewed)
 				}
+				capRejections(d, cwd, reviewed, rejections, reviewSignals, i)
+				escalateRejected(d, cwd, reviewed, cfg)
+			}
+
+			if !step.pause(d, "reviewer", reviewerStepSummary(reviewResult), workedOn) {
+				outcome.reason = exitAborted
+				break
 			}
 		} else {
 			d.Info("Reviewer skipped: no PRDs to review")
 		}
 
-		// Check for early exit (if enabled)
-		if cfg.EarlyExit.Enabled {
-			// Reload PRD state to get latest counts
-			prdFile, err = prd.Load(cwd)
-			if err == nil {
-				currentState := CaptureIterationState(prdFile, allSignals)
+		// Show only what this iteration changed, then check for early exit (if enabled)
+		prdFile, err = prd.Load(cwd)
+		if err == nil {
+			currentState := captureRunState(cwd, prdFile, allSignals)
+			showIterationChanges(d, currentState.Diff(startState))
 
+			if cfg.EarlyExit.Enabled {
 				// Check for idle iterations
 				if prevState != nil && currentState.Equals(prevState) {
 					idleCount++
 					if idleCount >= cfg.EarlyExit.IdleThreshold {
 						d.Warning(fmt.Sprintf("Early exit: %d consecutive idle iterations", idleCount))
 						d.Info("No state changes detected - work may be blocked or complete")
+						outcome.reason = exitIdle
 						break
 					}
 				} else {
@@ -253,9 +818,15 @@ func runRun(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		iterTime := time.Since(iterStarted)
+		d.IterationDuration(i, iterTime)
+		logEvent(d, evlog, events.Event{Type: events.TypeIteration, Iteration: i, DurationMs: iterTime.Milliseconds()})
 		d.Divider()
 	}
 
+	runTime := time.Since(runStarted)
+	logEvent(d, evlog, events.Event{Type: events.TypeRunEnd, Iterations: completed, DurationMs: runTime.Milliseconds()})
+
 	// Final status
 	d.Header("Final Status")
 	prdFile, err := prd.Load(cwd)
@@ -263,12 +834,11 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load final PRD state: %w", err)
 	}
 
-	open := prdFile.GetOpenPRDs()
-	active := prdFile.GetActivePRDs()
-	pending := prdFile.GetPendingPRDs()
-	complete := prdFile.GetCompletePRDs()
+	groups := prdFile.Group()
+	open, active, pending, complete := groups.Open, groups.Active, groups.Pending, groups.Complete
 
 	d.SummaryExtended(len(open), len(active), len(pending), len(complete))
+	d.Stat("Run time", display.FormatDuration(runTime))
 
 	if len(open) > 0 {
 		d.Info(fmt.Sprintf("Open PRDs remaining: %d", len(open)))
@@ -276,6 +846,222 @@ func runRun(cmd *cobra.Command, args []string) error {
 	if len(active) > 0 {
 		d.Info(fmt.Sprintf("Active PRDs (with plans): %d", len(active)))
 	}
+	if blocked := groups.Blocked; len(blocked) > 0 {
+		d.Warning(fmt.Sprintf("Blocked PRDs needing refinement: %d", len(blocked)))
+	}
+
+	return nil
+}
+
+// phaseLines returns a phase's progress-line override, falling back to
+// --progress-lines when the phase has none
+func phaseLines(phaseFlag int) int {
+	if phaseFlag != 0 {
+		return phaseFlag
+	}
+	return progressLinesFlag
+}
+
+// explainPhase prints why a phase ran or was skipped when --explain is set
+func explainPhase(d *display.Display, phase string, ran bool, reason string) {
+	if !explainFlag {
+		return
+	}
+	verdict := "ran"
+	if !ran {
+		verdict = "skipped"
+	}
+	d.Info(fmt.Sprintf("%s %s: %s", phase, verdict, reason))
+}
+
+// showPhaseSummary prints a phase's one-line recap when display.phaseSummary is set
+func showPhaseSummary(d *display.Display, cfg *config.Config, summary string) {
+	if cfg.Display.PhaseSummary && summary != "" {
+		d.Info(summary)
+	}
+}
+
+// applySplit replaces an oversized PRD with the planner's proposed children,
+// asking first when phases.planner.confirmSplit is set
+func applySplit(d *display.Display, cwd string, cfg *config.Config, proposal prd.SplitProposal, iteration int) {
+	d.Info(fmt.Sprintf("Planner proposes splitting %s into %d PRDs:", proposal.PRDID, len(proposal.Children)))
+	for _, c := range proposal.Children {
+		d.Info("  - " + display.Truncate(c.Description, 70))
+	}
 
+	if cfg.GetPhaseConfig("planner").ConfirmSplit && !confirm(fmt.Sprintf("Split PRD %s?", proposal.PRDID)) {
+		if err := planner.DeclineSplit(cwd, proposal); err != nil {
+			d.Warning(fmt.Sprintf("Failed to record declined split: %v", err))
+		}
+		d.Info(fmt.Sprintf("Split of %s declined", proposal.PRDID))
+		return
+	}
+
+	ids, err := planner.ApplySplit(cwd, proposal, iteration)
+	if err != nil {
+		d.Warning(fmt.Sprintf("Failed to split %s: %v", proposal.PRDID, err))
+		return
+	}
+	d.Success(fmt.Sprintf("Split %s into %s", proposal.PRDID, strings.Join(ids, ", ")))
+}
+
+// trackActivePRD keeps the sticky "Working on" line in step with prd.json
+// The line follows the active PRD within scope, stays while that PRD awaits
+// review, and clears once it completes or leaves the pipeline.
+func trackActivePRD(d *display.Display, prdFile *prd.PRDFileData, scope prd.Scope) {
+	if active := prdFile.GetActivePRDsIn(scope.Work()); len(active) > 0 {
+		d.SetActivePRD(active[0].ID)
+		return
+	}
+	if id := d.ActivePRDID(); id != "" {
+		if p := prdFile.FindByID(id); p == nil || !p.Passes.IsPending() {
+			d.SetActivePRD("")
+		}
+	}
+}
+
+// escalateRejected moves PRDs rejected too often to the escalation model
+func escalateRejected(d *display.Display, cwd string, prdFile *prd.PRDFileData, cfg *config.Config) {
+	model := cfg.Escalation.EscalatedModel()
+	escalated := prdFile.Escalate(cfg.Escalation.AfterRejections, model)
+	if len(escalated) == 0 {
+		return
+	}
+	if err := prd.Save(cwd, prdFile); err != nil {
+		d.Warning(fmt.Sprintf("Failed to record escalation: %v", err))
+		return
+	}
+	for _, id := range escalated {
+		d.Warning(fmt.Sprintf("Escalated %s to %s after %d rejections", id, model, cfg.Escalation.AfterRejections))
+	}
+}
+
+// loadSeedPrompt resolves --seed-prompt to the directive text
+// An existing file is read; a value that looks like a path (a single word
+// with a slash or file extension) must exist. Anything else is literal text.
+func loadSeedPrompt(value string) (string, error) {
+	if info, err := os.Stat(value); err == nil && !info.IsDir() {
+		content, err := prd.ReadTextFile(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to read seed prompt: %w", err)
+		}
+		value = content
+	} else if !strings.ContainsAny(value, " \t\n") &&
+		(strings.ContainsRune(value, os.PathSeparator) || len(filepath.Ext(value)) > 1) {
+		return "", fmt.Errorf("seed prompt file not found: %s", value)
+	}
+
+	directive := strings.TrimSpace(value)
+	if directive == "" {
+		return "", fmt.Errorf("seed prompt is empty")
+	}
+	return directive, nil
+}
+
+// applyDisplayConfig applies configured display settings to d and to every
+// display created afterwards, e.g. the streaming output of each phase
+func applyDisplayConfig(d *display.Display, cfg *config.Config) {
+	var loc *time.Location
+	if cfg.Display.UTC {
+		loc = time.UTC
+	}
+	display.SetTimestampDefaults(cfg.Display.TimestampFormat, loc)
+	d.SetTimestampFormat(cfg.Display.TimestampFormat)
+	d.SetTimezone(loc)
+
+	display.SetPreserveCodeDefault(cfg.Display.PreserveCode)
+	d.SetPreserveCode(cfg.Display.PreserveCode)
+
+	// A verbosity flag replaces whichever verbosity the config chose
+	if rootCmd.PersistentFlags().Changed("compact") {
+		cfg.Display.Compact = compactFlag
+		cfg.Display.Full = cfg.Display.Full && !compactFlag
+	}
+	if rootCmd.PersistentFlags().Changed("full") {
+		cfg.Display.Full = fullFlag
+		cfg.Display.Compact = cfg.Display.Compact && !fullFlag
+	}
+	if rootCmd.PersistentFlags().Changed("markdown") {
+		cfg.Display.Markdown = markdownFlag
+		cfg.Display.Compact = cfg.Display.Compact && !markdownFlag
+	}
+	// Markdown is rendered in full; compact output has no room for it
+	if cfg.Display.Markdown && !cfg.Display.Compact {
+		cfg.Display.Full = true
+	}
+	display.SetCompactDefault(cfg.Display.Compact)
+	d.SetCompact(cfg.Display.Compact)
+	display.SetFullDefault(cfg.Display.Full)
+	d.SetFull(cfg.Display.Full)
+	display.SetMarkdownDefault(cfg.Display.Markdown)
+	d.SetMarkdown(cfg.Display.Markdown)
+
+	// Mask secrets in agent output; the config was validated, so the
+	// patterns compile
+	if redactor, err := cfg.Redact.Redactor(); err == nil {
+		llm.SetRedactorDefault(redactor)
+	}
+}
+
+// reloadWithHistory reloads prd.json and records any status changes made
+// since before in each PRD's history, saving the file if anything changed
+func reloadWithHistory(cwd string, before *prd.PRDFileData, iteration int) (*prd.PRDFileData, error) {
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	if prdFile.RecordChanges(before, iteration) {
+		if err := prd.Save(cwd, prdFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return prdFile, nil
+}
+
+// logEvent appends to the event log, warning rather than failing the run
+func logEvent(d *display.Display, evlog *events.Log, e events.Event) {
+	if err := evlog.Append(e); err != nil {
+		d.Warning(fmt.Sprintf("Failed to write event log: %v", err))
+	}
+}
+
+// signalTypes returns the type of each signal, in order
+func signalTypes(signals []llm.Signal) []string {
+	types := make([]string, 0, len(signals))
+	for _, s := range signals {
+		types = append(types, s.Type)
+	}
+	return types
+}
+
+// checkAPIKey fails fast when config.yaml names an API key source that
+// yields no key, rather than letting the first agent phase fail
+func checkAPIKey(d *display.Display, cfg *config.Config) error {
+	if _, err := cfg.Auth.APIKey(); err != nil {
+		d.Error(fmt.Sprintf("No API key: %v", err))
+		d.Info("Provide the key, or remove auth from config.yaml to use the claude CLI's own login")
+		return fmt.Errorf("no API key: %w", err)
+	}
 	return nil
 }
+
+// warnUnmatchedPRDModels flags prdModels entries that match no PRD in
+// prd.json, which are usually typos or leftovers from removed PRDs
+func warnUnmatchedPRDModels(d *display.Display, cwd string, cfg *config.Config) {
+	if len(cfg.PRDModels) == 0 {
+		return
+	}
+	prdFile, err := prd.Load(cwd)
+	if err != nil {
+		return
+	}
+	ids := make([]string, len(prdFile.PRDs))
+	for i, p := range prdFile.PRDs {
+		ids[i] = p.ID
+	}
+	for _, key := range cfg.UnmatchedPRDModels(ids) {
+		d.Warning(fmt.Sprintf("prdModels: '%s' matches no PRD in %s", key, prd.PRDFile))
+	}
+}
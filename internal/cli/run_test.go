@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/builder"
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/planner"
+	"github.com/daydemir/milhouse/internal/prd"
+	"github.com/daydemir/milhouse/internal/reviewer"
+)
+
+func TestLoadSeedPrompt(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "directive.md")
+	if err := os.WriteFile(file, []byte("Prioritize test coverage\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "literal text", value: "  prioritize test coverage this time ", want: "prioritize test coverage this time"},
+		{name: "file", value: file, want: "Prioritize test coverage"},
+		{name: "missing file", value: filepath.Join(dir, "missing.md"), wantErr: true},
+		{name: "missing bare file name", value: "notes.txt", wantErr: true},
+		{name: "blank", value: "   ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadSeedPrompt(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// A status term in --prd-filter picks which PRDs the run starts; it must not
+// drop a PRD from the builder and reviewer once the planner activates it
+func TestRunPhasesWithStatusFilter(t *testing.T) {
+	filter, err := prd.ParseFilter("priority<=2 && status=open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Run.Filter = filter
+
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{
+		{ID: "auth", Priority: 1},
+		{ID: "docs", Priority: 2},
+		{ID: "ops", Priority: 3},
+	}}
+	for i := range prdFile.PRDs {
+		prdFile.PRDs[i].Passes.SetFalse()
+	}
+	transition := func(id, to string) {
+		t.Helper()
+		if err := prdFile.FindByID(id).Transition(to, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if run, reason := planner.ShouldRunPlannerWithReason(prdFile, cfg.Run.Scope()); !run {
+		t.Fatalf("planner skipped with open PRDs in scope: %s", reason)
+	}
+	transition("auth", prd.StatusActive)
+
+	// The planned PRD is built next, and the planner waits for it
+	if run, reason := builder.ShouldRunBuilderWithReason(prdFile, cfg.Run.Scope().Work()); !run {
+		t.Errorf("builder skipped the active PRD: %s", reason)
+	}
+	if p := builder.SelectActive(prdFile, "", cfg.Run.Scope().Work()); p == nil || p.ID != "auth" {
+		t.Errorf("SelectActive() = %v, want auth", p)
+	}
+	if run, reason := planner.ShouldRunPlannerWithReason(prdFile, cfg.Run.Scope()); run || reason != "active PRD auth must be built first" {
+		t.Errorf("planner with auth active = %v, %q; want it to wait", run, reason)
+	}
+	if active := prdFile.Group().ActiveIn(cfg.Run.Scope().Work()); len(active) != 1 {
+		t.Errorf("run loop sees %d active PRD(s) in scope, want 1", len(active))
+	}
+
+	// The built PRD is reviewed
+	transition("auth", prd.StatusPending)
+	if run, reason := reviewer.ShouldRunReviewerWithReason(prdFile, cfg.Run.Scope()); !run || reason != "1 pending PRD(s) to verify" {
+		t.Errorf("reviewer with auth pending = %v, %q", run, reason)
+	}
+
+	// PRDs the filter never allowed stay out when they are in progress
+	transition("ops", prd.StatusActive)
+	if p := builder.SelectActive(prdFile, "", cfg.Run.Scope().Work()); p != nil {
+		t.Errorf("SelectActive() = %s, want nothing outside priority<=2", p.ID)
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/events"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize metrics from past runs",
+	Long: `Aggregate metrics across all runs recorded in .milhouse/events.ndjson:
+iterations, PRDs completed, average tokens per phase, reviewer rejection
+rate, and average iterations from planning to verification.`,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	d := display.NewWithOptions(GetNoColor())
+
+	if !prd.MillhouseExists(cwd) {
+		d.Error(".milhouse/ directory not found")
+		d.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	evts, skipped, err := events.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		d.Warning(fmt.Sprintf("Skipped %d malformed line(s) in event log", skipped))
+	}
+	if len(evts) == 0 {
+		d.Info("No runs recorded yet")
+		d.Info("Run 'mil run N' to start collecting metrics")
+		return nil
+	}
+
+	stats := events.Summarize(evts)
+
+	d.Header("Milhouse Stats")
+	d.Stat("Runs", fmt.Sprintf("%d", stats.Runs))
+	d.Stat("Iterations", fmt.Sprintf("%d", stats.Iterations))
+	d.Stat("PRDs completed", fmt.Sprintf("%d", stats.Completed))
+	d.Stat("Rejections", fmt.Sprintf("%d (%.0f%% of verdicts)", stats.Rejected, stats.RejectionRate()*100))
+	if stats.CompletedPRDs > 0 {
+		d.Stat("Avg iterations to complete", fmt.Sprintf("%.1f", stats.AvgIterationsToComplete()))
+	}
+
+	d.SubHeader("Tokens by Phase")
+	for _, phase := range []string{"planner", "builder", "reviewer"} {
+		if stats.PhaseRuns[phase] == 0 {
+			continue
+		}
+		d.Stat(phase, fmt.Sprintf("%.1fK avg over %d runs (%.1fK total)",
+			float64(stats.AvgTokens(phase))/1000,
+			stats.PhaseRuns[phase],
+			float64(stats.PhaseTokens[phase])/1000))
+	}
+
+	if len(stats.PhaseTimed) > 0 {
+		d.SubHeader("Time by Phase")
+		for _, phase := range []string{"planner", "builder", "reviewer"} {
+			if stats.PhaseTimed[phase] == 0 {
+				continue
+			}
+			d.Stat(phase, fmt.Sprintf("%s avg over %d runs (%s total)",
+				display.FormatDuration(stats.AvgDuration(phase)),
+				stats.PhaseTimed[phase],
+				display.FormatDuration(stats.PhaseTime[phase])))
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -11,7 +12,12 @@ import (
 	"github.com/daydemir/milhouse/internal/prd"
 )
 
-var verboseFlag bool
+var (
+	verboseFlag      bool
+	statusOwnerFlag  string
+	statusOldestFlag bool
+	statusFilterFlag string
+)
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -22,6 +28,9 @@ var statusCmd = &cobra.Command{
 
 func init() {
 	statusCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show full PRD details")
+	statusCmd.Flags().StringVar(&statusOwnerFlag, "owner", "", "Only show PRDs owned by this person (or unowned)")
+	statusCmd.Flags().StringVar(&statusFilterFlag, "prd-filter", "", "Only show PRDs matching this `expression`, e.g. 'status=open && age>7d'")
+	statusCmd.Flags().BoolVar(&statusOldestFlag, "oldest", false, "List open PRDs oldest first, with how long each has been open")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -41,22 +50,40 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load PRDs: %w", err)
 	}
+	if statusOwnerFlag != "" {
+		prdFile.PRDs = prdFile.GetPRDsByOwner(statusOwnerFlag)
+	}
+	if statusFilterFlag != "" {
+		filter, err := prd.ParseFilter(statusFilterFlag)
+		if err != nil {
+			return err
+		}
+		prdFile.PRDs = filter.Apply(prdFile.PRDs, time.Now())
+	}
 
-	open := prdFile.GetOpenPRDs()
-	pending := prdFile.GetPendingPRDs()
-	complete := prdFile.GetCompletePRDs()
+	groups := prdFile.Group()
+	open, pending, complete, blocked := groups.Open, groups.Pending, groups.Complete, groups.Blocked
 
 	// Sort each by priority
 	sort.Slice(open, func(i, j int) bool { return open[i].Priority < open[j].Priority })
 	sort.Slice(pending, func(i, j int) bool { return pending[i].Priority < pending[j].Priority })
 	sort.Slice(complete, func(i, j int) bool { return complete[i].Priority < complete[j].Priority })
 
+	if len(prdFile.PRDs) == 0 && statusFilterFlag != "" {
+		display.Info(fmt.Sprintf("No PRDs match %q", statusFilterFlag))
+		return nil
+	}
 	if len(prdFile.PRDs) == 0 {
 		display.Info("No PRDs defined yet")
 		display.Info("Run 'mil chat' to add PRDs")
 		return nil
 	}
 
+	if statusOldestFlag {
+		showOldest(open)
+		return nil
+	}
+
 	// Verbose mode shows full details
 	if verboseFlag {
 		display.Header("Milhouse Status")
@@ -77,6 +104,14 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		// Show blocked PRDs
+		if len(blocked) > 0 {
+			display.SubHeader(fmt.Sprintf("Needs Refinement (%d)", len(blocked)))
+			for _, p := range blocked {
+				display.PRDStatus(p)
+			}
+		}
+
 		// Show complete PRDs
 		if len(complete) > 0 {
 			display.SubHeader(fmt.Sprintf("Complete (%d)", len(complete)))
@@ -117,6 +152,14 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		// Show blocked PRDs (compact)
+		if len(blocked) > 0 {
+			fmt.Println("\nNeeds Refinement:")
+			for _, p := range blocked {
+				display.PRDStatusCompact(p)
+			}
+		}
+
 		// Show complete PRDs (compact)
 		if len(complete) > 0 {
 			fmt.Println("\nComplete:")
@@ -140,3 +183,17 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// showOldest lists open PRDs oldest first so neglected work stands out
+// PRDs created before ages were recorded are listed last as unknown.
+func showOldest(open []prd.PRD) {
+	if len(open) == 0 {
+		display.Info("No open PRDs")
+		return
+	}
+	prd.SortByAge(open)
+	display.SubHeader(fmt.Sprintf("Open by Age (%d)", len(open)))
+	for _, p := range open {
+		display.PRDAge(p)
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/planner"
+	"github.com/daydemir/milhouse/internal/prd"
+	"github.com/daydemir/milhouse/internal/reviewer"
+)
+
+// stepper pauses mil run --step after each phase so a person can look at
+// what it did before the next phase starts
+type stepper struct {
+	in       *bufio.Reader // nil when not pausing
+	cwd      string
+	baseline string // HEAD when the iteration started, for listing its changes
+}
+
+// newStepper returns a stepper that pauses when enabled and stdin is a
+// terminal; without one --step is ignored with a warning
+func newStepper(d *display.Display, cwd string, enabled bool) *stepper {
+	s := &stepper{cwd: cwd}
+	if !enabled {
+		return s
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		d.Warning("--step needs a terminal to pause on - running without pauses")
+		return s
+	}
+	s.in = bufio.NewReader(os.Stdin)
+	return s
+}
+
+// startIteration records where the iteration began
+func (s *stepper) startIteration() {
+	if s.in == nil {
+		return
+	}
+	s.baseline, _ = git.RevParse(s.cwd, "HEAD")
+}
+
+// pause shows a one-line summary of the phase that just ran and waits for
+// the user; it returns false when they abort the run
+// Inspecting shows prdID in detail, its plan after the planner, and the files
+// changed so far this iteration, then asks again.
+func (s *stepper) pause(d *display.Display, phase, summary, prdID string) bool {
+	if s.in == nil {
+		return true
+	}
+	d.SectionBreak()
+	d.Info(fmt.Sprintf("Step: %s done - %s", phase, summary))
+	for {
+		// An empty answer continues and end of input aborts
+		switch askChoice(s.in, d.Out(), []string{"continue", "inspect", "abort"}, "continue") {
+		case "continue":
+			return true
+		case "inspect":
+			s.inspect(d, phase, prdID)
+		default:
+			return false
+		}
+	}
+}
+
+// inspect prints what a phase left behind
+func (s *stepper) inspect(d *display.Display, phase, prdID string) {
+	if prdFile, err := prd.Load(s.cwd); err != nil {
+		d.Warning(fmt.Sprintf("Failed to load PRDs: %v", err))
+	} else if p := prdFile.FindByID(prdID); p != nil {
+		_, evErr := os.Stat(prd.GetEvidencePath(s.cwd, p.ID))
+		d.PRDDetail(*p, prd.PlanExists(s.cwd, p.ID), evErr == nil)
+	}
+
+	if phase == "planner" && prdID != "" {
+		d.SubHeader("Plan")
+		if content, err := prd.ReadTextFile(prd.GetPlanPath(s.cwd, prdID)); err != nil {
+			d.Warning(fmt.Sprintf("No plan to show: %v", err))
+		} else {
+			printHead(d, content, prd.GetPlanPath(s.cwd, prdID))
+		}
+	}
+
+	d.SubHeader("Changes This Iteration")
+	var changed []string
+	if s.baseline != "" {
+		changed, _ = git.GetChangedFilesSince(s.cwd, s.baseline)
+	}
+	uncommitted, _ := git.UncommittedChanges(s.cwd)
+	if len(changed) == 0 && len(uncommitted) == 0 {
+		d.Info("  No changes")
+	}
+	for _, f := range changed {
+		fmt.Fprintf(d.Out(), "  %s (committed)\n", f)
+	}
+	for _, f := range uncommitted {
+		fmt.Fprintf(d.Out(), "  %s\n", f)
+	}
+}
+
+// printHead prints the first maxEvidenceLines lines of content from path
+func printHead(d *display.Display, content, path string) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	for _, line := range lines[:min(len(lines), maxEvidenceLines)] {
+		fmt.Fprintf(d.Out(), "  %s\n", line)
+	}
+	if len(lines) > maxEvidenceLines {
+		d.Info(fmt.Sprintf("  ... %d more lines in %s", len(lines)-maxEvidenceLines, path))
+	}
+}
+
+// plannerStepSummary describes a planner run for a --step pause
+func plannerStepSummary(r *planner.PlannerResult) string {
+	switch {
+	case r.Skipped:
+		return "skipped: " + r.SkipReason
+	case r.PRDID == "":
+		return "no plan written"
+	case r.Approach != nil:
+		return fmt.Sprintf("planned %s: %s", r.PRDID, display.Truncate(r.Approach.Summary, 80))
+	default:
+		return "planned " + r.PRDID
+	}
+}
+
+// statusStepSummary describes where the builder left its PRD
+func statusStepSummary(prdFile *prd.PRDFileData, id string) string {
+	p := prdFile.FindByID(id)
+	if p == nil {
+		return "no active PRD was built"
+	}
+	return fmt.Sprintf("%s is now %s", id, p.Passes)
+}
+
+// reviewerStepSummary describes a reviewer run's verdicts
+func reviewerStepSummary(r *reviewer.ReviewerResult) string {
+	if r == nil {
+		return "the reviewer failed"
+	}
+	return fmt.Sprintf("%d verified, %d rejected, %d held", len(r.Verified), len(r.Rejected), len(r.Held))
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestStepperPause(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir, prd.PlansDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := &prd.PRDFileData{PRDs: []prd.PRD{{ID: "a-1", Description: "Add login"}}}
+	data.PRDs[0].Passes.SetActive()
+	if err := prd.Save(dir, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prd.GetPlanPath(dir, "a-1"), []byte("# Plan\nStep one: add the handler\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	d := display.NewWithOptions(true)
+	d.SetOutput(&out, &out)
+
+	s := &stepper{in: bufio.NewReader(strings.NewReader("i\nc\n")), cwd: dir}
+	if !s.pause(d, "planner", "planned a-1", "a-1") {
+		t.Fatal("pause() aborted after inspect and continue")
+	}
+	for _, want := range []string{"Step: planner done - planned a-1", "Add login", "Step one: add the handler"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("pause output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	s.in = bufio.NewReader(strings.NewReader("a\n"))
+	if s.pause(d, "builder", "a-1 is now pending", "a-1") {
+		t.Error("pause() continued after abort")
+	}
+
+	// Without a terminal there is nothing to pause on
+	if !(&stepper{cwd: dir}).pause(d, "builder", "", "") {
+		t.Error("disabled stepper aborted")
+	}
+}
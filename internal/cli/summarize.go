@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/prd"
+	"github.com/daydemir/milhouse/internal/summarizer"
+)
+
+var (
+	summarizeKeepFlag  int
+	summarizeModelFlag string
+)
+
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize",
+	Short: "Condense progress.md into codebase patterns and learnings",
+	Long: `Condense the older entries of progress.md into a compact
+"Codebase Patterns and Learnings" section.
+
+The most recent entries are kept verbatim and the original file is archived
+under .milhouse/archive/. Set summarize.maxBytes in config.yaml to run this
+automatically before an iteration once progress.md grows past that size.`,
+	Args: cobra.NoArgs,
+	RunE: runSummarize,
+}
+
+func init() {
+	summarizeCmd.Flags().IntVar(&summarizeKeepFlag, "keep", 0, fmt.Sprintf("Recent entries to keep verbatim (default %d)", config.DefaultKeepEntries))
+	summarizeCmd.Flags().StringVar(&summarizeModelFlag, "model", "", "Override summarizer model (haiku, sonnet, opus)")
+	rootCmd.AddCommand(summarizeCmd)
+}
+
+func runSummarize(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	d := display.NewWithOptions(GetNoColor())
+
+	if !prd.MillhouseExists(cwd) {
+		d.Error(".milhouse/ directory not found")
+		d.Info("Run 'mil init' to initialize")
+		return fmt.Errorf("not initialized")
+	}
+
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		d.Warning(fmt.Sprintf("Failed to load config: %v, using defaults", err))
+		cfg = config.DefaultConfig()
+	}
+
+	applyDisplayConfig(d, cfg)
+
+	if summarizeKeepFlag < 0 {
+		return fmt.Errorf("--keep must not be negative")
+	}
+	if summarizeKeepFlag > 0 {
+		cfg.Summarize.KeepEntries = summarizeKeepFlag
+	}
+	if summarizeModelFlag != "" {
+		cfg.Summarize.Model = summarizeModelFlag
+	}
+	if err := cfg.Validate(); err != nil {
+		d.Error(fmt.Sprintf("Invalid configuration from CLI flags: %v", err))
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := checkAPIKey(d, cfg); err != nil {
+		return err
+	}
+
+	d.Header("Milhouse Summarize")
+	_, err = summarizeProgress(context.Background(), d, cwd, cfg)
+	return err
+}
+
+// summarizeProgress runs the summarizer and reports what it did
+func summarizeProgress(ctx context.Context, d *display.Display, cwd string, cfg *config.Config) (*summarizer.SummarizerResult, error) {
+	result, err := summarizer.Run(ctx, cwd, cfg)
+	if err != nil {
+		d.Error(fmt.Sprintf("Summarizer error: %v", err))
+		return result, fmt.Errorf("summarize failed: %w", err)
+	}
+
+	if result.Summarized == 0 {
+		d.Info(fmt.Sprintf("Nothing to summarize: progress.md has %d entries, keeping %d", result.Kept, cfg.Summarize.Keep()))
+		return result, nil
+	}
+	d.Success(fmt.Sprintf("Condensed %d entries into learnings, kept %d recent (%d → %d bytes)",
+		result.Summarized, result.Kept, result.BytesBefore, result.BytesAfter))
+	d.Info(fmt.Sprintf("Original archived to %s", result.ArchivePath))
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+)
+
+// rateLimiter spaces out phases after API rate limits
+// It is a simple AIMD controller: each rate-limited phase multiplies the
+// delay (starting from the backoff), each clean phase subtracts the recovery
+// step until the delay is back to zero.
+type rateLimiter struct {
+	cfg   config.RateLimitConfig
+	delay time.Duration // Current wait before each phase
+}
+
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	return &rateLimiter{cfg: cfg}
+}
+
+// Delay returns the current wait before each phase
+func (r *rateLimiter) Delay() time.Duration {
+	return r.delay
+}
+
+// Observe updates the delay after a phase
+func (r *rateLimiter) Observe(rateLimited bool) {
+	if rateLimited {
+		if r.delay == 0 {
+			r.delay = time.Duration(r.cfg.BackoffSeconds) * time.Second
+		} else {
+			r.delay = time.Duration(float64(r.delay) * max(r.cfg.Multiplier, 1))
+		}
+		if limit := time.Duration(r.cfg.MaxDelaySeconds) * time.Second; limit > 0 && r.delay > limit {
+			r.delay = limit
+		}
+		return
+	}
+
+	r.delay = max(r.delay-time.Duration(r.cfg.RecoverySeconds)*time.Second, 0)
+}
+
+// Wait sleeps for the current delay, returning early if ctx is cancelled
+func (r *rateLimiter) Wait(ctx context.Context, d *display.Display) error {
+	if r.delay <= 0 {
+		return nil
+	}
+
+	d.Info(fmt.Sprintf("Throttling: waiting %s before the next phase", r.delay))
+	timer := time.NewTimer(r.delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observePhase feeds a phase outcome to the limiter and reports changes
+func observePhase(d *display.Display, limiter *rateLimiter, rateLimited bool, err error) {
+	rateLimited = rateLimited || errors.Is(err, llm.ErrRateLimited)
+	before := limiter.Delay()
+	limiter.Observe(rateLimited)
+
+	switch after := limiter.Delay(); {
+	case rateLimited:
+		d.Warning(fmt.Sprintf("Rate limited: spacing phases by %s", after))
+	case before > 0 && after == 0:
+		d.Info("Rate limit backoff cleared")
+	}
+}
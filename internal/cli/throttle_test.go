@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/config"
+)
+
+func TestRateLimiterAIMD(t *testing.T) {
+	limiter := newRateLimiter(config.RateLimitConfig{
+		BackoffSeconds:  10,
+		MaxDelaySeconds: 35,
+		Multiplier:      2,
+		RecoverySeconds: 15,
+	})
+
+	steps := []struct {
+		rateLimited bool
+		want        time.Duration
+	}{
+		{false, 0},
+		{true, 10 * time.Second},
+		{true, 20 * time.Second},
+		{true, 35 * time.Second}, // capped
+		{false, 20 * time.Second},
+		{false, 5 * time.Second},
+		{false, 0},
+		{true, 10 * time.Second}, // starts over from the backoff
+	}
+	for i, step := range steps {
+		limiter.Observe(step.rateLimited)
+		if got := limiter.Delay(); got != step.want {
+			t.Fatalf("step %d (rateLimited=%v): delay = %s, want %s", i, step.rateLimited, got, step.want)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/llm"
 )
 
 // Version info - set via ldflags at build time
@@ -13,13 +18,30 @@ var (
 	BuildDate = "unknown"
 )
 
+// claudeVersionTimeout bounds how long mil version waits on claude --version
+const claudeVersionTimeout = 10 * time.Second
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
+	Long: `Print the Milhouse version, the commit and date it was built from, the Go
+version and platform, and the version of the claude CLI it will run.
+
+Include this output in bug reports.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Printf("mil %s\n", Version)
 		fmt.Printf("  commit: %s\n", GitCommit)
 		fmt.Printf("  built:  %s\n", BuildDate)
+		fmt.Printf("  go:     %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), claudeVersionTimeout)
+		defer cancel()
+		claude := llm.NewClaude("", "")
+		if version, err := claude.Version(ctx); err != nil {
+			fmt.Printf("  claude: not available (%s)\n", claude.BinaryPath)
+		} else {
+			fmt.Printf("  claude: %s (%s)\n", version, claude.BinaryPath)
+		}
 	},
 }
 
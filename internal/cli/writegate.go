@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/daydemir/milhouse/internal/llm"
+)
+
+// writeGateCmd is the PreToolUse hook claude runs before each mutating tool
+// while confirmBeforeWrite is waiting on the user; see llm.StartWriteGateHook
+var writeGateCmd = &cobra.Command{
+	Use:    llm.WriteGateHookCommand + " SOCKET",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	// Runs inside claude: skip the root's config and output setup
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(llm.RunWriteGateHook(args[0], os.Stdin, os.Stderr))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(writeGateCmd)
+}
@@ -3,10 +3,18 @@ package config
 import (
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
 )
 
 const (
@@ -27,6 +35,12 @@ const (
 	MinProgressLines = 10
 	MaxProgressLines = 1000
 
+	// Smallest PRD description or notes cap, in characters
+	MinPRDTextLimit = 100
+
+	// Most repositories mil batch run may work on at once
+	MaxBatchConcurrency = 8
+
 	// Reviewer prompt modes
 	ReviewerPromptModeStandard   = "standard"
 	ReviewerPromptModeEnhanced   = "enhanced"
@@ -34,20 +48,39 @@ const (
 
 	// Prompt file size limit
 	MaxPromptFileSize = 10240 // 10KB
+
+	// Hook command timeout (seconds)
+	DefaultHookTimeout = 600
+
+	// DefaultSignalHookTimeout is the timeout in seconds for signal hook commands
+	DefaultSignalHookTimeout = 30
 )
 
 // PhaseConfig represents configuration for a specific phase (planner, builder, reviewer)
 type PhaseConfig struct {
-	Model              string `yaml:"model,omitempty"`
-	MaxTokens          int    `yaml:"maxTokens,omitempty"`
-	ProgressLines      int    `yaml:"progressLines,omitempty"`
-	ReviewerPromptMode string `yaml:"reviewerPromptMode,omitempty"`
+	Model              string                   `yaml:"model,omitempty"`
+	MaxTokens          int                      `yaml:"maxTokens,omitempty"`
+	ProgressLines      int                      `yaml:"progressLines,omitempty"`
+	ReviewerPromptMode string                   `yaml:"reviewerPromptMode,omitempty"`
+	RequirePushed      bool                     `yaml:"requirePushed,omitempty"`      // Reviewer: reject work not pushed to upstream
+	ReviewNotes        bool                     `yaml:"reviewNotes,omitempty"`        // Reviewer: append each verdict to the PRD's evidence file
+	ConfirmSplit       bool                     `yaml:"confirmSplit,omitempty"`       // Planner: ask before splitting a PRD via SPLIT
+	ConfirmBeforeWrite bool                     `yaml:"confirmBeforeWrite,omitempty"` // Builder: ask before its first change (interactive runs only)
+	SaveHistory        bool                     `yaml:"saveHistory,omitempty"`        // Chat: save each session's transcript to chat-history/
+	AllowWrites        bool                     `yaml:"allowWrites,omitempty"`        // Custom: let mil x prompts edit files and run commands
+	ExtraArgs          []string                 `yaml:"extraArgs,omitempty"`          // Extra claude CLI arguments, passed verbatim
+	MCPServers         map[string]llm.MCPServer `yaml:"mcpServers,omitempty"`         // MCP servers the phase's agent may use, by name
+	MaxOutputTokens    int                      `yaml:"maxOutputTokens,omitempty"`    // Bail on generated tokens alone (0 = no limit)
+	IdleTimeout        int                      `yaml:"idleTimeout,omitempty"`        // Seconds without agent activity before the phase is cancelled (0 = off)
+	MaxRetainedOutput  int                      `yaml:"maxRetainedOutput,omitempty"`  // Bytes of agent output kept for the phase's result (0 = default, -1 = all)
 }
 
 // GlobalConfig represents global defaults applied to all phases
 type GlobalConfig struct {
-	Model     string `yaml:"model,omitempty"`
-	MaxTokens int    `yaml:"maxTokens,omitempty"`
+	Model             string `yaml:"model,omitempty"`
+	MaxTokens         int    `yaml:"maxTokens,omitempty"`
+	IdleTimeout       int    `yaml:"idleTimeout,omitempty"`       // Default idleTimeout for every phase
+	MaxRetainedOutput int    `yaml:"maxRetainedOutput,omitempty"` // Default maxRetainedOutput for every phase
 }
 
 // EarlyExitConfig controls early exit behavior when no work is being done
@@ -56,6 +89,255 @@ type EarlyExitConfig struct {
 	IdleThreshold int  `yaml:"idleIterationsThreshold"`
 }
 
+// HooksConfig configures shell commands run after the builder
+type HooksConfig struct {
+	BuildCommand          string `yaml:"buildCommand,omitempty"`          // e.g. "go build ./..."
+	BuildTimeout          int    `yaml:"buildTimeout,omitempty"`          // Seconds (DefaultHookTimeout if unset)
+	RequeueOnBuildFailure bool   `yaml:"requeueOnBuildFailure,omitempty"` // Keep the PRD active for another builder pass
+	TestCommand           string `yaml:"testCommand,omitempty"`           // e.g. "go test ./..."
+	TestTimeout           int    `yaml:"testTimeout,omitempty"`           // Seconds (DefaultHookTimeout if unset)
+
+	OnSignal []SignalHookConfig `yaml:"onSignal,omitempty"` // Commands run when agents emit signals
+
+	OnComplete        string `yaml:"onComplete,omitempty"`        // Command run when mil run finishes
+	OnCompleteTimeout int    `yaml:"onCompleteTimeout,omitempty"` // Seconds (DefaultHookTimeout if unset)
+	OnCompleteAlways  bool   `yaml:"onCompleteAlways,omitempty"`  // Also run onComplete when the run fails
+}
+
+// SignalHookConfig runs a command whenever a matching signal is emitted
+// The command receives the signal as JSON (events.SignalMessage) on stdin.
+type SignalHookConfig struct {
+	Signals []string `yaml:"signals,omitempty"` // Signal types to react to; empty means all
+	Command string   `yaml:"command"`
+	Timeout int      `yaml:"timeout,omitempty"` // Seconds (DefaultSignalHookTimeout if unset)
+}
+
+// RateLimitConfig tunes the backoff between phases after API rate limits
+// The delay grows multiplicatively on each rate limit and shrinks by a fixed
+// step after each phase that is not rate-limited (AIMD).
+type RateLimitConfig struct {
+	BackoffSeconds  int     `yaml:"backoffSeconds,omitempty"`  // Delay after the first rate limit
+	MaxDelaySeconds int     `yaml:"maxDelaySeconds,omitempty"` // Upper bound on the delay
+	Multiplier      float64 `yaml:"multiplier,omitempty"`      // Growth factor on further rate limits
+	RecoverySeconds int     `yaml:"recoverySeconds,omitempty"` // Delay removed after each clean phase
+}
+
+// EscalationConfig moves PRDs that keep getting rejected to a stronger model
+type EscalationConfig struct {
+	AfterRejections int    `yaml:"afterRejections,omitempty"` // Rejections before escalating; 0 disables
+	Model           string `yaml:"model,omitempty"`           // Builder model for escalated PRDs (default opus)
+}
+
+// SummarizeConfig controls condensing progress.md once it grows large
+type SummarizeConfig struct {
+	MaxBytes    int    `yaml:"maxBytes,omitempty"`    // Summarize before an iteration once progress.md exceeds this; 0 disables
+	KeepEntries int    `yaml:"keepEntries,omitempty"` // Recent entries kept verbatim (DefaultKeepEntries if unset)
+	Model       string `yaml:"model,omitempty"`       // Model for the summary (global model if unset)
+}
+
+// DefaultKeepEntries is how many recent progress entries survive a summary verbatim
+const DefaultKeepEntries = 10
+
+// Keep returns the number of recent entries to keep verbatim
+func (s SummarizeConfig) Keep() int {
+	if s.KeepEntries > 0 {
+		return s.KeepEntries
+	}
+	return DefaultKeepEntries
+}
+
+// PRDModels pins phase models for the PRDs a prdModels entry matches
+type PRDModels struct {
+	Planner  string `yaml:"planner,omitempty"`
+	Builder  string `yaml:"builder,omitempty"`
+	Reviewer string `yaml:"reviewer,omitempty"`
+}
+
+// model returns the pinned model for a phase, or ""
+func (m PRDModels) model(phase string) string {
+	switch phase {
+	case "planner":
+		return m.Planner
+	case "builder":
+		return m.Builder
+	case "reviewer":
+		return m.Reviewer
+	}
+	return ""
+}
+
+// ModelForPRD returns the model a phase should use while working on prdID
+// An exact prdModels key wins over glob patterns (path.Match syntax, e.g.
+// "auth-*"), which are tried in sorted order. Without a pin for the phase
+// the phase's configured model is used.
+func (c *Config) ModelForPRD(phase, prdID string) string {
+	if c != nil && prdID != "" {
+		if m := c.PRDModels[prdID].model(phase); m != "" {
+			return m
+		}
+		for _, pattern := range slices.Sorted(maps.Keys(c.PRDModels)) {
+			if matched, _ := path.Match(pattern, prdID); matched && pattern != prdID {
+				if m := c.PRDModels[pattern].model(phase); m != "" {
+					return m
+				}
+			}
+		}
+	}
+	return c.GetPhaseConfig(phase).Model
+}
+
+// UnmatchedPRDModels returns the prdModels keys that match none of ids, sorted
+func (c *Config) UnmatchedPRDModels(ids []string) []string {
+	var unmatched []string
+	for _, pattern := range slices.Sorted(maps.Keys(c.PRDModels)) {
+		if !slices.ContainsFunc(ids, func(id string) bool {
+			matched, _ := path.Match(pattern, id)
+			return matched
+		}) {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return unmatched
+}
+
+// EscalatedModel returns the model used for escalated PRDs
+func (e EscalationConfig) EscalatedModel() string {
+	if e.Model != "" {
+		return e.Model
+	}
+	return ModelOpus
+}
+
+// Definition-of-done checks a CompletionPolicy can require
+const (
+	CompletionCriteria  = "criteria"   // The PRD has acceptance criteria
+	CompletionChecks    = "checks"     // Recorded build and test hook results all passed
+	CompletionPushed    = "pushed"     // HEAD is on its upstream branch
+	CompletionEvidence  = "evidence"   // The builder's evidence file names a commit
+	CompletionCommit    = "commit"     // Every commit named in the evidence exists
+	CompletionCleanTree = "clean-tree" // No uncommitted changes outside .milhouse/
+)
+
+// AllCompletionChecks lists every check a CompletionPolicy can require, in
+// the order they are evaluated
+var AllCompletionChecks = []string{
+	CompletionCriteria, CompletionChecks, CompletionPushed,
+	CompletionEvidence, CompletionCommit, CompletionCleanTree,
+}
+
+// CompletionPolicy is the definition of done: checks run in Go, independent
+// of the reviewer agent, that a verified PRD must pass to become complete
+type CompletionPolicy struct {
+	Require []string `yaml:"require"` // Checks that must pass; an empty list trusts the reviewer alone
+}
+
+// Requires reports whether the policy includes check
+func (c CompletionPolicy) Requires(check string) bool {
+	return slices.Contains(c.Require, check)
+}
+
+// DisplayConfig controls how terminal output is formatted
+type DisplayConfig struct {
+	TimestampFormat string `yaml:"timestampFormat,omitempty"` // Go time layout (default "15:04:05")
+	UTC             bool   `yaml:"utc,omitempty"`             // Print timestamps in UTC instead of local time
+	PreserveCode    bool   `yaml:"preserveCode,omitempty"`    // Keep code blocks in agent output verbatim
+	Compact         bool   `yaml:"compact,omitempty"`         // One line per agent message
+	Full            bool   `yaml:"full,omitempty"`            // Complete agent messages wrapped to the terminal
+	Markdown        bool   `yaml:"markdown,omitempty"`        // Render headers, lists and code in agent messages; implies full
+	Theme           string `yaml:"theme,omitempty"`           // Color preset: default, high-contrast, solarized, mono, no-color
+	PhaseSummary    bool   `yaml:"phaseSummary,omitempty"`    // Print a one-line recap after each agent phase
+}
+
+// RedactConfig controls masking of secrets in agent output before it is
+// shown or saved
+type RedactConfig struct {
+	Patterns        []string `yaml:"patterns,omitempty"`        // Extra regular expressions to mask
+	DisableDefaults bool     `yaml:"disableDefaults,omitempty"` // Skip llm.DefaultRedactPatterns
+}
+
+// Redactor compiles the configured patterns
+func (r RedactConfig) Redactor() (*llm.Redactor, error) {
+	return llm.NewRedactor(r.Patterns, !r.DisableDefaults)
+}
+
+// AuthConfig names where the Anthropic API key for agent runs comes from
+// With neither field set the claude CLI authenticates itself.
+type AuthConfig struct {
+	APIKeyEnv  string `yaml:"apiKeyEnv,omitempty"`  // Environment variable holding the key
+	APIKeyFile string `yaml:"apiKeyFile,omitempty"` // File holding the key; a leading ~/ is the home directory
+}
+
+// APIKey reads the configured key
+// It returns "" when no source is configured and an error when the source
+// is configured but yields no key.
+func (a AuthConfig) APIKey() (string, error) {
+	switch {
+	case a.APIKeyEnv != "":
+		key := strings.TrimSpace(os.Getenv(a.APIKeyEnv))
+		if key == "" {
+			return "", fmt.Errorf("auth.apiKeyEnv: environment variable %s is not set", a.APIKeyEnv)
+		}
+		return key, nil
+	case a.APIKeyFile != "":
+		path := a.APIKeyFile
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("auth.apiKeyFile: failed to locate home directory: %w", err)
+			}
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("auth.apiKeyFile: %w", err)
+		}
+		key := strings.TrimSpace(string(data))
+		if key == "" {
+			return "", fmt.Errorf("auth.apiKeyFile: %s is empty", path)
+		}
+		return key, nil
+	}
+	return "", nil
+}
+
+// PRDLimits caps the PRD text Millhouse writes to prd.json, in characters
+// Longer text is truncated with a marker: descriptions keep their start,
+// notes keep their newest end. 0, the default, leaves a field uncapped.
+type PRDLimits struct {
+	MaxDescription int `yaml:"maxDescription,omitempty"`
+	MaxNotes       int `yaml:"maxNotes,omitempty"`
+}
+
+// BatchConfig limits mil batch run, which runs several projects at once
+type BatchConfig struct {
+	MaxConcurrency int `yaml:"maxConcurrency,omitempty"` // Highest --concurrency allowed; keeps parallel runs under API rate limits
+}
+
+// RunOptions holds per-invocation settings from CLI flags
+// They are never read from or written to config.yaml.
+type RunOptions struct {
+	BuilderReadOnly bool        // Builder previews changes with read-only tools
+	Select          string      // PRD ID the planner must select, if open
+	ResumePRD       string      // Active PRD the builder must continue, from --resume-prd
+	NoAugmentation  bool        // Ignore .milhouse/prompts/ and use stock prompts only
+	Owner           string      // Only work on PRDs owned by this person (or unowned)
+	MaxPriority     int         // Only work on PRDs with priority <= MaxPriority; 0 means any
+	Filter          *prd.Filter // Only work on PRDs matching --prd-filter; nil means any
+	RunDirective    string      // One-time instruction from --seed-prompt, added to every phase prompt
+	Cooldown        []string    // Open PRDs the planner passes over this iteration after a recent rejection
+	ChatSessionID   string      // Session ID passed to claude by mil chat, for saving the transcript
+
+	// ConfirmWrite asks whether the builder may make its first change to a
+	// PRD's code when confirmBeforeWrite is set; nil when nobody can answer
+	ConfirmWrite func(prdID, tool string) bool
+}
+
+// Scope returns the PRD filter these options impose on the run
+// Active and pending PRDs use Scope().Work(), which drops the cooldown.
+func (r RunOptions) Scope() prd.Scope {
+	return prd.Scope{Owner: r.Owner, MaxPriority: r.MaxPriority, Skip: r.Cooldown, Filter: r.Filter}
+}
+
 // Config represents the entire configuration structure
 type Config struct {
 	Phases struct {
@@ -63,10 +345,25 @@ type Config struct {
 		Builder  PhaseConfig `yaml:"builder,omitempty"`
 		Reviewer PhaseConfig `yaml:"reviewer,omitempty"`
 		Chat     PhaseConfig `yaml:"chat,omitempty"`
+		Custom   PhaseConfig `yaml:"custom,omitempty"` // mil x prompts
 	} `yaml:"phases,omitempty"`
-	Global       GlobalConfig    `yaml:"global,omitempty"`
-	EarlyExit    EarlyExitConfig `yaml:"earlyExit,omitempty"`
-	ContextFiles []string        `yaml:"contextFiles,omitempty"`
+	Global          GlobalConfig         `yaml:"global,omitempty"`
+	EarlyExit       EarlyExitConfig      `yaml:"earlyExit,omitempty"`
+	ContextFiles    []string             `yaml:"contextFiles,omitempty"`
+	Hooks           HooksConfig          `yaml:"hooks,omitempty"`
+	Display         DisplayConfig        `yaml:"display,omitempty"`
+	Redact          RedactConfig         `yaml:"redact,omitempty"`
+	Escalation      EscalationConfig     `yaml:"escalation,omitempty"`
+	RateLimit       RateLimitConfig      `yaml:"rateLimit,omitempty"`
+	Summarize       SummarizeConfig      `yaml:"summarize,omitempty"`
+	Completion      CompletionPolicy     `yaml:"completion,omitempty"`
+	Auth            AuthConfig           `yaml:"auth,omitempty"`
+	PRDLimits       PRDLimits            `yaml:"prdLimits,omitempty"`
+	Batch           BatchConfig          `yaml:"batch,omitempty"`
+	PRDModels       map[string]PRDModels `yaml:"prdModels,omitempty"`       // PRD ID or glob pattern -> pinned phase models
+	OutputDir       string               `yaml:"outputDir,omitempty"`       // Where plans, evidence and logs live; "cache" for the user cache
+	RequireEvidence bool                 `yaml:"requireEvidence,omitempty"` // Keep PRDs active until the builder writes evidence
+	Run             RunOptions           `yaml:"-"`
 }
 
 // DefaultConfig returns the default configuration matching current hardcoded values
@@ -94,6 +391,10 @@ func DefaultConfig() *Config {
 		Model: ModelSonnet,
 		// No MaxTokens - chat runs in interactive mode without token limits
 	}
+	cfg.Phases.Custom = PhaseConfig{
+		Model:     ModelSonnet,
+		MaxTokens: 80000,
+	}
 
 	// Set global defaults
 	cfg.Global = GlobalConfig{
@@ -107,6 +408,24 @@ func DefaultConfig() *Config {
 		IdleThreshold: 2,
 	}
 
+	// A PRD needs criteria to verify against and passing post-build checks
+	cfg.Completion = CompletionPolicy{
+		Require: []string{CompletionCriteria, CompletionChecks},
+	}
+
+	// Two projects at a time rarely trips API rate limits
+	cfg.Batch = BatchConfig{
+		MaxConcurrency: 2,
+	}
+
+	// Set rate limit backoff defaults
+	cfg.RateLimit = RateLimitConfig{
+		BackoffSeconds:  30,
+		MaxDelaySeconds: 600,
+		Multiplier:      2,
+		RecoverySeconds: 15,
+	}
+
 	return cfg
 }
 
@@ -165,8 +484,10 @@ func mergeConfigs(base, override *Config) *Config {
 	// Create a new config, copying all values from base
 	result := &Config{
 		Global: GlobalConfig{
-			Model:     base.Global.Model,
-			MaxTokens: base.Global.MaxTokens,
+			Model:             base.Global.Model,
+			MaxTokens:         base.Global.MaxTokens,
+			IdleTimeout:       base.Global.IdleTimeout,
+			MaxRetainedOutput: base.Global.MaxRetainedOutput,
 		},
 		ContextFiles: make([]string, len(base.ContextFiles)),
 	}
@@ -177,6 +498,7 @@ func mergeConfigs(base, override *Config) *Config {
 	result.Phases.Builder = base.Phases.Builder
 	result.Phases.Reviewer = base.Phases.Reviewer
 	result.Phases.Chat = base.Phases.Chat
+	result.Phases.Custom = base.Phases.Custom
 
 	// Merge global config
 	if override.Global.Model != "" {
@@ -185,6 +507,12 @@ func mergeConfigs(base, override *Config) *Config {
 	if override.Global.MaxTokens != 0 {
 		result.Global.MaxTokens = override.Global.MaxTokens
 	}
+	if override.Global.IdleTimeout != 0 {
+		result.Global.IdleTimeout = override.Global.IdleTimeout
+	}
+	if override.Global.MaxRetainedOutput != 0 {
+		result.Global.MaxRetainedOutput = override.Global.MaxRetainedOutput
+	}
 
 	// Merge phase configs
 	if override.Phases.Planner.Model != "" {
@@ -193,9 +521,27 @@ func mergeConfigs(base, override *Config) *Config {
 	if override.Phases.Planner.MaxTokens != 0 {
 		result.Phases.Planner.MaxTokens = override.Phases.Planner.MaxTokens
 	}
+	if override.Phases.Planner.MaxOutputTokens != 0 {
+		result.Phases.Planner.MaxOutputTokens = override.Phases.Planner.MaxOutputTokens
+	}
 	if override.Phases.Planner.ProgressLines != 0 {
 		result.Phases.Planner.ProgressLines = override.Phases.Planner.ProgressLines
 	}
+	if override.Phases.Planner.IdleTimeout != 0 {
+		result.Phases.Planner.IdleTimeout = override.Phases.Planner.IdleTimeout
+	}
+	if override.Phases.Planner.MaxRetainedOutput != 0 {
+		result.Phases.Planner.MaxRetainedOutput = override.Phases.Planner.MaxRetainedOutput
+	}
+	if len(override.Phases.Planner.ExtraArgs) > 0 {
+		result.Phases.Planner.ExtraArgs = override.Phases.Planner.ExtraArgs
+	}
+	if len(override.Phases.Planner.MCPServers) > 0 {
+		result.Phases.Planner.MCPServers = override.Phases.Planner.MCPServers
+	}
+	if override.Phases.Planner.ConfirmSplit {
+		result.Phases.Planner.ConfirmSplit = true
+	}
 
 	if override.Phases.Builder.Model != "" {
 		result.Phases.Builder.Model = override.Phases.Builder.Model
@@ -203,9 +549,27 @@ func mergeConfigs(base, override *Config) *Config {
 	if override.Phases.Builder.MaxTokens != 0 {
 		result.Phases.Builder.MaxTokens = override.Phases.Builder.MaxTokens
 	}
+	if override.Phases.Builder.MaxOutputTokens != 0 {
+		result.Phases.Builder.MaxOutputTokens = override.Phases.Builder.MaxOutputTokens
+	}
 	if override.Phases.Builder.ProgressLines != 0 {
 		result.Phases.Builder.ProgressLines = override.Phases.Builder.ProgressLines
 	}
+	if override.Phases.Builder.IdleTimeout != 0 {
+		result.Phases.Builder.IdleTimeout = override.Phases.Builder.IdleTimeout
+	}
+	if override.Phases.Builder.MaxRetainedOutput != 0 {
+		result.Phases.Builder.MaxRetainedOutput = override.Phases.Builder.MaxRetainedOutput
+	}
+	if override.Phases.Builder.ConfirmBeforeWrite {
+		result.Phases.Builder.ConfirmBeforeWrite = true
+	}
+	if len(override.Phases.Builder.ExtraArgs) > 0 {
+		result.Phases.Builder.ExtraArgs = override.Phases.Builder.ExtraArgs
+	}
+	if len(override.Phases.Builder.MCPServers) > 0 {
+		result.Phases.Builder.MCPServers = override.Phases.Builder.MCPServers
+	}
 
 	if override.Phases.Reviewer.Model != "" {
 		result.Phases.Reviewer.Model = override.Phases.Reviewer.Model
@@ -213,18 +577,216 @@ func mergeConfigs(base, override *Config) *Config {
 	if override.Phases.Reviewer.MaxTokens != 0 {
 		result.Phases.Reviewer.MaxTokens = override.Phases.Reviewer.MaxTokens
 	}
+	if override.Phases.Reviewer.MaxOutputTokens != 0 {
+		result.Phases.Reviewer.MaxOutputTokens = override.Phases.Reviewer.MaxOutputTokens
+	}
 	if override.Phases.Reviewer.ProgressLines != 0 {
 		result.Phases.Reviewer.ProgressLines = override.Phases.Reviewer.ProgressLines
 	}
+	if override.Phases.Reviewer.IdleTimeout != 0 {
+		result.Phases.Reviewer.IdleTimeout = override.Phases.Reviewer.IdleTimeout
+	}
+	if override.Phases.Reviewer.MaxRetainedOutput != 0 {
+		result.Phases.Reviewer.MaxRetainedOutput = override.Phases.Reviewer.MaxRetainedOutput
+	}
+	if len(override.Phases.Reviewer.ExtraArgs) > 0 {
+		result.Phases.Reviewer.ExtraArgs = override.Phases.Reviewer.ExtraArgs
+	}
+	if len(override.Phases.Reviewer.MCPServers) > 0 {
+		result.Phases.Reviewer.MCPServers = override.Phases.Reviewer.MCPServers
+	}
 	if override.Phases.Reviewer.ReviewerPromptMode != "" {
 		result.Phases.Reviewer.ReviewerPromptMode = override.Phases.Reviewer.ReviewerPromptMode
 	}
+	if override.Phases.Reviewer.RequirePushed {
+		result.Phases.Reviewer.RequirePushed = true
+	}
+	if override.Phases.Reviewer.ReviewNotes {
+		result.Phases.Reviewer.ReviewNotes = true
+	}
 
 	if override.Phases.Chat.Model != "" {
 		result.Phases.Chat.Model = override.Phases.Chat.Model
 	}
+	if override.Phases.Chat.SaveHistory {
+		result.Phases.Chat.SaveHistory = true
+	}
+	if len(override.Phases.Chat.ExtraArgs) > 0 {
+		result.Phases.Chat.ExtraArgs = override.Phases.Chat.ExtraArgs
+	}
+	if len(override.Phases.Chat.MCPServers) > 0 {
+		result.Phases.Chat.MCPServers = override.Phases.Chat.MCPServers
+	}
 	// No MaxTokens or ProgressLines for chat (interactive mode)
 
+	if override.Phases.Custom.Model != "" {
+		result.Phases.Custom.Model = override.Phases.Custom.Model
+	}
+	if override.Phases.Custom.MaxTokens != 0 {
+		result.Phases.Custom.MaxTokens = override.Phases.Custom.MaxTokens
+	}
+	if override.Phases.Custom.MaxOutputTokens != 0 {
+		result.Phases.Custom.MaxOutputTokens = override.Phases.Custom.MaxOutputTokens
+	}
+	if override.Phases.Custom.IdleTimeout != 0 {
+		result.Phases.Custom.IdleTimeout = override.Phases.Custom.IdleTimeout
+	}
+	if override.Phases.Custom.MaxRetainedOutput != 0 {
+		result.Phases.Custom.MaxRetainedOutput = override.Phases.Custom.MaxRetainedOutput
+	}
+	if override.Phases.Custom.AllowWrites {
+		result.Phases.Custom.AllowWrites = true
+	}
+	if len(override.Phases.Custom.ExtraArgs) > 0 {
+		result.Phases.Custom.ExtraArgs = override.Phases.Custom.ExtraArgs
+	}
+	if len(override.Phases.Custom.MCPServers) > 0 {
+		result.Phases.Custom.MCPServers = override.Phases.Custom.MCPServers
+	}
+
+	// Merge hooks
+	result.Hooks = base.Hooks
+	if override.Hooks.BuildCommand != "" {
+		result.Hooks.BuildCommand = override.Hooks.BuildCommand
+	}
+	if override.Hooks.BuildTimeout != 0 {
+		result.Hooks.BuildTimeout = override.Hooks.BuildTimeout
+	}
+	if override.Hooks.RequeueOnBuildFailure {
+		result.Hooks.RequeueOnBuildFailure = true
+	}
+	if override.Hooks.TestCommand != "" {
+		result.Hooks.TestCommand = override.Hooks.TestCommand
+	}
+	if override.Hooks.TestTimeout != 0 {
+		result.Hooks.TestTimeout = override.Hooks.TestTimeout
+	}
+	if len(override.Hooks.OnSignal) > 0 {
+		result.Hooks.OnSignal = override.Hooks.OnSignal
+	}
+	if override.Hooks.OnComplete != "" {
+		result.Hooks.OnComplete = override.Hooks.OnComplete
+	}
+	if override.Hooks.OnCompleteTimeout != 0 {
+		result.Hooks.OnCompleteTimeout = override.Hooks.OnCompleteTimeout
+	}
+	if override.Hooks.OnCompleteAlways {
+		result.Hooks.OnCompleteAlways = true
+	}
+
+	result.RequireEvidence = base.RequireEvidence || override.RequireEvidence
+
+	// Merge auth: a source set by the override replaces the base's
+	result.Auth = base.Auth
+	if override.Auth.APIKeyEnv != "" || override.Auth.APIKeyFile != "" {
+		result.Auth = override.Auth
+	}
+
+	result.PRDLimits = base.PRDLimits
+	if override.PRDLimits.MaxDescription != 0 {
+		result.PRDLimits.MaxDescription = override.PRDLimits.MaxDescription
+	}
+	if override.PRDLimits.MaxNotes != 0 {
+		result.PRDLimits.MaxNotes = override.PRDLimits.MaxNotes
+	}
+
+	result.Batch = base.Batch
+	if override.Batch.MaxConcurrency != 0 {
+		result.Batch.MaxConcurrency = override.Batch.MaxConcurrency
+	}
+
+	// A completion policy replaces the default list outright, so checks can be dropped
+	result.Completion = base.Completion
+	if override.Completion.Require != nil {
+		result.Completion.Require = override.Completion.Require
+	}
+
+	result.OutputDir = base.OutputDir
+	if override.OutputDir != "" {
+		result.OutputDir = override.OutputDir
+	}
+
+	// Merge rate limit settings
+	result.RateLimit = base.RateLimit
+	if override.RateLimit.BackoffSeconds != 0 {
+		result.RateLimit.BackoffSeconds = override.RateLimit.BackoffSeconds
+	}
+	if override.RateLimit.MaxDelaySeconds != 0 {
+		result.RateLimit.MaxDelaySeconds = override.RateLimit.MaxDelaySeconds
+	}
+	if override.RateLimit.Multiplier != 0 {
+		result.RateLimit.Multiplier = override.RateLimit.Multiplier
+	}
+	if override.RateLimit.RecoverySeconds != 0 {
+		result.RateLimit.RecoverySeconds = override.RateLimit.RecoverySeconds
+	}
+
+	// Merge per-PRD model pins; an override entry replaces the base's for that key
+	if len(base.PRDModels)+len(override.PRDModels) > 0 {
+		result.PRDModels = make(map[string]PRDModels, len(base.PRDModels)+len(override.PRDModels))
+		maps.Copy(result.PRDModels, base.PRDModels)
+		maps.Copy(result.PRDModels, override.PRDModels)
+	}
+
+	// Merge escalation settings
+	result.Escalation = base.Escalation
+	if override.Escalation.AfterRejections != 0 {
+		result.Escalation.AfterRejections = override.Escalation.AfterRejections
+	}
+	if override.Escalation.Model != "" {
+		result.Escalation.Model = override.Escalation.Model
+	}
+
+	// Merge summarize settings
+	result.Summarize = base.Summarize
+	if override.Summarize.MaxBytes != 0 {
+		result.Summarize.MaxBytes = override.Summarize.MaxBytes
+	}
+	if override.Summarize.KeepEntries != 0 {
+		result.Summarize.KeepEntries = override.Summarize.KeepEntries
+	}
+	if override.Summarize.Model != "" {
+		result.Summarize.Model = override.Summarize.Model
+	}
+
+	// Merge display settings
+	result.Display = base.Display
+	if override.Display.TimestampFormat != "" {
+		result.Display.TimestampFormat = override.Display.TimestampFormat
+	}
+	if override.Display.UTC {
+		result.Display.UTC = true
+	}
+	if override.Display.PreserveCode {
+		result.Display.PreserveCode = true
+	}
+	if override.Display.Compact {
+		result.Display.Compact = true
+		result.Display.Full = false
+	}
+	if override.Display.Full {
+		result.Display.Full = true
+		result.Display.Compact = false
+	}
+	if override.Display.Markdown {
+		result.Display.Markdown = true
+	}
+	if override.Display.Theme != "" {
+		result.Display.Theme = override.Display.Theme
+	}
+	if override.Display.PhaseSummary {
+		result.Display.PhaseSummary = true
+	}
+
+	// Merge redaction settings
+	result.Redact = base.Redact
+	if len(override.Redact.Patterns) > 0 {
+		result.Redact.Patterns = override.Redact.Patterns
+	}
+	if override.Redact.DisableDefaults {
+		result.Redact.DisableDefaults = true
+	}
+
 	// Merge context files with deduplication
 	allFiles := append(base.ContextFiles, override.ContextFiles...)
 	result.ContextFiles = deduplicateStrings(allFiles)
@@ -275,6 +837,8 @@ func (c *Config) GetPhaseConfig(phase string) PhaseConfig {
 		phaseConfig = c.Phases.Reviewer
 	case "chat":
 		phaseConfig = c.Phases.Chat
+	case "custom":
+		phaseConfig = c.Phases.Custom
 	default:
 		log.Printf("Warning: unknown phase '%s', using planner config as fallback", phase)
 		phaseConfig = c.Phases.Planner // default fallback
@@ -287,6 +851,12 @@ func (c *Config) GetPhaseConfig(phase string) PhaseConfig {
 	if phaseConfig.MaxTokens == 0 {
 		phaseConfig.MaxTokens = c.Global.MaxTokens
 	}
+	if phaseConfig.IdleTimeout == 0 {
+		phaseConfig.IdleTimeout = c.Global.IdleTimeout
+	}
+	if phaseConfig.MaxRetainedOutput == 0 {
+		phaseConfig.MaxRetainedOutput = c.Global.MaxRetainedOutput
+	}
 
 	// For progress lines, we don't have a global default, so use phase defaults
 	// This is because different phases may need different amounts of history
@@ -301,6 +871,8 @@ func (c *Config) GetPhaseConfig(phase string) PhaseConfig {
 			phaseConfig.ProgressLines = 200
 		case "chat":
 			phaseConfig.ProgressLines = 0 // Chat doesn't use progress lines
+		case "custom":
+			phaseConfig.ProgressLines = 0 // Custom prompts read progress.md themselves
 		}
 	}
 
@@ -319,6 +891,12 @@ func (c *Config) Validate() error {
 	if c.Global.Model != "" && !validModels[c.Global.Model] {
 		return fmt.Errorf("invalid global model '%s': must be 'haiku', 'sonnet', or 'opus'", c.Global.Model)
 	}
+	if c.Global.IdleTimeout < 0 {
+		return fmt.Errorf("invalid global idleTimeout %d: must not be negative", c.Global.IdleTimeout)
+	}
+	if c.Global.MaxRetainedOutput < -1 {
+		return fmt.Errorf("invalid global maxRetainedOutput %d: must be -1 (keep all) or more", c.Global.MaxRetainedOutput)
+	}
 	if c.Global.MaxTokens != 0 && (c.Global.MaxTokens < MinTokens || c.Global.MaxTokens > MaxTokens) {
 		return fmt.Errorf("invalid global maxTokens %d: must be between %d and %d", c.Global.MaxTokens, MinTokens, MaxTokens)
 	}
@@ -332,6 +910,7 @@ func (c *Config) Validate() error {
 		{"builder", c.Phases.Builder},
 		{"reviewer", c.Phases.Reviewer},
 		{"chat", c.Phases.Chat},
+		{"custom", c.Phases.Custom},
 	}
 
 	for _, p := range phases {
@@ -341,6 +920,21 @@ func (c *Config) Validate() error {
 		if p.config.MaxTokens != 0 && (p.config.MaxTokens < MinTokens || p.config.MaxTokens > MaxTokens) {
 			return fmt.Errorf("invalid %s maxTokens %d: must be between %d and %d", p.name, p.config.MaxTokens, MinTokens, MaxTokens)
 		}
+		if p.config.MaxOutputTokens < 0 || p.config.MaxOutputTokens > MaxTokens {
+			return fmt.Errorf("invalid %s maxOutputTokens %d: must be between 0 and %d", p.name, p.config.MaxOutputTokens, MaxTokens)
+		}
+		if p.config.IdleTimeout < 0 {
+			return fmt.Errorf("invalid %s idleTimeout %d: must not be negative", p.name, p.config.IdleTimeout)
+		}
+		if p.config.MaxRetainedOutput < -1 {
+			return fmt.Errorf("invalid %s maxRetainedOutput %d: must be -1 (keep all) or more", p.name, p.config.MaxRetainedOutput)
+		}
+		if err := llm.CheckExtraArgs(p.config.ExtraArgs); err != nil {
+			return fmt.Errorf("invalid %s extraArgs: %w", p.name, err)
+		}
+		if err := llm.CheckMCPServers(p.config.MCPServers); err != nil {
+			return fmt.Errorf("invalid %s mcpServers: %w", p.name, err)
+		}
 		if p.config.ProgressLines != 0 && (p.config.ProgressLines < MinProgressLines || p.config.ProgressLines > MaxProgressLines) {
 			return fmt.Errorf("invalid %s progressLines %d: must be between %d and %d", p.name, p.config.ProgressLines, MinProgressLines, MaxProgressLines)
 		}
@@ -359,12 +953,130 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate display
+	if c.Display.Compact && c.Display.Full {
+		return fmt.Errorf("invalid display: compact and full are mutually exclusive")
+	}
+
+	// Validate redaction patterns
+	if _, err := c.Redact.Redactor(); err != nil {
+		return fmt.Errorf("invalid redact: %w", err)
+	}
+
+	// Validate summarize
+	if c.Summarize.MaxBytes < 0 || c.Summarize.KeepEntries < 0 {
+		return fmt.Errorf("invalid summarize: maxBytes and keepEntries must not be negative")
+	}
+	if c.Summarize.Model != "" && !validModels[c.Summarize.Model] {
+		return fmt.Errorf("invalid summarize model '%s': must be 'haiku', 'sonnet', or 'opus'", c.Summarize.Model)
+	}
+
+	// Validate rate limit backoff
+	if c.RateLimit.BackoffSeconds < 0 || c.RateLimit.MaxDelaySeconds < 0 || c.RateLimit.RecoverySeconds < 0 {
+		return fmt.Errorf("invalid rateLimit: delays must not be negative")
+	}
+	if c.RateLimit.Multiplier != 0 && c.RateLimit.Multiplier < 1 {
+		return fmt.Errorf("invalid rateLimit multiplier %g: must be at least 1", c.RateLimit.Multiplier)
+	}
+
+	// Validate per-PRD model pins
+	for _, key := range slices.Sorted(maps.Keys(c.PRDModels)) {
+		if _, err := path.Match(key, ""); err != nil {
+			return fmt.Errorf("invalid prdModels key '%s': %w", key, err)
+		}
+		m := c.PRDModels[key]
+		for _, model := range []string{m.Planner, m.Builder, m.Reviewer} {
+			if model != "" && !validModels[model] {
+				return fmt.Errorf("invalid prdModels '%s' model '%s': must be 'haiku', 'sonnet', or 'opus'", key, model)
+			}
+		}
+	}
+
+	// Validate escalation
+	if c.Escalation.AfterRejections < 0 {
+		return fmt.Errorf("invalid escalation afterRejections %d: must not be negative", c.Escalation.AfterRejections)
+	}
+	if c.Escalation.Model != "" && !validModels[c.Escalation.Model] {
+		return fmt.Errorf("invalid escalation model '%s': must be 'haiku', 'sonnet', or 'opus'", c.Escalation.Model)
+	}
+
+	// Validate auth
+	if c.Auth.APIKeyEnv != "" && c.Auth.APIKeyFile != "" {
+		return fmt.Errorf("invalid auth: set apiKeyEnv or apiKeyFile, not both")
+	}
+
+	// Validate PRD text limits
+	if c.PRDLimits.MaxDescription != 0 && c.PRDLimits.MaxDescription < MinPRDTextLimit {
+		return fmt.Errorf("invalid prdLimits maxDescription %d: must be at least %d", c.PRDLimits.MaxDescription, MinPRDTextLimit)
+	}
+	if c.PRDLimits.MaxNotes != 0 && c.PRDLimits.MaxNotes < MinPRDTextLimit {
+		return fmt.Errorf("invalid prdLimits maxNotes %d: must be at least %d", c.PRDLimits.MaxNotes, MinPRDTextLimit)
+	}
+
+	// Validate batch limits
+	if c.Batch.MaxConcurrency < 0 || c.Batch.MaxConcurrency > MaxBatchConcurrency {
+		return fmt.Errorf("invalid batch maxConcurrency %d: must be between 1 and %d", c.Batch.MaxConcurrency, MaxBatchConcurrency)
+	}
+
+	// Validate completion policy
+	for _, check := range c.Completion.Require {
+		if !slices.Contains(AllCompletionChecks, check) {
+			return fmt.Errorf("invalid completion check '%s': must be one of %s", check, strings.Join(AllCompletionChecks, ", "))
+		}
+	}
+
+	// Validate hooks
+	if c.Hooks.BuildTimeout < 0 {
+		return fmt.Errorf("invalid hooks buildTimeout %d: must not be negative", c.Hooks.BuildTimeout)
+	}
+	if c.Hooks.TestTimeout < 0 {
+		return fmt.Errorf("invalid hooks testTimeout %d: must not be negative", c.Hooks.TestTimeout)
+	}
+	if c.Hooks.OnCompleteTimeout < 0 {
+		return fmt.Errorf("invalid hooks onCompleteTimeout %d: must not be negative", c.Hooks.OnCompleteTimeout)
+	}
+	for i, h := range c.Hooks.OnSignal {
+		if strings.TrimSpace(h.Command) == "" {
+			return fmt.Errorf("invalid hooks onSignal[%d]: command is required", i)
+		}
+		if h.Timeout < 0 {
+			return fmt.Errorf("invalid hooks onSignal[%d] timeout %d: must not be negative", i, h.Timeout)
+		}
+	}
+
 	return nil
 }
 
+// IdleTimeoutDuration returns the phase's idle timeout; 0 means disabled
+func (p PhaseConfig) IdleTimeoutDuration() time.Duration {
+	return time.Duration(p.IdleTimeout) * time.Second
+}
+
+// RetainedOutput returns how many bytes of agent output the phase keeps, in
+// the form llm.ConsoleHandler.SetMaxRetainedOutput takes; 0 keeps everything
+func (p PhaseConfig) RetainedOutput() int {
+	switch {
+	case p.MaxRetainedOutput == 0:
+		return llm.DefaultMaxRetainedOutput
+	case p.MaxRetainedOutput < 0:
+		return 0
+	}
+	return p.MaxRetainedOutput
+}
+
+// HookTimeout returns a hook timeout in seconds, applying the default when unset
+func HookTimeout(seconds int) int {
+	if seconds == 0 {
+		return DefaultHookTimeout
+	}
+	return seconds
+}
+
 // ApplyOverrides applies CLI flag overrides to the configuration
+// Empty models and zero token or progress-line counts leave the value as is.
 func (c *Config) ApplyOverrides(plannerModel, builderModel, reviewerModel, chatModel string,
-	plannerTokens, builderTokens, reviewerTokens int) {
+	plannerTokens, builderTokens, reviewerTokens int,
+	plannerLines, builderLines, reviewerLines int) {
 	if plannerModel != "" {
 		c.Phases.Planner.Model = plannerModel
 	}
@@ -388,4 +1100,15 @@ func (c *Config) ApplyOverrides(plannerModel, builderModel, reviewerModel, chatM
 		c.Phases.Reviewer.MaxTokens = reviewerTokens
 	}
 	// No chatTokens parameter - chat doesn't use token limits
+
+	if plannerLines > 0 {
+		c.Phases.Planner.ProgressLines = plannerLines
+	}
+	if builderLines > 0 {
+		c.Phases.Builder.ProgressLines = builderLines
+	}
+	if reviewerLines > 0 {
+		c.Phases.Reviewer.ProgressLines = reviewerLines
+	}
+	// No chatLines parameter - chat doesn't read progress.md
 }
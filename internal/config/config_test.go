@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/daydemir/milhouse/internal/llm"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -270,7 +273,7 @@ func TestApplyOverrides(t *testing.T) {
 	cfg := DefaultConfig()
 
 	// Apply overrides
-	cfg.ApplyOverrides(ModelHaiku, ModelOpus, "", "", 50000, 150000, 0)
+	cfg.ApplyOverrides(ModelHaiku, ModelOpus, "", "", 50000, 150000, 0, 0, 0, 0)
 
 	// Check overrides were applied
 	if cfg.Phases.Planner.Model != ModelHaiku {
@@ -297,6 +300,106 @@ func TestApplyOverrides(t *testing.T) {
 	if cfg.Phases.Reviewer.MaxTokens != 80000 {
 		t.Errorf("Expected reviewer maxTokens 80000, got %d", cfg.Phases.Reviewer.MaxTokens)
 	}
+
+	if cfg.Phases.Reviewer.ProgressLines != 200 {
+		t.Errorf("Expected reviewer progressLines 200, got %d", cfg.Phases.Reviewer.ProgressLines)
+	}
+}
+
+func TestApplyOverridesProgressLines(t *testing.T) {
+	tests := []struct {
+		name                                      string
+		plannerLines, builderLines, reviewerLines int
+		want                                      [3]int // planner, builder, reviewer
+	}{
+		{"zero keeps defaults", 0, 0, 0, [3]int{20, 20, 200}},
+		{"planner only", 50, 0, 0, [3]int{50, 20, 200}},
+		{"builder only", 0, 100, 0, [3]int{20, 100, 200}},
+		{"all phases", 500, 500, 500, [3]int{500, 500, 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ApplyOverrides("", "", "", "", 0, 0, 0, tt.plannerLines, tt.builderLines, tt.reviewerLines)
+
+			for i, phase := range []string{"planner", "builder", "reviewer"} {
+				if got := cfg.GetPhaseConfig(phase).ProgressLines; got != tt.want[i] {
+					t.Errorf("Expected %s progressLines %d, got %d", phase, tt.want[i], got)
+				}
+			}
+			if got := cfg.GetPhaseConfig("chat").ProgressLines; got != 0 {
+				t.Errorf("Expected chat progressLines 0, got %d", got)
+			}
+		})
+	}
+}
+
+func TestValidateExtraArgs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Phases.Builder.ExtraArgs = []string{"--append-system-prompt", "Prefer small commits"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	cfg.Phases.Reviewer.ExtraArgs = []string{"--model=opus"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "reviewer extraArgs") {
+		t.Errorf("expected reviewer extraArgs error, got %v", err)
+	}
+}
+
+func TestLoadMCPServers(t *testing.T) {
+	tmpDir := t.TempDir()
+	milhouseDir := filepath.Join(tmpDir, MillhouseDir)
+	if err := os.MkdirAll(milhouseDir, 0755); err != nil {
+		t.Fatalf("Failed to create .milhouse directory: %v", err)
+	}
+	yaml := `phases:
+  reviewer:
+    mcpServers:
+      db:
+        command: npx
+        args: ["-y", "@example/postgres-mcp", "--read-only"]
+        env:
+          DATABASE_URL: postgres://localhost/app
+      docs:
+        type: http
+        url: https://example.com/mcp
+`
+	if err := os.WriteFile(filepath.Join(milhouseDir, ConfigFile), []byte(yaml), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	servers := cfg.GetPhaseConfig("reviewer").MCPServers
+	if len(servers) != 2 || servers["db"].Command != "npx" || len(servers["db"].Args) != 3 ||
+		servers["db"].Env["DATABASE_URL"] == "" || servers["docs"].URL != "https://example.com/mcp" {
+		t.Errorf("reviewer mcpServers = %+v", servers)
+	}
+	if len(cfg.GetPhaseConfig("builder").MCPServers) != 0 {
+		t.Errorf("builder should have no MCP servers")
+	}
+
+	cfg.Phases.Reviewer.MCPServers["broken"] = llm.MCPServer{Type: "http"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "reviewer mcpServers") {
+		t.Errorf("expected reviewer mcpServers error, got %v", err)
+	}
+}
+
+func TestValidateRedactPatterns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Redact.Patterns = []string{`internal-[0-9]{6}`}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	cfg.Redact.Patterns = []string{"[unclosed"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid redact") {
+		t.Errorf("expected invalid redact error, got %v", err)
+	}
 }
 
 func TestValidProgressLinesRange(t *testing.T) {
@@ -459,6 +562,9 @@ func TestCLIFlagOverridesValidation(t *testing.T) {
 		plannerTokens int
 		builderTokens int
 		reviewerTokens int
+		plannerLines int
+		builderLines int
+		reviewerLines int
 		wantErr bool
 	}{
 		{
@@ -486,13 +592,31 @@ func TestCLIFlagOverridesValidation(t *testing.T) {
 			builderTokens: 300000,
 			wantErr: true,
 		},
+		{
+			name: "progress lines in range",
+			plannerLines: 50,
+			builderLines: 100,
+			reviewerLines: 500,
+			wantErr: false,
+		},
+		{
+			name: "progress lines too low",
+			builderLines: MinProgressLines - 1,
+			wantErr: true,
+		},
+		{
+			name: "progress lines too high",
+			reviewerLines: MaxProgressLines + 1,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := DefaultConfig()
 			cfg.ApplyOverrides(tt.planner, tt.builder, tt.reviewer, "",
-				tt.plannerTokens, tt.builderTokens, tt.reviewerTokens)
+				tt.plannerTokens, tt.builderTokens, tt.reviewerTokens,
+				tt.plannerLines, tt.builderLines, tt.reviewerLines)
 
 			err := cfg.Validate()
 			if (err != nil) != tt.wantErr {
@@ -548,3 +672,208 @@ func TestDeduplicateStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestDisplayVerbosity(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Display.Compact = true
+	cfg.Display.Full = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected compact and full together to fail validation")
+	}
+
+	// A project that asks for full output replaces a global compact default
+	global := &Config{Display: DisplayConfig{Compact: true}}
+	project := &Config{Display: DisplayConfig{Full: true}}
+	merged := mergeConfigs(global, project)
+	if merged.Display.Compact || !merged.Display.Full {
+		t.Errorf("Expected full only after merge, got %+v", merged.Display)
+	}
+}
+
+func TestSummarizeConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Summarize.MaxBytes != 0 || cfg.Summarize.Keep() != DefaultKeepEntries {
+		t.Errorf("Expected summarize disabled with default keep, got %+v", cfg.Summarize)
+	}
+
+	cfg.Summarize.Model = "gpt"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected invalid summarize model to fail validation")
+	}
+	cfg.Summarize = SummarizeConfig{MaxBytes: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected negative maxBytes to fail validation")
+	}
+
+	merged := mergeConfigs(DefaultConfig(), &Config{Summarize: SummarizeConfig{MaxBytes: 40000, KeepEntries: 5}})
+	if merged.Summarize.MaxBytes != 40000 || merged.Summarize.Keep() != 5 {
+		t.Errorf("Expected summarize settings to merge, got %+v", merged.Summarize)
+	}
+}
+
+func TestCompletionPolicy(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.Completion.Requires(CompletionCriteria) || !cfg.Completion.Requires(CompletionChecks) || cfg.Completion.Requires(CompletionCleanTree) {
+		t.Errorf("Expected criteria and checks by default, got %v", cfg.Completion.Require)
+	}
+
+	cfg.Completion.Require = []string{CompletionCleanTree, "tests"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected unknown completion check to fail validation")
+	}
+
+	// An explicit empty list drops every check; an absent one keeps the defaults
+	path := filepath.Join(t.TempDir(), ConfigFile)
+	if err := os.WriteFile(path, []byte("completion:\n  require: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err := loadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged := mergeConfigs(DefaultConfig(), project); len(merged.Completion.Require) != 0 {
+		t.Errorf("Expected an empty policy after merge, got %v", merged.Completion.Require)
+	}
+	if merged := mergeConfigs(DefaultConfig(), &Config{}); len(merged.Completion.Require) != 2 {
+		t.Errorf("Expected the default policy after merge, got %v", merged.Completion.Require)
+	}
+}
+
+func TestAuthConfig(t *testing.T) {
+	if key, err := (AuthConfig{}).APIKey(); key != "" || err != nil {
+		t.Errorf("Expected no key and no error without a source, got %q, %v", key, err)
+	}
+
+	t.Setenv("MIL_TEST_KEY", " sk-ant-test\n")
+	if key, err := (AuthConfig{APIKeyEnv: "MIL_TEST_KEY"}).APIKey(); key != "sk-ant-test" || err != nil {
+		t.Errorf("Expected key from env, got %q, %v", key, err)
+	}
+	if _, err := (AuthConfig{APIKeyEnv: "MIL_TEST_UNSET_KEY"}).APIKey(); err == nil || !strings.Contains(err.Error(), "MIL_TEST_UNSET_KEY") {
+		t.Errorf("Expected an error naming the unset variable, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key")
+	if _, err := (AuthConfig{APIKeyFile: path}).APIKey(); err == nil {
+		t.Error("Expected an error for a missing key file")
+	}
+	if err := os.WriteFile(path, []byte("sk-ant-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := (AuthConfig{APIKeyFile: path}).APIKey(); key != "sk-ant-file" || err != nil {
+		t.Errorf("Expected key from file, got %q, %v", key, err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Auth = AuthConfig{APIKeyEnv: "MIL_TEST_KEY", APIKeyFile: path}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected apiKeyEnv and apiKeyFile together to fail validation")
+	}
+}
+
+func TestModelForPRD(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PRDModels = map[string]PRDModels{
+		"refactor-core": {Builder: ModelOpus},
+		"auth-*":        {Builder: ModelHaiku, Reviewer: ModelOpus},
+		"auth-login":    {Planner: ModelOpus},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		phase, prdID, want string
+	}{
+		{"builder", "refactor-core", ModelOpus},
+		{"builder", "ui-polish", ModelSonnet},
+		{"builder", "", ModelSonnet},
+		{"reviewer", "auth-signup", ModelOpus},
+		{"planner", "refactor-core", ModelSonnet}, // Only the builder is pinned
+		{"planner", "auth-login", ModelOpus},      // Exact key beats the pattern
+		{"builder", "auth-login", ModelHaiku},     // Pattern fills phases the exact key leaves unset
+	}
+	for _, tt := range tests {
+		if got := cfg.ModelForPRD(tt.phase, tt.prdID); got != tt.want {
+			t.Errorf("ModelForPRD(%q, %q) = %q, want %q", tt.phase, tt.prdID, got, tt.want)
+		}
+	}
+
+	if got := cfg.UnmatchedPRDModels([]string{"auth-login", "ui-polish"}); len(got) != 1 || got[0] != "refactor-core" {
+		t.Errorf("UnmatchedPRDModels() = %v, want [refactor-core]", got)
+	}
+
+	cfg.PRDModels = map[string]PRDModels{"refactor-core": {Builder: "gpt"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an invalid prdModels model to fail validation")
+	}
+	cfg.PRDModels = map[string]PRDModels{"auth-[": {Builder: ModelOpus}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected a malformed prdModels pattern to fail validation")
+	}
+
+	merged := mergeConfigs(
+		&Config{PRDModels: map[string]PRDModels{"a": {Builder: ModelOpus}, "b": {Builder: ModelOpus}}},
+		&Config{PRDModels: map[string]PRDModels{"b": {Planner: ModelHaiku}}})
+	if merged.PRDModels["a"].Builder != ModelOpus || merged.PRDModels["b"] != (PRDModels{Planner: ModelHaiku}) {
+		t.Errorf("Expected per-key prdModels merge, got %+v", merged.PRDModels)
+	}
+}
+
+func TestPRDLimits(t *testing.T) {
+	if limits := DefaultConfig().PRDLimits; limits != (PRDLimits{}) {
+		t.Errorf("Expected no caps by default, got %+v", limits)
+	}
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("Expected uncapped limits to validate, got %v", err)
+	}
+
+	override := &Config{PRDLimits: PRDLimits{MaxNotes: 500}}
+	merged := mergeConfigs(DefaultConfig(), override)
+	if merged.PRDLimits.MaxDescription != 0 || merged.PRDLimits.MaxNotes != 500 {
+		t.Errorf("Expected an uncapped description and notes cap 500, got %+v", merged.PRDLimits)
+	}
+
+	merged.PRDLimits.MaxDescription = MinPRDTextLimit - 1
+	if err := merged.Validate(); err == nil {
+		t.Error("Expected a description cap below the minimum to fail validation")
+	}
+}
+
+func TestBatchConfig(t *testing.T) {
+	merged := mergeConfigs(DefaultConfig(), &Config{Batch: BatchConfig{MaxConcurrency: 4}})
+	if merged.Batch.MaxConcurrency != 4 {
+		t.Errorf("Expected batch maxConcurrency 4, got %d", merged.Batch.MaxConcurrency)
+	}
+	if kept := mergeConfigs(DefaultConfig(), &Config{}); kept.Batch.MaxConcurrency != DefaultConfig().Batch.MaxConcurrency {
+		t.Errorf("Expected default batch maxConcurrency, got %d", kept.Batch.MaxConcurrency)
+	}
+
+	merged.Batch.MaxConcurrency = MaxBatchConcurrency + 1
+	if err := merged.Validate(); err == nil {
+		t.Error("Expected a batch maxConcurrency above the maximum to fail validation")
+	}
+}
+
+func TestMaxRetainedOutput(t *testing.T) {
+	if got := DefaultConfig().GetPhaseConfig("builder").RetainedOutput(); got != llm.DefaultMaxRetainedOutput {
+		t.Errorf("Expected the default retained output %d, got %d", llm.DefaultMaxRetainedOutput, got)
+	}
+
+	override := &Config{Global: GlobalConfig{MaxRetainedOutput: 1024}}
+	override.Phases.Reviewer.MaxRetainedOutput = -1
+	merged := mergeConfigs(DefaultConfig(), override)
+	if got := merged.GetPhaseConfig("builder").RetainedOutput(); got != 1024 {
+		t.Errorf("Expected the global fallback 1024, got %d", got)
+	}
+	if got := merged.GetPhaseConfig("reviewer").RetainedOutput(); got != 0 {
+		t.Errorf("Expected -1 to keep all output (0), got %d", got)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("Expected retained output settings to validate, got %v", err)
+	}
+
+	merged.Phases.Planner.MaxRetainedOutput = -2
+	if err := merged.Validate(); err == nil {
+		t.Error("Expected a planner maxRetainedOutput below -1 to fail validation")
+	}
+}
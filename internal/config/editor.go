@@ -22,6 +22,7 @@ type Editor struct {
 	fieldOrder   []string
 	currentField int
 	err          error
+	invalid      error // Problem with the inputs as typed, shown until fixed
 	saved        bool
 	message      string
 	basePath     string
@@ -125,6 +126,7 @@ func (e *Editor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				e.err = err
 				e.message = fmt.Sprintf("Error: %v", err)
 			} else {
+				e.err = nil
 				e.saved = true
 				e.message = "✓ Configuration saved successfully!"
 			}
@@ -190,6 +192,7 @@ func (e *Editor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	input := e.inputs[currentFieldName]
 	input, cmd = input.Update(msg)
 	e.inputs[currentFieldName] = input
+	_, e.invalid = e.buildConfig()
 	return e, cmd
 }
 
@@ -251,6 +254,10 @@ func (e *Editor) View() string {
 			Foreground(lipgloss.Color("9")).
 			Render("✗ " + e.message) + "\n"
 		e.err = nil
+	} else if e.invalid != nil {
+		s += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Render("⚠ " + e.invalid.Error()) + "\n"
 	}
 
 	// Footer
@@ -290,7 +297,25 @@ func (e *Editor) renderField(fieldName, label string, focused bool) string {
 }
 
 // saveConfig saves the edited configuration
+// Nothing is written when an input is invalid, and the inputs are kept as
+// typed so only the bad field needs fixing.
 func (e *Editor) saveConfig() error {
+	newConfig, err := e.buildConfig()
+	if err != nil {
+		return err
+	}
+
+	// Save to file
+	if err := Save(e.basePath, newConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// buildConfig parses and validates the inputs into a new config without
+// changing them
+func (e *Editor) buildConfig() (*Config, error) {
 	// Parse and validate all fields
 	newConfig := DefaultConfig()
 
@@ -304,7 +329,7 @@ func (e *Editor) saveConfig() error {
 	globalModel := strings.TrimSpace(e.inputs["globalModel"].Value())
 	if globalModel != "" {
 		if !validModels[globalModel] {
-			return fmt.Errorf("invalid global model '%s': must be 'haiku', 'sonnet', or 'opus'", globalModel)
+			return nil, fmt.Errorf("invalid global model '%s': must be 'haiku', 'sonnet', or 'opus'", globalModel)
 		}
 		newConfig.Global.Model = globalModel
 	}
@@ -312,12 +337,12 @@ func (e *Editor) saveConfig() error {
 	if globalTokensStr := strings.TrimSpace(e.inputs["globalMaxTokens"].Value()); globalTokensStr != "" {
 		if tokens, err := strconv.Atoi(globalTokensStr); err == nil {
 			if tokens < MinTokens || tokens > MaxTokens {
-				return fmt.Errorf("invalid global maxTokens %d: must be between %d and %d",
+				return nil, fmt.Errorf("invalid global maxTokens %d: must be between %d and %d",
 					tokens, MinTokens, MaxTokens)
 			}
 			newConfig.Global.MaxTokens = tokens
 		} else {
-			return fmt.Errorf("invalid global maxTokens '%s': must be a number", globalTokensStr)
+			return nil, fmt.Errorf("invalid global maxTokens '%s': must be a number", globalTokensStr)
 		}
 	}
 
@@ -325,7 +350,7 @@ func (e *Editor) saveConfig() error {
 	plannerModel := strings.TrimSpace(e.inputs["plannerModel"].Value())
 	if plannerModel != "" {
 		if !validModels[plannerModel] {
-			return fmt.Errorf("invalid planner model '%s': must be 'haiku', 'sonnet', or 'opus'", plannerModel)
+			return nil, fmt.Errorf("invalid planner model '%s': must be 'haiku', 'sonnet', or 'opus'", plannerModel)
 		}
 		newConfig.Phases.Planner.Model = plannerModel
 	}
@@ -333,24 +358,24 @@ func (e *Editor) saveConfig() error {
 	if plannerTokensStr := strings.TrimSpace(e.inputs["plannerMaxTokens"].Value()); plannerTokensStr != "" {
 		if tokens, err := strconv.Atoi(plannerTokensStr); err == nil {
 			if tokens < MinTokens || tokens > MaxTokens {
-				return fmt.Errorf("invalid planner maxTokens %d: must be between %d and %d",
+				return nil, fmt.Errorf("invalid planner maxTokens %d: must be between %d and %d",
 					tokens, MinTokens, MaxTokens)
 			}
 			newConfig.Phases.Planner.MaxTokens = tokens
 		} else {
-			return fmt.Errorf("invalid planner maxTokens '%s': must be a number", plannerTokensStr)
+			return nil, fmt.Errorf("invalid planner maxTokens '%s': must be a number", plannerTokensStr)
 		}
 	}
 
 	if plannerLinesStr := strings.TrimSpace(e.inputs["plannerProgressLines"].Value()); plannerLinesStr != "" {
 		if lines, err := strconv.Atoi(plannerLinesStr); err == nil {
 			if lines < MinProgressLines || lines > MaxProgressLines {
-				return fmt.Errorf("invalid planner progressLines %d: must be between %d and %d",
+				return nil, fmt.Errorf("invalid planner progressLines %d: must be between %d and %d",
 					lines, MinProgressLines, MaxProgressLines)
 			}
 			newConfig.Phases.Planner.ProgressLines = lines
 		} else {
-			return fmt.Errorf("invalid planner progressLines '%s': must be a number", plannerLinesStr)
+			return nil, fmt.Errorf("invalid planner progressLines '%s': must be a number", plannerLinesStr)
 		}
 	}
 
@@ -358,7 +383,7 @@ func (e *Editor) saveConfig() error {
 	builderModel := strings.TrimSpace(e.inputs["builderModel"].Value())
 	if builderModel != "" {
 		if !validModels[builderModel] {
-			return fmt.Errorf("invalid builder model '%s': must be 'haiku', 'sonnet', or 'opus'", builderModel)
+			return nil, fmt.Errorf("invalid builder model '%s': must be 'haiku', 'sonnet', or 'opus'", builderModel)
 		}
 		newConfig.Phases.Builder.Model = builderModel
 	}
@@ -366,24 +391,24 @@ func (e *Editor) saveConfig() error {
 	if builderTokensStr := strings.TrimSpace(e.inputs["builderMaxTokens"].Value()); builderTokensStr != "" {
 		if tokens, err := strconv.Atoi(builderTokensStr); err == nil {
 			if tokens < MinTokens || tokens > MaxTokens {
-				return fmt.Errorf("invalid builder maxTokens %d: must be between %d and %d",
+				return nil, fmt.Errorf("invalid builder maxTokens %d: must be between %d and %d",
 					tokens, MinTokens, MaxTokens)
 			}
 			newConfig.Phases.Builder.MaxTokens = tokens
 		} else {
-			return fmt.Errorf("invalid builder maxTokens '%s': must be a number", builderTokensStr)
+			return nil, fmt.Errorf("invalid builder maxTokens '%s': must be a number", builderTokensStr)
 		}
 	}
 
 	if builderLinesStr := strings.TrimSpace(e.inputs["builderProgressLines"].Value()); builderLinesStr != "" {
 		if lines, err := strconv.Atoi(builderLinesStr); err == nil {
 			if lines < MinProgressLines || lines > MaxProgressLines {
-				return fmt.Errorf("invalid builder progressLines %d: must be between %d and %d",
+				return nil, fmt.Errorf("invalid builder progressLines %d: must be between %d and %d",
 					lines, MinProgressLines, MaxProgressLines)
 			}
 			newConfig.Phases.Builder.ProgressLines = lines
 		} else {
-			return fmt.Errorf("invalid builder progressLines '%s': must be a number", builderLinesStr)
+			return nil, fmt.Errorf("invalid builder progressLines '%s': must be a number", builderLinesStr)
 		}
 	}
 
@@ -391,7 +416,7 @@ func (e *Editor) saveConfig() error {
 	reviewerModel := strings.TrimSpace(e.inputs["reviewerModel"].Value())
 	if reviewerModel != "" {
 		if !validModels[reviewerModel] {
-			return fmt.Errorf("invalid reviewer model '%s': must be 'haiku', 'sonnet', or 'opus'", reviewerModel)
+			return nil, fmt.Errorf("invalid reviewer model '%s': must be 'haiku', 'sonnet', or 'opus'", reviewerModel)
 		}
 		newConfig.Phases.Reviewer.Model = reviewerModel
 	}
@@ -399,24 +424,24 @@ func (e *Editor) saveConfig() error {
 	if reviewerTokensStr := strings.TrimSpace(e.inputs["reviewerMaxTokens"].Value()); reviewerTokensStr != "" {
 		if tokens, err := strconv.Atoi(reviewerTokensStr); err == nil {
 			if tokens < MinTokens || tokens > MaxTokens {
-				return fmt.Errorf("invalid reviewer maxTokens %d: must be between %d and %d",
+				return nil, fmt.Errorf("invalid reviewer maxTokens %d: must be between %d and %d",
 					tokens, MinTokens, MaxTokens)
 			}
 			newConfig.Phases.Reviewer.MaxTokens = tokens
 		} else {
-			return fmt.Errorf("invalid reviewer maxTokens '%s': must be a number", reviewerTokensStr)
+			return nil, fmt.Errorf("invalid reviewer maxTokens '%s': must be a number", reviewerTokensStr)
 		}
 	}
 
 	if reviewerLinesStr := strings.TrimSpace(e.inputs["reviewerProgressLines"].Value()); reviewerLinesStr != "" {
 		if lines, err := strconv.Atoi(reviewerLinesStr); err == nil {
 			if lines < MinProgressLines || lines > MaxProgressLines {
-				return fmt.Errorf("invalid reviewer progressLines %d: must be between %d and %d",
+				return nil, fmt.Errorf("invalid reviewer progressLines %d: must be between %d and %d",
 					lines, MinProgressLines, MaxProgressLines)
 			}
 			newConfig.Phases.Reviewer.ProgressLines = lines
 		} else {
-			return fmt.Errorf("invalid reviewer progressLines '%s': must be a number", reviewerLinesStr)
+			return nil, fmt.Errorf("invalid reviewer progressLines '%s': must be a number", reviewerLinesStr)
 		}
 	}
 
@@ -424,20 +449,15 @@ func (e *Editor) saveConfig() error {
 	chatModel := strings.TrimSpace(e.inputs["chatModel"].Value())
 	if chatModel != "" {
 		if !validModels[chatModel] {
-			return fmt.Errorf("invalid chat model '%s': must be 'haiku', 'sonnet', or 'opus'", chatModel)
+			return nil, fmt.Errorf("invalid chat model '%s': must be 'haiku', 'sonnet', or 'opus'", chatModel)
 		}
 		newConfig.Phases.Chat.Model = chatModel
 	}
 
 	// Validate the new config
 	if err := newConfig.Validate(); err != nil {
-		return err
-	}
-
-	// Save to file
-	if err := Save(e.basePath, newConfig); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return newConfig, nil
 }
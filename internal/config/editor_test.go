@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setInput replaces an editor input's value as if the user retyped it
+func setInput(e *Editor, name, value string) {
+	input := e.inputs[name]
+	input.SetValue(value)
+	e.inputs[name] = input
+}
+
+// focusInput moves the editor's focus to the named input
+func focusInput(e *Editor, name string) {
+	for i, field := range e.fieldOrder {
+		input := e.inputs[field]
+		if field == name {
+			input.Focus()
+			e.currentField = i
+		} else {
+			input.Blur()
+		}
+		e.inputs[field] = input
+	}
+}
+
+func TestEditorFailedSaveKeepsInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	e := NewEditor(tmpDir, DefaultConfig())
+
+	setInput(e, "globalModel", ModelOpus)
+	setInput(e, "builderMaxTokens", "150000")
+	setInput(e, "reviewerProgressLines", "300")
+	setInput(e, "plannerMaxTokens", "lots")
+
+	typed := make(map[string]string)
+	for name, input := range e.inputs {
+		typed[name] = input.Value()
+	}
+
+	e.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if e.err == nil || !strings.Contains(e.message, "planner maxTokens") {
+		t.Fatalf("save with an invalid field: err = %v, message = %q", e.err, e.message)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, MillhouseDir, ConfigFile)); !os.IsNotExist(err) {
+		t.Errorf("failed save wrote %s (stat error %v)", ConfigFile, err)
+	}
+	for name, want := range typed {
+		if got := e.inputs[name].Value(); got != want {
+			t.Errorf("input %s = %q after failed save, want %q", name, got, want)
+		}
+	}
+
+	// Fixing only the bad field is enough
+	setInput(e, "plannerMaxTokens", "90000")
+	e.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if e.err != nil {
+		t.Fatalf("save after fix: %v", e.err)
+	}
+
+	cfg, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Global.Model != ModelOpus || cfg.Phases.Builder.MaxTokens != 150000 ||
+		cfg.Phases.Reviewer.ProgressLines != 300 || cfg.Phases.Planner.MaxTokens != 90000 {
+		t.Errorf("saved config lost typed values: global model %s, builder %d, reviewer lines %d, planner %d",
+			cfg.Global.Model, cfg.Phases.Builder.MaxTokens, cfg.Phases.Reviewer.ProgressLines, cfg.Phases.Planner.MaxTokens)
+	}
+}
+
+func TestEditorLiveValidation(t *testing.T) {
+	e := NewEditor(t.TempDir(), DefaultConfig())
+	focusInput(e, "builderProgressLines")
+	setInput(e, "builderProgressLines", "")
+
+	e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	if e.invalid == nil || !strings.Contains(e.invalid.Error(), "builder progressLines") {
+		t.Fatalf("invalid = %v after typing an out-of-range value", e.invalid)
+	}
+	if !strings.Contains(e.View(), e.invalid.Error()) {
+		t.Error("View() does not show the live validation error")
+	}
+
+	e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+	if e.invalid != nil {
+		t.Errorf("invalid = %v after fixing the value", e.invalid)
+	}
+}
@@ -0,0 +1,231 @@
+package custom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+	"github.com/daydemir/milhouse/internal/prompts"
+)
+
+// ReadOnlyTools are the tools a custom prompt gets unless
+// phases.custom.allowWrites is set
+var ReadOnlyTools = []string{"Read", "Glob", "Grep", "TodoWrite", "WebSearch", "WebFetch"}
+
+// WriteTools are the tools a custom prompt gets with phases.custom.allowWrites
+var WriteTools = []string{
+	"Read", "Write", "Edit", "Bash", "Glob", "Grep",
+	"Task", "TodoWrite", "WebSearch", "WebFetch",
+}
+
+// CustomResult contains the result of a custom prompt run
+type CustomResult struct {
+	Output      string
+	TotalTokens int
+	Signals     []llm.Signal
+	RateLimited bool // The API rate-limited the run
+	Summary     string
+}
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// List returns the names of the custom prompts in .milhouse/prompts/custom/,
+// sorted; a missing directory means there are none
+func List(basePath string) ([]string, error) {
+	dir := filepath.Dir(prompts.GetCustomPromptPath(basePath, "x"))
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom prompts: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".md")
+		if ok && !e.IsDir() && validName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads the custom prompt called name
+func Load(basePath, name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid prompt name '%s': use letters, digits, '-' and '_'", name)
+	}
+	path := prompts.GetCustomPromptPath(basePath, name)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no custom prompt '%s': create %s", name, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read custom prompt: %w", err)
+	}
+
+	text := strings.TrimSpace(string(content))
+	if text == "" {
+		return "", fmt.Errorf("custom prompt '%s' is empty", name)
+	}
+	return text, nil
+}
+
+// placeholder matches $ARGUMENTS, $1, ${1} and the $$ escape
+var placeholder = regexp.MustCompile(`\$\$|\$\{(ARGUMENTS|[1-9][0-9]*)\}|\$(ARGUMENTS|[1-9][0-9]*)`)
+
+// Expand substitutes args into a custom prompt
+// $ARGUMENTS is every argument joined by spaces, $N (or ${N}) is the Nth and
+// $$ is a literal $. A prompt without placeholders gets any arguments
+// appended, so simple prompts still see them.
+func Expand(text string, args []string) (string, error) {
+	used := false
+	var missing error
+	expanded := placeholder.ReplaceAllStringFunc(text, func(m string) string {
+		if m == "$$" {
+			return "$"
+		}
+		used = true
+		key := strings.Trim(m, "${}")
+		if key == "ARGUMENTS" {
+			return strings.Join(args, " ")
+		}
+		n, _ := strconv.Atoi(key)
+		if n > len(args) {
+			if missing == nil {
+				missing = fmt.Errorf("prompt uses $%d but %d argument(s) were given", n, len(args))
+			}
+			return m
+		}
+		return args[n-1]
+	})
+	if missing != nil {
+		return "", missing
+	}
+
+	if !used && len(args) > 0 {
+		expanded += "\n\nArguments: " + strings.Join(args, " ")
+	}
+	return expanded, nil
+}
+
+// Run executes the custom prompt called name as a single agent pass, with
+// args substituted and the custom phase's model, limits and tools
+func Run(ctx context.Context, basePath, name string, args []string, cfg *config.Config) (*CustomResult, error) {
+	// Nil guard - use default config if none provided
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	text, err := Load(basePath, name)
+	if err != nil {
+		return nil, err
+	}
+	task, err := Expand(text, args)
+	if err != nil {
+		return nil, fmt.Errorf("custom prompt '%s': %w", name, err)
+	}
+
+	apiKey, err := cfg.Auth.APIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	phaseConfig := cfg.GetPhaseConfig("custom")
+	display.AgentHeader("custom", name)
+
+	prompt := prompts.BuildCustomPrompt(prompts.CustomData{
+		Name:        name,
+		Task:        task,
+		Timestamp:   time.Now().Format("2006-01-02 15:04"),
+		AllowWrites: phaseConfig.AllowWrites,
+	})
+
+	return runClaude(ctx, llm.NewClaude("", apiKey), basePath, prompt, phaseConfig)
+}
+
+func runClaude(ctx context.Context, claude *llm.Claude, basePath, prompt string, phaseConfig config.PhaseConfig) (*CustomResult, error) {
+
+	// Create a cancellable context for this execution
+	execCtx, cancelExec := context.WithCancel(ctx)
+	defer cancelExec()
+
+	opts := llm.ExecuteOptions{
+		Prompt:       prompt,
+		Model:        phaseConfig.Model,
+		AllowedTools: WriteTools,
+		ContextFiles: []string{
+			prd.GetMillhousePath(basePath, prd.PRDFile),
+			prd.GetMillhousePath(basePath, prd.ProgressFile),
+			prd.GetMillhousePath(basePath, prd.PromptFile),
+		},
+		WorkDir:    basePath,
+		AddDirs:    prd.ExternalArtifactDirs(),
+		ExtraArgs:  phaseConfig.ExtraArgs,
+		MCPServers: phaseConfig.MCPServers,
+	}
+
+	// Sandboxed by default: inspection tools only, and deny anything that writes
+	if !phaseConfig.AllowWrites {
+		opts.AllowedTools = ReadOnlyTools
+		opts.DisallowedTools = append(append([]string{}, llm.MutatingTools...), "Task")
+		opts.MCPServers = nil // MCP tools could write
+	}
+
+	reader, err := claude.Execute(execCtx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create handler with termination support
+	handler := llm.NewConsoleHandlerWithLimits(phaseConfig.MaxTokens, phaseConfig.MaxOutputTokens, cancelExec)
+	handler.SetMaxRetainedOutput(phaseConfig.RetainedOutput())
+	defer handler.Flush() // Flush buffered output on every exit path
+
+	// Cancel the run if the agent goes quiet for too long
+	stopWatchdog := handler.WatchIdle(phaseConfig.IdleTimeoutDuration(), cancelExec)
+	defer stopWatchdog()
+
+	// Parse the stream
+	if err := llm.ParseStream(reader, handler, cancelExec); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("stream parsing failed: %w", err)
+	}
+	stopWatchdog()
+
+	closeErr := reader.Close()
+	if closeErr != nil && !handler.ShouldTerminate() {
+		if handler.RateLimited() {
+			return nil, fmt.Errorf("claude execution failed: %w: %w", closeErr, llm.ErrRateLimited)
+		}
+		return nil, fmt.Errorf("claude execution failed: %w", closeErr)
+	}
+
+	result := &CustomResult{
+		Output:      handler.GetOutput(),
+		TotalTokens: handler.GetTokenStats().TotalTokens,
+		Signals:     handler.GetSignals(),
+		RateLimited: handler.RateLimited(),
+		Summary:     handler.PhaseSummary("custom"),
+	}
+
+	handler.Flush()
+	fmt.Println() // Ensure newline after output
+	handler.DisplayFinalTokenUsage()
+	handler.DisplayToolSummary()
+
+	return result, nil
+}
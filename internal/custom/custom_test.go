@@ -0,0 +1,84 @@
+package custom
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/prompts"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		args []string
+		want string
+	}{
+		{"all arguments", "Audit $ARGUMENTS", []string{"a", "b"}, "Audit a b"},
+		{"positional", "Compare $2 with ${1}", []string{"old", "new"}, "Compare new with old"},
+		{"escaped dollar", "Costs $$5, file $1", []string{"x.go"}, "Costs $5, file x.go"},
+		{"no placeholders appends", "Audit the repo", []string{"auth"}, "Audit the repo\n\nArguments: auth"},
+		{"no placeholders no args", "Audit the repo", nil, "Audit the repo"},
+		{"empty arguments", "Audit $ARGUMENTS.", nil, "Audit ."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.text, tt.args)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Expand("Compare $1 with $3", []string{"a", "b"}); err == nil || !strings.Contains(err.Error(), "$3") {
+		t.Errorf("Expand() with a missing argument error = %v, want one naming $3", err)
+	}
+}
+
+func TestLoadAndList(t *testing.T) {
+	dir := t.TempDir()
+
+	names, err := List(dir)
+	if err != nil || names != nil {
+		t.Fatalf("List() on a missing directory = %v, %v; want nil, nil", names, err)
+	}
+
+	customDir := filepath.Dir(prompts.GetCustomPromptPath(dir, "x"))
+	if err := os.MkdirAll(customDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"security-audit.md": "\nLook for injection bugs in $1\n",
+		"empty.md":          "  \n",
+		"notes.txt":         "not a prompt",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(customDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err = List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []string{"empty", "security-audit"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List() = %v, want %v", names, want)
+	}
+
+	text, err := Load(dir, "security-audit")
+	if err != nil || text != "Look for injection bugs in $1" {
+		t.Errorf("Load() = %q, %v", text, err)
+	}
+	for _, name := range []string{"empty", "missing", "../secrets"} {
+		if _, err := Load(dir, name); err == nil {
+			t.Errorf("Load(%q) succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package display
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultFlushInterval bounds how long buffered output waits before reaching the terminal
+const DefaultFlushInterval = 50 * time.Millisecond
+
+// bufferedWriter batches small writes into fewer writes to the underlying
+// writer. Buffered data is flushed when the buffer fills, when the flush
+// interval elapses after the first unflushed write, or on Flush.
+type bufferedWriter struct {
+	mu       sync.Mutex
+	w        *bufio.Writer
+	interval time.Duration
+	timer    *time.Timer
+}
+
+func newBufferedWriter(w io.Writer, interval time.Duration) *bufferedWriter {
+	return &bufferedWriter{
+		w:        bufio.NewWriterSize(w, 32*1024),
+		interval: interval,
+	}
+}
+
+// Write buffers p and schedules a flush if none is pending
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n, err := b.w.Write(p)
+	if b.timer == nil && b.w.Buffered() > 0 {
+		b.timer = time.AfterFunc(b.interval, func() {
+			b.Flush()
+		})
+	}
+	return n, err
+}
+
+// Flush writes any buffered data and cancels the pending flush
+func (b *bufferedWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return b.w.Flush()
+}
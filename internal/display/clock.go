@@ -0,0 +1,18 @@
+package display
+
+import "time"
+
+// Clock reports the current time
+// Display and the stream handler read time through a Clock so tests can pin
+// timestamps and throttle intervals instead of depending on the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the Clock used unless one is injected
+var SystemClock Clock = systemClock{}
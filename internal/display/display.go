@@ -2,10 +2,15 @@ package display
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/fatih/color"
 	"golang.org/x/term"
 
 	"github.com/daydemir/milhouse/internal/prd"
@@ -15,6 +20,20 @@ const (
 	defaultTermWidth = 80
 	minTermWidth     = 40
 	maxTermWidth     = 120
+
+	// DefaultTimestampFormat is the time layout used for message timestamps
+	DefaultTimestampFormat = "15:04:05"
+)
+
+// Settings picked up by every new Display
+var (
+	timestampFormat   = DefaultTimestampFormat
+	timestampLocation *time.Location // nil means local time
+	preserveCode      bool           // Keep code formatting in Claude output
+	compact           bool           // One line per Claude message
+	full              bool           // Complete, wrapped Claude messages
+	markdown          bool           // Render markdown in full Claude messages
+	themeName         string         // Preset for colored output; empty means default
 )
 
 // Display handles styled terminal output
@@ -22,14 +41,38 @@ type Display struct {
 	theme     *Theme
 	termWidth int
 	noColor   bool
+	out       io.Writer // Regular output (stdout)
+	errOut    io.Writer // Errors and warnings (stderr)
+	buffer    *bufferedWriter
+	activePRD string // Sticky PRD shown in iteration and phase headers
+
+	timeFormat   string         // Layout for message timestamps
+	timeLocation *time.Location // Zone for message timestamps; nil means local
+	clock        Clock          // Source of message timestamps
+
+	preserveCode bool    // Keep code lines verbatim instead of collapsing whitespace
+	inCodeFence  bool    // Inside a ``` block spanning streamed chunks
+	compact      bool    // Render each Claude message on a single line
+	full         bool    // Render complete Claude messages wrapped to the terminal
+	markdown     bool    // Format full Claude messages with RenderMarkdown
+	tokenRate    float64 // Output tokens per second, shown in full mode
 }
 
 // New creates a new Display with default settings
 func New() *Display {
 	return &Display{
-		theme:     DefaultTheme(),
-		termWidth: getTerminalWidth(),
-		noColor:   false,
+		theme:        ThemeByName(themeName),
+		termWidth:    getTerminalWidth(),
+		noColor:      false,
+		out:          color.Output,
+		errOut:       color.Error,
+		timeFormat:   timestampFormat,
+		timeLocation: timestampLocation,
+		clock:        SystemClock,
+		preserveCode: preserveCode,
+		compact:      compact,
+		full:         full,
+		markdown:     markdown,
 	}
 }
 
@@ -39,18 +82,173 @@ func NewWithOptions(noColor bool) *Display {
 	if noColor {
 		theme = NoColorTheme()
 	} else {
-		theme = DefaultTheme()
+		theme = ThemeByName(themeName)
 	}
+	theme.Validate()
 	return &Display{
-		theme:     theme,
-		termWidth: getTerminalWidth(),
-		noColor:   noColor,
+		theme:        theme,
+		termWidth:    getTerminalWidth(),
+		noColor:      noColor,
+		out:          color.Output,
+		errOut:       color.Error,
+		timeFormat:   timestampFormat,
+		timeLocation: timestampLocation,
+		clock:        SystemClock,
+		preserveCode: preserveCode,
+		compact:      compact,
+		full:         full,
+		markdown:     markdown,
+	}
+}
+
+// SetTheme replaces the color theme; nil restores the default and missing
+// colors are taken from it
+func (d *Display) SetTheme(t *Theme) {
+	if t == nil {
+		t = DefaultTheme()
+	}
+	t.Validate()
+	d.theme = t
+}
+
+// SetCompact selects ClaudeCompact rendering for streamed Claude messages
+func (d *Display) SetCompact(c bool) {
+	d.compact = c
+}
+
+// Compact reports whether Claude messages are rendered on a single line
+func (d *Display) Compact() bool {
+	return d.compact
+}
+
+// SetFull selects ClaudeFull rendering for streamed Claude messages
+func (d *Display) SetFull(f bool) {
+	d.full = f
+}
+
+// Full reports whether Claude messages are rendered complete and wrapped
+func (d *Display) Full() bool {
+	return d.full
+}
+
+// SetMarkdown formats Claude messages with RenderMarkdown when they are
+// rendered in full
+func (d *Display) SetMarkdown(m bool) {
+	d.markdown = m
+}
+
+// Markdown reports whether full Claude messages are rendered as markdown
+func (d *Display) Markdown() bool {
+	return d.markdown
+}
+
+// SetPreserveCode selects CleanTextPreserveCode for Claude output, keeping
+// fenced and indented code verbatim instead of flattening it into one line
+func (d *Display) SetPreserveCode(preserve bool) {
+	d.preserveCode = preserve
+	d.inCodeFence = false
+}
+
+// cleanText normalizes Claude output according to the preserve-code setting
+func (d *Display) cleanText(text string) string {
+	if !d.preserveCode {
+		return CleanText(text)
+	}
+	var cleaned string
+	cleaned, d.inCodeFence = CleanTextPreserveCode(text, d.inCodeFence)
+	return cleaned
+}
+
+// printClaudeText prints cleaned text; extra lines get a continuation gutter
+func (d *Display) printClaudeText(text string) {
+	lines := strings.Split(d.cleanText(text), "\n")
+	d.theme.ClaudeText.Fprintln(d.out, lines[0])
+	for _, line := range lines[1:] {
+		d.theme.ClaudeGutter.Fprintf(d.out, "  %s ", GutterCont)
+		d.theme.ClaudeText.Fprintln(d.out, line)
+	}
+}
+
+// SetTimestampFormat sets the time layout for message timestamps
+// An empty layout restores DefaultTimestampFormat.
+func (d *Display) SetTimestampFormat(layout string) {
+	if layout == "" {
+		layout = DefaultTimestampFormat
+	}
+	d.timeFormat = layout
+}
+
+// SetTimezone sets the zone for message timestamps; nil means local time
+func (d *Display) SetTimezone(loc *time.Location) {
+	d.timeLocation = loc
+}
+
+// SetClock replaces the time source for message timestamps; nil restores
+// the system clock
+func (d *Display) SetClock(c Clock) {
+	if c == nil {
+		c = SystemClock
+	}
+	d.clock = c
+}
+
+// timestamp returns the current time formatted for message prefixes
+func (d *Display) timestamp() string {
+	now := d.clock.Now()
+	if d.timeLocation != nil {
+		now = now.In(d.timeLocation)
+	}
+	layout := d.timeFormat
+	if layout == "" {
+		layout = DefaultTimestampFormat
+	}
+	return now.Format(layout)
+}
+
+// Out returns the writer for regular output, e.g. for streaming command output
+func (d *Display) Out() io.Writer {
+	return d.out
+}
+
+// SetOutput redirects regular output and diagnostics (errors, warnings)
+// Any buffered output is flushed to the previous writer first.
+func (d *Display) SetOutput(out, errOut io.Writer) {
+	buffered := d.buffer != nil
+	d.Flush()
+	d.buffer = nil
+	d.out = out
+	d.errOut = errOut
+	if buffered {
+		d.EnableBuffering(DefaultFlushInterval)
+	}
+}
+
+// EnableBuffering batches regular output, flushing at least every interval
+// Used for streaming Claude output, where each delta would otherwise cost
+// several write syscalls. Call Flush when the stream ends.
+func (d *Display) EnableBuffering(interval time.Duration) {
+	if d.buffer != nil {
+		return
+	}
+	d.buffer = newBufferedWriter(d.out, interval)
+	d.out = d.buffer
+}
+
+// Flush writes any buffered output
+func (d *Display) Flush() {
+	if d.buffer != nil {
+		d.buffer.Flush()
 	}
 }
 
 // getTerminalWidth returns the current terminal width
+// When stdout is not a console (piped, or a Windows terminal without a
+// console handle) the COLUMNS environment variable is used if set.
 func getTerminalWidth() int {
 	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, err = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
 	if err != nil || width < minTermWidth {
 		return defaultTermWidth
 	}
@@ -77,34 +275,61 @@ func (d *Display) MillhouseBox(title string, lines ...string) {
 	}
 	topBorder := titlePart + strings.Repeat(BoxHorizontal, remaining) + BoxTopRight
 
-	d.theme.MillhouseBox.Println(topBorder)
+	d.theme.MillhouseBox.Fprintln(d.out, topBorder)
 
 	// Content lines
 	for _, line := range lines {
 		wrapped := wrapText(line, width-4)
 		for _, wl := range wrapped {
-			d.theme.MillhouseBox.Print(BoxVertical + " ")
-			d.theme.MillhouseText.Print(fmt.Sprintf("%-*s", width-4, wl))
-			d.theme.MillhouseBox.Println(" " + BoxVertical)
+			d.theme.MillhouseBox.Fprint(d.out, BoxVertical+" ")
+			d.theme.MillhouseText.Fprint(d.out, fmt.Sprintf("%-*s", width-4, wl))
+			d.theme.MillhouseBox.Fprintln(d.out, " "+BoxVertical)
 		}
 	}
 
 	// Bottom border
 	bottomBorder := BoxBottomLeft + strings.Repeat(BoxHorizontal, width) + BoxBottomRight
-	d.theme.MillhouseBox.Println(bottomBorder)
+	d.theme.MillhouseBox.Fprintln(d.out, bottomBorder)
 }
 
 // SectionBreak prints a heavy horizontal line for section separation
 func (d *Display) SectionBreak() {
-	d.theme.SectionBreak.Println(strings.Repeat(BoxHeavy, d.termWidth))
+	d.theme.SectionBreak.Fprintln(d.out, strings.Repeat(BoxHeavy, d.termWidth))
 }
 
 // IterationHeader prints the iteration header with section breaks
 func (d *Display) IterationHeader(n, total int) {
-	fmt.Println()
+	fmt.Fprintln(d.out)
 	d.SectionBreak()
-	d.theme.MillhouseTitle.Printf("Iteration %d/%d\n", n, total)
+	d.theme.MillhouseTitle.Fprintf(d.out, "Iteration %d/%d\n", n, total)
 	d.SectionBreak()
+	d.stickyActivePRD()
+}
+
+// PhaseHeader prints a phase sub-header followed by the sticky active PRD
+func (d *Display) PhaseHeader(text string) {
+	d.SubHeader(text)
+	d.stickyActivePRD()
+}
+
+// SetActivePRD sets the PRD re-shown in iteration and phase headers
+// An empty ID clears it.
+func (d *Display) SetActivePRD(prdID string) {
+	d.activePRD = prdID
+}
+
+// ActivePRDID returns the sticky active PRD, or "" if none
+func (d *Display) ActivePRDID() string {
+	return d.activePRD
+}
+
+// stickyActivePRD re-prints the active PRD so it survives long scrolling output
+func (d *Display) stickyActivePRD() {
+	if d.activePRD == "" {
+		return
+	}
+	d.theme.Dim.Fprint(d.out, SymbolArrow+" Working on: ")
+	d.theme.ActivePRD.Fprintln(d.out, d.activePRD)
 }
 
 // Claude prints Claude output with gutter prefix
@@ -114,105 +339,202 @@ func (d *Display) Claude(text string, toolCount int) {
 
 // ClaudeWithTokens prints Claude output with timestamp, gutter, tool count, and tokens
 func (d *Display) ClaudeWithTokens(text string, toolCount int, usedTokens, maxTokens int) {
-	timestamp := time.Now().Format("15:04:05")
+	d.claudePrefix(toolCount, usedTokens, maxTokens)
+
+	// Print the text
+	d.printClaudeText(text)
+}
+
+// ClaudeCompact prints Claude output as a single line cut to the terminal
+// width, keeping the tool count and token badge
+func (d *Display) ClaudeCompact(text string, toolCount int, usedTokens, maxTokens int) {
+	width := d.claudePrefix(toolCount, usedTokens, maxTokens)
+	d.theme.ClaudeText.Fprintln(d.out, truncateWidth(CleanText(text), d.termWidth-width))
+}
 
+// ClaudeFull prints complete Claude output wrapped to the terminal width
+// The first line gets the normal prefix, the rest ClaudeContinuation lines;
+// line breaks in the text are kept and blank lines dropped. With markdown
+// set the text goes through RenderMarkdown instead.
+func (d *Display) ClaudeFull(text string, toolCount int, usedTokens, maxTokens int) {
+	// The first-line prefix is the widest, so it sets the wrap width
+	width := d.termWidth - d.claudePrefix(toolCount, usedTokens, maxTokens)
+
+	if d.markdown {
+		lines := d.RenderMarkdown(text, width)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		fmt.Fprintln(d.out, lines[0])
+		for _, line := range lines[1:] {
+			// Already styled, so printed without ClaudeContinuation's cleanup
+			d.theme.ClaudeGutter.Fprintf(d.out, "  %s [%s] ", GutterCont, d.timestamp())
+			fmt.Fprintln(d.out, line)
+		}
+		return
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, wrapText(line, width)...)
+		}
+	}
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+
+	d.theme.ClaudeText.Fprintln(d.out, lines[0])
+	for _, line := range lines[1:] {
+		d.ClaudeContinuation(line)
+	}
+}
+
+// claudePrefix prints "[timestamp] │ [tools] [tokens] " and returns its width
+func (d *Display) claudePrefix(toolCount int, usedTokens, maxTokens int) int {
 	// Build the prefix: [timestamp] │
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.ClaudeGutter.Print(GutterClaude + " ")
+	prefix := fmt.Sprintf("[%s] ", d.timestamp())
+	d.theme.ClaudeTimestamp.Fprint(d.out, prefix)
+	d.theme.ClaudeGutter.Fprint(d.out, GutterClaude+" ")
+	width := utf8.RuneCountInString(prefix) + 2
 
 	// Tool badge (always show, even when 0)
-	d.theme.ClaudeToolBadge.Printf("[%d] ", toolCount)
+	badge := fmt.Sprintf("[%d] ", toolCount)
+	d.theme.ClaudeToolBadge.Fprint(d.out, badge)
+	width += len(badge)
 
 	// Token display if provided
 	if usedTokens > 0 && maxTokens > 0 {
-		percentage := float64(usedTokens) / float64(maxTokens) * 100
-		d.theme.ClaudeTokens.Printf("[%.1fK/%.0fK] ", float64(usedTokens)/1000, float64(maxTokens)/1000)
-		_ = percentage // Could use for color selection
+		tokens := fmt.Sprintf("[%.1fK/%.0fK] ", float64(usedTokens)/1000, float64(maxTokens)/1000)
+		d.theme.ClaudeTokens.Fprint(d.out, tokens)
+		width += len(tokens)
 	}
 
-	// Print the text
-	d.theme.ClaudeText.Println(CleanText(text))
+	// Throughput, for spotting stalls in verbose output
+	if d.full && d.tokenRate >= 0.5 {
+		rate := fmt.Sprintf("[%.0f tok/s] ", d.tokenRate)
+		d.theme.ClaudeTokens.Fprint(d.out, rate)
+		width += len(rate)
+	}
+	return width
+}
+
+// SetTokenRate sets the output token rate shown next to the token badge
+// It only appears in full mode; 0 hides it.
+func (d *Display) SetTokenRate(tokensPerSecond float64) {
+	d.tokenRate = tokensPerSecond
 }
 
 // ClaudeContinuation prints a continuation line with subdued gutter
 func (d *Display) ClaudeContinuation(text string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeGutter.Printf("  %s [%s] ", GutterCont, timestamp)
-	d.theme.ClaudeText.Println(CleanText(text))
+	timestamp := d.timestamp()
+	d.theme.ClaudeGutter.Fprintf(d.out, "  %s [%s] ", GutterCont, timestamp)
+	d.printClaudeText(text)
 }
 
 // ClaudeStreaming prints streaming Claude text (no newline)
 func (d *Display) ClaudeStreaming(text string) {
-	d.theme.ClaudeText.Print(text)
+	d.theme.ClaudeText.Fprint(d.out, text)
 }
 
 // AnalysisStart prints the reviewer start indicator
 func (d *Display) AnalysisStart() {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.ReviewerGutter.Printf("%s ", GutterReviewer)
-	d.theme.ReviewerText.Println("[reviewer] Starting review...")
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.out, "[%s] ", timestamp)
+	d.theme.ReviewerGutter.Fprintf(d.out, "%s ", GutterReviewer)
+	d.theme.ReviewerText.Fprintln(d.out, "[reviewer] Starting review...")
 }
 
 // Header prints a styled header (backwards compatible)
 func (d *Display) Header(text string) {
-	fmt.Println()
+	fmt.Fprintln(d.out)
 	d.MillhouseBox("MILHOUSE", text)
 }
 
 // SubHeader prints a styled sub-header
 func (d *Display) SubHeader(text string) {
-	fmt.Println()
-	d.theme.MillhouseTitle.Println(text)
+	fmt.Fprintln(d.out)
+	d.theme.MillhouseTitle.Fprintln(d.out, text)
 }
 
 // Success prints a success message with checkmark
 func (d *Display) Success(text string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.Success.Printf("%s ", SymbolCheck)
-	fmt.Println(text)
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.out, "[%s] ", timestamp)
+	d.theme.Success.Fprintf(d.out, "%s ", SymbolCheck)
+	fmt.Fprintln(d.out, text)
 }
 
-// Error prints an error message with X
+// Error prints an error message with X to stderr
 func (d *Display) Error(text string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.Error.Printf("%s ", SymbolCross)
-	fmt.Println(text)
+	d.Flush() // Keep ordering with buffered regular output
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.errOut, "[%s] ", timestamp)
+	d.theme.Error.Fprintf(d.errOut, "%s ", SymbolCross)
+	fmt.Fprintln(d.errOut, text)
 }
 
-// Warning prints a warning message
+// Warning prints a warning message to stderr
 func (d *Display) Warning(text string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.Warning.Printf("%s ", SymbolWarning)
-	fmt.Println(text)
+	d.Flush() // Keep ordering with buffered regular output
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.errOut, "[%s] ", timestamp)
+	d.theme.Warning.Fprintf(d.errOut, "%s ", SymbolWarning)
+	fmt.Fprintln(d.errOut, text)
 }
 
 // Info prints an info message
 func (d *Display) Info(text string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.Info.Printf("%s ", SymbolArrow)
-	fmt.Println(text)
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.out, "[%s] ", timestamp)
+	d.theme.Info.Fprintf(d.out, "%s ", SymbolArrow)
+	fmt.Fprintln(d.out, text)
 }
 
-// Signal prints a detected signal with warning style
+// Signal categories, from good news to bad
+const (
+	signalPositive = "positive"
+	signalNeutral  = "neutral"
+	signalNegative = "negative"
+)
+
+// signalCategory classifies a signal type so it can be styled at a glance
+// The types mirror the llm package's signal constants; unknown ones are neutral.
+func signalCategory(signalType string) string {
+	switch signalType {
+	case "VERIFIED", "PLAN_COMPLETE", "PRD_COMPLETE", "SUBTASK_DONE":
+		return signalPositive
+	case "BAILOUT", "BLOCKED", "REJECTED":
+		return signalNegative
+	default:
+		return signalNeutral
+	}
+}
+
+// Signal prints a detected signal styled by its category: green for
+// completions, red for bailouts and rejections, yellow for the rest
 func (d *Display) Signal(signal, details string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.Warning.Printf("%s >>> %s", SymbolWarning, signal)
+	style, symbol := d.theme.Warning, SymbolWarning
+	switch signalCategory(signal) {
+	case signalPositive:
+		style, symbol = d.theme.Success, SymbolCheck
+	case signalNegative:
+		style, symbol = d.theme.Error, SymbolCross
+	}
+
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.out, "[%s] ", timestamp)
+	style.Fprintf(d.out, "%s >>> %s", symbol, signal)
 	if details != "" {
-		fmt.Printf(": %s", details)
+		fmt.Fprintf(d.out, ": %s", details)
 	}
-	fmt.Println()
+	fmt.Fprintln(d.out)
 }
 
 // TokenUsage prints token usage information
 func (d *Display) TokenUsage(input, output, total int) {
-	fmt.Println() // Ensure new line after Claude output
-	timestamp := time.Now().Format("15:04:05")
+	fmt.Fprintln(d.out) // Ensure new line after Claude output
+	timestamp := d.timestamp()
 	percentage := float64(total) / 100000 * 100
 
 	var statusColor = d.theme.Success
@@ -226,13 +548,13 @@ func (d *Display) TokenUsage(input, output, total int) {
 	tokenStr := fmt.Sprintf("%.1fK", float64(total)/1000)
 
 	// Compact format: [HH:MM:SS | tokens/100K] ✓ - unified color
-	statusColor.Printf("[%s | %s/100K] %s\n", timestamp, tokenStr, SymbolCheck)
+	statusColor.Fprintf(d.out, "[%s | %s/100K] %s\n", timestamp, tokenStr, SymbolCheck)
 }
 
 // TokenUsageDetailed prints detailed token usage breakdown with input/output stats
 func (d *Display) TokenUsageDetailed(input, output, total, threshold int) {
-	fmt.Println() // Ensure new line after Claude output
-	timestamp := time.Now().Format("15:04:05")
+	fmt.Fprintln(d.out) // Ensure new line after Claude output
+	timestamp := d.timestamp()
 	percentage := float64(total) / float64(threshold) * 100
 
 	var statusColor = d.theme.Success
@@ -243,7 +565,7 @@ func (d *Display) TokenUsageDetailed(input, output, total, threshold int) {
 	}
 
 	// Detailed format: [HH:MM:SS | Input=XK Output=YK Total=ZK (XX.X%)] ✓
-	statusColor.Printf("[%s | Input=%.1fK Output=%.1fK Total=%.1fK (%.1f%%)] %s\n",
+	statusColor.Fprintf(d.out, "[%s | Input=%.1fK Output=%.1fK Total=%.1fK (%.1f%%)] %s\n",
 		timestamp,
 		float64(input)/1000,
 		float64(output)/1000,
@@ -252,126 +574,406 @@ func (d *Display) TokenUsageDetailed(input, output, total, threshold int) {
 		SymbolCheck)
 }
 
+// ToolSummary prints how many tools a phase used, most used first
+// e.g. "[12:00:00] Tools: 19 (Read ×12, Edit ×4, Bash ×3)"
+func (d *Display) ToolSummary(counts map[string]int) {
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.out, "[%s] ", timestamp)
+	d.theme.Dim.Fprintln(d.out, formatToolCounts(counts))
+}
+
+// formatToolCounts renders tool counts sorted by count, then name
+func formatToolCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "Tools: none"
+	}
+
+	names := make([]string, 0, len(counts))
+	total := 0
+	for name, n := range counts {
+		names = append(names, name)
+		total += n
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s ×%d", name, counts[name])
+	}
+	return fmt.Sprintf("Tools: %d (%s)", total, strings.Join(parts, ", "))
+}
+
 // PRDStatus prints PRD status with color coding
 func (d *Display) PRDStatus(p prd.PRD) {
-	var status string
-	var statusColor = d.theme.Error
-
-	if p.Passes.IsTrue() {
-		status = "complete"
-		statusColor = d.theme.Success
-	} else if p.Passes.IsPending() {
-		status = "pending"
-		statusColor = d.theme.Warning
-	} else if p.Passes.IsActive() {
-		status = "active"
-		statusColor = d.theme.Info
-	} else {
-		status = "open"
-		statusColor = d.theme.Error
-	}
+	status, statusColor := d.statusStyle(p)
 
-	statusColor.Printf("  [%s]", status)
-	fmt.Printf(" P%d ", p.Priority)
-	d.theme.Bold.Print(p.ID)
-	fmt.Printf(": %s\n", p.Description)
+	statusColor.Fprintf(d.out, "  [%s]", status)
+	fmt.Fprintf(d.out, " P%d ", p.Priority)
+	d.theme.Bold.Fprint(d.out, p.ID)
+	if p.Owner != "" {
+		d.theme.Dim.Fprintf(d.out, " @%s", p.Owner)
+	}
+	fmt.Fprintf(d.out, ": %s", p.Description)
+	if done, total := p.SubtaskProgress(); total > 0 {
+		d.theme.Dim.Fprintf(d.out, " [%d/%d]", done, total)
+	}
+	fmt.Fprintln(d.out)
 
 	if p.Notes != "" {
 		notes := Truncate(p.Notes, 60)
-		d.theme.Dim.Printf("       %s\n", notes)
+		d.theme.Dim.Fprintf(d.out, "       %s\n", notes)
+	}
+
+	for _, l := range p.Links() {
+		d.theme.Dim.Fprintf(d.out, "       %s: ", l.Type)
+		fmt.Fprintln(d.out, d.link(l.URL))
+	}
+
+	if len(p.History) > 0 {
+		d.theme.Dim.Fprintf(d.out, "       history: %s\n", historyTimeline(p.History))
+	}
+}
+
+// statusStyle returns a PRD's status name and the color used to show it
+func (d *Display) statusStyle(p prd.PRD) (string, *color.Color) {
+	status := p.Passes.String()
+	return status, d.statusColor(status)
+}
+
+// statusColor returns the color used to show a status name
+func (d *Display) statusColor(status string) *color.Color {
+	switch status {
+	case prd.StatusComplete:
+		return d.theme.Success
+	case prd.StatusPending, prd.StatusBlocked:
+		return d.theme.Warning
+	case prd.StatusActive:
+		return d.theme.Info
+	default:
+		return d.theme.Error
+	}
+}
+
+// StatusChange prints a PRD moving between statuses; an empty from means the
+// PRD was added and an empty to that it was removed
+func (d *Display) StatusChange(prdID, from, to string) {
+	fmt.Fprint(d.out, "  ")
+	d.theme.Bold.Fprint(d.out, prdID)
+	switch {
+	case from == "":
+		fmt.Fprint(d.out, ": added as ")
+		d.statusColor(to).Fprintln(d.out, to)
+	case to == "":
+		d.theme.Dim.Fprintf(d.out, ": removed (was %s)\n", from)
+	default:
+		fmt.Fprintf(d.out, ": %s %s ", from, SymbolArrow)
+		d.statusColor(to).Fprintln(d.out, to)
 	}
 }
 
+// PlanChange prints a plan file being created or deleted for a PRD
+func (d *Display) PlanChange(prdID string, created bool) {
+	fmt.Fprint(d.out, "  ")
+	d.theme.Bold.Fprint(d.out, prdID)
+	if created {
+		d.theme.Dim.Fprintln(d.out, ": plan created")
+	} else {
+		d.theme.Dim.Fprintln(d.out, ": plan deleted")
+	}
+}
+
+// PRDDetail prints everything known about a single PRD
+func (d *Display) PRDDetail(p prd.PRD, planExists, evidenceExists bool) {
+	status, statusColor := d.statusStyle(p)
+
+	d.Stat("ID", p.ID)
+	d.theme.Dim.Fprintf(d.out, "  %-26s", "Status:")
+	statusColor.Fprintln(d.out, status)
+	d.Stat("Priority", fmt.Sprintf("P%d", p.Priority))
+	if p.Owner != "" {
+		d.Stat("Owner", p.Owner)
+	}
+	if len(p.Tags) > 0 {
+		d.Stat("Tags", strings.Join(p.Tags, ", "))
+	}
+	if p.ModelOverride != "" {
+		d.Stat("Model override", p.ModelOverride)
+	}
+	if done, total := p.SubtaskProgress(); total > 0 {
+		d.Stat("Subtasks", fmt.Sprintf("%d/%d done", done, total))
+	}
+	d.Stat("Plan", yesNo(planExists))
+	d.Stat("Evidence", yesNo(evidenceExists))
+
+	d.SubHeader("Description")
+	for _, line := range wrapText(p.Description, d.termWidth-4) {
+		fmt.Fprintf(d.out, "  %s\n", line)
+	}
+
+	d.SubHeader("Acceptance Criteria")
+	if len(p.AcceptanceCriteria) == 0 {
+		d.theme.Dim.Fprintln(d.out, "  None")
+	}
+	for i, c := range p.AcceptanceCriteria {
+		fmt.Fprintf(d.out, "  %d. %s\n", i+1, c)
+	}
+
+	if len(p.Subtasks) > 0 {
+		d.SubHeader("Subtasks")
+		for i, s := range p.Subtasks {
+			if s.Done {
+				d.theme.Success.Fprintf(d.out, "  %d. %s ", i+1, SymbolCheck)
+			} else {
+				d.theme.Dim.Fprintf(d.out, "  %d. %s ", i+1, SymbolCircle)
+			}
+			fmt.Fprintln(d.out, s.Description)
+		}
+	}
+
+	if a := p.Approach; a != nil {
+		d.SubHeader("Planned Approach")
+		for _, line := range wrapText(a.Summary, d.termWidth-4) {
+			fmt.Fprintf(d.out, "  %s\n", line)
+		}
+		if a.Risk != "" {
+			d.Stat("Risk", a.Risk)
+		}
+		if len(a.Files) > 0 {
+			d.Stat("Files", strings.Join(a.Files, ", "))
+		}
+		if !a.PlannedAt.IsZero() {
+			d.Stat("Planned", a.PlannedAt.Local().Format("2006-01-02 15:04"))
+		}
+	}
+
+	if links := p.Links(); len(links) > 0 {
+		d.SubHeader("Links")
+		for _, l := range links {
+			d.theme.Dim.Fprintf(d.out, "  %-8s ", l.Type)
+			fmt.Fprintln(d.out, d.link(l.URL))
+		}
+	}
+
+	d.SubHeader("Notes")
+	if p.Notes == "" {
+		d.theme.Dim.Fprintln(d.out, "  None")
+	} else {
+		for _, line := range strings.Split(strings.TrimRight(p.Notes, "\n"), "\n") {
+			fmt.Fprintf(d.out, "  %s\n", line)
+		}
+	}
+
+	d.SubHeader("History")
+	d.PRDHistory(p)
+}
+
+// yesNo renders a boolean for detail views
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// PRDHistory prints a PRD's full status transition timeline
+func (d *Display) PRDHistory(p prd.PRD) {
+	if len(p.History) == 0 {
+		d.theme.Dim.Fprintln(d.out, "  No recorded transitions")
+		return
+	}
+
+	for _, t := range p.History {
+		d.theme.ClaudeTimestamp.Fprintf(d.out, "  [%s] ", t.At.Local().Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(d.out, "%s %s %s", t.From, SymbolArrow, t.To)
+		if t.Iteration > 0 {
+			d.theme.Dim.Fprintf(d.out, " (iteration %d)", t.Iteration)
+		}
+		if t.Commit != "" {
+			d.theme.Dim.Fprintf(d.out, " commit %s", t.Commit[:min(7, len(t.Commit))])
+		}
+		fmt.Fprintln(d.out)
+	}
+
+	rejections := p.CountTransitions(prd.StatusPending, prd.StatusOpen)
+	fmt.Fprintf(d.out, "\n  %d transitions, %d rejections\n", len(p.History), rejections)
+}
+
+// historyTimeline renders transitions as a compact chain of statuses
+func historyTimeline(history []prd.Transition) string {
+	parts := []string{history[0].From}
+	for _, t := range history {
+		parts = append(parts, t.To)
+	}
+	return strings.Join(parts, " "+SymbolArrow+" ")
+}
+
 // Summary prints a summary line
 func (d *Display) Summary(open, pending, complete int) {
 	total := open + pending + complete
-	d.theme.Bold.Printf("\nTotal: %d ", total)
-	fmt.Print("(")
-	d.theme.Error.Printf("%d open", open)
-	fmt.Print(", ")
-	d.theme.Warning.Printf("%d pending", pending)
-	fmt.Print(", ")
-	d.theme.Success.Printf("%d complete", complete)
-	fmt.Println(")")
+	d.theme.Bold.Fprintf(d.out, "\nTotal: %d ", total)
+	fmt.Fprint(d.out, "(")
+	d.theme.Error.Fprintf(d.out, "%d open", open)
+	fmt.Fprint(d.out, ", ")
+	d.theme.Warning.Fprintf(d.out, "%d pending", pending)
+	fmt.Fprint(d.out, ", ")
+	d.theme.Success.Fprintf(d.out, "%d complete", complete)
+	fmt.Fprintln(d.out, ")")
 }
 
 // SummaryExtended prints a summary line with active count
 func (d *Display) SummaryExtended(open, active, pending, complete int) {
 	total := open + active + pending + complete
-	d.theme.Bold.Printf("\nTotal: %d ", total)
-	fmt.Print("(")
-	d.theme.Error.Printf("%d open", open)
-	fmt.Print(", ")
-	d.theme.Info.Printf("%d active", active)
-	fmt.Print(", ")
-	d.theme.Warning.Printf("%d pending", pending)
-	fmt.Print(", ")
-	d.theme.Success.Printf("%d complete", complete)
-	fmt.Println(")")
+	d.theme.Bold.Fprintf(d.out, "\nTotal: %d ", total)
+	fmt.Fprint(d.out, "(")
+	d.theme.Error.Fprintf(d.out, "%d open", open)
+	fmt.Fprint(d.out, ", ")
+	d.theme.Info.Fprintf(d.out, "%d active", active)
+	fmt.Fprint(d.out, ", ")
+	d.theme.Warning.Fprintf(d.out, "%d pending", pending)
+	fmt.Fprint(d.out, ", ")
+	d.theme.Success.Fprintf(d.out, "%d complete", complete)
+	fmt.Fprintln(d.out, ")")
 }
 
 // SummaryCompact prints a one-line summary (for compact status)
 func (d *Display) SummaryCompact(open, pending, complete int) {
 	total := open + pending + complete
-	fmt.Print("PRDs: ")
-	d.theme.Error.Printf("%d open", open)
-	fmt.Print(", ")
-	d.theme.Warning.Printf("%d pending", pending)
-	fmt.Print(", ")
-	d.theme.Success.Printf("%d complete", complete)
-	fmt.Printf(" (%d total)\n", total)
+	fmt.Fprint(d.out, "PRDs: ")
+	d.theme.Error.Fprintf(d.out, "%d open", open)
+	fmt.Fprint(d.out, ", ")
+	d.theme.Warning.Fprintf(d.out, "%d pending", pending)
+	fmt.Fprint(d.out, ", ")
+	d.theme.Success.Fprintf(d.out, "%d complete", complete)
+	fmt.Fprintf(d.out, " (%d total)\n", total)
 }
 
 // PRDStatusCompact prints a one-line PRD status
 func (d *Display) PRDStatusCompact(p prd.PRD) {
-	if p.Passes.IsTrue() {
-		d.theme.Success.Print("  ✓ ")
-	} else if p.Passes.IsPending() {
-		d.theme.Warning.Print("  ⏸ ")
+	switch p.Passes.String() {
+	case prd.StatusComplete:
+		d.theme.Success.Fprint(d.out, "  ✓ ")
+	case prd.StatusPending:
+		d.theme.Warning.Fprint(d.out, "  ⏸ ")
+	case prd.StatusBlocked:
+		d.theme.Warning.Fprint(d.out, "  ⊘ ")
+	default:
+		d.theme.Dim.Fprint(d.out, "  • ")
+	}
+	d.theme.Bold.Fprintln(d.out, p.ID)
+}
+
+// PRDAge prints a PRD with how long it has been open, for staleness reports
+func (d *Display) PRDAge(p prd.PRD) {
+	d.theme.Dim.Fprint(d.out, "  • ")
+	d.theme.Bold.Fprint(d.out, p.ID)
+	if age, ok := p.Age(d.clock.Now()); ok {
+		fmt.Fprintf(d.out, "  open %s", FormatAge(age))
 	} else {
-		d.theme.Dim.Print("  • ")
+		d.theme.Dim.Fprint(d.out, "  unknown age")
+	}
+	d.theme.Dim.Fprintf(d.out, "  %s\n", Truncate(p.Description, 50))
+}
+
+// FormatAge renders a duration in whole days, or hours when under a day
+func FormatAge(age time.Duration) string {
+	days, hours := int(age.Hours())/24, int(age.Hours())
+	switch {
+	case days == 1:
+		return "1 day"
+	case days > 1:
+		return fmt.Sprintf("%d days", days)
+	case hours == 1:
+		return "1 hour"
+	case hours > 1:
+		return fmt.Sprintf("%d hours", hours)
+	default:
+		return "less than an hour"
 	}
-	d.theme.Bold.Println(p.ID)
+}
+
+// FormatDuration renders a wall-clock span compactly, e.g. 42s, 3m12s or 1h02m
+func FormatDuration(elapsed time.Duration) string {
+	if elapsed < time.Second {
+		return fmt.Sprintf("%.1fs", elapsed.Seconds())
+	}
+	elapsed = elapsed.Round(time.Second)
+	switch {
+	case elapsed < time.Minute:
+		return fmt.Sprintf("%ds", int(elapsed.Seconds()))
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+	default:
+		elapsed = elapsed.Round(time.Minute)
+		return fmt.Sprintf("%dh%02dm", int(elapsed.Hours()), int(elapsed.Minutes())%60)
+	}
+}
+
+// PhaseDuration prints how long a phase took
+func (d *Display) PhaseDuration(phase string, elapsed time.Duration) {
+	d.theme.Dim.Fprintf(d.out, "  %s took %s\n", phase, FormatDuration(elapsed))
+}
+
+// IterationDuration prints how long an iteration took
+func (d *Display) IterationDuration(n int, elapsed time.Duration) {
+	d.theme.Dim.Fprintf(d.out, "Iteration %d took %s\n", n, FormatDuration(elapsed))
+}
+
+// Stat prints a labeled metric line for reports
+func (d *Display) Stat(label, value string) {
+	d.theme.Dim.Fprintf(d.out, "  %-26s", label+":")
+	fmt.Fprintln(d.out, value)
 }
 
 // Divider prints a horizontal divider
 func (d *Display) Divider() {
-	d.theme.Dim.Println(strings.Repeat(BoxHorizontal, 50))
+	d.theme.Dim.Fprintln(d.out, strings.Repeat(BoxHorizontal, 50))
+}
+
+// legacyAgentNames maps retired agent names to the phase that replaced them
+// The analyzer was folded into the reviewer and the executor into the builder;
+// the reviewer package is the only verification implementation.
+var legacyAgentNames = map[string]string{
+	"executor": "builder",
+	"analyzer": "reviewer",
 }
 
 // AgentHeader prints a header for agent execution
 func (d *Display) AgentHeader(agentType, prdID string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.ClaudeGutter.Printf("%s ", GutterClaude)
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.out, "[%s] ", timestamp)
+	d.theme.ClaudeGutter.Fprintf(d.out, "%s ", GutterClaude)
+
+	if current, ok := legacyAgentNames[agentType]; ok {
+		agentType = current
+	}
 
 	switch agentType {
 	case "planner":
-		d.theme.Warning.Printf("[%s]", agentType)
+		d.theme.Warning.Fprintf(d.out, "[%s]", agentType)
 	case "builder":
-		d.theme.Info.Printf("[%s]", agentType)
+		d.theme.Info.Fprintf(d.out, "[%s]", agentType)
 	case "reviewer":
-		d.theme.ReviewerText.Printf("[%s]", agentType)
-	// Legacy support
-	case "executor":
-		d.theme.Info.Printf("[%s]", agentType)
-	case "analyzer":
-		d.theme.ReviewerText.Printf("[%s]", agentType)
+		d.theme.ReviewerText.Fprintf(d.out, "[%s]", agentType)
 	default:
-		fmt.Printf("[%s]", agentType)
+		fmt.Fprintf(d.out, "[%s]", agentType)
 	}
 
-	fmt.Printf(" Working on: ")
-	d.theme.Bold.Println(prdID)
+	fmt.Fprintf(d.out, " Working on: ")
+	d.theme.Bold.Fprintln(d.out, prdID)
 }
 
 // ActivePRD prints the active PRD with prominent highlighting
 func (d *Display) ActivePRD(prdID string) {
-	timestamp := time.Now().Format("15:04:05")
-	d.theme.ClaudeTimestamp.Printf("[%s] ", timestamp)
-	d.theme.ClaudeGutter.Print(GutterClaude + " ")
-	fmt.Print("WORKING ON: ")
-	d.theme.ActivePRD.Println(prdID)
+	timestamp := d.timestamp()
+	d.theme.ClaudeTimestamp.Fprintf(d.out, "[%s] ", timestamp)
+	d.theme.ClaudeGutter.Fprint(d.out, GutterClaude+" ")
+	fmt.Fprint(d.out, "WORKING ON: ")
+	d.theme.ActivePRD.Fprintln(d.out, prdID)
 }
 
 // --- Text Utilities ---
@@ -401,6 +1003,21 @@ func wrapText(text string, width int) []string {
 	return lines
 }
 
+// Hyperlink wraps text in an OSC 8 escape so terminals that support it make
+// it a clickable link to url; others show the text alone
+func Hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// link renders url as a clickable link when writing styled output, and as
+// plain text otherwise
+func (d *Display) link(url string) string {
+	if d.noColor || color.NoColor {
+		return url
+	}
+	return Hyperlink(url, url)
+}
+
 // Truncate truncates text to max length with ellipsis
 func Truncate(text string, maxLen int) string {
 	if len(text) <= maxLen {
@@ -412,6 +1029,18 @@ func Truncate(text string, maxLen int) string {
 	return text[:maxLen-3] + "..."
 }
 
+// truncateWidth cuts text to width runes, ending in an ellipsis when cut
+func truncateWidth(text string, width int) string {
+	if width < 1 {
+		width = 1
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
 // CleanText removes control characters and normalizes whitespace
 func CleanText(text string) string {
 	// Replace multiple whitespace with single space
@@ -419,6 +1048,45 @@ func CleanText(text string) string {
 	return strings.TrimSpace(text)
 }
 
+// CleanTextPreserveCode normalizes prose like CleanText but keeps code intact
+// Lines inside ``` fences and lines indented by a tab or four spaces are kept
+// verbatim (minus trailing whitespace); other lines have their whitespace
+// collapsed, and runs of blank lines become one. inFence carries the fence
+// state across streamed chunks; the updated state is returned.
+func CleanTextPreserveCode(text string, inFence bool) (string, bool) {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inFence = !inFence
+			lines = append(lines, line)
+		case inFence:
+			lines = append(lines, line)
+		case trimmed == "":
+			if !blank && len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		case strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    "):
+			lines = append(lines, line)
+		default:
+			lines = append(lines, strings.Join(strings.Fields(line), " "))
+		}
+		blank = false
+	}
+
+	// Drop a trailing blank line left by the run collapsing above
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n"), inFence
+}
+
 // --- Backwards Compatibility Layer ---
 
 var defaultDisplay *Display
@@ -427,6 +1095,56 @@ func init() {
 	defaultDisplay = New()
 }
 
+// SetTimestampDefaults sets the timestamp layout and zone for the default
+// Display and every Display created afterwards
+// An empty layout restores DefaultTimestampFormat; a nil loc means local time.
+func SetTimestampDefaults(layout string, loc *time.Location) {
+	if layout == "" {
+		layout = DefaultTimestampFormat
+	}
+	timestampFormat = layout
+	timestampLocation = loc
+	defaultDisplay.SetTimestampFormat(layout)
+	defaultDisplay.SetTimezone(loc)
+}
+
+// SetPreserveCodeDefault sets the preserve-code mode for the default Display
+// and every Display created afterwards
+func SetPreserveCodeDefault(preserve bool) {
+	preserveCode = preserve
+	defaultDisplay.SetPreserveCode(preserve)
+}
+
+// SetCompactDefault sets compact Claude output for the default Display and
+// every Display created afterwards
+func SetCompactDefault(c bool) {
+	compact = c
+	defaultDisplay.SetCompact(c)
+}
+
+// SetFullDefault sets full Claude output for the default Display and every
+// Display created afterwards
+func SetFullDefault(f bool) {
+	full = f
+	defaultDisplay.SetFull(f)
+}
+
+// SetMarkdownDefault sets markdown rendering of full Claude output for the
+// default Display and every Display created afterwards
+func SetMarkdownDefault(m bool) {
+	markdown = m
+	defaultDisplay.SetMarkdown(m)
+}
+
+// SetThemeDefault selects the named theme preset for the default Display and
+// every Display created afterwards; displays without color keep no color
+func SetThemeDefault(name string) {
+	themeName = name
+	if !defaultDisplay.noColor {
+		defaultDisplay.SetTheme(ThemeByName(name))
+	}
+}
+
 // Package-level functions that delegate to the default Display instance
 
 // Header prints a styled header
@@ -464,6 +1182,16 @@ func PRDStatus(p prd.PRD) {
 	defaultDisplay.PRDStatus(p)
 }
 
+// PRDHistory prints a PRD's status transition timeline
+func PRDHistory(p prd.PRD) {
+	defaultDisplay.PRDHistory(p)
+}
+
+// PRDDetail prints everything known about a single PRD
+func PRDDetail(p prd.PRD, planExists, evidenceExists bool) {
+	defaultDisplay.PRDDetail(p, planExists, evidenceExists)
+}
+
 // Summary prints a summary line
 func Summary(open, pending, complete int) {
 	defaultDisplay.Summary(open, pending, complete)
@@ -504,6 +1232,11 @@ func PRDStatusCompact(p prd.PRD) {
 	defaultDisplay.PRDStatusCompact(p)
 }
 
+// PRDAge prints a PRD with how long it has been open, for staleness reports
+func PRDAge(p prd.PRD) {
+	defaultDisplay.PRDAge(p)
+}
+
 // ActivePRD prints the active PRD with highlighting
 func ActivePRD(prdID string) {
 	defaultDisplay.ActivePRD(prdID)
@@ -0,0 +1,557 @@
+package display
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestDiagnosticsGoToErrOut(t *testing.T) {
+	var out, errOut bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&out, &errOut)
+
+	d.Success("built")
+	d.Info("running")
+	d.Warning("careful")
+	d.Error("failed")
+
+	if !strings.Contains(out.String(), "built") || !strings.Contains(out.String(), "running") {
+		t.Errorf("Success/Info should write to out, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "careful") || strings.Contains(out.String(), "failed") {
+		t.Errorf("Warning/Error should not write to out, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "careful") || !strings.Contains(errOut.String(), "failed") {
+		t.Errorf("Warning/Error should write to errOut, got %q", errOut.String())
+	}
+}
+
+// countingWriter counts Write calls to measure syscall pressure
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+// streamFixture simulates a verbose run: many small streamed deltas
+func streamFixture(n int) []string {
+	deltas := make([]string, n)
+	for i := range deltas {
+		deltas[i] = "Reading internal/llm/output.go to check the signal parser"
+	}
+	return deltas
+}
+
+func TestBufferingBatchesWrites(t *testing.T) {
+	deltas := streamFixture(200)
+
+	var plain countingWriter
+	d := NewWithOptions(true)
+	d.SetOutput(&plain, &bytes.Buffer{})
+	for _, text := range deltas {
+		d.ClaudeStreaming(text)
+	}
+
+	var buffered countingWriter
+	bd := NewWithOptions(true)
+	bd.SetOutput(&buffered, &bytes.Buffer{})
+	bd.EnableBuffering(time.Hour) // Only explicit flushes
+	for _, text := range deltas {
+		bd.ClaudeStreaming(text)
+	}
+	if buffered.Len() != 0 {
+		t.Fatalf("output written before Flush: %d bytes", buffered.Len())
+	}
+	bd.Flush()
+
+	if buffered.String() != plain.String() {
+		t.Error("buffered output differs from unbuffered output")
+	}
+	if buffered.writes >= plain.writes {
+		t.Errorf("expected fewer writes with buffering, got %d vs %d", buffered.writes, plain.writes)
+	}
+}
+
+func TestErrorFlushesBufferedOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&out, &errOut)
+	d.EnableBuffering(time.Hour)
+
+	d.Info("before")
+	d.Error("failed")
+
+	if !strings.Contains(out.String(), "before") {
+		t.Errorf("Error should flush pending output first, got %q", out.String())
+	}
+}
+
+func BenchmarkClaudeWithTokens(b *testing.B) {
+	deltas := streamFixture(5000)
+
+	for _, bc := range []struct {
+		name     string
+		buffered bool
+	}{{"unbuffered", false}, {"buffered", true}} {
+		b.Run(bc.name, func(b *testing.B) {
+			var w countingWriter
+			d := NewWithOptions(true)
+			d.SetOutput(&w, &bytes.Buffer{})
+			if bc.buffered {
+				d.EnableBuffering(DefaultFlushInterval)
+			}
+			for i := 0; i < b.N; i++ {
+				for _, text := range deltas {
+					d.ClaudeWithTokens(text, 3, 42000, 100000)
+				}
+				d.Flush()
+				w.Reset()
+			}
+			b.ReportMetric(float64(w.writes)/float64(b.N), "writes/op")
+		})
+	}
+}
+
+func TestPRDDetail(t *testing.T) {
+	var out bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&out, &bytes.Buffer{})
+
+	p := prd.PRD{ID: "auth-1", Description: "Add login", AcceptanceCriteria: []string{"works", "tested"}, Priority: 2}
+	p.Passes.SetPending()
+	p.Approach = &prd.PlannedApproach{Summary: "Session cookie middleware", Files: []string{"auth/login.go", "auth/session.go"}, Risk: prd.RiskMedium}
+	d.PRDDetail(p, true, false)
+
+	for _, want := range []string{"auth-1", "pending", "P2", "1. works", "2. tested", "No recorded transitions",
+		"Planned Approach", "Session cookie middleware", "medium", "auth/login.go, auth/session.go"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("detail view missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPRDLinks(t *testing.T) {
+	p := prd.PRD{ID: "auth-1", Description: "Add login"}
+	p.AddLink(prd.Link{Type: "jira", URL: "https://acme.atlassian.net/browse/AUTH-12"})
+
+	var out bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&out, &bytes.Buffer{})
+	d.PRDDetail(p, false, false)
+	if !strings.Contains(out.String(), "jira     https://acme.atlassian.net/browse/AUTH-12\n") {
+		t.Errorf("detail view missing the plain link:\n%s", out.String())
+	}
+
+	if got, want := Hyperlink("https://x.test", "x"), "\x1b]8;;https://x.test\x1b\\x\x1b]8;;\x1b\\"; got != want {
+		t.Errorf("Hyperlink() = %q, want %q", got, want)
+	}
+}
+
+func TestSubtaskProgress(t *testing.T) {
+	var out bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&out, &bytes.Buffer{})
+
+	p := prd.PRD{ID: "big-1", Description: "Big", Subtasks: []prd.Subtask{
+		{Description: "schema", Done: true},
+		{Description: "api"},
+	}}
+	d.PRDStatus(p)
+	if !strings.Contains(out.String(), "Big [1/2]") {
+		t.Errorf("status line missing progress:\n%s", out.String())
+	}
+
+	out.Reset()
+	d.PRDDetail(p, false, false)
+	for _, want := range []string{"1/2 done", "1. " + SymbolCheck + " schema", "2. " + SymbolCircle + " api"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("detail view missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestStickyActivePRD(t *testing.T) {
+	var out bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&out, &bytes.Buffer{})
+
+	d.IterationHeader(1, 3)
+	if strings.Contains(out.String(), "Working on") {
+		t.Errorf("no sticky line expected without an active PRD:\n%s", out.String())
+	}
+
+	d.SetActivePRD("auth-1")
+	d.IterationHeader(2, 3)
+	d.PhaseHeader("Phase 2: Builder")
+	if n := strings.Count(out.String(), "Working on: auth-1"); n != 2 {
+		t.Errorf("expected sticky line in iteration and phase headers, got %d:\n%s", n, out.String())
+	}
+
+	out.Reset()
+	d.SetActivePRD("")
+	d.PhaseHeader("Phase 3: Reviewer")
+	if strings.Contains(out.String(), "Working on") {
+		t.Errorf("sticky line should clear:\n%s", out.String())
+	}
+}
+
+func TestTimestampFormat(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+
+	d.Info("default")
+	if !regexp.MustCompile(`^\[\d{2}:\d{2}:\d{2}\] `).MatchString(buf.String()) {
+		t.Errorf("default timestamp not HH:MM:SS: %q", buf.String())
+	}
+
+	buf.Reset()
+	d.SetTimestampFormat(time.RFC3339)
+	d.SetTimezone(time.UTC)
+	d.Info("utc")
+	if !regexp.MustCompile(`^\[\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z\] `).MatchString(buf.String()) {
+		t.Errorf("expected RFC3339 UTC timestamp, got %q", buf.String())
+	}
+
+	buf.Reset()
+	d.SetTimestampFormat("")
+	d.Info("reset")
+	if !regexp.MustCompile(`^\[\d{2}:\d{2}:\d{2}\] `).MatchString(buf.String()) {
+		t.Errorf("empty layout should restore default, got %q", buf.String())
+	}
+}
+
+// fixedClock always reports the same instant
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestTimestampUsesClock(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+	d.SetTimezone(time.UTC)
+	d.SetClock(fixedClock(time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC)))
+
+	d.Info("pinned")
+	if want := "[14:05:07] "; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("expected %q prefix, got %q", want, buf.String())
+	}
+
+	d.SetClock(nil)
+	if d.clock != SystemClock {
+		t.Error("nil clock should restore the system clock")
+	}
+}
+
+func TestPRDAge(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	d.SetClock(fixedClock(now))
+
+	d.PRDAge(prd.PRD{ID: "stale", Description: "Old work", CreatedAt: now.AddDate(0, 0, -45)})
+	d.PRDAge(prd.PRD{ID: "legacy", Description: "Predates ages"})
+
+	out := buf.String()
+	if !strings.Contains(out, "stale  open 45 days") {
+		t.Errorf("expected the 45-day age, got %q", out)
+	}
+	if !strings.Contains(out, "legacy  unknown age") {
+		t.Errorf("expected unknown age, got %q", out)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{10 * time.Minute, "less than an hour"},
+		{time.Hour, "1 hour"},
+		{23 * time.Hour, "23 hours"},
+		{25 * time.Hour, "1 day"},
+		{45 * 24 * time.Hour, "45 days"},
+	}
+	for _, tt := range tests {
+		if got := FormatAge(tt.age); got != tt.want {
+			t.Errorf("FormatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{400 * time.Millisecond, "0.4s"},
+		{42*time.Second + 300*time.Millisecond, "42s"},
+		{59*time.Second + 700*time.Millisecond, "1m00s"},
+		{3*time.Minute + 12*time.Second, "3m12s"},
+		{time.Hour + 2*time.Minute + 20*time.Second, "1h02m"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.elapsed); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestCleanTextPreserveCode(t *testing.T) {
+	text := "Here   is\tthe fix:\n\n\n```go\nfunc main() {\n\tfmt.Println(\"hi\")  \n}\n```\nDone   now.\n    indented  code\n"
+	got, inFence := CleanTextPreserveCode(text, false)
+	want := "Here is the fix:\n\n```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\nDone now.\n    indented  code"
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+	if inFence {
+		t.Error("fence should be closed")
+	}
+
+	// A fence opened in one chunk stays open for the next
+	_, inFence = CleanTextPreserveCode("Example:\n```", false)
+	if !inFence {
+		t.Fatal("fence should stay open across chunks")
+	}
+	got, inFence = CleanTextPreserveCode("  if x  {\n```", inFence)
+	if got != "  if x  {\n```" || inFence {
+		t.Errorf("continued chunk = %q (inFence %v)", got, inFence)
+	}
+
+	if CleanText("a   b\n  c") != "a b c" {
+		t.Error("CleanText should still collapse whitespace")
+	}
+}
+
+func TestFormatToolCounts(t *testing.T) {
+	if got := formatToolCounts(nil); got != "Tools: none" {
+		t.Errorf("empty = %q", got)
+	}
+	got := formatToolCounts(map[string]int{"Bash": 3, "Read": 12, "Edit": 4, "Glob": 3})
+	want := "Tools: 22 (Read ×12, Edit ×4, Bash ×3, Glob ×3)"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestAgentHeaderLegacyNames(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+
+	d.AgentHeader("analyzer", "prd-1")
+	d.AgentHeader("executor", "prd-1")
+	out := buf.String()
+	if !strings.Contains(out, "[reviewer]") || !strings.Contains(out, "[builder]") {
+		t.Errorf("legacy names should render as their current phase, got %q", out)
+	}
+	if strings.Contains(out, "analyzer") || strings.Contains(out, "executor") {
+		t.Errorf("legacy names leaked into output: %q", out)
+	}
+}
+
+func TestClaudeCompact(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+	d.SetTimestampFormat("15:04")
+	d.termWidth = 40
+
+	d.ClaudeCompact("first line\nsecond line that is far too long to fit on one terminal line", 3, 1500, 100000)
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if strings.Contains(line, "\n") {
+		t.Fatalf("compact output spans lines: %q", buf.String())
+	}
+	if n := utf8.RuneCountInString(line); n != 40 {
+		t.Errorf("compact line is %d runes, want 40: %q", n, line)
+	}
+	if !strings.Contains(line, "[3] [1.5K/100K] first line s") || !strings.HasSuffix(line, "…") {
+		t.Errorf("unexpected compact line %q", line)
+	}
+
+	buf.Reset()
+	d.ClaudeCompact("short", 0, 0, 0)
+	if !strings.HasSuffix(buf.String(), "[0] short\n") {
+		t.Errorf("short message should print whole with tool badge, got %q", buf.String())
+	}
+}
+
+func TestClaudeFull(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+	d.SetTimestampFormat("15:04")
+	d.termWidth = 40
+
+	d.ClaudeFull("one two three four five six seven eight nine ten\n\nsecond paragraph", 2, 0, 0)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasSuffix(lines[0], "[2] one two three four five") {
+		t.Errorf("first line = %q", lines[0])
+	}
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line); n > 40 {
+			t.Errorf("line exceeds terminal width (%d): %q", n, line)
+		}
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "  "+GutterCont+" [") {
+			t.Errorf("continuation line lacks gutter: %q", line)
+		}
+	}
+	if !strings.HasSuffix(lines[2], "second paragraph") {
+		t.Errorf("paragraph break lost: %q", lines[2])
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	d := NewWithOptions(true)
+
+	got := d.RenderMarkdown("## Plan\n\n- run `go test`\n1. ship it\n\n\n```go\nif err != nil {\n\treturn err\n}\n```\nDone", 30)
+	want := []string{
+		"Plan",
+		"",
+		"• run `go test`",
+		"1. ship it",
+		"",
+		"┌─ go ────────────────────────",
+		"│ if err != nil {",
+		"│     return err",
+		"│ }",
+		"└─────────────────────────────",
+		"Done",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderMarkdown() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Wrapped list items hang under their text, and open fences are closed
+	got = d.RenderMarkdown("- alpha beta gamma delta\n```\ncode", 14)
+	want = []string{"• alpha beta", "  gamma delta", "┌─────────────", "│ code", "└─────────────"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestClaudeFullMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+	d.SetTimestampFormat("15:04")
+	d.SetMarkdown(true)
+	d.termWidth = 60
+
+	d.ClaudeFull("# Summary\n```\n  indented  code\n```", 0, 0, 0)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasSuffix(lines[0], "[0] Summary") {
+		t.Errorf("first line = %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[2], BoxVertical+"   indented  code") {
+		t.Errorf("code line not preserved: %q", lines[2])
+	}
+}
+
+func TestThemePresets(t *testing.T) {
+	for _, name := range ThemeNames() {
+		theme := reflect.ValueOf(*ThemeByName(name))
+		for i := 0; i < theme.NumField(); i++ {
+			if theme.Field(i).IsNil() {
+				t.Errorf("theme %s: %s is nil", name, theme.Type().Field(i).Name)
+			}
+		}
+	}
+
+	for _, name := range []string{ThemeDefault, ThemeHighContrast, ThemeSolarized, ThemeMono, ThemeNoColor} {
+		if !IsTheme(name) {
+			t.Errorf("%s should be a preset", name)
+		}
+	}
+	if IsTheme("neon") {
+		t.Error("unknown names are not presets")
+	}
+	if !reflect.DeepEqual(ThemeByName("neon"), DefaultTheme()) {
+		t.Error("unknown theme should fall back to default")
+	}
+}
+
+func TestPartialThemeDoesNotPanic(t *testing.T) {
+	keep := NoColorTheme().Success
+	partial := &Theme{Success: keep}
+
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+	d.SetTheme(partial)
+
+	d.Header("title")
+	d.IterationHeader(1, 2)
+	d.ClaudeWithTokens("working", 1, 10, 100)
+	d.Success("done")
+	d.Warning("careful")
+
+	if partial.Success != keep {
+		t.Error("colors already set must be kept")
+	}
+	if partial.ClaudeText == nil {
+		t.Error("missing colors should be filled from the default theme")
+	}
+	if filled := (&Theme{}).Validate(); len(filled) != reflect.TypeOf(Theme{}).NumField() {
+		t.Errorf("empty theme filled %d fields, want all %d", len(filled), reflect.TypeOf(Theme{}).NumField())
+	}
+	if filled := DefaultTheme().Validate(); len(filled) != 0 {
+		t.Errorf("complete theme should need no filling, got %v", filled)
+	}
+}
+
+func TestSignalCategory(t *testing.T) {
+	tests := map[string]string{
+		"VERIFIED":      signalPositive,
+		"PLAN_COMPLETE": signalPositive,
+		"PRD_COMPLETE":  signalPositive,
+		"BAILOUT":       signalNegative,
+		"BLOCKED":       signalNegative,
+		"REJECTED":      signalNegative,
+		"LOOP_RISK":     signalNeutral,
+		"SOMETHING_NEW": signalNeutral,
+	}
+	for signal, want := range tests {
+		if got := signalCategory(signal); got != want {
+			t.Errorf("signalCategory(%q) = %s, want %s", signal, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	d := NewWithOptions(true)
+	d.SetOutput(&buf, &buf)
+	d.Signal("VERIFIED", "")
+	d.Signal("BAILOUT", "stuck")
+	d.Signal("LOOP_RISK", "")
+
+	out := buf.String()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("no-color signals contain escape codes: %q", out)
+	}
+	for _, want := range []string{SymbolCheck + " >>> VERIFIED", SymbolCross + " >>> BAILOUT: stuck", SymbolWarning + " >>> LOOP_RISK"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
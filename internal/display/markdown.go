@@ -0,0 +1,126 @@
+package display
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdHeader   = regexp.MustCompile(`^#{1,6}\s+(.*?)(?:\s+#+)?$`)
+	mdListItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdRule     = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+)
+
+// RenderMarkdown renders the common markdown constructs in agent output for
+// the terminal, wrapping prose to width
+// Headers are bold, list bullets styled, inline code dimmed and fenced code
+// blocks kept verbatim in a box. Without color, inline code keeps its
+// backticks so it still stands out. The returned lines are already styled.
+func (d *Display) RenderMarkdown(text string, width int) []string {
+	var lines []string
+	inFence := false
+	blank := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inFence {
+				lines = append(lines, d.theme.Dim.Sprint(BoxBottomLeft+strings.Repeat(BoxHorizontal, max(width-1, 1))))
+			} else {
+				lines = append(lines, d.fenceTop(strings.TrimPrefix(trimmed, "```"), width))
+			}
+			inFence = !inFence
+			blank = false
+			continue
+		}
+		if inFence {
+			lines = append(lines, d.theme.Dim.Sprint(BoxVertical+" ")+d.theme.ClaudeText.Sprint(strings.ReplaceAll(line, "\t", "    ")))
+			continue
+		}
+
+		if trimmed == "" {
+			if !blank && len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+
+		switch {
+		case mdRule.MatchString(line):
+			lines = append(lines, d.theme.Dim.Sprint(strings.Repeat(BoxHorizontal, max(width, 1))))
+		case mdHeader.MatchString(trimmed):
+			title := mdHeader.FindStringSubmatch(trimmed)[1]
+			for _, wl := range wrapText(title, width) {
+				lines = append(lines, d.theme.Bold.Sprint(strings.ReplaceAll(wl, "`", "")))
+			}
+		case mdListItem.MatchString(line):
+			m := mdListItem.FindStringSubmatch(line)
+			indent, marker := strings.Repeat(" ", len(m[1])), m[2]
+			if !strings.ContainsAny(marker[:1], "0123456789") {
+				marker = "•"
+			}
+			hang := indent + strings.Repeat(" ", len([]rune(marker))+1)
+			inCode := false
+			for i, wl := range wrapText(m[3], width-len(hang)) {
+				var styled string
+				styled, inCode = d.styleInline(wl, inCode)
+				if i == 0 {
+					lines = append(lines, indent+d.theme.Info.Sprint(marker)+" "+styled)
+				} else {
+					lines = append(lines, hang+styled)
+				}
+			}
+		default:
+			inCode := false
+			for _, wl := range wrapText(trimmed, width) {
+				var styled string
+				styled, inCode = d.styleInline(wl, inCode)
+				lines = append(lines, styled)
+			}
+		}
+	}
+
+	// Close a fence the agent left open
+	if inFence {
+		lines = append(lines, d.theme.Dim.Sprint(BoxBottomLeft+strings.Repeat(BoxHorizontal, max(width-1, 1))))
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// fenceTop draws the top border of a code block, labelled with its language
+func (d *Display) fenceTop(lang string, width int) string {
+	top := BoxTopLeft + BoxHorizontal
+	if lang = strings.TrimSpace(lang); lang != "" {
+		top += " " + lang + " "
+	}
+	return d.theme.Dim.Sprint(top + strings.Repeat(BoxHorizontal, max(width-len([]rune(top)), 1)))
+}
+
+// styleInline dims `code` spans in a line of prose; inCode carries an open
+// span across wrapped lines and the updated state is returned
+func (d *Display) styleInline(line string, inCode bool) (string, bool) {
+	var b strings.Builder
+	for i, part := range strings.Split(line, "`") {
+		if i > 0 {
+			inCode = !inCode
+			if d.noColor {
+				b.WriteString("`")
+			}
+		}
+		if part == "" {
+			continue
+		}
+		if inCode {
+			b.WriteString(d.theme.Dim.Sprint(part))
+		} else {
+			b.WriteString(d.theme.ClaudeText.Sprint(part))
+		}
+	}
+	return b.String(), inCode
+}
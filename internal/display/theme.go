@@ -1,6 +1,9 @@
 package display
 
 import (
+	"reflect"
+	"sort"
+
 	"github.com/fatih/color"
 )
 
@@ -67,6 +70,70 @@ type Theme struct {
 	ActivePRD       *color.Color
 }
 
+// Theme preset names for ThemeByName
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeSolarized    = "solarized"
+	ThemeMono         = "mono"
+	ThemeNoColor      = "no-color"
+)
+
+// themes maps preset names to their constructors
+var themes = map[string]func() *Theme{
+	ThemeDefault:      DefaultTheme,
+	ThemeHighContrast: HighContrastTheme,
+	ThemeSolarized:    SolarizedTheme,
+	ThemeMono:         MonoTheme,
+	ThemeNoColor:      NoColorTheme,
+}
+
+// ThemeByName returns the named preset, or the default theme when the
+// name is empty or unknown
+func ThemeByName(name string) *Theme {
+	if preset, ok := themes[name]; ok {
+		t := preset()
+		t.Validate()
+		return t
+	}
+	return DefaultTheme()
+}
+
+// Validate fills every nil color with the default theme's color so a
+// partial theme cannot panic, returning the names of the fields it filled
+func (t *Theme) Validate() []string {
+	var filled []string
+	v := reflect.ValueOf(t).Elem()
+	var defaults reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			continue
+		}
+		if !defaults.IsValid() {
+			defaults = reflect.ValueOf(DefaultTheme()).Elem()
+		}
+		v.Field(i).Set(defaults.Field(i))
+		filled = append(filled, v.Type().Field(i).Name)
+	}
+	return filled
+}
+
+// IsTheme reports whether name is a theme preset
+func IsTheme(name string) bool {
+	_, ok := themes[name]
+	return ok
+}
+
+// ThemeNames returns the preset names, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // DefaultTheme returns the default color theme
 func DefaultTheme() *Theme {
 	return &Theme{
@@ -102,6 +169,112 @@ func DefaultTheme() *Theme {
 	}
 }
 
+// HighContrastTheme returns a theme of bright, bold colors that stay legible
+// on dark and light backgrounds; agent output is not dimmed
+func HighContrastTheme() *Theme {
+	return &Theme{
+		MillhouseTitle:  color.New(color.FgHiCyan, color.Bold, color.Underline),
+		MillhouseBox:    color.New(color.FgHiCyan, color.Bold),
+		MillhouseText:   color.New(color.Bold),
+		SectionBreak:    color.New(color.FgHiCyan, color.Bold),
+
+		ClaudeGutter:    color.New(color.FgHiWhite),
+		ClaudeText:      color.New(color.Reset),
+		ClaudeTimestamp: color.New(color.FgHiWhite),
+		ClaudeToolBadge: color.New(color.FgHiBlue, color.Bold),
+		ClaudeTokens:    color.New(color.FgHiWhite),
+
+		ReviewerGutter:  color.New(color.FgHiYellow, color.Bold),
+		ReviewerText:    color.New(color.FgHiYellow),
+
+		Success:         color.New(color.FgHiGreen, color.Bold),
+		Error:           color.New(color.FgHiRed, color.Bold),
+		Warning:         color.New(color.FgHiYellow, color.Bold),
+		Info:            color.New(color.FgHiBlue, color.Bold),
+
+		Dim:             color.New(color.Reset),
+		Bold:            color.New(color.Bold),
+
+		ActivePRD:       color.New(color.FgHiGreen, color.Bold, color.Underline),
+	}
+}
+
+// solarized builds a 256-color foreground from the Solarized palette
+func solarized(code color.Attribute, extra ...color.Attribute) *color.Color {
+	return color.New(append([]color.Attribute{38, 5, code}, extra...)...)
+}
+
+// Solarized palette, as xterm-256 approximations
+const (
+	solBase01  color.Attribute = 240
+	solBase1   color.Attribute = 245
+	solYellow  color.Attribute = 136
+	solOrange  color.Attribute = 166
+	solRed     color.Attribute = 160
+	solViolet  color.Attribute = 61
+	solBlue    color.Attribute = 33
+	solCyan    color.Attribute = 37
+	solGreen   color.Attribute = 64
+)
+
+// SolarizedTheme returns a theme using the Solarized accent colors, which
+// read well on both Solarized light and dark backgrounds
+func SolarizedTheme() *Theme {
+	return &Theme{
+		MillhouseTitle:  solarized(solBlue, color.Bold),
+		MillhouseBox:    solarized(solBlue),
+		MillhouseText:   solarized(solBase1),
+		SectionBreak:    solarized(solBlue, color.Bold),
+
+		ClaudeGutter:    solarized(solBase01),
+		ClaudeText:      solarized(solBase01),
+		ClaudeTimestamp: solarized(solBase01),
+		ClaudeToolBadge: solarized(solViolet),
+		ClaudeTokens:    solarized(solBase01),
+
+		ReviewerGutter:  solarized(solYellow),
+		ReviewerText:    solarized(solYellow),
+
+		Success:         solarized(solGreen),
+		Error:           solarized(solRed),
+		Warning:         solarized(solOrange),
+		Info:            solarized(solCyan),
+
+		Dim:             solarized(solBase01),
+		Bold:            color.New(color.Bold),
+
+		ActivePRD:       solarized(solGreen, color.Bold),
+	}
+}
+
+// MonoTheme returns a theme without hues that keeps emphasis through bold,
+// faint and underlined text
+func MonoTheme() *Theme {
+	plain := color.New(color.Reset)
+	faint := color.New(color.Faint)
+	bold := color.New(color.Bold)
+	return &Theme{
+		MillhouseTitle:  color.New(color.Bold, color.Underline),
+		MillhouseBox:    bold,
+		MillhouseText:   plain,
+		SectionBreak:    bold,
+		ClaudeGutter:    faint,
+		ClaudeText:      faint,
+		ClaudeTimestamp: faint,
+		ClaudeToolBadge: plain,
+		ClaudeTokens:    faint,
+		ReviewerGutter:  plain,
+		ReviewerText:    plain,
+		Success:         bold,
+		Error:           color.New(color.Bold, color.ReverseVideo),
+		Warning:         color.New(color.Bold, color.Underline),
+		Info:            plain,
+		Dim:             faint,
+		Bold:            bold,
+		ActivePRD:       color.New(color.Bold, color.Underline),
+	}
+}
+
 // NoColorTheme returns a theme with colors disabled
 func NoColorTheme() *Theme {
 	noColor := color.New()
@@ -0,0 +1,57 @@
+package events
+
+import "time"
+
+// SignalSchemaVersion is bumped on incompatible changes to SignalMessage
+const SignalSchemaVersion = 1
+
+// SignalMessage is the stable JSON form of a signal handed to hooks
+type SignalMessage struct {
+	Version   int            `json:"version"`
+	Time      time.Time      `json:"time"`
+	RunID     string         `json:"runId"`
+	Iteration int            `json:"iteration"`
+	Phase     string         `json:"phase"` // planner, builder, reviewer
+	Type      string         `json:"type"`  // e.g. VERIFIED, BAILOUT
+	PRDID     string         `json:"prdId,omitempty"`
+	Details   string         `json:"details,omitempty"`
+	Payload   map[string]any `json:"payload,omitempty"` // From ###SIGNAL:TYPE:{json}### signals
+}
+
+// SignalHook reacts to a signal processed by the run loop
+type SignalHook func(SignalMessage)
+
+// Bus dispatches signals to the hooks registered for their type
+type Bus struct {
+	hooks map[string][]SignalHook // Keyed by signal type; "" matches every type
+}
+
+// NewBus creates an empty signal bus
+func NewBus() *Bus {
+	return &Bus{hooks: make(map[string][]SignalHook)}
+}
+
+// On registers a hook for a signal type, or for every signal if signalType is empty
+func (b *Bus) On(signalType string, hook SignalHook) {
+	b.hooks[signalType] = append(b.hooks[signalType], hook)
+}
+
+// Publish calls the hooks for msg's type, then the catch-all hooks, in
+// registration order. Version and Time are filled in when unset.
+func (b *Bus) Publish(msg SignalMessage) {
+	if msg.Version == 0 {
+		msg.Version = SignalSchemaVersion
+	}
+	if msg.Time.IsZero() {
+		msg.Time = time.Now()
+	}
+
+	for _, hook := range b.hooks[msg.Type] {
+		hook(msg)
+	}
+	if msg.Type != "" {
+		for _, hook := range b.hooks[""] {
+			hook(msg)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package events
+
+import "testing"
+
+func TestBusDispatchesByType(t *testing.T) {
+	bus := NewBus()
+
+	var verified, all []string
+	bus.On("VERIFIED", func(m SignalMessage) { verified = append(verified, m.PRDID) })
+	bus.On("", func(m SignalMessage) { all = append(all, m.Type) })
+
+	bus.Publish(SignalMessage{Type: "VERIFIED", PRDID: "auth-1"})
+	bus.Publish(SignalMessage{Type: "BAILOUT"})
+
+	if len(verified) != 1 || verified[0] != "auth-1" {
+		t.Errorf("VERIFIED hook got %v", verified)
+	}
+	if len(all) != 2 || all[0] != "VERIFIED" || all[1] != "BAILOUT" {
+		t.Errorf("catch-all hook got %v", all)
+	}
+}
+
+func TestBusFillsSchemaFields(t *testing.T) {
+	bus := NewBus()
+
+	var got SignalMessage
+	bus.On("", func(m SignalMessage) { got = m })
+	bus.Publish(SignalMessage{Type: "BLOCKED"})
+
+	if got.Version != SignalSchemaVersion || got.Time.IsZero() {
+		t.Errorf("Publish should fill version and time, got %+v", got)
+	}
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// Event types written to the log
+const (
+	TypeRunStart  = "run_start"
+	TypePhase     = "phase"
+	TypeIteration = "iteration"
+	TypeRunEnd    = "run_end"
+)
+
+// Event is a single line of the NDJSON event log
+type Event struct {
+	Time       time.Time `json:"time"`
+	RunID      string    `json:"runId"`
+	Type       string    `json:"type"`
+	Iteration  int       `json:"iteration,omitempty"`
+	Phase      string    `json:"phase,omitempty"`    // planner, builder, reviewer
+	PRDID      string    `json:"prdId,omitempty"`    // PRD the phase worked on
+	Tokens     int       `json:"tokens,omitempty"`   // Tokens used by the phase
+	Signals    []string  `json:"signals,omitempty"`  // Signal types emitted
+	Verified   []string  `json:"verified,omitempty"` // Reviewer verdicts
+	Rejected   []string  `json:"rejected,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Iterations int       `json:"iterations,omitempty"` // Iterations completed (run_end)
+	DurationMs int64     `json:"durationMs,omitempty"` // Wall-clock time of the phase, iteration or run
+}
+
+// Log appends events for a single run to events.ndjson in the artifacts directory
+type Log struct {
+	path  string
+	runID string
+}
+
+// NewLog creates a log for a new run, identified by its start time
+func NewLog(basePath string) *Log {
+	return &Log{
+		path:  prd.GetArtifactPath(basePath, prd.EventsFile),
+		runID: time.Now().Format("20060102-150405"),
+	}
+}
+
+// RunID returns the identifier stamped on every event of this run
+func (l *Log) RunID() string {
+	return l.runID
+}
+
+// Append writes an event as one JSON line, filling in time and run ID
+func (l *Log) Append(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.RunID = l.runID
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create event log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	return nil
+}
+
+// Read loads all events from the log
+// A missing log yields no events. Malformed lines (e.g. from an interrupted
+// write) are skipped and counted rather than failing the whole read.
+func Read(basePath string) ([]Event, int, error) {
+	return ReadFile(prd.GetArtifactPath(basePath, prd.EventsFile))
+}
+
+// ReadFile loads all events from the log at path, like Read
+// It serves projects whose artifacts directory differs from this process's.
+func ReadFile(path string) ([]Event, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			skipped++
+			continue
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return events, skipped, fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	return events, skipped, nil
+}
@@ -0,0 +1,108 @@
+package events
+
+import "time"
+
+// Stats aggregates metrics across all logged runs
+type Stats struct {
+	Runs          int
+	Iterations    int
+	Completed     int                      // Reviewer verifications
+	Rejected      int                      // Reviewer rejections
+	PhaseTokens   map[string]int           // Total tokens by phase
+	PhaseRuns     map[string]int           // Number of executions by phase
+	PhaseTime     map[string]time.Duration // Total wall-clock time by phase
+	PhaseTimed    map[string]int           // Executions by phase that logged a duration
+	CompletedPRDs int                      // PRDs with a measured plan-to-verify span
+	iterSpans     int                      // Sum of iterations-to-complete
+}
+
+// RejectionRate returns the fraction of reviewer verdicts that were rejections
+func (s *Stats) RejectionRate() float64 {
+	total := s.Completed + s.Rejected
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Rejected) / float64(total)
+}
+
+// AvgTokens returns the average tokens per execution of a phase
+func (s *Stats) AvgTokens(phase string) int {
+	if s.PhaseRuns[phase] == 0 {
+		return 0
+	}
+	return s.PhaseTokens[phase] / s.PhaseRuns[phase]
+}
+
+// AvgDuration returns the average wall-clock time per timed execution of a phase
+func (s *Stats) AvgDuration(phase string) time.Duration {
+	if s.PhaseTimed[phase] == 0 {
+		return 0
+	}
+	return s.PhaseTime[phase] / time.Duration(s.PhaseTimed[phase])
+}
+
+// AvgIterationsToComplete returns the mean number of iterations from a PRD
+// being planned to it being verified
+func (s *Stats) AvgIterationsToComplete() float64 {
+	if s.CompletedPRDs == 0 {
+		return 0
+	}
+	return float64(s.iterSpans) / float64(s.CompletedPRDs)
+}
+
+// Summarize computes stats from events in log order
+func Summarize(events []Event) *Stats {
+	stats := &Stats{
+		PhaseTokens: make(map[string]int),
+		PhaseRuns:   make(map[string]int),
+		PhaseTime:   make(map[string]time.Duration),
+		PhaseTimed:  make(map[string]int),
+	}
+
+	runs := make(map[string]bool)
+	type iterKey struct {
+		run  string
+		iter int
+	}
+	seenIter := make(map[iterKey]bool)
+	planned := make(map[string]int) // PRD ID -> global iteration it was planned in
+
+	for _, e := range events {
+		runs[e.RunID] = true
+		if e.Type != TypePhase {
+			continue
+		}
+
+		key := iterKey{e.RunID, e.Iteration}
+		if !seenIter[key] {
+			seenIter[key] = true
+			stats.Iterations++
+		}
+
+		stats.PhaseRuns[e.Phase]++
+		stats.PhaseTokens[e.Phase] += e.Tokens
+		if e.DurationMs > 0 {
+			stats.PhaseTime[e.Phase] += time.Duration(e.DurationMs) * time.Millisecond
+			stats.PhaseTimed[e.Phase]++
+		}
+
+		if e.Phase == "planner" && e.PRDID != "" {
+			if _, ok := planned[e.PRDID]; !ok {
+				planned[e.PRDID] = stats.Iterations
+			}
+		}
+
+		stats.Completed += len(e.Verified)
+		stats.Rejected += len(e.Rejected)
+		for _, id := range e.Verified {
+			if start, ok := planned[id]; ok {
+				stats.iterSpans += stats.Iterations - start + 1
+				stats.CompletedPRDs++
+				delete(planned, id)
+			}
+		}
+	}
+	stats.Runs = len(runs)
+
+	return stats
+}
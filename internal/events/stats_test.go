@@ -0,0 +1,84 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+func TestSummarize(t *testing.T) {
+	evts := []Event{
+		{RunID: "r1", Type: TypeRunStart},
+		{RunID: "r1", Type: TypePhase, Iteration: 1, Phase: "planner", PRDID: "a", Tokens: 1000, DurationMs: 30000},
+		{RunID: "r1", Type: TypePhase, Iteration: 1, Phase: "builder", PRDID: "a", Tokens: 5000},
+		{RunID: "r1", Type: TypePhase, Iteration: 1, Phase: "reviewer", Tokens: 2000, Rejected: []string{"a"}},
+		{RunID: "r1", Type: TypeRunEnd, Iterations: 1},
+		{RunID: "r2", Type: TypePhase, Iteration: 1, Phase: "planner", PRDID: "a", Tokens: 3000, DurationMs: 60000},
+		{RunID: "r2", Type: TypePhase, Iteration: 1, Phase: "reviewer", Tokens: 4000, Verified: []string{"a"}},
+	}
+
+	stats := Summarize(evts)
+
+	if stats.Runs != 2 {
+		t.Errorf("Runs = %d, want 2", stats.Runs)
+	}
+	if stats.Iterations != 2 {
+		t.Errorf("Iterations = %d, want 2", stats.Iterations)
+	}
+	if stats.Completed != 1 || stats.Rejected != 1 {
+		t.Errorf("Completed/Rejected = %d/%d, want 1/1", stats.Completed, stats.Rejected)
+	}
+	if got := stats.RejectionRate(); got != 0.5 {
+		t.Errorf("RejectionRate() = %v, want 0.5", got)
+	}
+	if got := stats.AvgTokens("planner"); got != 2000 {
+		t.Errorf("AvgTokens(planner) = %d, want 2000", got)
+	}
+	if got := stats.AvgDuration("planner"); got != 45*time.Second {
+		t.Errorf("AvgDuration(planner) = %v, want 45s", got)
+	}
+	if got := stats.AvgDuration("builder"); got != 0 {
+		t.Errorf("AvgDuration(builder) = %v with no timed runs, want 0", got)
+	}
+	if got := stats.AvgIterationsToComplete(); got != 2 {
+		t.Errorf("AvgIterationsToComplete() = %v, want 2", got)
+	}
+}
+
+func TestReadSkipsMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Missing log is not an error
+	evts, skipped, err := Read(dir)
+	if err != nil || len(evts) != 0 || skipped != 0 {
+		t.Fatalf("Read() on missing log = %v, %d, %v", evts, skipped, err)
+	}
+
+	log := NewLog(dir)
+	if err := log.Append(Event{Type: TypeRunStart}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	f, err := os.OpenFile(prd.GetMillhousePath(dir, prd.EventsFile), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"type\": \"phase\", \"trunc\n")
+	f.Close()
+
+	evts, skipped, err = Read(dir)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(evts) != 1 || skipped != 1 {
+		t.Errorf("Read() = %d events, %d skipped; want 1, 1", len(evts), skipped)
+	}
+	if evts[0].RunID != log.RunID() {
+		t.Errorf("RunID = %q, want %q", evts[0].RunID, log.RunID())
+	}
+}
@@ -0,0 +1,195 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTailLines is how many trailing output lines are kept for prompts and evidence
+	DefaultTailLines = 40
+
+	// maxCapture bounds the output buffered in memory before tailing
+	maxCapture = 256 * 1024
+
+	// waitDelay bounds how long to wait for output after the command is killed
+	waitDelay = 500 * time.Millisecond
+)
+
+// Options controls how a command is run
+type Options struct {
+	Dir       string        // Working directory
+	Timeout   time.Duration // Zero means no timeout
+	TailLines int           // Lines of output to keep (DefaultTailLines if zero)
+	Stream    io.Writer     // Optional live copy of combined output
+	Stdin     io.Reader     // Optional input for the command
+	Env       []string      // Extra KEY=value variables on top of the current environment
+}
+
+// Result is the outcome of running a shell command
+type Result struct {
+	Name     string        `json:"name"` // Hook name, e.g. "test" or "build"
+	Command  string        `json:"command"`
+	ExitCode int           `json:"exitCode"`
+	TimedOut bool          `json:"timedOut,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Tail     string        `json:"tail,omitempty"` // Last lines of combined output
+	RanAt    time.Time     `json:"ranAt"`
+	Tree     string        `json:"tree,omitempty"` // Fingerprint of the code the command ran against, set by the caller
+}
+
+// Passed reports whether the command exited zero within its timeout
+func (r *Result) Passed() bool {
+	return r.ExitCode == 0 && !r.TimedOut
+}
+
+// Status returns PASSED, FAILED or TIMED OUT
+func (r *Result) Status() string {
+	switch {
+	case r.TimedOut:
+		return "TIMED OUT"
+	case r.ExitCode != 0:
+		return "FAILED"
+	default:
+		return "PASSED"
+	}
+}
+
+// Summary renders the result for agent prompts and evidence
+func (r *Result) Summary() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: `%s` %s (exit %d, %s)", r.Name, r.Command, r.Status(), r.ExitCode, r.Duration.Round(100*time.Millisecond))
+	if r.Tail != "" {
+		fmt.Fprintf(&sb, "\n```\n%s\n```", r.Tail)
+	}
+	return sb.String()
+}
+
+// Run executes command through the platform shell (sh, or cmd.exe on
+// Windows), capturing exit code and an output tail
+// An error is returned only if the command could not be started.
+func Run(ctx context.Context, name, command string, opts Options) (*Result, error) {
+	if opts.TailLines == 0 {
+		opts.TailLines = DefaultTailLines
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	capture := &limitedBuffer{max: maxCapture}
+	var out io.Writer = capture
+	if opts.Stream != nil {
+		out = io.MultiWriter(capture, opts.Stream)
+	}
+
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = opts.Dir
+	cmd.Stdin = opts.Stdin
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// Don't hang on children of the shell that keep the output pipe open after a kill
+	cmd.WaitDelay = waitDelay
+
+	result := &Result{Name: name, Command: command, RanAt: time.Now()}
+	start := time.Now()
+	err := cmd.Run()
+	result.Duration = time.Since(start)
+	result.Tail = tailLines(capture.String(), opts.TailLines)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		result.ExitCode = -1
+		return result, nil
+	}
+
+	var exitErr *osexec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return result, fmt.Errorf("failed to run %s command: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// SaveResults writes hook results as JSON, replacing any previous results
+func SaveResults(path string, results []*Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
+
+// LoadResults reads hook results saved by SaveResults
+// A missing file yields no results.
+func LoadResults(path string) ([]*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+
+	var results []*Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+	return results, nil
+}
+
+// AllPassed reports whether every result passed
+func AllPassed(results []*Result) bool {
+	for _, r := range results {
+		if !r.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// tailLines returns the last n lines of s
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// limitedBuffer keeps only the most recent max bytes written to it
+type limitedBuffer struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n, _ := b.buf.Write(p)
+	if over := b.buf.Len() - b.max; over > 0 {
+		b.buf.Next(over)
+	}
+	return n, nil
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
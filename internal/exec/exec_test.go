@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		timeout    time.Duration
+		wantStatus string
+		wantExit   int
+		wantTail   string
+	}{
+		{name: "passing command", command: "echo ok", wantStatus: "PASSED", wantExit: 0, wantTail: "ok"},
+		{name: "failing command", command: "echo broken >&2; exit 3", wantStatus: "FAILED", wantExit: 3, wantTail: "broken"},
+		{name: "timeout", command: "sleep 5", timeout: 100 * time.Millisecond, wantStatus: "TIMED OUT", wantExit: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(context.Background(), "test", tt.command, Options{Dir: t.TempDir(), Timeout: tt.timeout})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if result.Status() != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s", result.Status(), tt.wantStatus)
+			}
+			if result.ExitCode != tt.wantExit {
+				t.Errorf("ExitCode = %d, want %d", result.ExitCode, tt.wantExit)
+			}
+			if !strings.Contains(result.Tail, tt.wantTail) {
+				t.Errorf("Tail = %q, want it to contain %q", result.Tail, tt.wantTail)
+			}
+		})
+	}
+}
+
+func TestRunKeepsOutputTail(t *testing.T) {
+	result, err := Run(context.Background(), "test", "seq 1 100", Options{Dir: t.TempDir(), TailLines: 3})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Tail != "98\n99\n100" {
+		t.Errorf("Tail = %q, want last 3 lines", result.Tail)
+	}
+}
+
+func TestSaveLoadResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checks.json")
+
+	if results, err := LoadResults(path); err != nil || results != nil {
+		t.Fatalf("LoadResults() on missing file = %v, %v", results, err)
+	}
+
+	saved := []*Result{
+		{Name: "build", Command: "go build ./...", ExitCode: 0},
+		{Name: "test", Command: "go test ./...", ExitCode: 1},
+	}
+	if err := SaveResults(path, saved); err != nil {
+		t.Fatalf("SaveResults() error = %v", err)
+	}
+
+	loaded, err := LoadResults(path)
+	if err != nil {
+		t.Fatalf("LoadResults() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[1].Name != "test" {
+		t.Fatalf("LoadResults() = %+v", loaded)
+	}
+	if AllPassed(loaded) {
+		t.Error("AllPassed() = true, want false with a failing test")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package exec
+
+import (
+	"context"
+	osexec "os/exec"
+)
+
+// shellCommand runs command through the POSIX shell
+func shellCommand(ctx context.Context, command string) *osexec.Cmd {
+	return osexec.CommandContext(ctx, "sh", "-c", command)
+}
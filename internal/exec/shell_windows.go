@@ -0,0 +1,18 @@
+//go:build windows
+
+package exec
+
+import (
+	"context"
+	osexec "os/exec"
+	"syscall"
+)
+
+// shellCommand runs command through cmd.exe
+// The command line is passed verbatim: cmd.exe does its own quote parsing,
+// which Go's argument escaping would break. /S strips only the outer quotes.
+func shellCommand(ctx context.Context, command string) *osexec.Cmd {
+	cmd := osexec.CommandContext(ctx, "cmd.exe")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd.exe /S /C "` + command + `"`}
+	return cmd
+}
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Commit stages every change in the working tree and commits it with message
+// When the tree is already clean nothing is committed and committed is false.
+func Commit(basePath, message string) (sha string, committed bool, err error) {
+	clean, _, err := CheckWorkingTreeClean(basePath)
+	if err != nil {
+		return "", false, err
+	}
+	if clean {
+		return "", false, nil
+	}
+
+	add := exec.Command("git", "add", "-A")
+	add.Dir = basePath
+	if output, err := add.CombinedOutput(); err != nil {
+		return "", false, fmt.Errorf("failed to stage changes: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	commit := exec.Command("git", "commit", "-m", message)
+	commit.Dir = basePath
+	if output, err := commit.CombinedOutput(); err != nil {
+		return "", false, fmt.Errorf("failed to commit: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	sha, err = RevParse(basePath, "HEAD")
+	if err != nil {
+		return "", true, err
+	}
+	return sha, true, nil
+}
@@ -0,0 +1,48 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommit(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	createTestCommit(t, repoPath, []string{"README.md"}, "initial")
+
+	// Clean tree: nothing to commit
+	sha, committed, err := Commit(repoPath, "millhouse: nothing")
+	if err != nil || committed || sha != "" {
+		t.Fatalf("Commit on clean tree = %q, %v, %v", sha, committed, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoPath, "feature.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sha, committed, err = Commit(repoPath, "millhouse: feature-1 — Add feature")
+	if err != nil || !committed {
+		t.Fatalf("Commit = %q, %v, %v", sha, committed, err)
+	}
+
+	head, err := RevParse(repoPath, "HEAD")
+	if err != nil || head != sha {
+		t.Errorf("HEAD = %q, want %q", head, sha)
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%s")
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "millhouse: feature-1 — Add feature" {
+		t.Errorf("subject = %q", got)
+	}
+
+	if clean, _, _ := CheckWorkingTreeClean(repoPath); !clean {
+		t.Error("working tree should be clean after Commit")
+	}
+}
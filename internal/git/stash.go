@@ -0,0 +1,157 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// pathspec limits a git command to the repo minus the excluded paths
+func pathspec(exclude []string) []string {
+	spec := []string{"--", "."}
+	for _, path := range exclude {
+		spec = append(spec, ":(exclude)"+path)
+	}
+	return spec
+}
+
+// UncommittedChanges lists uncommitted changes, untracked files included,
+// outside the excluded paths, in git status --porcelain form
+func UncommittedChanges(basePath string, exclude ...string) ([]string, error) {
+	cmd := exec.Command("git", append([]string{"status", "--porcelain"}, pathspec(exclude)...)...)
+	cmd.Dir = basePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check git status: %w", err)
+	}
+
+	var changes []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			changes = append(changes, line)
+		}
+	}
+	return changes, nil
+}
+
+// Stash saves uncommitted changes, untracked files included, outside the
+// excluded paths and returns the stash commit SHA
+// When there is nothing to stash the SHA is empty and nothing is created.
+func Stash(basePath, message string, exclude ...string) (string, error) {
+	changes, err := UncommittedChanges(basePath, exclude...)
+	if err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	args := append([]string{"stash", "push", "--include-untracked", "-m", message}, pathspec(exclude)...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = basePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to stash changes: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return RevParse(basePath, "stash@{0}")
+}
+
+// StashPop restores the stash with the given SHA and drops it
+// The stash is found by SHA so stashes pushed since are left alone. If it
+// does not apply cleanly git keeps it, so nothing is lost.
+func StashPop(basePath, sha string) error {
+	list := exec.Command("git", "stash", "list", "--format=%H")
+	list.Dir = basePath
+	output, err := list.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+
+	index := -1
+	for i, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == sha {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("stash %s not found", shortSHA(sha))
+	}
+
+	pop := exec.Command("git", "stash", "pop", fmt.Sprintf("stash@{%d}", index))
+	pop.Dir = basePath
+	if output, err := pop.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore stash %s: %w: %s", shortSHA(sha), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// StashGuard holds changes stashed before a run until Restore puts them back
+type StashGuard struct {
+	basePath string
+	sha      string
+	changes  int
+}
+
+// GuardStash stashes the uncommitted changes outside the excluded paths
+// A guard is returned even when the tree was clean; Restore is then a no-op.
+func GuardStash(basePath, message string, exclude ...string) (*StashGuard, error) {
+	changes, err := UncommittedChanges(basePath, exclude...)
+	if err != nil {
+		return nil, err
+	}
+	sha, err := Stash(basePath, message, exclude...)
+	if err != nil {
+		return nil, err
+	}
+	return &StashGuard{basePath: basePath, sha: sha, changes: len(changes)}, nil
+}
+
+// Stashed reports how many changes the guard is holding
+func (g *StashGuard) Stashed() int {
+	if g.sha == "" {
+		return 0
+	}
+	return g.changes
+}
+
+// SHA returns the stash commit, empty when nothing was stashed
+func (g *StashGuard) SHA() string {
+	return g.sha
+}
+
+// Restore pops the guarded stash; calling it again does nothing
+func (g *StashGuard) Restore() error {
+	if g.sha == "" {
+		return nil
+	}
+	if err := StashPop(g.basePath, g.sha); err != nil {
+		return err
+	}
+	g.sha = ""
+	return nil
+}
+
+// Snapshot returns a commit holding the current content of tracked files:
+// HEAD when nothing is uncommitted, otherwise a stash commit of the changes
+// The stash commit is not added to the stash list and the working tree is
+// left alone.
+func Snapshot(basePath string) (string, error) {
+	cmd := exec.Command("git", "stash", "create")
+	cmd.Dir = basePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot the working tree: %w", err)
+	}
+	if sha := strings.TrimSpace(string(output)); sha != "" {
+		return sha, nil
+	}
+	return RevParse(basePath, "HEAD")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
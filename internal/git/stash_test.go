@@ -0,0 +1,127 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStashAndPop(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	createTestCommit(t, repoPath, []string{"main.go", ".milhouse/prd.json"}, "initial")
+
+	// A clean tree stashes nothing
+	sha, err := Stash(repoPath, "pre-run")
+	if err != nil || sha != "" {
+		t.Fatalf("Stash() on a clean tree = %q, %v; want empty", sha, err)
+	}
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.go", "user edit")
+	write("notes.txt", "untracked")
+	write(".milhouse/prd.json", "run state")
+
+	sha, err = Stash(repoPath, "pre-run", ".milhouse")
+	if err != nil || sha == "" {
+		t.Fatalf("Stash() = %q, %v; want a stash", sha, err)
+	}
+	changes, err := UncommittedChanges(repoPath, ".milhouse")
+	if err != nil || len(changes) != 0 {
+		t.Errorf("after Stash() changes = %v, %v; want none", changes, err)
+	}
+	// Excluded paths stay in the working tree
+	if content, _ := os.ReadFile(filepath.Join(repoPath, ".milhouse/prd.json")); string(content) != "run state" {
+		t.Errorf(".milhouse/prd.json = %q, want it left in place", content)
+	}
+
+	// Restoring by SHA skips stashes pushed since
+	write("main.go", "agent edit")
+	if _, err := Stash(repoPath, "later"); err != nil {
+		t.Fatal(err)
+	}
+	if err := StashPop(repoPath, sha); err != nil {
+		t.Fatalf("StashPop() error = %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(repoPath, "main.go")); string(content) != "user edit" {
+		t.Errorf("main.go = %q, want the stashed edit back", content)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, "notes.txt")); err != nil {
+		t.Errorf("untracked file not restored: %v", err)
+	}
+
+	if err := StashPop(repoPath, sha); err == nil {
+		t.Error("StashPop() of a dropped stash succeeded, want an error")
+	}
+}
+
+func TestStashGuard(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	createTestCommit(t, repoPath, []string{"main.go"}, "initial")
+
+	guard, err := GuardStash(repoPath, "pre-run")
+	if err != nil {
+		t.Fatalf("GuardStash() error = %v", err)
+	}
+	if guard.Stashed() != 0 || guard.Restore() != nil {
+		t.Errorf("clean tree guard = %d stashed; want 0 and a no-op Restore", guard.Stashed())
+	}
+
+	if err := os.WriteFile(filepath.Join(repoPath, "new.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	guard, err = GuardStash(repoPath, "pre-run")
+	if err != nil || guard.Stashed() != 1 {
+		t.Fatalf("GuardStash() = %v stashed, %v; want 1", guard, err)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, "new.go")); !os.IsNotExist(err) {
+		t.Errorf("new.go still present after stashing: %v", err)
+	}
+	if err := guard.Restore(); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if err := guard.Restore(); err != nil {
+		t.Errorf("second Restore() error = %v, want a no-op", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, "new.go")); err != nil {
+		t.Errorf("new.go not restored: %v", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	createTestCommit(t, repoPath, []string{"main.go", ".milhouse/prd.json"}, "initial")
+	head, err := RevParse(repoPath, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clean, err := Snapshot(repoPath)
+	if err != nil || clean != head {
+		t.Fatalf("Snapshot() on a clean tree = %q, %v; want HEAD %q", clean, err, head)
+	}
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(".milhouse/prd.json", "run state")
+	write("main.go", "edit")
+	state, err := Snapshot(repoPath)
+	if err != nil || state == head {
+		t.Fatalf("Snapshot() with changes = %q, %v; want a stash commit", state, err)
+	}
+	if changes, _ := UncommittedChanges(repoPath); len(changes) != 2 {
+		t.Errorf("Snapshot() touched the working tree: %v", changes)
+	}
+}
@@ -0,0 +1,152 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// TreeState fingerprints the content of a working tree, so a later change is
+// found even in a file that was already modified or untracked
+type TreeState struct {
+	basePath  string
+	exclude   []string
+	commit    string            // Snapshot of tracked files
+	untracked map[string]string // Blob hash of each untracked file
+}
+
+// CaptureTree records the content of the working tree outside the excluded
+// paths
+// Ignored files are left out.
+func CaptureTree(basePath string, exclude ...string) (*TreeState, error) {
+	commit, err := Snapshot(basePath)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := hashUntracked(basePath, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return &TreeState{basePath: basePath, exclude: exclude, commit: commit, untracked: untracked}, nil
+}
+
+// Changed captures the working tree again and returns the files whose
+// content differs from the captured state, sorted
+// Added and deleted files count as changed.
+func (t *TreeState) Changed() ([]string, error) {
+	now, err := CaptureTree(t.basePath, t.exclude...)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", append([]string{"diff", "--name-only", t.commit, now.commit}, pathspec(t.exclude)...)...)
+	cmd.Dir = t.basePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare the working tree: %w", err)
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			changed[line] = true
+		}
+	}
+	for path, hash := range t.untracked {
+		if now.untracked[path] != hash {
+			changed[path] = true
+		}
+	}
+	for path := range now.untracked {
+		if _, ok := t.untracked[path]; !ok {
+			changed[path] = true
+		}
+	}
+
+	files := make([]string, 0, len(changed))
+	for path := range changed {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Fingerprint returns a hash of the captured content that is equal for two
+// captures exactly when no file outside the excluded paths differs
+// Unlike the snapshot commit, which changes with every capture of a dirty
+// tree, it can be stored and compared in a later process.
+func (t *TreeState) Fingerprint() (string, error) {
+	cmd := exec.Command("git", "ls-tree", "-r", "-z", t.commit)
+	cmd.Dir = t.basePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list the working tree: %w", err)
+	}
+
+	h := sha256.New()
+	for _, entry := range strings.Split(string(output), "\x00") {
+		_, path, ok := strings.Cut(entry, "\t")
+		if ok && !excluded(path, t.exclude) {
+			fmt.Fprintf(h, "%s\x00", entry)
+		}
+	}
+	paths := make([]string, 0, len(t.untracked))
+	for path := range t.untracked {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(h, "untracked %s\t%s\x00", t.untracked[path], path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// excluded reports whether path is one of the excluded paths or inside one
+func excluded(path string, exclude []string) bool {
+	for _, ex := range exclude {
+		if path == ex || strings.HasPrefix(path, ex+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// hashUntracked returns the blob hash of every untracked, unignored file
+// outside the excluded paths
+func hashUntracked(basePath string, exclude []string) (map[string]string, error) {
+	list := exec.Command("git", append([]string{"ls-files", "-z", "--others", "--exclude-standard"}, pathspec(exclude)...)...)
+	list.Dir = basePath
+	output, err := list.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	var paths []string
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	hashes := make(map[string]string, len(paths))
+	if len(paths) == 0 {
+		return hashes, nil
+	}
+
+	hash := exec.Command("git", "hash-object", "--stdin-paths")
+	hash.Dir = basePath
+	hash.Stdin = strings.NewReader(strings.Join(paths, "\n") + "\n")
+	output, err = hash.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash untracked files: %w", err)
+	}
+	sums := strings.Fields(string(output))
+	if len(sums) != len(paths) {
+		return nil, fmt.Errorf("failed to hash untracked files: got %d hashes for %d files", len(sums), len(paths))
+	}
+	for i, path := range paths {
+		hashes[path] = sums[i]
+	}
+	return hashes, nil
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestTreeStateChanged(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	createTestCommit(t, repoPath, []string{"main.go", "lib.go", "old.go"}, "initial")
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(repoPath, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Files already modified or untracked before the capture
+	write("main.go", "user edit")
+	write("notes file.txt", "draft")
+	write(".milhouse/progress.md", "run state")
+
+	before, err := CaptureTree(repoPath, ".milhouse")
+	if err != nil {
+		t.Fatalf("CaptureTree() error = %v", err)
+	}
+	if changed, err := before.Changed(); err != nil || len(changed) != 0 {
+		t.Fatalf("Changed() with no edits = %v, %v; want none", changed, err)
+	}
+
+	write("main.go", "agent edit")
+	write("notes file.txt", "rewritten")
+	write("new.go", "package main")
+	write(".milhouse/progress.md", "more run state")
+	if err := os.Remove(filepath.Join(repoPath, "old.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := before.Changed()
+	if err != nil {
+		t.Fatalf("Changed() error = %v", err)
+	}
+	if want := []string{"main.go", "new.go", "notes file.txt", "old.go"}; !slices.Equal(changed, want) {
+		t.Errorf("Changed() = %q, want %q", changed, want)
+	}
+}
+
+func TestTreeStateFingerprint(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	createTestCommit(t, repoPath, []string{"main.go"}, "initial")
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(repoPath, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fingerprint := func() string {
+		t.Helper()
+		tree, err := CaptureTree(repoPath, ".milhouse")
+		if err != nil {
+			t.Fatalf("CaptureTree() error = %v", err)
+		}
+		fp, err := tree.Fingerprint()
+		if err != nil {
+			t.Fatalf("Fingerprint() error = %v", err)
+		}
+		return fp
+	}
+
+	write("main.go", "user edit")
+	before := fingerprint()
+	if again := fingerprint(); again != before {
+		t.Errorf("Fingerprint() of an unchanged dirty tree changed: %s then %s", before, again)
+	}
+
+	write(".milhouse/evidence/feat-checks.json", "[]")
+	if fingerprint() != before {
+		t.Error("Fingerprint() changed for a file in an excluded path")
+	}
+
+	write("fix.go", "package main")
+	withNew := fingerprint()
+	if withNew == before {
+		t.Error("Fingerprint() did not change when an untracked file was added")
+	}
+	write("fix.go", "package main // edited")
+	if fingerprint() == withNew {
+		t.Error("Fingerprint() did not change when an untracked file was edited")
+	}
+}
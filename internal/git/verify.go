@@ -3,9 +3,18 @@ package git
 import (
 	"fmt"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// Remote status values returned by CheckRemoteStatus
+const (
+	RemoteNoUpstream  = "no-upstream"
+	RemoteUpToDate    = "up-to-date"
+	remoteAheadPrefix = "ahead-"
+)
+
 // VerificationResult contains git verification outcomes
 type VerificationResult struct {
 	CommitExists       bool
@@ -15,9 +24,21 @@ type VerificationResult struct {
 	UnstagedChanges    []string // Unstaged changes in repo
 	UncommittedChanges bool     // Are there uncommitted changes?
 	RemoteStatus       string   // ahead/behind/up-to-date
+	RequirePushed      bool     // Treat unpushed commits as a verification failure
 	Errors             []string
 }
 
+// RevParse resolves a ref (e.g. "HEAD") to its full commit SHA
+func RevParse(basePath string, ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", ref)
+	cmd.Dir = basePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // VerifyCommitExists checks if a commit SHA exists in the repository
 func VerifyCommitExists(basePath string, commitSHA string) (bool, error) {
 	cmd := exec.Command("git", "cat-file", "-t", commitSHA)
@@ -59,6 +80,44 @@ func VerifyCommitFiles(basePath string, commitSHA string, files []string) (match
 	return matches, missing, nil
 }
 
+// GetChangedFilesSince returns the files changed between baselineSHA and HEAD,
+// sorted, including deleted ones
+func GetChangedFilesSince(basePath string, baselineSHA string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", baselineSHA, "HEAD")
+	cmd.Dir = basePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files changed since %.7s: %w", baselineSHA, err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// DiffStat summarizes what the given commits changed, one --stat block per
+// commit headed by its short SHA and subject
+// Without commits it summarizes uncommitted changes to tracked files.
+func DiffStat(basePath string, commits ...string) (string, error) {
+	args := []string{"diff", "--stat", "HEAD"}
+	if len(commits) > 0 {
+		args = append([]string{"show", "--stat", "--format=%h %s"}, commits...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = basePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff stat: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // CheckWorkingTreeClean verifies no unstaged or uncommitted changes
 func CheckWorkingTreeClean(basePath string) (clean bool, changes []string, err error) {
 	// Check for unstaged and uncommitted changes
@@ -93,17 +152,30 @@ func CheckRemoteStatus(basePath string) (status string, err error) {
 	output, err := cmd.Output()
 	if err != nil {
 		// If there's no upstream branch, return specific message
-		return "no-upstream", nil
+		return RemoteNoUpstream, nil
 	}
 
 	outputStr := strings.TrimSpace(string(output))
 	if outputStr == "" {
-		return "up-to-date", nil
+		return RemoteUpToDate, nil
 	}
 
 	// Count unpushed commits
 	unpushedCommits := len(strings.Split(outputStr, "\n"))
-	return fmt.Sprintf("ahead-%d", unpushedCommits), nil
+	return fmt.Sprintf("%s%d", remoteAheadPrefix, unpushedCommits), nil
+}
+
+// UnpushedCommits returns the number of commits ahead of upstream for an
+// "ahead-N" remote status, or 0 for any other status
+func UnpushedCommits(status string) int {
+	if !strings.HasPrefix(status, remoteAheadPrefix) {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(status, remoteAheadPrefix))
+	if err != nil {
+		return 0
+	}
+	return n
 }
 
 // VerifyEvidence checks git state against evidence claims
@@ -157,9 +229,18 @@ func (r *VerificationResult) IsVerified() bool {
 	return r.CommitExists &&
 		len(r.FilesMissing) == 0 &&
 		!r.UncommittedChanges &&
+		!r.PushPending() &&
 		len(r.Errors) == 0
 }
 
+// PushPending reports whether pushing is required but work is not yet on upstream
+func (r *VerificationResult) PushPending() bool {
+	if !r.RequirePushed {
+		return false
+	}
+	return r.RemoteStatus == RemoteNoUpstream || UnpushedCommits(r.RemoteStatus) > 0
+}
+
 // GetErrorSummary returns a human-readable summary of verification failures
 func (r *VerificationResult) GetErrorSummary() string {
 	var issues []string
@@ -176,6 +257,14 @@ func (r *VerificationResult) GetErrorSummary() string {
 		issues = append(issues, fmt.Sprintf("Uncommitted changes detected: %d files", len(r.UnstagedChanges)))
 	}
 
+	if r.PushPending() {
+		if r.RemoteStatus == RemoteNoUpstream {
+			issues = append(issues, "Commits not pushed: no upstream branch configured")
+		} else {
+			issues = append(issues, fmt.Sprintf("Unpushed commits: %d ahead of upstream", UnpushedCommits(r.RemoteStatus)))
+		}
+	}
+
 	if len(r.Errors) > 0 {
 		issues = append(issues, fmt.Sprintf("Verification errors: %s", strings.Join(r.Errors, "; ")))
 	}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -174,6 +175,61 @@ func TestVerifyCommitFiles(t *testing.T) {
 	}
 }
 
+func TestGetChangedFilesSince(t *testing.T) {
+	repo, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	baseline := createTestCommit(t, repo, []string{"README.md"}, "Initial commit")
+	createTestCommit(t, repo, []string{"src/main.go"}, "Add main")
+	createTestCommit(t, repo, []string{"src/util.go", "README.md"}, "Add util")
+
+	files, err := GetChangedFilesSince(repo, baseline)
+	if err != nil {
+		t.Fatalf("GetChangedFilesSince() error = %v", err)
+	}
+	// README.md was rewritten with identical content, so it is unchanged
+	want := []string{"src/main.go", "src/util.go"}
+	if len(files) != len(want) || files[0] != want[0] || files[1] != want[1] {
+		t.Errorf("GetChangedFilesSince() = %v, want %v", files, want)
+	}
+
+	head, _ := RevParse(repo, "HEAD")
+	if files, err := GetChangedFilesSince(repo, head); err != nil || len(files) != 0 {
+		t.Errorf("GetChangedFilesSince(HEAD) = %v, %v; want no files", files, err)
+	}
+
+	if _, err := GetChangedFilesSince(repo, "phantom123"); err == nil {
+		t.Error("expected an error for an unknown baseline")
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	repo, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	createTestCommit(t, repo, []string{"README.md"}, "Initial commit")
+	sha := createTestCommit(t, repo, []string{"src/main.go"}, "Add main")
+
+	stat, err := DiffStat(repo, sha)
+	if err != nil {
+		t.Fatalf("DiffStat() error = %v", err)
+	}
+	if !strings.Contains(stat, "Add main") || !strings.Contains(stat, "src/main.go") || !strings.Contains(stat, "1 file changed") {
+		t.Errorf("DiffStat() = %q, want the commit subject and its file", stat)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if stat, err := DiffStat(repo); err != nil || !strings.Contains(stat, "README.md") {
+		t.Errorf("DiffStat() of the working tree = %q, %v; want README.md", stat, err)
+	}
+
+	if _, err := DiffStat(repo, "phantom123"); err == nil {
+		t.Error("expected an error for an unknown commit")
+	}
+}
+
 func TestCheckWorkingTreeClean(t *testing.T) {
 	repo, cleanup := setupTestRepo(t)
 	defer cleanup()
@@ -329,3 +385,104 @@ func TestVerificationResultErrorSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckRemoteStatus(t *testing.T) {
+	repo, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	createTestCommit(t, repo, []string{"initial.txt"}, "Initial commit")
+
+	t.Run("No upstream", func(t *testing.T) {
+		status, err := CheckRemoteStatus(repo)
+		if err != nil {
+			t.Fatalf("CheckRemoteStatus() error = %v", err)
+		}
+		if status != RemoteNoUpstream {
+			t.Errorf("CheckRemoteStatus() = %q, want %q", status, RemoteNoUpstream)
+		}
+	})
+
+	// Create a bare remote and push the current branch to it
+	remote := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--bare", remote},
+		{"-C", repo, "remote", "add", "origin", remote},
+		{"-C", repo, "push", "-u", "origin", "HEAD"},
+	} {
+		cmd := exec.Command("git", args...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	t.Run("Up to date", func(t *testing.T) {
+		status, err := CheckRemoteStatus(repo)
+		if err != nil {
+			t.Fatalf("CheckRemoteStatus() error = %v", err)
+		}
+		if status != RemoteUpToDate {
+			t.Errorf("CheckRemoteStatus() = %q, want %q", status, RemoteUpToDate)
+		}
+	})
+
+	t.Run("Ahead of upstream", func(t *testing.T) {
+		createTestCommit(t, repo, []string{"second.txt"}, "Second commit")
+		createTestCommit(t, repo, []string{"third.txt"}, "Third commit")
+
+		status, err := CheckRemoteStatus(repo)
+		if err != nil {
+			t.Fatalf("CheckRemoteStatus() error = %v", err)
+		}
+		if status != "ahead-2" {
+			t.Errorf("CheckRemoteStatus() = %q, want %q", status, "ahead-2")
+		}
+		if got := UnpushedCommits(status); got != 2 {
+			t.Errorf("UnpushedCommits() = %d, want 2", got)
+		}
+	})
+}
+
+func TestPushRequirement(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       VerificationResult
+		wantVerified bool
+		want         string
+	}{
+		{
+			name:         "Unpushed commits ignored when not required",
+			result:       VerificationResult{CommitExists: true, RemoteStatus: "ahead-3"},
+			wantVerified: true,
+			want:         "All verification checks passed",
+		},
+		{
+			name:         "Ahead of upstream when required",
+			result:       VerificationResult{CommitExists: true, RemoteStatus: "ahead-3", RequirePushed: true},
+			wantVerified: false,
+			want:         "Unpushed commits: 3 ahead of upstream",
+		},
+		{
+			name:         "No upstream when required",
+			result:       VerificationResult{CommitExists: true, RemoteStatus: RemoteNoUpstream, RequirePushed: true},
+			wantVerified: false,
+			want:         "Commits not pushed: no upstream branch configured",
+		},
+		{
+			name:         "Up to date when required",
+			result:       VerificationResult{CommitExists: true, RemoteStatus: RemoteUpToDate, RequirePushed: true},
+			wantVerified: true,
+			want:         "All verification checks passed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.IsVerified(); got != tt.wantVerified {
+				t.Errorf("IsVerified() = %v, want %v", got, tt.wantVerified)
+			}
+			if got := tt.result.GetErrorSummary(); got != tt.want {
+				t.Errorf("GetErrorSummary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
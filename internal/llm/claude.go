@@ -13,27 +13,72 @@ import (
 
 // ExecuteOptions contains options for Claude execution
 type ExecuteOptions struct {
-	Prompt       string
-	ContextFiles []string
-	Model        string
-	AllowedTools []string
-	WorkDir      string
-	SystemPrompt string // For interactive mode
+	Prompt          string
+	ContextFiles    []string
+	Model           string
+	AllowedTools    []string
+	DisallowedTools []string // Denied outright, even with permissions skipped
+	WorkDir         string
+	AddDirs         []string             // Extra directories outside WorkDir the agent may access
+	SystemPrompt    string               // Fixed instructions; replaces the CLI's own when interactive, appended to it otherwise
+	SessionID       string               // UUID for the session, so its transcript can be found later
+	Resume          string               // Session ID to continue instead of starting a new session
+	ExtraArgs       []string             // Passed to the claude CLI verbatim; see CheckExtraArgs
+	MCPServers      map[string]MCPServer // Written to a private file for --mcp-config; see CheckMCPServers
+	Settings        string               // Settings file passed with --settings, e.g. the write gate hook
+
+	mcpConfigPath string // Set by prepareMCPConfig while the command runs
+}
+
+// managedFlags are claude CLI flags Millhouse sets itself; passing them again
+// through ExtraArgs would fight its model, tool and stream handling
+var managedFlags = []string{
+	"--model", "--allowedTools", "--allowed-tools", "--disallowedTools", "--disallowed-tools",
+	"--output-format", "--input-format", "--verbose", "-p", "--print",
+	"--system-prompt", "--add-dir", "--session-id", "--resume", "--dangerously-skip-permissions",
+}
+
+// CheckExtraArgs rejects extra arguments that repeat a flag Millhouse manages,
+// in either "--flag value" or "--flag=value" form
+func CheckExtraArgs(args []string) error {
+	for _, arg := range args {
+		name, _, _ := strings.Cut(arg, "=")
+		for _, flag := range managedFlags {
+			if name == flag {
+				return fmt.Errorf("%s is managed by Millhouse", flag)
+			}
+		}
+	}
+	return nil
 }
 
 // Claude implements the Backend interface for Claude Code CLI
 type Claude struct {
 	BinaryPath string
+	APIKey     string // Passed to claude as ANTHROPIC_API_KEY; empty uses the CLI's own auth
 }
 
+// APIKeyEnv is the environment variable the claude CLI reads its API key from
+const APIKeyEnv = "ANTHROPIC_API_KEY"
+
 // NewClaude creates a new Claude backend
-func NewClaude(binaryPath string) *Claude {
+// An empty apiKey leaves authentication to the claude CLI (its login or an
+// inherited ANTHROPIC_API_KEY).
+func NewClaude(binaryPath, apiKey string) *Claude {
 	if binaryPath == "" {
 		binaryPath = "claude"
 	}
 	// Try to resolve the binary path
 	resolved := utils.ResolveBinaryPath(binaryPath)
-	return &Claude{BinaryPath: resolved}
+	return &Claude{BinaryPath: resolved, APIKey: apiKey}
+}
+
+// env returns the environment for a claude process, nil meaning inherit ours
+func (c *Claude) env() []string {
+	if c.APIKey == "" {
+		return nil
+	}
+	return append(os.Environ(), APIKeyEnv+"="+c.APIKey)
 }
 
 // Name returns the name of this backend
@@ -43,18 +88,25 @@ func (c *Claude) Name() string {
 
 // Execute runs Claude Code with the given options and returns streaming output
 func (c *Claude) Execute(ctx context.Context, opts ExecuteOptions) (io.ReadCloser, error) {
+	cleanup, err := prepareMCPConfig(&opts)
+	if err != nil {
+		return nil, err
+	}
 	args := c.buildArgs(opts, false)
 
 	cmd := exec.CommandContext(ctx, c.BinaryPath, args...)
 	cmd.Dir = opts.WorkDir
+	cmd.Env = c.env()
 	cmd.Stderr = os.Stderr
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
+		cleanup()
 		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 
 	if err := cmd.Start(); err != nil {
+		cleanup()
 		if strings.Contains(err.Error(), "executable file not found") {
 			return nil, utils.ClaudeNotFoundError()
 		}
@@ -65,15 +117,35 @@ func (c *Claude) Execute(ctx context.Context, opts ExecuteOptions) (io.ReadClose
 	return &cmdReader{
 		ReadCloser: stdout,
 		cmd:        cmd,
+		cleanup:    cleanup,
 	}, nil
 }
 
+// Version returns the first line of `claude --version`
+func (c *Claude) Version(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, c.BinaryPath, "--version").Output()
+	if err != nil {
+		if strings.Contains(err.Error(), "executable file not found") {
+			return "", utils.ClaudeNotFoundError()
+		}
+		return "", fmt.Errorf("claude --version failed: %w", err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return line, nil
+}
+
 // ExecuteInteractive runs Claude Code in interactive mode
 func (c *Claude) ExecuteInteractive(ctx context.Context, opts ExecuteOptions) error {
+	cleanup, err := prepareMCPConfig(&opts)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	args := c.buildArgs(opts, true)
 
 	cmd := exec.CommandContext(ctx, c.BinaryPath, args...)
 	cmd.Dir = opts.WorkDir
+	cmd.Env = c.env()
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -87,14 +159,56 @@ func (c *Claude) buildArgs(opts ExecuteOptions, interactive bool) []string {
 	// Skip permissions for autonomous execution
 	args = append(args, "--dangerously-skip-permissions")
 
+	// User-supplied flags; placed before --add-dir so a variadic flag among
+	// them cannot swallow the context files at the end
+	extraArgs, appendSystem := opts.ExtraArgs, ""
+	if !interactive && opts.SystemPrompt != "" {
+		extraArgs, appendSystem = takeAppendSystemPrompt(extraArgs)
+	}
+	args = append(args, extraArgs...)
+
+	// MCP servers, from a private file so their env values and header tokens
+	// stay off the command line; the --flag=value form keeps the variadic
+	// --mcp-config from consuming the arguments after it
+	if opts.mcpConfigPath != "" {
+		args = append(args, "--mcp-config="+opts.mcpConfigPath)
+	}
+
+	// Settings such as the write gate's PreToolUse hook
+	if opts.Settings != "" {
+		args = append(args, "--settings", opts.Settings)
+	}
+
+	// Extra directories (e.g. relocated artifacts); kept ahead of other flags
+	// because --add-dir consumes every following non-flag argument
+	for _, dir := range opts.AddDirs {
+		args = append(args, "--add-dir", dir)
+	}
+
 	// Model
 	if opts.Model != "" {
 		args = append(args, "--model", opts.Model)
 	}
 
-	// System prompt (for interactive mode)
-	if interactive && opts.SystemPrompt != "" {
-		args = append(args, "--system-prompt", opts.SystemPrompt)
+	// Session ID (lets callers read the transcript afterwards)
+	if opts.SessionID != "" {
+		args = append(args, "--session-id", opts.SessionID)
+	}
+
+	// Resume an earlier session (e.g. after the builder's write gate)
+	if opts.Resume != "" {
+		args = append(args, "--resume", opts.Resume)
+	}
+
+	// System prompt: interactive sessions replace the CLI's own, autonomous
+	// runs append to it so its tool instructions are kept. A user's
+	// --append-system-prompt is folded in, since the CLI takes only one.
+	if opts.SystemPrompt != "" {
+		if interactive {
+			args = append(args, "--system-prompt", opts.SystemPrompt)
+		} else {
+			args = append(args, "--append-system-prompt", opts.SystemPrompt+appendSystem)
+		}
 	}
 
 	// Prompt (only for non-interactive)
@@ -106,6 +220,9 @@ func (c *Claude) buildArgs(opts ExecuteOptions, interactive bool) []string {
 	if len(opts.AllowedTools) > 0 {
 		args = append(args, "--allowedTools", strings.Join(opts.AllowedTools, ","))
 	}
+	if len(opts.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(opts.DisallowedTools, ","))
+	}
 
 	// Output format (only for non-interactive)
 	if !interactive {
@@ -118,15 +235,37 @@ func (c *Claude) buildArgs(opts ExecuteOptions, interactive bool) []string {
 	return args
 }
 
+// takeAppendSystemPrompt removes --append-system-prompt from extra args,
+// returning the rest and the removed values, each preceded by a blank line
+func takeAppendSystemPrompt(extra []string) ([]string, string) {
+	const flag = "--append-system-prompt"
+	var rest []string
+	var appended strings.Builder
+	for i := 0; i < len(extra); i++ {
+		switch name, value, hasValue := strings.Cut(extra[i], "="); {
+		case name == flag && hasValue:
+			appended.WriteString("\n\n" + value)
+		case name == flag && i+1 < len(extra):
+			i++
+			appended.WriteString("\n\n" + extra[i])
+		default:
+			rest = append(rest, extra[i])
+		}
+	}
+	return rest, appended.String()
+}
+
 // cmdReader wraps an io.ReadCloser and waits for the command on close
 type cmdReader struct {
 	io.ReadCloser
-	cmd *exec.Cmd
+	cmd     *exec.Cmd
+	cleanup func() // Removes files the command needed, once it has exited
 }
 
 func (r *cmdReader) Close() error {
 	closeErr := r.ReadCloser.Close()
 	waitErr := r.cmd.Wait()
+	r.cleanup()
 	if waitErr != nil {
 		return waitErr
 	}
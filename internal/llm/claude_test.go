@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildArgsExtraArgs(t *testing.T) {
+	c := &Claude{BinaryPath: "claude"}
+	args := c.buildArgs(ExecuteOptions{
+		Model:        "sonnet",
+		AddDirs:      []string{"/tmp/artifacts"},
+		ExtraArgs:    []string{"--mcp-config", "mcp.json"},
+		ContextFiles: []string{"prd.json"},
+	}, false)
+
+	got := strings.Join(args, " ")
+	if !strings.Contains(got, "--dangerously-skip-permissions --mcp-config mcp.json --add-dir /tmp/artifacts") {
+		t.Errorf("extra args should follow the permissions flag and precede --add-dir: %s", got)
+	}
+	if args[len(args)-1] != "prd.json" {
+		t.Errorf("context files should stay last: %s", got)
+	}
+}
+
+func TestBuildArgsSystemPrompt(t *testing.T) {
+	c := &Claude{BinaryPath: "claude"}
+
+	// Only Prompt: no system prompt flag, as before the split
+	got := strings.Join(c.buildArgs(ExecuteOptions{Prompt: "data"}, false), " ")
+	if strings.Contains(got, "system-prompt") || !strings.Contains(got, "-p data") {
+		t.Errorf("prompt-only args = %s", got)
+	}
+
+	args := c.buildArgs(ExecuteOptions{
+		SystemPrompt: "rules",
+		Prompt:       "data",
+		ExtraArgs:    []string{"--append-system-prompt", "Be terse", "--mcp-config", "mcp.json", "--append-system-prompt=No emoji"},
+	}, false)
+	got = strings.Join(args, " ")
+	if strings.Count(got, "--append-system-prompt") != 1 {
+		t.Errorf("want a single --append-system-prompt: %s", got)
+	}
+	if !slices.Contains(args, "rules\n\nBe terse\n\nNo emoji") {
+		t.Errorf("user's --append-system-prompt should follow the phase rules: %q", args)
+	}
+	if !strings.Contains(got, "--mcp-config mcp.json") || !strings.Contains(got, "-p data") {
+		t.Errorf("other args lost: %s", got)
+	}
+
+	got = strings.Join(c.buildArgs(ExecuteOptions{SystemPrompt: "rules"}, true), " ")
+	if !strings.Contains(got, "--system-prompt rules") || strings.Contains(got, "--append-system-prompt") {
+		t.Errorf("interactive args = %s", got)
+	}
+}
+
+func TestCheckExtraArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{nil, ""},
+		{[]string{"--append-system-prompt", "Be terse", "--mcp-config", "mcp.json"}, ""},
+		{[]string{"--model", "opus"}, "--model"},
+		{[]string{"--output-format=text"}, "--output-format"},
+		{[]string{"-p", "hi"}, "-p"},
+	}
+	for _, tt := range tests {
+		err := CheckExtraArgs(tt.args)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("CheckExtraArgs(%v) error = %v", tt.args, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("CheckExtraArgs(%v) error = %v, want mention of %s", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestClaudeEnv(t *testing.T) {
+	if env := (&Claude{}).env(); env != nil {
+		t.Errorf("env() without a key = %d entries, want nil to inherit", len(env))
+	}
+	env := (&Claude{APIKey: "sk-ant-test"}).env()
+	if len(env) == 0 || env[len(env)-1] != APIKeyEnv+"=sk-ant-test" {
+		t.Errorf("env() should end with the configured key")
+	}
+}
+
+func TestClaudeVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake claude is a shell script")
+	}
+	bin := filepath.Join(t.TempDir(), "claude")
+	script := "#!/bin/sh\n[ \"$1\" = --version ] && printf '2.1.0 (Claude Code)\\nextra\\n'\n"
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := (&Claude{BinaryPath: bin}).Version(context.Background())
+	if err != nil || version != "2.1.0 (Claude Code)" {
+		t.Errorf("Version() = %q, %v; want the first line", version, err)
+	}
+	if _, err := (&Claude{BinaryPath: filepath.Join(t.TempDir(), "missing")}).Version(context.Background()); err == nil {
+		t.Error("Version() of a missing binary should fail")
+	}
+}
@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrRateLimited marks a phase that failed because the API rate-limited it
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrorHandler is implemented by handlers that want API errors reported
+// in the stream (error events and results flagged is_error)
+type ErrorHandler interface {
+	OnError(message string)
+}
+
+// rateLimitMarkers are substrings of API error messages that indicate a
+// rate limit rather than a failure of the request itself
+var rateLimitMarkers = []string{
+	"429",
+	"rate_limit",
+	"rate limit",
+	"too many requests",
+	"usage limit",
+}
+
+// IsRateLimitError reports whether an API error message describes a rate limit
+func IsRateLimitError(message string) bool {
+	lower := strings.ToLower(message)
+	for _, marker := range rateLimitMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package llm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MutatingTools are the claude tools that can change files
+var MutatingTools = []string{"Write", "Edit", "MultiEdit", "NotebookEdit", "Bash"}
+
+// IsMutatingTool reports whether a tool can change files
+// MCP tools (mcp__server__tool) count, since Millhouse cannot tell what they do.
+func IsMutatingTool(name string) bool {
+	for _, t := range MutatingTools {
+		if t == name {
+			return true
+		}
+	}
+	return strings.HasPrefix(name, "mcp__")
+}
+
+// WriteGate decides whether an agent may go on to change files; tool is the
+// first mutating tool it tried to use
+type WriteGate func(tool string) bool
+
+// WriteGateHookCommand is the hidden mil subcommand claude runs as its
+// PreToolUse hook while a write gate is open
+const WriteGateHookCommand = "write-gate"
+
+// writeGateTimeout is how long claude waits on the hook, which covers the
+// time the user takes to answer
+// Without it claude kills the hook after its default timeout and may go on
+// with the change.
+const writeGateTimeout = 24 * time.Hour
+
+// writeGateAnswerTimeout is how long the hook waits for an answer; it gives
+// up before claude's timeout so a missing answer blocks the tool
+var writeGateAnswerTimeout = writeGateTimeout - time.Minute
+
+// writeGateDenied is what claude tells the agent when the user declines
+const writeGateDenied = "The user declined changes for this PRD. Do not retry; end your turn."
+
+// SetWriteGate makes the handler ask gate the first time the agent is about
+// to use a mutating tool, through the hook served by StartWriteGateHook
+// The agent keeps every tool; claude waits on the hook, so nothing changes
+// before the answer. A refusal stops the phase.
+func (h *ConsoleHandler) SetWriteGate(gate WriteGate) {
+	h.writeGate = gate
+}
+
+// WriteGated reports whether the agent reached the write gate
+func (h *ConsoleHandler) WriteGated() bool {
+	h.gateMu.Lock()
+	defer h.gateMu.Unlock()
+	return h.gateAsked
+}
+
+// WriteApproved reports whether the user allowed the agent to change files
+func (h *ConsoleHandler) WriteApproved() bool {
+	h.gateMu.Lock()
+	defer h.gateMu.Unlock()
+	return h.writeApproved
+}
+
+// AskWriteGate answers claude's hook for a mutating tool use
+// The gate is asked once per phase and later uses get the same answer. Time
+// spent waiting on the user does not count toward the idle timeout.
+func (h *ConsoleHandler) AskWriteGate(tool string) bool {
+	h.gateMu.Lock()
+	defer h.gateMu.Unlock()
+	if h.writeGate == nil {
+		return true
+	}
+	if !h.gateAsked {
+		h.gateAsked = true
+		h.gateWaiting.Store(true)
+		h.display.Flush() // Show everything the agent said before asking
+		h.writeApproved = h.writeGate(tool)
+		h.touch()
+		h.gateWaiting.Store(false)
+		if !h.writeApproved {
+			h.writeDeclined.Store(true)
+			if h.onTerminate != nil {
+				h.onTerminate()
+			}
+		}
+	}
+	return h.writeApproved
+}
+
+// WriteGateHook serves the PreToolUse hook that lets Millhouse answer before
+// claude runs a mutating tool
+// Hooks run as separate processes, so they reach Millhouse over a unix socket
+// in a private temporary directory that also holds the settings file.
+type WriteGateHook struct {
+	dir      string
+	listener net.Listener
+	ask      func(tool string) bool
+	wg       sync.WaitGroup
+}
+
+// StartWriteGateHook listens for hook requests, answering each with ask
+// exe is the mil binary claude runs as the hook. Close the hook after the run.
+func StartWriteGateHook(exe string, ask func(tool string) bool) (*WriteGateHook, error) {
+	dir, err := os.MkdirTemp("", "mil-gate-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create write gate directory: %w", err)
+	}
+	socket := filepath.Join(dir, "gate.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to open write gate socket: %w", err)
+	}
+
+	h := &WriteGateHook{dir: dir, listener: listener, ask: ask}
+	if err := writePrivateFile(h.SettingsPath(), writeGateSettings(exe, socket)); err != nil {
+		h.Close()
+		return nil, fmt.Errorf("failed to write write gate settings: %w", err)
+	}
+
+	h.wg.Add(1)
+	go h.serve()
+	return h, nil
+}
+
+// SettingsPath returns the claude settings file registering the hook
+func (h *WriteGateHook) SettingsPath() string {
+	return filepath.Join(h.dir, "settings.json")
+}
+
+// Close stops serving hook requests and removes the socket and settings
+func (h *WriteGateHook) Close() error {
+	err := h.listener.Close()
+	h.wg.Wait()
+	os.RemoveAll(h.dir)
+	return err
+}
+
+func (h *WriteGateHook) serve() {
+	defer h.wg.Done()
+	for {
+		conn, err := h.listener.Accept()
+		if err != nil {
+			return // Closed
+		}
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			defer conn.Close()
+			tool, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return
+			}
+			answer := "deny\n"
+			if h.ask(strings.TrimSpace(tool)) {
+				answer = "allow\n"
+			}
+			io.WriteString(conn, answer)
+		}()
+	}
+}
+
+// writeGateSettings builds the claude settings that run the hook before
+// every mutating tool
+func writeGateSettings(exe, socket string) []byte {
+	matcher := strings.Join(MutatingTools, "|") + "|mcp__.*"
+	hook := map[string]any{
+		"type":    "command",
+		"command": shellQuote(exe) + " " + WriteGateHookCommand + " " + shellQuote(socket),
+		"timeout": int(writeGateTimeout.Seconds()),
+	}
+	settings := map[string]any{
+		"hooks": map[string]any{
+			"PreToolUse": []any{map[string]any{"matcher": matcher, "hooks": []any{hook}}},
+		},
+	}
+	data, _ := json.Marshal(settings)
+	return data
+}
+
+// RunWriteGateHook is the hook side: it reads claude's PreToolUse input from
+// stdin, asks Millhouse over socket and returns the exit code for claude
+// 0 lets the tool run; 2 blocks it and shows stderr to the agent. Any failure
+// to get an answer blocks, so a broken gate never lets a change through.
+func RunWriteGateHook(socket string, stdin io.Reader, stderr io.Writer) int {
+	var input struct {
+		ToolName string `json:"tool_name"`
+	}
+	if err := json.NewDecoder(stdin).Decode(&input); err != nil || input.ToolName == "" {
+		fmt.Fprintln(stderr, "Millhouse write gate: unreadable hook input; the change was blocked")
+		return 2
+	}
+
+	conn, err := net.DialTimeout("unix", socket, 5*time.Second)
+	if err != nil {
+		fmt.Fprintf(stderr, "Millhouse write gate unreachable (%v); the change was blocked\n", err)
+		return 2
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(writeGateAnswerTimeout))
+
+	if _, err := io.WriteString(conn, input.ToolName+"\n"); err != nil {
+		fmt.Fprintf(stderr, "Millhouse write gate failed (%v); the change was blocked\n", err)
+		return 2
+	}
+	answer, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		fmt.Fprintf(stderr, "Millhouse write gate gave no answer (%v); the change was blocked\n", err)
+		return 2
+	}
+	if strings.TrimSpace(answer) != "allow" {
+		fmt.Fprintln(stderr, writeGateDenied)
+		return 2
+	}
+	return 0
+}
+
+// shellQuote quotes s for the sh -c claude runs hook commands with
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// writePrivateFile writes data readable by the current user only
+func writePrivateFile(path string, data []byte) error {
+	return os.WriteFile(path, data, 0600)
+}
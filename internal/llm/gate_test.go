@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteGateHook(t *testing.T) {
+	for _, approve := range []bool{true, false} {
+		handler := NewConsoleHandler()
+		handler.display.SetOutput(io.Discard, io.Discard)
+		terminated := false
+		handler.onTerminate = func() { terminated = true }
+		var asked []string
+		handler.SetWriteGate(func(tool string) bool {
+			asked = append(asked, tool)
+			return approve
+		})
+
+		hook, err := StartWriteGateHook("/usr/local/bin/mil", handler.AskWriteGate)
+		if err != nil {
+			t.Fatalf("StartWriteGateHook() error = %v", err)
+		}
+		settings, err := os.ReadFile(hook.SettingsPath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info, _ := os.Stat(hook.SettingsPath()); info.Mode().Perm() != 0600 {
+			t.Errorf("settings mode = %v, want 0600", info.Mode().Perm())
+		}
+		if !strings.Contains(string(settings), `"PreToolUse"`) || !strings.Contains(string(settings), `'/usr/local/bin/mil' write-gate '`) {
+			t.Errorf("settings do not register the hook: %s", settings)
+		}
+		if !strings.Contains(string(settings), `"timeout":86400`) {
+			t.Errorf("settings should give the hook time for the user to answer: %s", settings)
+		}
+		socket := filepath.Join(filepath.Dir(hook.SettingsPath()), "gate.sock")
+
+		wantCode := 0
+		if !approve {
+			wantCode = 2
+		}
+		for _, tool := range []string{"Edit", "Bash"} {
+			var stderr bytes.Buffer
+			code := RunWriteGateHook(socket, strings.NewReader(`{"tool_name":"`+tool+`","tool_input":{}}`), &stderr)
+			if code != wantCode {
+				t.Errorf("approve=%v: hook for %s exited %d (%s), want %d", approve, tool, code, stderr.String(), wantCode)
+			}
+		}
+
+		if len(asked) != 1 || asked[0] != "Edit" {
+			t.Errorf("gate asked for %v, want only the first mutating tool [Edit]", asked)
+		}
+		if !handler.WriteGated() || handler.WriteApproved() != approve {
+			t.Errorf("approve=%v: gated=%v approved=%v", approve, handler.WriteGated(), handler.WriteApproved())
+		}
+		if terminated == approve || handler.ShouldTerminate() == approve {
+			t.Errorf("approve=%v: terminated=%v, should stop only when declined", approve, terminated)
+		}
+
+		hook.Close()
+		if _, err := os.Stat(hook.SettingsPath()); !os.IsNotExist(err) {
+			t.Errorf("Close() left the settings file behind (%v)", err)
+		}
+		var stderr bytes.Buffer
+		if code := RunWriteGateHook(socket, strings.NewReader(`{"tool_name":"Edit"}`), &stderr); code != 2 {
+			t.Errorf("hook with the gate closed exited %d, want 2 so nothing slips through", code)
+		}
+	}
+}
+
+func TestWriteGateHookNoAnswer(t *testing.T) {
+	defer func(timeout time.Duration) { writeGateAnswerTimeout = timeout }(writeGateAnswerTimeout)
+	writeGateAnswerTimeout = 100 * time.Millisecond
+
+	// A gate that takes the request but never answers
+	socket := filepath.Join(t.TempDir(), "gate.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(io.Discard, conn)
+	}()
+
+	var stderr bytes.Buffer
+	if code := RunWriteGateHook(socket, strings.NewReader(`{"tool_name":"Edit"}`), &stderr); code != 2 {
+		t.Errorf("hook without an answer exited %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "no answer") {
+		t.Errorf("stderr = %q, want the missing answer explained", stderr.String())
+	}
+}
+
+func TestWriteGateUnset(t *testing.T) {
+	handler := NewConsoleHandler()
+	handler.OnToolUse("Bash")
+	if handler.WriteGated() || handler.ShouldTerminate() {
+		t.Error("without a gate, mutating tools should not stop the phase")
+	}
+}
+
+func TestBuildArgsResume(t *testing.T) {
+	c := &Claude{BinaryPath: "claude"}
+	args := strings.Join(c.buildArgs(ExecuteOptions{Resume: "abc-123", Prompt: "continue"}, false), " ")
+	if !strings.Contains(args, "--resume abc-123") || strings.Contains(args, "--session-id") {
+		t.Errorf("expected --resume without --session-id: %s", args)
+	}
+	args = strings.Join(c.buildArgs(ExecuteOptions{Settings: "/tmp/gate/settings.json"}, false), " ")
+	if !strings.Contains(args, "--settings /tmp/gate/settings.json") {
+		t.Errorf("expected --settings with the gate's file: %s", args)
+	}
+	if err := CheckExtraArgs([]string{"--resume", "x"}); err == nil {
+		t.Error("--resume should be managed by Millhouse")
+	}
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MCP server transport types accepted by the claude CLI
+const (
+	MCPTypeStdio = "stdio"
+	MCPTypeSSE   = "sse"
+	MCPTypeHTTP  = "http"
+)
+
+// MCPServer describes one MCP server, in the shape the claude CLI reads from
+// --mcp-config. Stdio servers (the default type) set Command; sse and http
+// servers set URL.
+type MCPServer struct {
+	Type    string            `yaml:"type,omitempty" json:"type,omitempty"`
+	Command string            `yaml:"command,omitempty" json:"command,omitempty"`
+	Args    []string          `yaml:"args,omitempty" json:"args,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	URL     string            `yaml:"url,omitempty" json:"url,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// CheckMCPServers validates server names and that each server sets the
+// fields its transport needs
+func CheckMCPServers(servers map[string]MCPServer) error {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names) // Report the same error on every load
+
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, " \t\n") {
+			return fmt.Errorf("invalid server name %q: must be non-empty without whitespace", name)
+		}
+		s := servers[name]
+		switch s.Type {
+		case "", MCPTypeStdio:
+			if s.Command == "" {
+				return fmt.Errorf("server %s: stdio servers need a command", name)
+			}
+			if s.URL != "" || len(s.Headers) > 0 {
+				return fmt.Errorf("server %s: url and headers only apply to sse and http servers", name)
+			}
+		case MCPTypeSSE, MCPTypeHTTP:
+			if s.URL == "" {
+				return fmt.Errorf("server %s: %s servers need a url", name, s.Type)
+			}
+			if s.Command != "" || len(s.Args) > 0 || len(s.Env) > 0 {
+				return fmt.Errorf("server %s: command, args and env only apply to stdio servers", name)
+			}
+		default:
+			return fmt.Errorf("server %s: invalid type '%s': must be 'stdio', 'sse', or 'http'", name, s.Type)
+		}
+	}
+	return nil
+}
+
+// mcpConfigJSON renders servers as the JSON document --mcp-config accepts
+func mcpConfigJSON(servers map[string]MCPServer) (string, error) {
+	data, err := json.Marshal(struct {
+		MCPServers map[string]MCPServer `json:"mcpServers"`
+	}{servers})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode MCP config: %w", err)
+	}
+	return string(data), nil
+}
+
+// prepareMCPConfig writes opts.MCPServers to a file only the current user can
+// read and points opts at it, returning the function that removes it
+// MCP env values and headers often hold tokens, which on the command line
+// any local user could read from the process list.
+func prepareMCPConfig(opts *ExecuteOptions) (func(), error) {
+	if len(opts.MCPServers) == 0 {
+		return func() {}, nil
+	}
+	mcpConfig, err := mcpConfigJSON(opts.MCPServers)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "mil-mcp-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MCP config directory: %w", err)
+	}
+	path := filepath.Join(dir, "mcp.json")
+	if err := writePrivateFile(path, []byte(mcpConfig)); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to write MCP config: %w", err)
+	}
+
+	opts.mcpConfigPath = path
+	return func() { os.RemoveAll(dir) }, nil
+}
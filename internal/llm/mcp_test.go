@@ -0,0 +1,113 @@
+package llm
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBuildArgsMCPServers(t *testing.T) {
+	c := &Claude{BinaryPath: "claude"}
+	opts := ExecuteOptions{
+		AddDirs: []string{"/tmp/artifacts"},
+		MCPServers: map[string]MCPServer{
+			"db":   {Command: "db-mcp", Args: []string{"--read-only"}, Env: map[string]string{"DB_TOKEN": "s3cret"}},
+			"docs": {Type: MCPTypeHTTP, URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer t0ken"}},
+		},
+		ContextFiles: []string{"prd.json"},
+	}
+	cleanup, err := prepareMCPConfig(&opts)
+	if err != nil {
+		t.Fatalf("prepareMCPConfig() error = %v", err)
+	}
+	args := c.buildArgs(opts, false)
+
+	var mcpPath string
+	for i, arg := range args {
+		if path, ok := strings.CutPrefix(arg, "--mcp-config="); ok {
+			mcpPath = path
+			if args[i+1] != "--add-dir" {
+				t.Errorf("--mcp-config should precede --add-dir: %v", args)
+			}
+		}
+	}
+	if mcpPath == "" {
+		t.Fatalf("no --mcp-config argument in %v", args)
+	}
+	if joined := strings.Join(args, " "); strings.Contains(joined, "s3cret") || strings.Contains(joined, "t0ken") {
+		t.Errorf("MCP secrets on the command line: %v", args)
+	}
+
+	info, err := os.Stat(mcpPath)
+	if err != nil {
+		t.Fatalf("MCP config file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("MCP config mode = %o, want 600", perm)
+	}
+	data, err := os.ReadFile(mcpPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		MCPServers map[string]map[string]any `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("MCP config is not valid JSON: %v", err)
+	}
+	db := doc.MCPServers["db"]
+	if db["command"] != "db-mcp" {
+		t.Errorf("db server = %v", db)
+	}
+	if _, ok := db["url"]; ok {
+		t.Errorf("unset fields should be omitted: %v", db)
+	}
+
+	cleanup()
+	if _, err := os.Stat(mcpPath); !os.IsNotExist(err) {
+		t.Errorf("MCP config still exists after cleanup: %v", err)
+	}
+
+	opts = ExecuteOptions{}
+	cleanup, err = prepareMCPConfig(&opts)
+	if err != nil {
+		t.Fatalf("prepareMCPConfig() without servers error = %v", err)
+	}
+	defer cleanup()
+	args = c.buildArgs(opts, false)
+	if strings.Contains(strings.Join(args, " "), "--mcp-config") {
+		t.Errorf("no --mcp-config expected without servers: %v", args)
+	}
+}
+
+func TestCheckMCPServers(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  MCPServer
+		wantErr string
+	}{
+		{"db", MCPServer{Command: "db-mcp"}, ""},
+		{"db", MCPServer{Type: MCPTypeStdio, Command: "db-mcp", Env: map[string]string{"A": "b"}}, ""},
+		{"docs", MCPServer{Type: MCPTypeHTTP, URL: "https://example.com/mcp"}, ""},
+		{"docs", MCPServer{Type: MCPTypeSSE, URL: "https://example.com/sse", Headers: map[string]string{"X": "y"}}, ""},
+		{"db", MCPServer{}, "need a command"},
+		{"db", MCPServer{Command: "db-mcp", URL: "https://example.com"}, "only apply to sse and http"},
+		{"docs", MCPServer{Type: MCPTypeHTTP}, "need a url"},
+		{"docs", MCPServer{Type: MCPTypeHTTP, URL: "https://example.com", Command: "x"}, "only apply to stdio"},
+		{"docs", MCPServer{Type: "websocket", URL: "wss://example.com"}, "invalid type"},
+		{"my db", MCPServer{Command: "db-mcp"}, "invalid server name"},
+	}
+	for _, tt := range tests {
+		err := CheckMCPServers(map[string]MCPServer{tt.name: tt.server})
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("CheckMCPServers(%s: %+v) error = %v", tt.name, tt.server, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("CheckMCPServers(%s: %+v) error = %v, want %q", tt.name, tt.server, err, tt.wantErr)
+		}
+	}
+}
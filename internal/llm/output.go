@@ -3,9 +3,12 @@ package llm
 import (
 	"bufio"
 	"encoding/json"
+	"fmt"
 	"io"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/daydemir/milhouse/internal/display"
@@ -20,10 +23,15 @@ const (
 	SignalVerified         = "VERIFIED"
 	SignalRejected         = "REJECTED"
 	SignalLoopRisk         = "LOOP_RISK"
+	SignalSubtaskDone      = "SUBTASK_DONE"
 	// Planner signals
 	SignalPlanComplete = "PLAN_COMPLETE"
 	SignalPlanSkipped  = "PLAN_SKIPPED"
 	SignalPlanUpdated  = "PLAN_UPDATED"
+	SignalNeedsRefine  = "NEEDS_REFINEMENT"
+	SignalSplit        = "SPLIT"
+	SignalCriteria     = "CRITERIA_PROPOSED"
+	SignalPlanMeta     = "PLAN_META"
 	// Reviewer signals
 	SignalPromptUpdated = "PROMPT_UPDATED"
 )
@@ -32,7 +40,8 @@ const (
 type Signal struct {
 	Type    string
 	Details string
-	PRDID   string // For VERIFIED, REJECTED, LOOP_RISK
+	PRDID   string         // For VERIFIED, REJECTED, LOOP_RISK, NEEDS_REFINEMENT, SUBTASK_DONE, SPLIT, CRITERIA_PROPOSED
+	Payload map[string]any // Structured data from ###SIGNAL:TYPE:{json}### signals
 }
 
 // TokenStats tracks token usage during execution
@@ -63,10 +72,18 @@ type StreamEvent struct {
 	Type    string          `json:"type"`
 	Message *MessageContent `json:"message,omitempty"`
 	Result  string          `json:"result,omitempty"`
+	IsError bool            `json:"is_error,omitempty"` // Set on result events for failed runs
+	Error   *ErrorBlock     `json:"error,omitempty"`    // Set on error events
 	Delta   *DeltaContent   `json:"delta,omitempty"`
 	Usage   *UsageBlock     `json:"usage,omitempty"`
 }
 
+// ErrorBlock represents an API error reported in the stream
+type ErrorBlock struct {
+	Type    string `json:"type"` // e.g. "rate_limit_error", "overloaded_error"
+	Message string `json:"message"`
+}
+
 // MessageContent represents the message field in stream events
 type MessageContent struct {
 	Content []ContentBlock `json:"content,omitempty"`
@@ -99,24 +116,48 @@ type ConsoleHandler struct {
 	signals        []Signal
 	tokenStats     TokenStats
 	tokenThreshold int
-	output         strings.Builder
+	outputLimit    int        // Bail on generated tokens alone; 0 disables
+	output         tailBuffer // Last DefaultMaxRetainedOutput bytes unless changed
 	onTerminate    func()
 	shouldStop     bool
 	display        *display.Display
 	toolCount      int
-	textBuffer     strings.Builder
+	toolCounts     map[string]int // Tool uses by name over the whole phase
+	errors         []string       // API errors reported in the stream
+	rateLimited    bool           // At least one error was a rate limit
+	lastActivity   atomic.Int64   // UnixNano of the last text, tool use or signal
+	idle           atomic.Bool    // The idle watchdog cancelled the phase
+	clock          display.Clock  // Time source for activity and throttling
+	redactor       *Redactor      // Masks secrets before text is shown or retained
+	writeGate      WriteGate      // Asked before the first mutating tool use; nil disables
+	gateMu         sync.Mutex     // Guards the write gate, asked from the hook server
+	gateAsked      bool           // The write gate has been asked
+	writeApproved  bool           // The write gate allowed changes
+	gateWaiting    atomic.Bool    // The write gate is waiting on the user
+	writeDeclined  atomic.Bool    // The user declined changes; the phase stops
+	traceEvents    bool           // Report unrecognized stream event types
+	seenEvents     map[string]bool
 
 	// Throttling fields
-	lastTokenDisplay time.Time
-	throttleInterval time.Duration
+	lastTokenDisplay time.Time     // Last output token rate sample
+	throttleInterval time.Duration // Minimum time between rate samples
+	rateTokens       int           // Output tokens at the last rate sample
+	tokenRate        float64       // Smoothed output tokens per second
 }
 
+// tokenRateSmoothing weights each new rate sample against the running rate
+const tokenRateSmoothing = 0.3
+
 // NewConsoleHandler creates a basic console handler
 func NewConsoleHandler() *ConsoleHandler {
 	return &ConsoleHandler{
 		tokenThreshold:   100000, // 100K for Millhouse
-		display:          display.New(),
+		display:          newStreamDisplay(),
+		output:           tailBuffer{max: DefaultMaxRetainedOutput},
 		throttleInterval: 500 * time.Millisecond,
+		clock:            display.SystemClock,
+		redactor:         defaultRedactor,
+		traceEvents:      defaultTraceEvents,
 	}
 }
 
@@ -124,8 +165,12 @@ func NewConsoleHandler() *ConsoleHandler {
 func NewConsoleHandlerWithThreshold(threshold int) *ConsoleHandler {
 	return &ConsoleHandler{
 		tokenThreshold:   threshold,
-		display:          display.New(),
+		display:          newStreamDisplay(),
+		output:           tailBuffer{max: DefaultMaxRetainedOutput},
 		throttleInterval: 500 * time.Millisecond,
+		clock:            display.SystemClock,
+		redactor:         defaultRedactor,
+		traceEvents:      defaultTraceEvents,
 	}
 }
 
@@ -134,72 +179,173 @@ func NewConsoleHandlerWithTerminate(threshold int, onTerminate func()) *ConsoleH
 	return &ConsoleHandler{
 		tokenThreshold:   threshold,
 		onTerminate:      onTerminate,
-		display:          display.New(),
+		display:          newStreamDisplay(),
+		output:           tailBuffer{max: DefaultMaxRetainedOutput},
 		throttleInterval: 500 * time.Millisecond,
+		clock:            display.SystemClock,
+		redactor:         defaultRedactor,
+		traceEvents:      defaultTraceEvents,
 	}
 }
 
+// newStreamDisplay returns a display that batches the many small writes of
+// streamed output
+func newStreamDisplay() *display.Display {
+	d := display.New()
+	d.EnableBuffering(display.DefaultFlushInterval)
+	return d
+}
+
+// NewConsoleHandlerWithLimits creates a handler that bails out when either the
+// total token threshold or the output token limit is reached
+func NewConsoleHandlerWithLimits(threshold, outputLimit int, onTerminate func()) *ConsoleHandler {
+	h := NewConsoleHandlerWithTerminate(threshold, onTerminate)
+	h.outputLimit = outputLimit
+	return h
+}
+
 // NewConsoleHandlerWithDisplay creates a handler with a custom display instance
 func NewConsoleHandlerWithDisplay(d *display.Display, threshold int, onTerminate func()) *ConsoleHandler {
 	return &ConsoleHandler{
 		tokenThreshold:   threshold,
 		onTerminate:      onTerminate,
 		display:          d,
+		output:           tailBuffer{max: DefaultMaxRetainedOutput},
 		throttleInterval: 500 * time.Millisecond,
+		clock:            display.SystemClock,
+		redactor:         defaultRedactor,
+		traceEvents:      defaultTraceEvents,
 	}
 }
 
 func (h *ConsoleHandler) OnToolUse(name string) {
+	h.touch()
+
 	// Increment tool count for display
 	h.toolCount++
+
+	if h.toolCounts == nil {
+		h.toolCounts = make(map[string]int)
+	}
+	h.toolCounts[name]++
 }
 
 func (h *ConsoleHandler) OnText(text string) {
-	// Buffer text and print with styled output
-	h.textBuffer.WriteString(text)
+	h.touch()
+	text = h.redactor.Redact(text)
 	h.output.WriteString(text)
 
 	// Check for WORKING ON pattern and highlight
 	if matches := workingOnPattern.FindStringSubmatch(text); matches != nil {
+		h.display.SetActivePRD(matches[1])
 		h.display.ActivePRD(matches[1])
 		h.toolCount = 0 // Reset after display
 		return          // Don't double-print
 	}
 
+	// A whitespace-only chunk would print a gutter and timestamp with nothing
+	// after it; it stays in the captured output, and the tool count carries
+	// over to the next visible line
+	if display.CleanText(text) == "" {
+		return
+	}
+
 	// Display text with tool count and current token stats
-	h.display.ClaudeWithTokens(text, h.toolCount, h.tokenStats.TotalTokens, h.tokenThreshold)
+	h.sampleTokenRate()
+	h.display.SetTokenRate(h.tokenRate)
+	switch {
+	case h.display.Compact():
+		h.display.ClaudeCompact(text, h.toolCount, h.tokenStats.TotalTokens, h.tokenThreshold)
+	case h.display.Full():
+		h.display.ClaudeFull(text, h.toolCount, h.tokenStats.TotalTokens, h.tokenThreshold)
+	default:
+		h.display.ClaudeWithTokens(text, h.toolCount, h.tokenStats.TotalTokens, h.tokenThreshold)
+	}
 	h.toolCount = 0 // Reset after display
 }
 
 func (h *ConsoleHandler) OnDone(result string) {
 	// Capture result text
-	h.output.WriteString(result)
+	h.output.WriteString(h.redactor.Redact(result))
 }
 
 func (h *ConsoleHandler) OnSignal(signal Signal) {
-	h.signals = append(h.signals, signal)
+	h.touch()
+	h.signals = append(h.signals, h.redactor.RedactSignal(signal))
 
 	// Terminal signals should stop execution
-	if isTerminalSignal(signal) {
+	if IsTerminal(signal.Type) {
 		h.shouldStop = true
 	}
 }
 
+// OnError records an API error from the stream and shows it
+func (h *ConsoleHandler) OnError(message string) {
+	message = h.redactor.Redact(message)
+	h.errors = append(h.errors, message)
+	if IsRateLimitError(message) {
+		h.rateLimited = true
+	}
+	h.display.Warning(fmt.Sprintf("API error: %s", message))
+}
+
+// GetErrors returns the API errors reported in the stream
+func (h *ConsoleHandler) GetErrors() []string {
+	return h.errors
+}
+
+// RateLimited reports whether the API rate-limited this run
+func (h *ConsoleHandler) RateLimited() bool {
+	return h.rateLimited
+}
+
 // recalculateTotalAndCheckThreshold recalculates total tokens and checks threshold
 func (h *ConsoleHandler) recalculateTotalAndCheckThreshold() {
 	// Match Ralph: TotalTokens = InputTokens + OutputTokens only
 	// Cache tokens are tracked separately but not included in total
 	h.tokenStats.TotalTokens = h.tokenStats.InputTokens + h.tokenStats.OutputTokens
+	h.sampleTokenRate()
 
 	if h.tokenStats.TotalTokens >= h.tokenThreshold {
-		h.shouldStop = true
-		h.signals = append(h.signals, Signal{
-			Type:    SignalBailout,
-			Details: "token limit exceeded",
-		})
-		if h.onTerminate != nil {
-			h.onTerminate()
-		}
+		h.tokenBailout("token limit exceeded")
+	} else if h.outputLimit > 0 && h.tokenStats.OutputTokens >= h.outputLimit {
+		h.tokenBailout(fmt.Sprintf("output token limit exceeded (%d >= %d)", h.tokenStats.OutputTokens, h.outputLimit))
+	}
+}
+
+// sampleTokenRate updates the smoothed output token rate at most once per
+// throttle interval, so the rate doesn't flicker. A sample without new tokens
+// pulls the rate down, which is how a stalled agent shows.
+func (h *ConsoleHandler) sampleTokenRate() {
+	now := h.clock.Now()
+	if h.lastTokenDisplay.IsZero() {
+		h.lastTokenDisplay, h.rateTokens = now, h.tokenStats.OutputTokens
+		return
+	}
+	elapsed := now.Sub(h.lastTokenDisplay)
+	if elapsed < h.throttleInterval || elapsed <= 0 {
+		return
+	}
+
+	sample := float64(max(h.tokenStats.OutputTokens-h.rateTokens, 0)) / elapsed.Seconds()
+	h.tokenRate += tokenRateSmoothing * (sample - h.tokenRate)
+	h.lastTokenDisplay, h.rateTokens = now, h.tokenStats.OutputTokens
+}
+
+// GetTokenRate returns the smoothed output token rate in tokens per second
+func (h *ConsoleHandler) GetTokenRate() float64 {
+	return h.tokenRate
+}
+
+// tokenBailout stops the agent with a BAILOUT naming the threshold that tripped
+func (h *ConsoleHandler) tokenBailout(details string) {
+	h.shouldStop = true
+	h.signals = append(h.signals, Signal{
+		Type:    SignalBailout,
+		Details: details,
+	})
+	if h.onTerminate != nil {
+		h.onTerminate()
 	}
 }
 
@@ -232,12 +378,25 @@ func (h *ConsoleHandler) GetTokenStats() TokenStats {
 	return h.tokenStats
 }
 
+// GetOutput returns the agent's text output, or its tail if it exceeded
+// the retention limit
 func (h *ConsoleHandler) GetOutput() string {
 	return h.output.String()
 }
 
+// SetMaxRetainedOutput caps how many bytes of output GetOutput keeps;
+// n <= 0 keeps the full output. Call it before streaming starts.
+func (h *ConsoleHandler) SetMaxRetainedOutput(n int) {
+	h.output.max = n
+}
+
+// OutputTruncated reports whether GetOutput has lost older output to the cap
+func (h *ConsoleHandler) OutputTruncated() bool {
+	return h.output.Truncated()
+}
+
 func (h *ConsoleHandler) ShouldTerminate() bool {
-	return h.shouldStop
+	return h.shouldStop || h.writeDeclined.Load()
 }
 
 // GetToolCount returns the current tool use count
@@ -245,6 +404,20 @@ func (h *ConsoleHandler) GetToolCount() int {
 	return h.toolCount
 }
 
+// GetToolCounts returns tool uses by name since the handler was created
+func (h *ConsoleHandler) GetToolCounts() map[string]int {
+	counts := make(map[string]int, len(h.toolCounts))
+	for name, n := range h.toolCounts {
+		counts[name] = n
+	}
+	return counts
+}
+
+// DisplayToolSummary prints the per-tool breakdown for the phase
+func (h *ConsoleHandler) DisplayToolSummary() {
+	h.display.ToolSummary(h.toolCounts)
+}
+
 // DisplayFinalTokenUsage forces a final token display regardless of throttling
 func (h *ConsoleHandler) DisplayFinalTokenUsage() {
 	if h.tokenStats.TotalTokens > 0 {
@@ -254,38 +427,62 @@ func (h *ConsoleHandler) DisplayFinalTokenUsage() {
 	}
 }
 
+// Flush writes any buffered output; call it when the stream ends
+func (h *ConsoleHandler) Flush() {
+	h.display.Flush()
+}
+
 // SetDisplay sets the display instance for styled output
 func (h *ConsoleHandler) SetDisplay(d *display.Display) {
 	h.display = d
 }
 
-// isTerminalSignal returns true if the signal indicates the agent should stop
-func isTerminalSignal(s Signal) bool {
-	return s.Type == SignalPRDComplete ||
-		s.Type == SignalBailout ||
-		s.Type == SignalBlocked ||
-		s.Type == SignalAnalysisComplete ||
-		s.Type == SignalPlanComplete ||
-		s.Type == SignalPlanSkipped
-}
-
-// Signal patterns (Millhouse-specific)
-var (
-	prdCompletePattern      = regexp.MustCompile(`###PRD_COMPLETE###`)
-	bailoutPattern          = regexp.MustCompile(`###BAILOUT:(.+?)###`)
-	blockedPattern          = regexp.MustCompile(`###BLOCKED:(.+?)###`)
-	analysisCompletePattern = regexp.MustCompile(`###ANALYSIS_COMPLETE###`)
-	verifiedPattern         = regexp.MustCompile(`###VERIFIED:(.+?)###`)
-	rejectedPattern         = regexp.MustCompile(`###REJECTED:(.+?):(.+?)###`)
-	loopRiskPattern         = regexp.MustCompile(`###LOOP_RISK:(.+?)###`)
-	workingOnPattern        = regexp.MustCompile(`(?:\*\*)?WORKING ON:\s*([a-z0-9-]+)(?:\*\*)?`)
-	// Planner patterns
-	planCompletePattern = regexp.MustCompile(`###PLAN_COMPLETE:(.+?)###`)
-	planSkippedPattern  = regexp.MustCompile(`###PLAN_SKIPPED:(.+?)###`)
-	planUpdatedPattern  = regexp.MustCompile(`###PLAN_UPDATED:(.+?)###`)
-	// Reviewer patterns
-	promptUpdatedPattern = regexp.MustCompile(`###PROMPT_UPDATED:(.+?)###`)
-)
+// SetClock replaces the time source for the handler and its display; nil
+// restores the system clock
+func (h *ConsoleHandler) SetClock(c display.Clock) {
+	if c == nil {
+		c = display.SystemClock
+	}
+	h.clock = c
+	h.display.SetClock(c)
+}
+
+// OnUnknownEvent reports each unrecognized event type once when tracing is on
+func (h *ConsoleHandler) OnUnknownEvent(eventType string) {
+	if !h.traceEvents || h.seenEvents[eventType] {
+		return
+	}
+	if h.seenEvents == nil {
+		h.seenEvents = make(map[string]bool)
+	}
+	h.seenEvents[eventType] = true
+	h.display.Info(fmt.Sprintf("Unrecognized stream event: %q", eventType))
+}
+
+// UnknownEventHandler is implemented by handlers that want to hear about
+// stream event types ParseStream does not recognize
+type UnknownEventHandler interface {
+	OnUnknownEvent(eventType string)
+}
+
+// defaultTraceEvents is picked up by every new ConsoleHandler
+var defaultTraceEvents bool
+
+// SetTraceEventsDefault makes ConsoleHandlers created afterwards report
+// unrecognized stream event types, to spot new ones worth handling
+func SetTraceEventsDefault(trace bool) {
+	defaultTraceEvents = trace
+}
+
+// reportError passes an API error to handlers that implement ErrorHandler
+func reportError(handler OutputHandler, message string) {
+	if eh, ok := handler.(ErrorHandler); ok && message != "" {
+		eh.OnError(message)
+	}
+}
+
+// Pattern for the builder's progress marker (not a signal)
+var workingOnPattern = regexp.MustCompile(`(?:\*\*)?WORKING ON:\s*([a-z0-9-]+)(?:\*\*)?`)
 
 // ParseStream reads the Claude stream-json output and calls the handler
 // onTerminate is called when a termination signal is detected
@@ -359,8 +556,25 @@ func ParseStream(reader io.Reader, handler OutputHandler, onTerminate func()) er
 		case "result":
 			// Token extraction removed - Ralph only extracts from assistant event
 			// Result event was causing double-counting
+			if event.IsError {
+				reportError(handler, event.Result)
+			}
 			checkSignals(event.Result, handler)
 			handler.OnDone(event.Result)
+
+		case "error":
+			if event.Error != nil {
+				reportError(handler, strings.TrimSpace(event.Error.Type+": "+event.Error.Message))
+			}
+
+		case "ping", "content_block_start", "content_block_stop", "message_stop", "system", "user":
+			// Keepalives, block boundaries, the session init and tool results
+			// carry nothing the handler needs, whatever fields they grow
+
+		default:
+			if uh, ok := handler.(UnknownEventHandler); ok {
+				uh.OnUnknownEvent(event.Type)
+			}
 		}
 
 		// Check if we should terminate
@@ -375,102 +589,24 @@ func ParseStream(reader io.Reader, handler OutputHandler, onTerminate func()) er
 	return scanner.Err()
 }
 
-// checkSignals looks for Millhouse signal patterns in text
-func checkSignals(text string, handler OutputHandler) {
-	// Check for PRD_COMPLETE
-	if prdCompletePattern.MatchString(text) {
-		handler.OnSignal(Signal{Type: SignalPRDComplete})
-	}
-
-	// Check for BAILOUT
-	if matches := bailoutPattern.FindStringSubmatch(text); matches != nil {
-		handler.OnSignal(Signal{
-			Type:    SignalBailout,
-			Details: strings.TrimSpace(matches[1]),
-		})
-	}
-
-	// Check for BLOCKED
-	if matches := blockedPattern.FindStringSubmatch(text); matches != nil {
-		handler.OnSignal(Signal{
-			Type:    SignalBlocked,
-			Details: strings.TrimSpace(matches[1]),
-		})
-	}
-
-	// Check for ANALYSIS_COMPLETE
-	if analysisCompletePattern.MatchString(text) {
-		handler.OnSignal(Signal{Type: SignalAnalysisComplete})
-	}
-
-	// Check for VERIFIED
-	if matches := verifiedPattern.FindAllStringSubmatch(text, -1); matches != nil {
-		for _, match := range matches {
-			handler.OnSignal(Signal{
-				Type:  SignalVerified,
-				PRDID: strings.TrimSpace(match[1]),
-			})
-		}
-	}
-
-	// Check for REJECTED
-	if matches := rejectedPattern.FindAllStringSubmatch(text, -1); matches != nil {
-		for _, match := range matches {
-			handler.OnSignal(Signal{
-				Type:    SignalRejected,
-				PRDID:   strings.TrimSpace(match[1]),
-				Details: strings.TrimSpace(match[2]),
-			})
-		}
-	}
-
-	// Check for LOOP_RISK
-	if matches := loopRiskPattern.FindAllStringSubmatch(text, -1); matches != nil {
-		for _, match := range matches {
-			handler.OnSignal(Signal{
-				Type:  SignalLoopRisk,
-				PRDID: strings.TrimSpace(match[1]),
-			})
-		}
-	}
+// parsePayloadSignal builds a signal from a JSON payload
+// The "prdId" and "details" keys fill the matching Signal fields. A payload
+// that is not valid JSON still yields the signal, with the raw text as Details.
+func parsePayloadSignal(signalType, raw string) Signal {
+	signal := Signal{Type: signalType}
 
-	// Check for PLAN_COMPLETE
-	if matches := planCompletePattern.FindAllStringSubmatch(text, -1); matches != nil {
-		for _, match := range matches {
-			handler.OnSignal(Signal{
-				Type:  SignalPlanComplete,
-				PRDID: strings.TrimSpace(match[1]),
-			})
-		}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		signal.Details = strings.TrimSpace(raw)
+		return signal
 	}
 
-	// Check for PLAN_SKIPPED
-	if matches := planSkippedPattern.FindAllStringSubmatch(text, -1); matches != nil {
-		for _, match := range matches {
-			handler.OnSignal(Signal{
-				Type:    SignalPlanSkipped,
-				Details: strings.TrimSpace(match[1]),
-			})
-		}
+	signal.Payload = payload
+	if id, ok := payload["prdId"].(string); ok {
+		signal.PRDID = id
 	}
-
-	// Check for PLAN_UPDATED
-	if matches := planUpdatedPattern.FindAllStringSubmatch(text, -1); matches != nil {
-		for _, match := range matches {
-			handler.OnSignal(Signal{
-				Type:  SignalPlanUpdated,
-				PRDID: strings.TrimSpace(match[1]),
-			})
-		}
-	}
-
-	// Check for PROMPT_UPDATED
-	if matches := promptUpdatedPattern.FindAllStringSubmatch(text, -1); matches != nil {
-		for _, match := range matches {
-			handler.OnSignal(Signal{
-				Type:    SignalPromptUpdated,
-				Details: strings.TrimSpace(match[1]), // phase name
-			})
-		}
+	if details, ok := payload["details"].(string); ok {
+		signal.Details = details
 	}
+	return signal
 }
@@ -1,6 +1,12 @@
 package llm
 
-import "testing"
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestOnTokenUsage_InputTokensAccumulated(t *testing.T) {
 	handler := NewConsoleHandler()
@@ -131,3 +137,501 @@ func TestOnTokenUsageCumulative_TakesMaxOutputTokens(t *testing.T) {
 		t.Errorf("TotalTokens should be 30300 (Input + Output), got %d", stats.TotalTokens)
 	}
 }
+
+func TestCheckSignals_JSONPayload(t *testing.T) {
+	handler := NewConsoleHandler()
+
+	checkSignals("Done.\n###SIGNAL:PLAN_COMPLETE:{\n  \"prdId\": \"auth-1\",\n  \"files\": [\"a.go\", \"b.go\"]\n}###", handler)
+
+	signals := handler.GetSignals()
+	if len(signals) != 1 {
+		t.Fatalf("expected 1 signal, got %d: %+v", len(signals), signals)
+	}
+
+	s := signals[0]
+	if s.Type != SignalPlanComplete {
+		t.Errorf("Type should be PLAN_COMPLETE, got %s", s.Type)
+	}
+	if s.PRDID != "auth-1" {
+		t.Errorf("PRDID should come from payload, got %q", s.PRDID)
+	}
+	files, ok := s.Payload["files"].([]any)
+	if !ok || len(files) != 2 || files[0] != "a.go" {
+		t.Errorf("Payload files not parsed: %+v", s.Payload)
+	}
+	if !handler.ShouldTerminate() {
+		t.Error("PLAN_COMPLETE payload signal should be terminal")
+	}
+}
+
+func TestCheckSignals_MalformedJSONPayload(t *testing.T) {
+	handler := NewConsoleHandler()
+
+	checkSignals(`###SIGNAL:BLOCKED:{"details": "missing creds",}###`, handler)
+
+	signals := handler.GetSignals()
+	if len(signals) != 1 {
+		t.Fatalf("expected 1 signal, got %d", len(signals))
+	}
+	if signals[0].Type != SignalBlocked {
+		t.Errorf("Type should be BLOCKED, got %s", signals[0].Type)
+	}
+	if signals[0].Payload != nil {
+		t.Errorf("Payload should be nil for malformed JSON, got %+v", signals[0].Payload)
+	}
+	if signals[0].Details != `{"details": "missing creds",}` {
+		t.Errorf("Details should hold the raw payload, got %q", signals[0].Details)
+	}
+}
+
+func TestCheckSignals_LegacyFormsUnaffected(t *testing.T) {
+	handler := NewConsoleHandler()
+
+	checkSignals("###VERIFIED:auth-1###", handler)
+
+	signals := handler.GetSignals()
+	if len(signals) != 1 || signals[0].PRDID != "auth-1" || signals[0].Payload != nil {
+		t.Errorf("legacy VERIFIED signal parsed incorrectly: %+v", signals)
+	}
+}
+
+func TestCheckSignals_NeedsRefinement(t *testing.T) {
+	handler := NewConsoleHandler()
+
+	checkSignals("###NEEDS_REFINEMENT:search-a1b2:contradictory_requirements###", handler)
+
+	signals := handler.GetSignals()
+	if len(signals) != 1 {
+		t.Fatalf("expected 1 signal, got %d", len(signals))
+	}
+	if signals[0].Type != SignalNeedsRefine || signals[0].PRDID != "search-a1b2" || signals[0].Details != "contradictory_requirements" {
+		t.Errorf("NEEDS_REFINEMENT parsed incorrectly: %+v", signals[0])
+	}
+	if handler.ShouldTerminate() {
+		t.Error("NEEDS_REFINEMENT should not stop the planner")
+	}
+}
+
+func TestSignalTable_FieldsMatchCaptureGroups(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, def := range signalTable {
+		if seen[def.Type] {
+			t.Errorf("duplicate signal table entry for %s", def.Type)
+		}
+		seen[def.Type] = true
+		if got := def.Pattern.NumSubexp(); got != len(def.Fields) {
+			t.Errorf("%s: pattern has %d capture groups but %d fields", def.Type, got, len(def.Fields))
+		}
+	}
+}
+
+func TestCheckSignals_EveryTableEntry(t *testing.T) {
+	tests := []struct {
+		text     string
+		want     Signal
+		terminal bool
+	}{
+		{"###PRD_COMPLETE###", Signal{Type: SignalPRDComplete}, true},
+		{"###BAILOUT: out of tokens ###", Signal{Type: SignalBailout, Details: "out of tokens"}, true},
+		{"###BLOCKED:missing creds###", Signal{Type: SignalBlocked, Details: "missing creds"}, true},
+		{"###ANALYSIS_COMPLETE###", Signal{Type: SignalAnalysisComplete}, true},
+		{"###VERIFIED:auth-1###", Signal{Type: SignalVerified, PRDID: "auth-1"}, false},
+		{"###REJECTED:auth-1:tests fail###", Signal{Type: SignalRejected, PRDID: "auth-1", Details: "tests fail"}, false},
+		{"###LOOP_RISK:auth-1###", Signal{Type: SignalLoopRisk, PRDID: "auth-1"}, false},
+		{"###PLAN_COMPLETE:auth-1###", Signal{Type: SignalPlanComplete, PRDID: "auth-1"}, true},
+		{"###PLAN_SKIPPED:nothing open###", Signal{Type: SignalPlanSkipped, Details: "nothing open"}, true},
+		{"###PLAN_UPDATED:auth-1###", Signal{Type: SignalPlanUpdated, PRDID: "auth-1"}, false},
+		{"###NEEDS_REFINEMENT:auth-1:vague###", Signal{Type: SignalNeedsRefine, PRDID: "auth-1", Details: "vague"}, false},
+		{"###SPLIT:auth-1:{\"children\": []}###", Signal{Type: SignalSplit, PRDID: "auth-1"}, true},
+		{"###CRITERIA_PROPOSED:auth-1:{\"acceptanceCriteria\": [\"x\"]}###", Signal{Type: SignalCriteria, PRDID: "auth-1"}, false},
+		{"###PLAN_META:auth-1:{\"summary\": \"x\"}###", Signal{Type: SignalPlanMeta, PRDID: "auth-1"}, false},
+		{"###SUBTASK_DONE:auth-1:3###", Signal{Type: SignalSubtaskDone, PRDID: "auth-1", Details: "3"}, false},
+		{"###PROMPT_UPDATED:builder###", Signal{Type: SignalPromptUpdated, Details: "builder"}, false},
+	}
+
+	if len(tests) != len(signalTable) {
+		t.Fatalf("test covers %d signals, table has %d", len(tests), len(signalTable))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.Type, func(t *testing.T) {
+			handler := NewConsoleHandler()
+			checkSignals(tt.text, handler)
+
+			signals := handler.GetSignals()
+			if len(signals) != 1 {
+				t.Fatalf("expected 1 signal, got %d: %+v", len(signals), signals)
+			}
+			got := signals[0]
+			if got.Type != tt.want.Type || got.PRDID != tt.want.PRDID || got.Details != tt.want.Details {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if handler.ShouldTerminate() != tt.terminal {
+				t.Errorf("ShouldTerminate() = %v, want %v", handler.ShouldTerminate(), tt.terminal)
+			}
+		})
+	}
+}
+
+func TestCheckSignals_SplitPayload(t *testing.T) {
+	handler := NewConsoleHandler()
+
+	checkSignals("###SPLIT:search-a1b2:{\n  \"children\": [{\"description\": \"Index\", \"acceptanceCriteria\": [\"fast\"]}]\n}###", handler)
+
+	signals := handler.GetSignals()
+	if len(signals) != 1 {
+		t.Fatalf("expected 1 signal, got %d: %+v", len(signals), signals)
+	}
+	children, ok := signals[0].Payload["children"].([]any)
+	if signals[0].PRDID != "search-a1b2" || !ok || len(children) != 1 {
+		t.Errorf("SPLIT parsed incorrectly: %+v", signals[0])
+	}
+}
+
+func TestCheckSignals_RepeatedSignals(t *testing.T) {
+	handler := NewConsoleHandler()
+
+	checkSignals("###VERIFIED:a###\n###VERIFIED:b###\n###BLOCKED:x###\n###BLOCKED:y###", handler)
+
+	var verified, blocked int
+	for _, s := range handler.GetSignals() {
+		switch s.Type {
+		case SignalVerified:
+			verified++
+		case SignalBlocked:
+			blocked++
+		}
+	}
+	if verified != 2 {
+		t.Errorf("each VERIFIED should be emitted, got %d", verified)
+	}
+	if blocked != 1 {
+		t.Errorf("BLOCKED should be emitted once, got %d", blocked)
+	}
+}
+
+func TestIsProductive(t *testing.T) {
+	if !IsProductive(SignalPromptUpdated) {
+		t.Error("PROMPT_UPDATED should be productive")
+	}
+	if IsProductive(SignalLoopRisk) || IsProductive("UNKNOWN") {
+		t.Error("LOOP_RISK and unknown signals should not be productive")
+	}
+	if !IsIdleSignal(SignalLoopRisk) || !IsIdleSignal(SignalAnalysisComplete) {
+		t.Error("LOOP_RISK and ANALYSIS_COMPLETE should be idle")
+	}
+	if IsIdleSignal(SignalVerified) || IsIdleSignal("DEPLOYED") {
+		t.Error("VERIFIED and unknown signals should not be idle")
+	}
+}
+
+func TestOutputLimitTriggersBailout(t *testing.T) {
+	terminated := false
+	handler := NewConsoleHandlerWithLimits(100000, 5000, func() {
+		terminated = true
+	})
+
+	handler.OnTokenUsage(TokenStats{InputTokens: 20000, OutputTokens: 1000})
+	if handler.ShouldTerminate() {
+		t.Fatal("should not stop below both limits")
+	}
+
+	handler.OnTokenUsageCumulative(TokenStats{OutputTokens: 6000})
+
+	if !terminated || !handler.ShouldTerminate() {
+		t.Fatal("expected output limit to stop the agent")
+	}
+	signals := handler.GetSignals()
+	if len(signals) != 1 || signals[0].Type != SignalBailout || signals[0].Details != "output token limit exceeded (6000 >= 5000)" {
+		t.Errorf("unexpected signals: %+v", signals)
+	}
+}
+
+func TestOutputLimitDisabledByDefault(t *testing.T) {
+	handler := NewConsoleHandlerWithThreshold(100000)
+
+	handler.OnTokenUsage(TokenStats{InputTokens: 1000, OutputTokens: 90000})
+
+	if handler.ShouldTerminate() {
+		t.Error("output tokens alone should not stop the agent without an output limit")
+	}
+}
+
+func TestToolCountsAccumulateAcrossText(t *testing.T) {
+	handler := NewConsoleHandler()
+	handler.display.SetOutput(io.Discard, io.Discard)
+
+	handler.OnToolUse("Read")
+	handler.OnToolUse("Read")
+	handler.OnText("looking around")
+	handler.OnToolUse("Edit")
+	handler.OnToolUse("Read")
+
+	counts := handler.GetToolCounts()
+	if counts["Read"] != 3 || counts["Edit"] != 1 || len(counts) != 2 {
+		t.Errorf("GetToolCounts = %v, want Read:3 Edit:1", counts)
+	}
+	if handler.GetToolCount() != 2 {
+		t.Errorf("running tool count should reset after text, got %d", handler.GetToolCount())
+	}
+
+	// Callers get a copy
+	counts["Read"] = 100
+	if handler.GetToolCounts()["Read"] != 3 {
+		t.Error("GetToolCounts should return a copy")
+	}
+}
+
+func TestParseStreamReportsRateLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		stream      string
+		rateLimited bool
+		errors      int
+	}{
+		{
+			name:        "error event",
+			stream:      `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`,
+			rateLimited: true,
+			errors:      1,
+		},
+		{
+			name:        "failed result",
+			stream:      `{"type":"result","is_error":true,"result":"API Error: 429 Too Many Requests"}`,
+			rateLimited: true,
+			errors:      1,
+		},
+		{
+			name:   "other API error",
+			stream: `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			errors: 1,
+		},
+		{
+			name:   "successful result",
+			stream: `{"type":"result","result":"done"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewConsoleHandler()
+			handler.display.SetOutput(io.Discard, io.Discard)
+			if err := ParseStream(strings.NewReader(tt.stream+"\n"), handler, nil); err != nil {
+				t.Fatal(err)
+			}
+			if handler.RateLimited() != tt.rateLimited {
+				t.Errorf("RateLimited() = %v, want %v", handler.RateLimited(), tt.rateLimited)
+			}
+			if len(handler.GetErrors()) != tt.errors {
+				t.Errorf("GetErrors() = %v, want %d errors", handler.GetErrors(), tt.errors)
+			}
+		})
+	}
+}
+
+func TestParseStreamTracesUnknownEvents(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"type":"system","subtype":"init"}`,
+		`{"type":"ping"}`,
+		`{"type":"content_block_start","index":0}`,
+		`{"type":"content_block_delta","delta":{"type":"text_delta","text":"hello"}}`,
+		`{"type":"content_block_stop","index":0,"delta":{"type":"text_delta","text":"ignored"}}`,
+		`{"type":"message_stop","usage":{"output_tokens":999}}`,
+		`{"type":"compaction_notice"}`,
+		`{"type":"compaction_notice"}`,
+		`{"type":"rate_limit_event"}`,
+	}, "\n") + "\n"
+
+	for _, trace := range []bool{false, true} {
+		var out bytes.Buffer
+		handler := NewConsoleHandler()
+		handler.traceEvents = trace
+		handler.display.SetOutput(&out, &out)
+		if err := ParseStream(strings.NewReader(stream), handler, nil); err != nil {
+			t.Fatal(err)
+		}
+		handler.display.Flush()
+
+		// No-op events must not leak fields into the run
+		if got := handler.GetOutput(); got != "hello" {
+			t.Errorf("GetOutput() = %q, want only the text delta", got)
+		}
+		if got := handler.GetTokenStats().OutputTokens; got != 0 {
+			t.Errorf("OutputTokens = %d, want message_stop usage ignored", got)
+		}
+
+		traced := strings.Count(out.String(), "Unrecognized stream event")
+		switch {
+		case !trace && traced != 0:
+			t.Errorf("untraced handler reported %d unknown events:\n%s", traced, out.String())
+		case trace && traced != 2:
+			t.Errorf("traced handler reported %d unknown events, want 2 (each type once):\n%s", traced, out.String())
+		case trace && (!strings.Contains(out.String(), `"compaction_notice"`) || strings.Contains(out.String(), `"ping"`)):
+			t.Errorf("trace reported the wrong events:\n%s", out.String())
+		}
+	}
+}
+
+func TestRetainedOutputCap(t *testing.T) {
+	handler := NewConsoleHandler()
+	handler.display.SetOutput(io.Discard, io.Discard)
+	handler.SetMaxRetainedOutput(10)
+
+	for i := 0; i < 100; i++ {
+		handler.OnText("0123456789")
+	}
+	handler.OnDone("é-done")
+
+	out := handler.GetOutput()
+	if len(out) > 10 || !strings.HasSuffix(out, "-done") {
+		t.Errorf("GetOutput() = %q, want at most 10 bytes ending in -done", out)
+	}
+	if !handler.OutputTruncated() {
+		t.Error("OutputTruncated() = false after exceeding the cap")
+	}
+
+	full := NewConsoleHandler()
+	full.display.SetOutput(io.Discard, io.Discard)
+	full.SetMaxRetainedOutput(0)
+	for i := 0; i < 10000; i++ {
+		full.OnText("0123456789")
+	}
+	if len(full.GetOutput()) != 100000 || full.OutputTruncated() {
+		t.Errorf("uncapped handler kept %d bytes, want 100000", len(full.GetOutput()))
+	}
+}
+
+func TestWatchIdle(t *testing.T) {
+	t.Run("quiet agent is cancelled", func(t *testing.T) {
+		handler := NewConsoleHandler()
+		handler.display.SetOutput(io.Discard, io.Discard)
+		fired := make(chan struct{})
+		stop := handler.WatchIdle(100*time.Millisecond, func() { close(fired) })
+
+		select {
+		case <-fired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("watchdog did not fire")
+		}
+		stop()
+		stop() // Safe to call twice
+
+		signals := handler.GetSignals()
+		if !handler.IdleTimedOut() || !handler.ShouldTerminate() || len(signals) != 1 || signals[0].Type != SignalBailout {
+			t.Errorf("expected one idle BAILOUT, got %v", signals)
+		}
+	})
+
+	t.Run("active agent is left alone", func(t *testing.T) {
+		handler := NewConsoleHandler()
+		handler.display.SetOutput(io.Discard, io.Discard)
+		stop := handler.WatchIdle(200*time.Millisecond, func() { t.Error("watchdog fired on an active agent") })
+		for i := 0; i < 8; i++ {
+			time.Sleep(50 * time.Millisecond)
+			handler.OnToolUse("Bash")
+		}
+		stop()
+		if handler.IdleTimedOut() || len(handler.GetSignals()) != 0 {
+			t.Errorf("unexpected idle timeout, signals %v", handler.GetSignals())
+		}
+	})
+
+	t.Run("zero disables", func(t *testing.T) {
+		handler := NewConsoleHandler()
+		handler.WatchIdle(0, func() { t.Error("disabled watchdog fired") })()
+	})
+
+	t.Run("frozen clock never goes idle", func(t *testing.T) {
+		handler := NewConsoleHandler()
+		handler.display.SetOutput(io.Discard, io.Discard)
+		handler.SetClock(frozenClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		stop := handler.WatchIdle(100*time.Millisecond, func() { t.Error("watchdog fired while time stood still") })
+		time.Sleep(300 * time.Millisecond)
+		stop()
+		if handler.IdleTimedOut() {
+			t.Error("unexpected idle timeout with a frozen clock")
+		}
+		if got := handler.LastActivity(); !got.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("LastActivity() = %v, want the injected time", got)
+		}
+	})
+}
+
+// frozenClock always reports the same instant
+type frozenClock time.Time
+
+func (c frozenClock) Now() time.Time { return time.Time(c) }
+
+func TestOnTextSkipsWhitespaceOnlyChunks(t *testing.T) {
+	for _, mode := range []string{"default", "compact", "full"} {
+		t.Run(mode, func(t *testing.T) {
+			var out bytes.Buffer
+			handler := NewConsoleHandler()
+			handler.display.SetOutput(&out, &out)
+			handler.display.SetCompact(mode == "compact")
+			handler.display.SetFull(mode == "full")
+
+			handler.OnToolUse("Read")
+			for _, chunk := range []string{"\n", "  ", "\n\n\t"} {
+				handler.OnText(chunk)
+			}
+			handler.Flush()
+			if out.Len() != 0 {
+				t.Errorf("whitespace-only chunks printed %q, want no rows", out.String())
+			}
+			if handler.GetOutput() != "\n  \n\n\t" {
+				t.Errorf("GetOutput() = %q, want the chunks kept", handler.GetOutput())
+			}
+			if handler.GetToolCount() != 1 {
+				t.Errorf("tool count = %d, want it carried to the next visible text", handler.GetToolCount())
+			}
+
+			handler.OnText("done")
+			handler.Flush()
+			if lines := strings.Count(out.String(), "\n"); lines != 1 || !strings.Contains(out.String(), "done") {
+				t.Errorf("visible text printed %d rows: %q", lines, out.String())
+			}
+		})
+	}
+}
+
+// stepClock reports a time tests move forward by hand
+type stepClock struct{ now time.Time }
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func TestTokenRate(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	handler := NewConsoleHandler()
+	var out bytes.Buffer
+	handler.display.SetOutput(&out, io.Discard)
+	handler.SetClock(clock)
+
+	handler.OnTokenUsageCumulative(TokenStats{OutputTokens: 10}) // Baseline sample
+
+	// Within the throttle interval the rate holds still
+	clock.now = clock.now.Add(100 * time.Millisecond)
+	handler.OnTokenUsageCumulative(TokenStats{OutputTokens: 50})
+	if rate := handler.GetTokenRate(); rate != 0 {
+		t.Errorf("rate within the throttle interval = %v, want 0", rate)
+	}
+
+	// 100 tokens over one second, smoothed from 0
+	clock.now = clock.now.Add(900 * time.Millisecond)
+	handler.OnTokenUsageCumulative(TokenStats{OutputTokens: 110})
+	if rate, want := handler.GetTokenRate(), 100*tokenRateSmoothing; rate != want {
+		t.Errorf("rate = %v, want %v", rate, want)
+	}
+
+	// A stall pulls the rate down
+	before := handler.GetTokenRate()
+	clock.now = clock.now.Add(2 * time.Second)
+	handler.display.SetFull(true)
+	handler.OnText("still thinking")
+	handler.Flush()
+	if rate := handler.GetTokenRate(); rate >= before {
+		t.Errorf("rate after a stall = %v, want below %v", rate, before)
+	}
+	if !strings.Contains(out.String(), "tok/s]") {
+		t.Errorf("full mode output has no token rate:\n%s", out.String())
+	}
+}
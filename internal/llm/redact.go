@@ -0,0 +1,130 @@
+package llm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactMask replaces every secret found in agent output
+const RedactMask = "***"
+
+// DefaultRedactPatterns match common credential formats
+// A pattern with a group named "secret" masks only that group, so the label
+// in front of the value stays readable.
+var DefaultRedactPatterns = []string{
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,                                                   // AWS access key ID
+	`(?i)aws_secret_access_key\W{0,4}(?P<secret>[A-Za-z0-9/+=]{40})`,                  // AWS secret key
+	`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]{16,}=*)`,                            // Bearer tokens
+	`\bsk-[A-Za-z0-9_\-]{20,}`,                                                        // sk-... API keys
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,                                                  // GitHub tokens
+	`\bxox[abposr]-[A-Za-z0-9\-]{10,}`,                                                // Slack tokens
+	`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?(?:-----END [A-Z ]*PRIVATE KEY-----|$)`, // PEM private keys
+}
+
+// Redactor masks secrets in text before it is shown or saved
+// A nil Redactor leaves text unchanged.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles extra patterns, plus DefaultRedactPatterns when
+// defaults is set
+func NewRedactor(extra []string, defaults bool) (*Redactor, error) {
+	var sources []string
+	if defaults {
+		sources = append(sources, DefaultRedactPatterns...)
+	}
+	sources = append(sources, extra...)
+
+	r := &Redactor{}
+	for _, src := range sources {
+		re, err := regexp.Compile(src)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", src, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact returns text with every match replaced by RedactMask
+func (r *Redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	for _, re := range r.patterns {
+		text = redactPattern(re, text)
+	}
+	return text
+}
+
+// RedactSignal returns the signal with its details and every string in its
+// payload redacted
+// Signals are parsed from the agent's raw text, and their details end up in
+// prd.json notes and the event log.
+func (r *Redactor) RedactSignal(signal Signal) Signal {
+	if r == nil {
+		return signal
+	}
+	signal.Details = r.Redact(signal.Details)
+	if signal.Payload != nil {
+		signal.Payload = r.redactValue(signal.Payload).(map[string]any)
+	}
+	return signal
+}
+
+// redactValue redacts the strings in a decoded JSON value, copying maps and
+// slices rather than changing them
+func (r *Redactor) redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return r.Redact(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = r.redactValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = r.redactValue(item)
+		}
+		return out
+	}
+	return v
+}
+
+// redactPattern masks the "secret" group of each match, or the whole match
+// when the pattern has no such group
+func redactPattern(re *regexp.Regexp, text string) string {
+	group := re.SubexpIndex("secret")
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group > 0 && m[2*group] >= 0 {
+			start, end = m[2*group], m[2*group+1]
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(RedactMask)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// defaultRedactor is picked up by every new ConsoleHandler
+var defaultRedactor, _ = NewRedactor(nil, true)
+
+// SetRedactorDefault sets the redactor used by ConsoleHandlers created
+// afterwards; nil turns redaction off
+func SetRedactorDefault(r *Redactor) {
+	defaultRedactor = r
+}
@@ -0,0 +1,122 @@
+package llm
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// signalField is where a pattern's capture group is stored on a Signal
+type signalField int
+
+const (
+	fieldDetails signalField = iota
+	fieldPRDID
+	fieldPayload // JSON object decoded into Signal.Payload
+)
+
+// signalDef declares a ###TYPE:...### signal and how it affects the loop
+type signalDef struct {
+	Type       string
+	Pattern    *regexp.Regexp
+	Terminal   bool          // Agent stops once the signal is emitted
+	Productive bool          // Counts as work done for idle detection
+	Fields     []signalField // Destination of each capture group, in order
+	FirstOnly  bool          // Emit once per text even if repeated
+}
+
+// signalTable is the single source of truth for Millhouse signals
+// Adding a signal means adding its constant and one entry here.
+var signalTable = []signalDef{
+	{Type: SignalPRDComplete, Pattern: regexp.MustCompile(`###PRD_COMPLETE###`), Terminal: true, Productive: true, FirstOnly: true},
+	{Type: SignalBailout, Pattern: regexp.MustCompile(`###BAILOUT:(.+?)###`), Terminal: true, Productive: true, Fields: []signalField{fieldDetails}, FirstOnly: true},
+	{Type: SignalBlocked, Pattern: regexp.MustCompile(`###BLOCKED:(.+?)###`), Terminal: true, Fields: []signalField{fieldDetails}, FirstOnly: true},
+	{Type: SignalAnalysisComplete, Pattern: regexp.MustCompile(`###ANALYSIS_COMPLETE###`), Terminal: true, FirstOnly: true},
+	{Type: SignalVerified, Pattern: regexp.MustCompile(`###VERIFIED:(.+?)###`), Productive: true, Fields: []signalField{fieldPRDID}},
+	{Type: SignalRejected, Pattern: regexp.MustCompile(`###REJECTED:(.+?):(.+?)###`), Productive: true, Fields: []signalField{fieldPRDID, fieldDetails}},
+	{Type: SignalSubtaskDone, Pattern: regexp.MustCompile(`###SUBTASK_DONE:(.+?):(\d+)###`), Productive: true, Fields: []signalField{fieldPRDID, fieldDetails}}, // Details is the 1-based index
+	{Type: SignalLoopRisk, Pattern: regexp.MustCompile(`###LOOP_RISK:(.+?)###`), Fields: []signalField{fieldPRDID}},
+	{Type: SignalPlanComplete, Pattern: regexp.MustCompile(`###PLAN_COMPLETE:(.+?)###`), Terminal: true, Productive: true, Fields: []signalField{fieldPRDID}},
+	{Type: SignalPlanSkipped, Pattern: regexp.MustCompile(`###PLAN_SKIPPED:(.+?)###`), Terminal: true, Fields: []signalField{fieldDetails}},
+	{Type: SignalPlanUpdated, Pattern: regexp.MustCompile(`###PLAN_UPDATED:(.+?)###`), Productive: true, Fields: []signalField{fieldPRDID}},
+	{Type: SignalNeedsRefine, Pattern: regexp.MustCompile(`###NEEDS_REFINEMENT:(.+?):(.+?)###`), Productive: true, Fields: []signalField{fieldPRDID, fieldDetails}},
+	{Type: SignalSplit, Pattern: regexp.MustCompile(`(?s)###SPLIT:([^:#]+?):(\{.*?\})###`), Terminal: true, Productive: true, Fields: []signalField{fieldPRDID, fieldPayload}, FirstOnly: true},
+	{Type: SignalPlanMeta, Pattern: regexp.MustCompile(`(?s)###PLAN_META:([^:#]+?):(\{.*?\})###`), Fields: []signalField{fieldPRDID, fieldPayload}},
+	{Type: SignalCriteria, Pattern: regexp.MustCompile(`(?s)###CRITERIA_PROPOSED:([^:#]+?):(\{.*?\})###`), Productive: true, Fields: []signalField{fieldPRDID, fieldPayload}},
+	{Type: SignalPromptUpdated, Pattern: regexp.MustCompile(`###PROMPT_UPDATED:(.+?)###`), Productive: true, Fields: []signalField{fieldDetails}}, // Details is the phase name
+}
+
+// Generic JSON payload form: ###SIGNAL:TYPE:{...}### (may span lines)
+var payloadSignalPattern = regexp.MustCompile(`(?s)###SIGNAL:([A-Z_]+):(\{.*?\})###`)
+
+// lookupSignal returns the table entry for a signal type
+func lookupSignal(signalType string) (signalDef, bool) {
+	for _, def := range signalTable {
+		if def.Type == signalType {
+			return def, true
+		}
+	}
+	return signalDef{}, false
+}
+
+// IsTerminal reports whether a signal type means the agent should stop
+func IsTerminal(signalType string) bool {
+	def, ok := lookupSignal(signalType)
+	return ok && def.Terminal
+}
+
+// IsProductive reports whether a signal type indicates real work was done
+func IsProductive(signalType string) bool {
+	def, ok := lookupSignal(signalType)
+	return ok && def.Productive
+}
+
+// IsIdleSignal reports whether a signal type is known to do no work
+// Unknown types, such as custom ###SIGNAL:TYPE:{...}### payloads, are not
+// idle: Millhouse cannot tell what they did.
+func IsIdleSignal(signalType string) bool {
+	def, ok := lookupSignal(signalType)
+	return ok && !def.Productive
+}
+
+// checkSignals looks for Millhouse signal patterns in text
+func checkSignals(text string, handler OutputHandler) {
+	for _, def := range signalTable {
+		var matches [][]string
+		if def.FirstOnly {
+			if m := def.Pattern.FindStringSubmatch(text); m != nil {
+				matches = [][]string{m}
+			}
+		} else {
+			matches = def.Pattern.FindAllStringSubmatch(text, -1)
+		}
+
+		for _, match := range matches {
+			handler.OnSignal(def.build(match))
+		}
+	}
+
+	// JSON payload signals
+	for _, match := range payloadSignalPattern.FindAllStringSubmatch(text, -1) {
+		handler.OnSignal(parsePayloadSignal(match[1], match[2]))
+	}
+}
+
+// build creates a signal from a pattern match, filling captured fields
+func (def signalDef) build(match []string) Signal {
+	signal := Signal{Type: def.Type}
+	for i, field := range def.Fields {
+		value := strings.TrimSpace(match[i+1])
+		switch field {
+		case fieldDetails:
+			signal.Details = value
+		case fieldPRDID:
+			signal.PRDID = value
+		case fieldPayload:
+			if err := json.Unmarshal([]byte(value), &signal.Payload); err != nil {
+				signal.Details = value // Keep the raw text so callers can report it
+			}
+		}
+	}
+	return signal
+}
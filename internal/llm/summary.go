@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// editTools are the tools that change files
+var editTools = []string{"Edit", "MultiEdit", "Write", "NotebookEdit"}
+
+// PhaseSummary returns a one-line recap of what the agent did, built from
+// the signals and tool counts seen so far without another LLM call, e.g.
+// "Builder: 23 tool uses, 4 edits, emitted PRD_COMPLETE (42.0K tokens)"
+func (h *ConsoleHandler) PhaseSummary(phase string) string {
+	var parts []string
+
+	tools := 0
+	for _, n := range h.toolCounts {
+		tools += n
+	}
+	if tools > 0 {
+		parts = append(parts, plural(tools, "tool use"))
+	}
+	edits := 0
+	for _, name := range editTools {
+		edits += h.toolCounts[name]
+	}
+	if edits > 0 {
+		parts = append(parts, plural(edits, "edit"))
+	}
+
+	var emitted []string
+	seen := make(map[string]bool)
+	for _, s := range h.signals {
+		label := s.Type
+		if s.PRDID != "" {
+			label += " for " + s.PRDID
+		}
+		if !seen[label] {
+			seen[label] = true
+			emitted = append(emitted, label)
+		}
+	}
+	if len(emitted) > 0 {
+		parts = append(parts, "emitted "+strings.Join(emitted, ", "))
+	} else {
+		parts = append(parts, "no signals")
+	}
+
+	name := phase
+	if name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return fmt.Sprintf("%s: %s (%.1fK tokens)", name, strings.Join(parts, ", "), float64(h.tokenStats.TotalTokens)/1000)
+}
+
+// plural formats a count with a noun, adding "s" when needed
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"io"
+	"testing"
+)
+
+func TestPhaseSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(h *ConsoleHandler)
+		phase string
+		want  string
+	}{
+		{
+			name:  "idle agent",
+			setup: func(h *ConsoleHandler) {},
+			phase: "planner",
+			want:  "Planner: no signals (0.0K tokens)",
+		},
+		{
+			name: "builder with edits",
+			setup: func(h *ConsoleHandler) {
+				for _, tool := range []string{"Read", "Read", "Edit", "Write", "Edit", "Bash"} {
+					h.OnToolUse(tool)
+				}
+				h.OnSignal(Signal{Type: SignalPRDComplete})
+				h.OnTokenUsage(TokenStats{InputTokens: 40000, OutputTokens: 2000, TotalTokens: 42000})
+			},
+			phase: "builder",
+			want:  "Builder: 6 tool uses, 3 edits, emitted PRD_COMPLETE (42.0K tokens)",
+		},
+		{
+			name: "reviewer verdicts",
+			setup: func(h *ConsoleHandler) {
+				h.OnToolUse("Bash")
+				h.OnSignal(Signal{Type: SignalVerified, PRDID: "a"})
+				h.OnSignal(Signal{Type: SignalRejected, PRDID: "b", Details: "tests fail"})
+				h.OnSignal(Signal{Type: SignalVerified, PRDID: "a"})
+			},
+			phase: "reviewer",
+			want:  "Reviewer: 1 tool use, emitted VERIFIED for a, REJECTED for b (0.0K tokens)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewConsoleHandler()
+			h.display.SetOutput(io.Discard, io.Discard)
+			tt.setup(h)
+			if got := h.PhaseSummary(tt.phase); got != tt.want {
+				t.Errorf("PhaseSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
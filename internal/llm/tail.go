@@ -0,0 +1,43 @@
+package llm
+
+import "unicode/utf8"
+
+// DefaultMaxRetainedOutput is how much agent output a handler keeps by
+// default; callers only need the tail, and signals are parsed as they stream
+const DefaultMaxRetainedOutput = 64 * 1024
+
+// tailBuffer keeps the last max bytes written to it; max <= 0 keeps everything
+// It grows to at most twice max before dropping the oldest bytes, so writes
+// stay amortized O(1).
+type tailBuffer struct {
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+func (t *tailBuffer) WriteString(s string) {
+	t.buf = append(t.buf, s...)
+	if t.max > 0 && len(t.buf) > 2*t.max {
+		t.buf = append(t.buf[:0], t.buf[len(t.buf)-t.max:]...)
+		t.truncated = true
+	}
+}
+
+// String returns the retained output, starting on a rune boundary
+func (t *tailBuffer) String() string {
+	b := t.buf
+	if t.max > 0 && len(b) > t.max {
+		b = b[len(b)-t.max:]
+	}
+	if t.truncated || len(b) < len(t.buf) {
+		for len(b) > 0 && !utf8.RuneStart(b[0]) {
+			b = b[1:]
+		}
+	}
+	return string(b)
+}
+
+// Truncated reports whether older output has been dropped
+func (t *tailBuffer) Truncated() bool {
+	return t.truncated || (t.max > 0 && len(t.buf) > t.max)
+}
@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TranscriptTurn is one message of a saved Claude session
+type TranscriptTurn struct {
+	Role  string   // "user" or "assistant"
+	Text  string   // Message text, blocks joined by blank lines
+	Tools []string // Tools the assistant used in this message
+}
+
+// nonPathChar matches characters the claude CLI replaces when naming a
+// project's session directory
+var nonPathChar = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// NewSessionID returns a random UUID for the claude --session-id flag
+func NewSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40 // Version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// SessionTranscriptPath returns where the claude CLI records a session
+// started in workDir: <config dir>/projects/<encoded workDir>/<id>.jsonl
+func SessionTranscriptPath(workDir, sessionID string) (string, error) {
+	configDir := os.Getenv("CLAUDE_CONFIG_DIR")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home directory: %w", err)
+		}
+		configDir = filepath.Join(home, ".claude")
+	}
+	abs, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", workDir, err)
+	}
+	project := nonPathChar.ReplaceAllString(abs, "-")
+	return filepath.Join(configDir, "projects", project, sessionID+".jsonl"), nil
+}
+
+// transcriptEntry is the subset of a session JSONL line Millhouse reads
+type transcriptEntry struct {
+	Type    string `json:"type"`
+	IsMeta  bool   `json:"isMeta"`
+	Message struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// transcriptBlock is one content block of a message
+type transcriptBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	Name string `json:"name"`
+}
+
+// ReadTranscript parses a claude session transcript into conversation turns
+// Tool results and internal entries are skipped; lines that fail to parse
+// are ignored so a partially written transcript still yields its turns.
+func ReadTranscript(path string) ([]TranscriptTurn, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer f.Close()
+
+	var turns []TranscriptTurn
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Tool results can be large
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.IsMeta || (entry.Type != "user" && entry.Type != "assistant") {
+			continue
+		}
+		if turn, ok := parseTurn(entry); ok {
+			turns = append(turns, turn)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return turns, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return turns, nil
+}
+
+// parseTurn extracts text and tool names from an entry's content, which is
+// either a plain string or a list of blocks
+func parseTurn(entry transcriptEntry) (TranscriptTurn, bool) {
+	turn := TranscriptTurn{Role: entry.Type}
+
+	var text string
+	if err := json.Unmarshal(entry.Message.Content, &text); err == nil {
+		turn.Text = strings.TrimSpace(text)
+		return turn, turn.Text != ""
+	}
+
+	var blocks []transcriptBlock
+	if err := json.Unmarshal(entry.Message.Content, &blocks); err != nil {
+		return turn, false
+	}
+	var parts []string
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			if t := strings.TrimSpace(b.Text); t != "" {
+				parts = append(parts, t)
+			}
+		case "tool_use":
+			turn.Tools = append(turn.Tools, b.Name)
+		}
+	}
+	turn.Text = strings.Join(parts, "\n\n")
+	return turn, turn.Text != "" || len(turn.Tools) > 0
+}
@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReadTranscript(t *testing.T) {
+	lines := []string{
+		`{"type":"summary","summary":"PRD cleanup"}`,
+		`{"type":"user","isMeta":true,"message":{"role":"user","content":"<command-name>/clear</command-name>"}}`,
+		`{"type":"user","message":{"role":"user","content":"Add a PRD for password reset"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Let me check prd.json."},{"type":"tool_use","name":"Read","input":{}}]}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":"..."}]}}`,
+		`not json`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","name":"Edit","input":{}},{"type":"text","text":"Added reset-password-1a2b."}]}}`,
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	turns, err := ReadTranscript(path)
+	if err != nil {
+		t.Fatalf("ReadTranscript() error = %v", err)
+	}
+	want := []TranscriptTurn{
+		{Role: "user", Text: "Add a PRD for password reset"},
+		{Role: "assistant", Text: "Let me check prd.json.", Tools: []string{"Read"}},
+		{Role: "assistant", Text: "Added reset-password-1a2b.", Tools: []string{"Edit"}},
+	}
+	if len(turns) != len(want) {
+		t.Fatalf("got %d turns, want %d: %+v", len(turns), len(want), turns)
+	}
+	for i := range want {
+		if turns[i].Role != want[i].Role || turns[i].Text != want[i].Text || strings.Join(turns[i].Tools, ",") != strings.Join(want[i].Tools, ",") {
+			t.Errorf("turn %d = %+v, want %+v", i, turns[i], want[i])
+		}
+	}
+}
+
+func TestSessionTranscriptPath(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", "/cfg")
+
+	got, err := SessionTranscriptPath("/home/ana/my.project", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("/cfg", "projects", "-home-ana-my-project", "abc.jsonl"); got != want {
+		t.Errorf("SessionTranscriptPath() = %q, want %q", got, want)
+	}
+}
+
+func TestNewSessionID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	id := NewSessionID()
+	if !uuid.MatchString(id) {
+		t.Errorf("NewSessionID() = %q, not a v4 UUID", id)
+	}
+	if NewSessionID() == id {
+		t.Error("NewSessionID() returned the same ID twice")
+	}
+}
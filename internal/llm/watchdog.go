@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Bounds for how often WatchIdle checks for activity
+const (
+	minIdleCheck = 50 * time.Millisecond
+	maxIdleCheck = 5 * time.Second
+)
+
+// touch records agent activity for the idle watchdog
+func (h *ConsoleHandler) touch() {
+	h.lastActivity.Store(h.clock.Now().UnixNano())
+}
+
+// LastActivity returns when the agent last produced text, a tool use or a signal
+func (h *ConsoleHandler) LastActivity() time.Time {
+	return time.Unix(0, h.lastActivity.Load())
+}
+
+// IdleTimedOut reports whether the watchdog cancelled the phase
+func (h *ConsoleHandler) IdleTimedOut() bool {
+	return h.idle.Load()
+}
+
+// WatchIdle starts a watchdog that calls onIdle once the agent has been quiet
+// for timeout, a liveness guard separate from the token limits. Call the
+// returned stop function after the stream ends; it waits for the watchdog and,
+// if it fired, records a BAILOUT so the phase ends as a soft failure.
+// A timeout <= 0 disables the watchdog.
+func (h *ConsoleHandler) WatchIdle(timeout time.Duration, onIdle func()) (stop func()) {
+	if timeout <= 0 {
+		return func() {}
+	}
+	h.touch()
+
+	interval := min(max(timeout/10, minIdleCheck), maxIdleCheck)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if h.gateWaiting.Load() {
+					continue // Waiting on the user is not agent idleness
+				}
+				if h.clock.Now().Sub(h.LastActivity()) >= timeout {
+					h.idle.Store(true)
+					if onIdle != nil {
+						onIdle()
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+			if h.idle.Load() {
+				details := fmt.Sprintf("idle timeout: no agent activity for %s", timeout)
+				h.shouldStop = true
+				h.signals = append(h.signals, Signal{Type: SignalBailout, Details: details})
+				h.display.Warning("Phase cancelled, " + details)
+			}
+		})
+	}
+}
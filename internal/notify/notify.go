@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds how long a desktop notifier may take before the
+// notification falls back to the bell
+const commandTimeout = 5 * time.Second
+
+// Notifier gets a user's attention, preferring a desktop notification and
+// falling back to the terminal bell
+type Notifier struct {
+	goos     string
+	lookPath func(file string) (string, error)
+	run      func(name string, args ...string) error
+	bell     io.Writer
+}
+
+// New creates a Notifier for the current platform that rings the bell on stderr
+func New() *Notifier {
+	return &Notifier{
+		goos:     runtime.GOOS,
+		lookPath: exec.LookPath,
+		run:      runCommand,
+		bell:     os.Stderr,
+	}
+}
+
+// Send shows title and message as a desktop notification: osascript on macOS,
+// notify-send elsewhere. When neither is available or it fails, the terminal
+// bell rings instead. Send never fails the caller.
+func (n *Notifier) Send(title, message string) {
+	if name, args := n.command(title, message); name != "" {
+		if err := n.run(name, args...); err == nil {
+			return
+		}
+	}
+	fmt.Fprint(n.bell, "\a")
+}
+
+// command returns the desktop notifier invocation for this platform, or ""
+// when there is none
+func (n *Notifier) command(title, message string) (string, []string) {
+	switch n.goos {
+	case "darwin":
+		if _, err := n.lookPath("osascript"); err == nil {
+			script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+			return "osascript", []string{"-e", script}
+		}
+	case "windows":
+		// No notifier without extra tooling; the bell still works in a console
+	default:
+		if _, err := n.lookPath("notify-send"); err == nil {
+			return "notify-send", []string{title, message}
+		}
+	}
+	return "", nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ", "\r", " ").Replace(s)
+	return `"` + s + `"`
+}
+
+// runCommand runs a notifier, giving up after commandTimeout
+func runCommand(name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Run()
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		installed string // Notifier binary on PATH, if any
+		runErr    error
+		wantCmd   string
+		wantBell  bool
+	}{
+		{name: "macOS", goos: "darwin", installed: "osascript", wantCmd: "osascript"},
+		{name: "linux", goos: "linux", installed: "notify-send", wantCmd: "notify-send"},
+		{name: "no notifier", goos: "linux", wantBell: true},
+		{name: "notifier fails", goos: "linux", installed: "notify-send", runErr: errors.New("no display"), wantCmd: "notify-send", wantBell: true},
+		{name: "windows", goos: "windows", wantBell: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bell bytes.Buffer
+			var ran string
+			var ranArgs []string
+			n := &Notifier{
+				goos: tt.goos,
+				lookPath: func(file string) (string, error) {
+					if file == tt.installed {
+						return "/usr/bin/" + file, nil
+					}
+					return "", errors.New("not found")
+				},
+				run: func(name string, args ...string) error {
+					ran, ranArgs = name, args
+					return tt.runErr
+				},
+				bell: &bell,
+			}
+
+			n.Send("Milhouse", `Builder "bailed" out`)
+
+			if ran != tt.wantCmd {
+				t.Errorf("ran %q, want %q", ran, tt.wantCmd)
+			}
+			if got := bell.String() == "\a"; got != tt.wantBell {
+				t.Errorf("bell rang = %v, want %v", got, tt.wantBell)
+			}
+			if ran == "osascript" && ranArgs[1] != `display notification "Builder \"bailed\" out" with title "Milhouse"` {
+				t.Errorf("osascript script = %s", ranArgs[1])
+			}
+		})
+	}
+}
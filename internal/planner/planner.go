@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -25,6 +24,13 @@ type PlannerResult struct {
 	Skipped     bool   // True if planner skipped (no open PRDs or active exists)
 	SkipReason  string // Reason for skipping
 	Error       error
+	RateLimited bool   // The API rate-limited the planner
+	Summary     string // One-line recap of the agent's work
+
+	NeedsRefinement  []string             // PRD IDs blocked via NEEDS_REFINEMENT
+	CriteriaProposed []string             // PRD IDs given criteria via CRITERIA_PROPOSED
+	Splits           []prd.SplitProposal  // Valid SPLIT proposals, applied by the caller
+	Approach         *prd.PlannedApproach // Approach from PLAN_META for the planned PRD, if given
 }
 
 // Run executes the planner agent to select a PRD and create a plan
@@ -37,9 +43,9 @@ func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, cfg *co
 	result := &PlannerResult{}
 
 	// Check if we should run
-	if !ShouldRunPlanner(prdFile) {
+	if !ShouldRunPlanner(prdFile, cfg.Run.Scope()) {
 		result.Skipped = true
-		if len(prdFile.GetActivePRDs()) > 0 {
+		if len(prdFile.GetActivePRDsIn(cfg.Run.Scope().Work())) > 0 {
 			result.SkipReason = "active PRD exists"
 		} else {
 			result.SkipReason = "no open PRDs"
@@ -54,9 +60,21 @@ func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, cfg *co
 
 	prompt := buildPlannerPrompt(basePath, prdFile, cfg)
 
-	display.AgentHeader("planner", "selecting PRD and creating plan")
+	if id := selectedPRD(prdFile, cfg); id != "" {
+		display.AgentHeader("planner", "planning selected PRD "+id)
+	} else {
+		display.AgentHeader("planner", "selecting PRD and creating plan")
+	}
 
-	execResult, err := runClaude(ctx, basePath, prompt, cfg)
+	model := cfg.GetPhaseConfig("planner").Model
+	if id := plannerTarget(prdFile, cfg); id != "" {
+		model = cfg.ModelForPRD("planner", id)
+		if model != cfg.GetPhaseConfig("planner").Model {
+			display.Info(fmt.Sprintf("Using model %s for %s (prdModels)", model, id))
+		}
+	}
+
+	execResult, err := runClaude(ctx, basePath, prompt, model, cfg)
 	if err != nil {
 		result.Error = err
 		return result, err
@@ -65,6 +83,8 @@ func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, cfg *co
 	result.Output = execResult.Output
 	result.TotalTokens = execResult.TotalTokens
 	result.Signals = execResult.Signals
+	result.RateLimited = execResult.RateLimited
+	result.Summary = execResult.Summary
 
 	// Process signals to extract PRD ID
 	for _, signal := range execResult.Signals {
@@ -78,39 +98,295 @@ func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, cfg *co
 		}
 	}
 
+	if result.PRDID != "" {
+		approach, err := recordApproach(basePath, result.PRDID, execResult.Signals)
+		if err != nil {
+			result.Error = err
+			return result, err
+		}
+		result.Approach = approach
+	}
+
+	proposed, err := applyProposedCriteria(basePath, execResult.Signals)
+	if err != nil {
+		result.Error = err
+		return result, err
+	}
+	result.CriteriaProposed = proposed
+
+	blocked, err := markNeedsRefinement(basePath, execResult.Signals)
+	if err != nil {
+		result.Error = err
+		return result, err
+	}
+	result.NeedsRefinement = blocked
+	result.Splits = splitProposals(prdFile, execResult.Signals)
+
 	return result, nil
 }
 
+// splitProposals decodes SPLIT signals, dropping proposals that could not
+// be applied so the caller only confirms ones that will succeed
+func splitProposals(prdFile *prd.PRDFileData, signals []llm.Signal) []prd.SplitProposal {
+	var proposals []prd.SplitProposal
+	for _, s := range signals {
+		if s.Type != llm.SignalSplit || s.PRDID == "" {
+			continue
+		}
+		raw, err := json.Marshal(s.Payload)
+		var proposal prd.SplitProposal
+		if err == nil {
+			err = json.Unmarshal(raw, &proposal)
+		}
+		if err != nil || s.Payload == nil {
+			display.Warning(fmt.Sprintf("Ignoring malformed SPLIT for %s", s.PRDID))
+			continue
+		}
+		proposal.PRDID = s.PRDID
+		if err := prdFile.CheckSplit(proposal); err != nil {
+			display.Warning(fmt.Sprintf("Ignoring SPLIT: %v", err))
+			continue
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals
+}
+
+// ApplySplit replaces an oversized PRD with the proposed children, deleting
+// any plan written for the parent, and returns the new PRD IDs
+func ApplySplit(basePath string, proposal prd.SplitProposal, iteration int) ([]string, error) {
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PRDs: %w", err)
+	}
+	ids, err := prdFile.Split(proposal, iteration)
+	if err != nil {
+		return nil, err
+	}
+	if err := prd.DeletePlan(basePath, proposal.PRDID); err != nil {
+		return nil, err
+	}
+	if err := prd.Save(basePath, prdFile); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// DeclineSplit notes a rejected proposal on the PRD so the next planner run
+// plans it whole instead of proposing the same split again
+func DeclineSplit(basePath string, proposal prd.SplitProposal) error {
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+	p := prdFile.FindByID(proposal.PRDID)
+	if p == nil {
+		return nil
+	}
+	p.AppendNote(fmt.Sprintf("[split declined] a split into %d PRDs was rejected; plan this PRD as a whole", len(proposal.Children)))
+	return prd.Save(basePath, prdFile)
+}
+
+// plannedApproach decodes the last PLAN_META signal for prdID, or returns
+// nil when the planner gave none or it was unusable
+func plannedApproach(prdID string, signals []llm.Signal) *prd.PlannedApproach {
+	var approach *prd.PlannedApproach
+	for _, s := range signals {
+		if s.Type != llm.SignalPlanMeta || s.PRDID != prdID {
+			continue
+		}
+		if s.Payload == nil {
+			display.Warning(fmt.Sprintf("Ignoring malformed PLAN_META for %s", prdID))
+			continue
+		}
+		raw, err := json.Marshal(s.Payload)
+		var a prd.PlannedApproach
+		if err == nil {
+			err = json.Unmarshal(raw, &a)
+		}
+		if err == nil {
+			err = a.Check()
+		}
+		if err != nil {
+			display.Warning(fmt.Sprintf("Ignoring PLAN_META for %s: %v", prdID, err))
+			continue
+		}
+		approach = &a
+	}
+	return approach
+}
+
+// recordApproach stores the planner's approach for the PRD it just planned,
+// replacing the one from any earlier plan; a plan without PLAN_META clears it
+func recordApproach(basePath, prdID string, signals []llm.Signal) (*prd.PlannedApproach, error) {
+	approach := plannedApproach(prdID, signals)
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PRDs: %w", err)
+	}
+	p := prdFile.FindByID(prdID)
+	if p == nil || (approach == nil && p.Approach == nil) {
+		return approach, nil
+	}
+	if approach != nil {
+		approach.PlannedAt = time.Now()
+	}
+	p.Approach = approach
+	if err := prd.Save(basePath, prdFile); err != nil {
+		return nil, err
+	}
+	return approach, nil
+}
+
+// applyProposedCriteria writes CRITERIA_PROPOSED criteria back to PRDs that
+// have none, noting that the planner proposed them
+// Criteria a human already wrote are never replaced.
+func applyProposedCriteria(basePath string, signals []llm.Signal) ([]string, error) {
+	proposals := make(map[string][]string)
+	var order []string
+	for _, s := range signals {
+		if s.Type != llm.SignalCriteria || s.PRDID == "" {
+			continue
+		}
+		criteria := proposedCriteria(s.Payload)
+		if len(criteria) == 0 {
+			display.Warning(fmt.Sprintf("Ignoring CRITERIA_PROPOSED for %s: no criteria", s.PRDID))
+			continue
+		}
+		if _, seen := proposals[s.PRDID]; !seen {
+			order = append(order, s.PRDID)
+		}
+		proposals[s.PRDID] = criteria
+	}
+	if len(order) == 0 {
+		return nil, nil
+	}
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	var applied []string
+	for _, id := range order {
+		p := prdFile.FindByID(id)
+		if p == nil || p.Passes.IsTrue() || len(p.AcceptanceCriteria) > 0 {
+			continue
+		}
+		p.AcceptanceCriteria = proposals[id]
+		p.AppendNote(fmt.Sprintf("[criteria proposed] the planner wrote %d acceptance criteria; review them", len(proposals[id])))
+		applied = append(applied, id)
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+
+	if err := prd.Save(basePath, prdFile); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// proposedCriteria extracts the non-blank criteria from a CRITERIA_PROPOSED
+// payload
+func proposedCriteria(payload map[string]any) []string {
+	list, _ := payload["acceptanceCriteria"].([]any)
+	var criteria []string
+	for _, c := range list {
+		if text, ok := c.(string); ok && strings.TrimSpace(text) != "" {
+			criteria = append(criteria, strings.TrimSpace(text))
+		}
+	}
+	return criteria
+}
+
+// markNeedsRefinement blocks PRDs the planner judged too vague to implement
+// The reason is recorded in the PRD's notes and the PRD is skipped by
+// selection until someone refines it and sets passes back to false.
+func markNeedsRefinement(basePath string, signals []llm.Signal) ([]string, error) {
+	var refine []llm.Signal
+	for _, s := range signals {
+		if s.Type == llm.SignalNeedsRefine && s.PRDID != "" {
+			refine = append(refine, s)
+		}
+	}
+	if len(refine) == 0 {
+		return nil, nil
+	}
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	var blocked []string
+	for _, s := range refine {
+		p := prdFile.FindByID(s.PRDID)
+		if p == nil || p.Passes.IsTrue() || p.Passes.IsPending() {
+			continue
+		}
+		p.Passes.SetBlocked()
+		p.ActivePlan = ""
+		p.AppendNote(fmt.Sprintf("[needs refinement] %s", s.Details))
+		if err := prd.DeletePlan(basePath, p.ID); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, p.ID)
+	}
+
+	if err := prd.Save(basePath, prdFile); err != nil {
+		return nil, err
+	}
+	return blocked, nil
+}
+
 // ShouldRunPlanner determines if the planner should run
-// Planner should run only if there are open PRDs AND no active PRDs
-func ShouldRunPlanner(prdFile *prd.PRDFileData) bool {
+// Planner should run only if there are open PRDs within scope AND no active PRDs
+// Active PRDs outside scope belong to other runs and do not hold planning back.
+func ShouldRunPlanner(prdFile *prd.PRDFileData, scope prd.Scope) bool {
+	run, _ := ShouldRunPlannerWithReason(prdFile, scope)
+	return run
+}
+
+// ShouldRunPlannerWithReason is ShouldRunPlanner plus the rationale for the
+// decision, e.g. "3 open PRDs, 0 active"
+func ShouldRunPlannerWithReason(prdFile *prd.PRDFileData, scope prd.Scope) (bool, string) {
+	active := prdFile.GetActivePRDsIn(scope.Work())
+	open := prdFile.GetOpenPRDsIn(scope)
+
 	// Skip if there's already an active PRD
-	if len(prdFile.GetActivePRDs()) > 0 {
-		return false
+	if len(active) > 0 {
+		return false, fmt.Sprintf("active PRD %s must be built first", active[0].ID)
 	}
 
 	// Skip if there are no open PRDs to plan
-	if len(prdFile.GetOpenPRDs()) == 0 {
-		return false
+	if len(open) == 0 {
+		return false, fmt.Sprintf("no open PRDs within %s", scope)
 	}
 
-	return true
+	return true, fmt.Sprintf("%d open PRD(s) within %s, 0 active", len(open), scope)
 }
 
-func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config) (*PlannerResult, error) {
+func runClaude(ctx context.Context, basePath string, prompt prompts.PhasePrompt, model string, cfg *config.Config) (*PlannerResult, error) {
 	result := &PlannerResult{}
 
 	phaseConfig := cfg.GetPhaseConfig("planner")
 
-	claude := llm.NewClaude("")
+	apiKey, err := cfg.Auth.APIKey()
+	if err != nil {
+		return nil, err
+	}
+	claude := llm.NewClaude("", apiKey)
 
 	// Create a cancellable context for this execution
 	execCtx, cancelExec := context.WithCancel(ctx)
 	defer cancelExec()
 
 	opts := llm.ExecuteOptions{
-		Prompt:       prompt,
-		Model:        phaseConfig.Model,
+		SystemPrompt: prompt.System,
+		Prompt:       prompt.User,
+		Model:        model,
 		AllowedTools: []string{
 			"Read", "Write", "Edit", "Bash", "Glob", "Grep",
 			"Task", "TodoWrite", "WebSearch", "WebFetch",
@@ -120,7 +396,10 @@ func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config)
 			prd.GetMillhousePath(basePath, prd.ProgressFile),
 			prd.GetMillhousePath(basePath, prd.PromptFile),
 		},
-		WorkDir: basePath,
+		WorkDir:    basePath,
+		AddDirs:    prd.ExternalArtifactDirs(),
+		ExtraArgs:  phaseConfig.ExtraArgs,
+		MCPServers: phaseConfig.MCPServers,
 	}
 
 	reader, err := claude.Execute(execCtx, opts)
@@ -129,18 +408,28 @@ func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config)
 	}
 
 	// Create handler with termination support
-	handler := llm.NewConsoleHandlerWithTerminate(phaseConfig.MaxTokens, cancelExec)
+	handler := llm.NewConsoleHandlerWithLimits(phaseConfig.MaxTokens, phaseConfig.MaxOutputTokens, cancelExec)
+	handler.SetMaxRetainedOutput(phaseConfig.RetainedOutput())
+	defer handler.Flush() // Flush buffered output on every exit path
+
+	// Cancel the phase if the agent goes quiet for too long
+	stopWatchdog := handler.WatchIdle(phaseConfig.IdleTimeoutDuration(), cancelExec)
+	defer stopWatchdog()
 
 	// Parse the stream
 	if err := llm.ParseStream(reader, handler, cancelExec); err != nil {
 		reader.Close()
 		return nil, fmt.Errorf("stream parsing failed: %w", err)
 	}
+	stopWatchdog()
 
 	// Close reader and check for process exit errors (e.g., Claude CLI failure)
 	// Note: "signal: killed" is expected when we intentionally terminate after a signal
 	closeErr := reader.Close()
 	if closeErr != nil && !handler.ShouldTerminate() {
+		if handler.RateLimited() {
+			return nil, fmt.Errorf("claude execution failed: %w: %w", closeErr, llm.ErrRateLimited)
+		}
 		return nil, fmt.Errorf("claude execution failed: %w", closeErr)
 	}
 
@@ -148,21 +437,28 @@ func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config)
 	result.Output = handler.GetOutput()
 	result.TotalTokens = handler.GetTokenStats().TotalTokens
 	result.Signals = handler.GetSignals()
+	result.RateLimited = handler.RateLimited()
+	result.Summary = handler.PhaseSummary("planner")
 
+	handler.Flush()
 	fmt.Println() // Ensure newline after output
 	handler.DisplayFinalTokenUsage()
+	handler.DisplayToolSummary()
 
 	return result, nil
 }
 
-func buildPlannerPrompt(basePath string, prdFile *prd.PRDFileData, cfg *config.Config) string {
+func buildPlannerPrompt(basePath string, prdFile *prd.PRDFileData, cfg *config.Config) prompts.PhasePrompt {
 	phaseConfig := cfg.GetPhaseConfig("planner")
 
 	promptMD := readFileContent(prd.GetMillhousePath(basePath, prd.PromptFile))
-	openPRDs := prdFile.GetOpenPRDs()
+	openPRDs := prdFile.GetOpenPRDsIn(cfg.Run.Scope())
 	openPRDsJSON, _ := json.MarshalIndent(openPRDs, "", "  ")
 	progressContent := readLastLines(prd.GetMillhousePath(basePath, prd.ProgressFile), phaseConfig.ProgressLines)
-	plannerAugmentation := prompts.LoadAugmentation(basePath, "planner")
+	plannerAugmentation := ""
+	if !cfg.Run.NoAugmentation {
+		plannerAugmentation = prompts.LoadAugmentation(basePath, "planner")
+	}
 
 	return prompts.BuildPlannerPrompt(prompts.PlannerData{
 		PromptMD:            promptMD,
@@ -170,26 +466,48 @@ func buildPlannerPrompt(basePath string, prdFile *prd.PRDFileData, cfg *config.C
 		ProgressContent:     progressContent,
 		Timestamp:           time.Now().Format("2006-01-02 15:04"),
 		PlannerAugmentation: plannerAugmentation,
+		SelectedPRD:         selectedPRD(prdFile, cfg),
+		RunDirective:        cfg.Run.RunDirective,
 	})
 }
 
+// selectedPRD returns the --select target if it is currently open
+func selectedPRD(prdFile *prd.PRDFileData, cfg *config.Config) string {
+	if p := prd.SelectNextWith(prdFile, cfg.Run.Select, cfg.Run.Scope()); p != nil && cfg.Run.Select != "" {
+		return p.ID
+	}
+	return ""
+}
+
+// plannerTarget returns the PRD the planner is known to plan before it runs:
+// the --select target, or the only open PRD in scope
+func plannerTarget(prdFile *prd.PRDFileData, cfg *config.Config) string {
+	if id := selectedPRD(prdFile, cfg); id != "" {
+		return id
+	}
+	if open := prdFile.GetOpenPRDsIn(cfg.Run.Scope()); len(open) == 1 {
+		return open[0].ID
+	}
+	return ""
+}
+
 func readFileContent(path string) string {
-	content, err := os.ReadFile(path)
+	content, err := prd.ReadTextFile(path)
 	if err != nil {
 		return ""
 	}
-	return string(content)
+	return content
 }
 
 func readLastLines(path string, n int) string {
-	content, err := os.ReadFile(path)
+	content, err := prd.ReadTextFile(path)
 	if err != nil {
 		return ""
 	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(content, "\n")
 	if len(lines) <= n {
-		return string(content)
+		return content
 	}
 
 	return strings.Join(lines[len(lines)-n:], "\n")
@@ -0,0 +1,145 @@
+package planner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// savePRDs writes prds to a fresh project's prd.json and returns its path
+func savePRDs(t *testing.T, prds ...prd.PRD) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := prd.Save(dir, &prd.PRDFileData{PRDs: prds}); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestApplyProposedCriteria(t *testing.T) {
+	vague := prd.PRD{ID: "vague", Description: "no criteria yet"}
+	vague.Passes.SetFalse()
+	defined := prd.PRD{ID: "defined", Description: "has criteria", AcceptanceCriteria: []string{"human wrote this"}}
+	defined.Passes.SetFalse()
+	dir := savePRDs(t, vague, defined)
+
+	criteria := func(id string, items ...any) llm.Signal {
+		return llm.Signal{Type: llm.SignalCriteria, PRDID: id, Payload: map[string]any{"acceptanceCriteria": items}}
+	}
+	applied, err := applyProposedCriteria(dir, []llm.Signal{
+		criteria("vague", "login succeeds", "  ", "logout clears the session "),
+		criteria("defined", "planner's version"),
+		criteria("missing", "nowhere"),
+		{Type: llm.SignalBlocked, PRDID: "vague"},
+	})
+	if err != nil {
+		t.Fatalf("applyProposedCriteria() error = %v", err)
+	}
+	if !slices.Equal(applied, []string{"vague"}) {
+		t.Errorf("applied = %v, want only vague", applied)
+	}
+
+	prdFile, err := prd.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := prdFile.FindByID("vague")
+	if want := []string{"login succeeds", "logout clears the session"}; !slices.Equal(got.AcceptanceCriteria, want) {
+		t.Errorf("vague criteria = %q, want %q", got.AcceptanceCriteria, want)
+	}
+	if !strings.Contains(got.Notes, "[criteria proposed] the planner wrote 2 acceptance criteria") {
+		t.Errorf("vague notes = %q, want the proposal note", got.Notes)
+	}
+
+	kept := prdFile.FindByID("defined")
+	if !slices.Equal(kept.AcceptanceCriteria, []string{"human wrote this"}) || kept.Notes != "" {
+		t.Errorf("defined = %q with notes %q, want it left alone", kept.AcceptanceCriteria, kept.Notes)
+	}
+
+	// Empty proposals change nothing
+	applied, err = applyProposedCriteria(dir, []llm.Signal{criteria("vague")})
+	if err != nil || applied != nil {
+		t.Errorf("applyProposedCriteria(empty) = %v, %v", applied, err)
+	}
+}
+
+func TestPlannedApproach(t *testing.T) {
+	meta := func(id string, payload map[string]any) llm.Signal {
+		return llm.Signal{Type: llm.SignalPlanMeta, PRDID: id, Payload: payload}
+	}
+	first := meta("feat", map[string]any{"summary": "Add a handler", "risk": "low"})
+	second := meta("feat", map[string]any{"summary": " Reuse the middleware ", "files": []any{"auth.go", "auth.go"}, "risk": "MEDIUM"})
+
+	// The last usable PLAN_META for the PRD wins; invalid ones are ignored
+	got := plannedApproach("feat", []llm.Signal{
+		first,
+		second,
+		meta("feat", map[string]any{"summary": "Rewrite it", "risk": "extreme"}),
+		meta("feat", map[string]any{"files": []any{"x.go"}}),
+		meta("feat", nil),
+		meta("other", map[string]any{"summary": "Not this PRD"}),
+	})
+	if got == nil || got.Summary != "Reuse the middleware" || got.Risk != prd.RiskMedium || !slices.Equal(got.Files, []string{"auth.go"}) {
+		t.Errorf("plannedApproach() = %+v, want the second, normalized", got)
+	}
+
+	if got := plannedApproach("feat", []llm.Signal{meta("feat", map[string]any{"risk": "low"})}); got != nil {
+		t.Errorf("plannedApproach(no summary) = %+v, want nil", got)
+	}
+	if got := plannedApproach("feat", nil); got != nil {
+		t.Errorf("plannedApproach(no signals) = %+v, want nil", got)
+	}
+}
+
+func TestRecordApproach(t *testing.T) {
+	feat := prd.PRD{ID: "feat", Description: "feature"}
+	feat.Passes.SetActive()
+	dir := savePRDs(t, feat)
+	load := func() *prd.PlannedApproach {
+		t.Helper()
+		prdFile, err := prd.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return prdFile.FindByID("feat").Approach
+	}
+	meta := func(payload map[string]any) llm.Signal {
+		return llm.Signal{Type: llm.SignalPlanMeta, PRDID: "feat", Payload: payload}
+	}
+
+	approach, err := recordApproach(dir, "feat", []llm.Signal{meta(map[string]any{"summary": "Add a handler", "risk": "low"})})
+	if err != nil {
+		t.Fatalf("recordApproach() error = %v", err)
+	}
+	if saved := load(); approach == nil || saved == nil || saved.Summary != "Add a handler" || saved.PlannedAt.IsZero() {
+		t.Fatalf("recordApproach() = %+v, saved %+v; want the approach with its planning time", approach, saved)
+	}
+
+	// An invalid PLAN_META is ignored, so it clears the earlier approach
+	// like a plan without one
+	approach, err = recordApproach(dir, "feat", []llm.Signal{meta(map[string]any{"summary": "Rewrite it", "risk": "extreme"})})
+	if err != nil || approach != nil {
+		t.Fatalf("recordApproach(invalid) = %+v, %v; want nil", approach, err)
+	}
+	if saved := load(); saved != nil {
+		t.Errorf("invalid PLAN_META left %+v, want the earlier approach cleared", saved)
+	}
+
+	if _, err := recordApproach(dir, "feat", []llm.Signal{meta(map[string]any{"summary": "Add a handler"})}); err != nil {
+		t.Fatal(err)
+	}
+	if approach, err = recordApproach(dir, "feat", nil); err != nil || approach != nil {
+		t.Fatalf("recordApproach(no PLAN_META) = %+v, %v; want nil", approach, err)
+	}
+	if saved := load(); saved != nil {
+		t.Errorf("a plan without PLAN_META left %+v, want the earlier approach cleared", saved)
+	}
+}
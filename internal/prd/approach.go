@@ -0,0 +1,45 @@
+package prd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Risk levels a planner may give its approach
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// PlannedApproach is the planner's account of how it means to build a PRD,
+// kept on the PRD so it can be read without opening the plan file
+type PlannedApproach struct {
+	Summary   string    `json:"summary"`            // One or two sentences on the approach
+	Files     []string  `json:"files,omitempty"`    // Files the planner expects the builder to touch
+	Risk      string    `json:"risk,omitempty"`     // RiskLow, RiskMedium or RiskHigh
+	PlannedAt time.Time `json:"plannedAt,omitzero"` // When the plan was made
+}
+
+// Check normalizes the approach and reports what makes it unusable
+// A summary is required; the risk, when given, must be a known level.
+func (a *PlannedApproach) Check() error {
+	a.Summary = strings.TrimSpace(a.Summary)
+	if a.Summary == "" {
+		return fmt.Errorf("no approach summary")
+	}
+	a.Risk = strings.ToLower(strings.TrimSpace(a.Risk))
+	if a.Risk != "" && !slices.Contains([]string{RiskLow, RiskMedium, RiskHigh}, a.Risk) {
+		return fmt.Errorf("unknown risk %q (want %s, %s or %s)", a.Risk, RiskLow, RiskMedium, RiskHigh)
+	}
+	files := a.Files[:0]
+	for _, f := range a.Files {
+		if f = strings.TrimSpace(f); f != "" && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	a.Files = files
+	return nil
+}
@@ -0,0 +1,50 @@
+package prd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlannedApproachCheck(t *testing.T) {
+	a := PlannedApproach{Summary: "  Add a cache layer  ", Risk: " High", Files: []string{"cache.go", " ", "cache.go", "api.go"}}
+	if err := a.Check(); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if a.Summary != "Add a cache layer" || a.Risk != RiskHigh || !reflect.DeepEqual(a.Files, []string{"cache.go", "api.go"}) {
+		t.Errorf("Check() left %+v", a)
+	}
+
+	for _, bad := range []PlannedApproach{{Summary: " "}, {Summary: "ok", Risk: "extreme"}} {
+		if err := bad.Check(); err == nil {
+			t.Errorf("Check() accepted %+v", bad)
+		}
+	}
+}
+
+func TestPlannedApproachStored(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	planned := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	approach := &PlannedApproach{Summary: "Wrap handlers in middleware", Files: []string{"api/mw.go"}, Risk: RiskLow, PlannedAt: planned}
+	data := &PRDFileData{PRDs: []PRD{{ID: "a-1", Approach: approach}, {ID: "a-2"}}}
+	if err := Save(dir, data); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.FindByID("a-1").Approach; !reflect.DeepEqual(got, approach) {
+		t.Errorf("stored approach = %+v, want %+v", got, approach)
+	}
+	if got := loaded.FindByID("a-2"); got.Approach != nil || len(got.Extra) != 0 {
+		t.Errorf("PRD without an approach loaded as %+v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package prd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// artifactsDir relocates generated artifacts (plans, evidence, event log)
+// Empty means they live in .milhouse/ next to prd.json.
+var artifactsDir string
+
+// SetArtifactsDir moves generated artifacts to dir; empty restores .milhouse/
+// prd.json, progress.md, prompt.md, config and prompts always stay in the project.
+func SetArtifactsDir(dir string) {
+	artifactsDir = dir
+}
+
+// GetArtifactsDir returns the root directory for generated artifacts
+func GetArtifactsDir(basePath string) string {
+	if artifactsDir != "" {
+		return artifactsDir
+	}
+	return filepath.Join(basePath, MillhouseDir)
+}
+
+// GetArtifactPath returns the path to a generated artifact, e.g. EventsFile
+func GetArtifactPath(basePath string, elem ...string) string {
+	return filepath.Join(append([]string{GetArtifactsDir(basePath)}, elem...)...)
+}
+
+// AgentArtifactDir returns an artifact directory (PlansDir, EvidenceDir) as
+// agents are told about it: relative to the project by default, absolute when
+// relocated. The result ends with a slash.
+func AgentArtifactDir(name string) string {
+	if artifactsDir == "" {
+		return MillhouseDir + "/" + name + "/"
+	}
+	return filepath.Join(artifactsDir, name) + string(filepath.Separator)
+}
+
+// ExternalArtifactDirs returns directories outside the project that agents
+// need access to, i.e. the relocated artifacts root if one is set
+func ExternalArtifactDirs() []string {
+	if artifactsDir == "" {
+		return nil
+	}
+	return []string{artifactsDir}
+}
+
+// CacheArtifactsDir returns a per-project artifacts directory in the user's
+// cache, e.g. ~/.cache/milhouse/<hash of the project path>
+func CacheArtifactsDir(basePath string) (string, error) {
+	abs, err := filepath.Abs(basePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project path: %w", err)
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(cache, "milhouse", hex.EncodeToString(sum[:])[:12]), nil
+}
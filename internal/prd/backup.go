@@ -0,0 +1,115 @@
+package prd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// BackupFile is the last-known-good copy of prd.json that Save keeps
+const BackupFile = "prd.json.bak"
+
+// backupBeforeSave copies the current prd.json to BackupFile when it parses,
+// so a corrupt file never replaces a good backup
+func backupBeforeSave(basePath string) error {
+	data, err := os.ReadFile(GetMillhousePath(basePath, PRDFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read prd.json for backup: %w", err)
+	}
+	if !parses(data) {
+		return nil
+	}
+	if err := writeFileAtomic(GetMillhousePath(basePath, BackupFile), data); err != nil {
+		return fmt.Errorf("failed to back up prd.json: %w", err)
+	}
+	return nil
+}
+
+// parses reports whether data is a prd.json object Load reads without recovery
+func parses(data []byte) bool {
+	var prdFile PRDFileData
+	return json.Unmarshal(data, &prdFile) == nil
+}
+
+// Intact reports whether prd.json exists and parses as-is, i.e. without the
+// recovery Load falls back to for empty or bare-array files
+func Intact(basePath string) bool {
+	data, err := os.ReadFile(GetMillhousePath(basePath, PRDFile))
+	return err == nil && parses(data)
+}
+
+// BackupExists reports whether a prd.json backup is present
+func BackupExists(basePath string) bool {
+	_, err := os.Stat(GetMillhousePath(basePath, BackupFile))
+	return err == nil
+}
+
+// backupPRDCount returns how many PRDs the backup holds, 0 when there is no
+// readable backup
+func backupPRDCount(basePath string) int {
+	data, err := os.ReadFile(GetMillhousePath(basePath, BackupFile))
+	if err != nil {
+		return 0
+	}
+	var prdFile PRDFileData
+	if json.Unmarshal(data, &prdFile) != nil {
+		return 0
+	}
+	return len(prdFile.PRDs)
+}
+
+// restoreHint tells the user how to recover from the backup, or returns ""
+// when there is none
+func restoreHint(basePath string) string {
+	if !BackupExists(basePath) {
+		return ""
+	}
+	return fmt.Sprintf("; run 'mil prd restore' or 'mil doctor --fix' to recover it from %s", BackupFile)
+}
+
+// Restore replaces prd.json with the backup Save keeps, returning the
+// restored PRDs. The backup is checked before anything is overwritten.
+func Restore(basePath string) (*PRDFileData, error) {
+	data, err := os.ReadFile(GetMillhousePath(basePath, BackupFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	var prdFile PRDFileData
+	if err := json.Unmarshal(data, &prdFile); err != nil {
+		return nil, fmt.Errorf("backup %s is not valid either: %w", BackupFile, err)
+	}
+	if err := writeFileAtomic(GetMillhousePath(basePath, PRDFile), data); err != nil {
+		return nil, fmt.Errorf("failed to restore prd.json: %w", err)
+	}
+	return &prdFile, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so an interrupted write leaves the old file intact
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,165 @@
+package prd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveKeepsBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := GetMillhousePath(dir, PRDFile)
+
+	first := &PRDFileData{PRDs: []PRD{{ID: "a-1", Description: "First"}}}
+	if err := Save(dir, first); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if BackupExists(dir) {
+		t.Fatal("first Save() wrote a backup with no previous file")
+	}
+
+	second := &PRDFileData{PRDs: []PRD{{ID: "a-1", Description: "First"}, {ID: "a-2", Description: "Second"}}}
+	if err := Save(dir, second); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if !BackupExists(dir) {
+		t.Fatal("second Save() did not back up the previous file")
+	}
+
+	// Simulate a write cut off halfway
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Fatal("Load() of a truncated file succeeded")
+	}
+	if Intact(dir) {
+		t.Error("Intact() = true for a truncated file")
+	}
+
+	// Saving over the corrupt file must not replace the good backup
+	if err := Save(dir, first); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"prds": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(dir, second); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"prds": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := Restore(dir)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if len(restored.PRDs) != 1 {
+		t.Errorf("Restore() returned %d PRDs, want 1", len(restored.PRDs))
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() after Restore() error = %v", err)
+	}
+	if len(loaded.PRDs) != 1 || loaded.PRDs[0].ID != "a-1" {
+		t.Errorf("Load() after Restore() = %+v, want the last good file", loaded.PRDs)
+	}
+	if !Intact(dir) {
+		t.Error("Intact() = false after Restore()")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, MillhouseDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temporary file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestRestoreRejectsInvalidBackup(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := GetMillhousePath(dir, PRDFile)
+	if err := os.WriteFile(path, []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Restore(dir); err == nil {
+		t.Error("Restore() with no backup succeeded")
+	}
+
+	if err := os.WriteFile(GetMillhousePath(dir, BackupFile), []byte("also broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(dir); err == nil {
+		t.Error("Restore() from an invalid backup succeeded")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{broken" {
+		t.Errorf("prd.json = %q, want it left untouched", data)
+	}
+}
+
+func TestLoadEmptyFileWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := GetMillhousePath(dir, PRDFile)
+
+	// With no backup an empty file is a fresh start
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if prdFile, err := Load(dir); err != nil || len(prdFile.PRDs) != 0 {
+		t.Fatalf("Load() of an empty file without a backup = %v, %v", prdFile, err)
+	}
+
+	good := &PRDFileData{PRDs: []PRD{{ID: "a-1"}, {ID: "a-2"}}}
+	for range 2 {
+		if err := Save(dir, good); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path, []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Fatal("Load() of an empty file read zero PRDs over a backup holding 2")
+	}
+	for _, want := range []string{"2 PRDs", "mil prd restore", "mil doctor --fix"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load() error = %q, want it to mention %q", err, want)
+		}
+	}
+	if restored, err := Restore(dir); err != nil || len(restored.PRDs) != 2 {
+		t.Errorf("Restore() = %v, %v; want the 2 backed-up PRDs", restored, err)
+	}
+
+	// Parse failures point at the backup too
+	if err := os.WriteFile(path, []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "mil prd restore") {
+		t.Errorf("Load() of a corrupt file error = %v, want the restore hint", err)
+	}
+}
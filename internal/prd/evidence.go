@@ -0,0 +1,146 @@
+package prd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Reviewer verdicts recorded by AppendEvidenceReview
+const (
+	ReviewVerified = "verified"
+	ReviewRejected = "rejected"
+)
+
+// Evidence is the builder's completion claim for a PRD, parsed from its
+// evidence file
+type Evidence struct {
+	Path    string
+	Content string
+	Commits []string // Commit SHAs named in commit sections
+	Files   []string // Paths listed under a "Files changed" section
+}
+
+var (
+	evidenceHeading = regexp.MustCompile(`^\s*(?:#+\s*(.+?)|\*\*(.+?)\*\*:?)\s*$`)
+	evidenceItem    = regexp.MustCompile("^\\s*(?:[-*+]|\\d+\\.)\\s+(?:\\[[ xX]\\]\\s+)?`?([^`\\s]+)`?")
+	commitSHA       = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+)
+
+// LoadEvidence reads and parses the evidence file for a PRD
+// It fails when the file is missing, empty, or names no commit.
+func LoadEvidence(basePath, prdID string) (*Evidence, error) {
+	path := GetEvidencePath(basePath, prdID)
+	content, err := ReadTextFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no evidence file at %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evidence: %w", err)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("evidence file %s is empty", path)
+	}
+
+	ev := ParseEvidence(content)
+	ev.Path = path
+	if len(ev.Commits) == 0 {
+		return ev, fmt.Errorf("evidence file %s names no commit SHA", path)
+	}
+	return ev, nil
+}
+
+// ParseEvidence extracts commit SHAs and changed files from evidence markdown
+// Sections are introduced by markdown headings or bold lines; SHAs are taken
+// from sections mentioning commits and list items from "files" sections.
+func ParseEvidence(content string) *Evidence {
+	ev := &Evidence{Content: content}
+	seenCommit := make(map[string]bool)
+	seenFile := make(map[string]bool)
+
+	section := ""
+	for _, line := range strings.Split(content, "\n") {
+		if m := evidenceHeading.FindStringSubmatch(line); m != nil {
+			section = strings.ToLower(m[1] + m[2])
+			continue
+		}
+
+		if strings.Contains(section, "commit") {
+			for _, sha := range commitSHA.FindAllString(line, -1) {
+				if !seenCommit[sha] {
+					seenCommit[sha] = true
+					ev.Commits = append(ev.Commits, sha)
+				}
+			}
+		}
+
+		if strings.Contains(section, "files") {
+			if m := evidenceItem.FindStringSubmatch(line); m != nil && !seenFile[m[1]] {
+				seenFile[m[1]] = true
+				ev.Files = append(ev.Files, m[1])
+			}
+		}
+	}
+	return ev
+}
+
+// AppendEvidenceReview adds a dated Review section with the reviewer's verdict
+// to the end of a PRD's evidence file. Verified PRDs get a "✓ Verified" line
+// and rejected ones the reason.
+// PRDs without an evidence file are skipped, so its existence still means the
+// builder left a claim, and a verdict repeating the latest review is not
+// appended again.
+func AppendEvidenceReview(basePath, prdID, verdict, reason string) error {
+	var line string
+	switch verdict {
+	case ReviewVerified:
+		line = "✓ Verified"
+	case ReviewRejected:
+		line = "✗ Rejected"
+	default:
+		return fmt.Errorf("unknown review verdict %q", verdict)
+	}
+	if reason = strings.TrimSpace(reason); reason != "" {
+		line += ": " + reason
+	}
+
+	path := GetEvidencePath(basePath, prdID)
+	content, err := ReadTextFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read evidence: %w", err)
+	}
+	if latestReview(content) == line {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open evidence file: %w", err)
+	}
+	defer f.Close()
+
+	section := fmt.Sprintf("\n%s%s)\n\n%s\n", reviewHeading, time.Now().Format("2006-01-02 15:04"), line)
+	if _, err := f.WriteString(section); err != nil {
+		return fmt.Errorf("failed to append review to evidence: %w", err)
+	}
+	return nil
+}
+
+// reviewHeading starts each section AppendEvidenceReview writes
+const reviewHeading = "## Review ("
+
+// latestReview returns the verdict line of the last review in evidence
+// content, or "" when it has none
+func latestReview(content string) string {
+	i := strings.LastIndex(content, "\n"+reviewHeading)
+	if i < 0 {
+		return ""
+	}
+	_, body, _ := strings.Cut(content[i+1:], "\n")
+	return strings.TrimSpace(body)
+}
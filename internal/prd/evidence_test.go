@@ -0,0 +1,122 @@
+package prd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleEvidence = "# Evidence: add-login\n\n" +
+	"## Files Changed\n" +
+	"- `internal/auth/login.go` - new handler\n" +
+	"- internal/auth/login_test.go\n\n" +
+	"## Git Commits\n" +
+	"- 3f2a9c1 Add login handler\n\n" +
+	"## Verification Evidence\n" +
+	"```bash\n" +
+	"# Commit verification\n" +
+	"$ git show --name-only 3f2a9c1d\n" +
+	"```\n"
+
+func TestParseEvidence(t *testing.T) {
+	ev := ParseEvidence(sampleEvidence)
+	if got := strings.Join(ev.Files, ","); got != "internal/auth/login.go,internal/auth/login_test.go" {
+		t.Errorf("Files = %v", ev.Files)
+	}
+	if got := strings.Join(ev.Commits, ","); got != "3f2a9c1,3f2a9c1d" {
+		t.Errorf("Commits = %v", ev.Commits)
+	}
+}
+
+func TestLoadEvidence(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir, EvidenceDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadEvidence(dir, "missing"); err == nil {
+		t.Error("expected an error for a missing evidence file")
+	}
+
+	os.WriteFile(GetEvidencePath(dir, "blank"), []byte("\n  \n"), 0644)
+	if _, err := LoadEvidence(dir, "blank"); err == nil {
+		t.Error("expected an error for an empty evidence file")
+	}
+
+	os.WriteFile(GetEvidencePath(dir, "vague"), []byte("# Evidence\nIt works.\n"), 0644)
+	if _, err := LoadEvidence(dir, "vague"); err == nil {
+		t.Error("expected an error for evidence without a commit")
+	}
+
+	os.WriteFile(GetEvidencePath(dir, "ok"), []byte(sampleEvidence), 0644)
+	ev, err := LoadEvidence(dir, "ok")
+	if err != nil {
+		t.Fatalf("LoadEvidence() error = %v", err)
+	}
+	if ev.Path != GetEvidencePath(dir, "ok") || len(ev.Commits) == 0 {
+		t.Errorf("LoadEvidence() = %+v", ev)
+	}
+}
+
+func TestAppendEvidenceReview(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir, EvidenceDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := GetEvidencePath(dir, "ok")
+	os.WriteFile(path, []byte(sampleEvidence), 0644)
+
+	if err := AppendEvidenceReview(dir, "ok", ReviewRejected, "login fails with an expired token\n"); err != nil {
+		t.Fatalf("AppendEvidenceReview() error = %v", err)
+	}
+	if err := AppendEvidenceReview(dir, "ok", ReviewVerified, ""); err != nil {
+		t.Fatalf("AppendEvidenceReview() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, sampleEvidence) {
+		t.Error("builder evidence was not kept at the top")
+	}
+	rejected := strings.Index(content, "✗ Rejected: login fails with an expired token\n")
+	verified := strings.Index(content, "✓ Verified\n")
+	if rejected < 0 || verified < rejected || strings.Count(content, "## Review (") != 2 {
+		t.Errorf("reviews not appended in order:\n%s", content)
+	}
+
+	// The builder's claim still parses the same
+	if got, want := ParseEvidence(content), ParseEvidence(sampleEvidence); !reflect.DeepEqual(got.Commits, want.Commits) || !reflect.DeepEqual(got.Files, want.Files) {
+		t.Errorf("ParseEvidence() after reviews = %v %v, want %v %v", got.Commits, got.Files, want.Commits, want.Files)
+	}
+
+	// Repeating the latest verdict adds nothing; a new one is appended
+	if err := AppendEvidenceReview(dir, "ok", ReviewVerified, ""); err != nil {
+		t.Fatalf("AppendEvidenceReview() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("a repeated verdict was appended:\n%s", data)
+	}
+	if err := AppendEvidenceReview(dir, "ok", ReviewVerified, "held at pending: checks failed"); err != nil {
+		t.Fatalf("AppendEvidenceReview() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); strings.Count(string(data), "## Review (") != 3 {
+		t.Errorf("a changed verdict was not appended:\n%s", data)
+	}
+
+	// A PRD without builder evidence gets no file
+	other := t.TempDir()
+	if err := AppendEvidenceReview(other, "new", ReviewVerified, ""); err != nil {
+		t.Errorf("AppendEvidenceReview() without an evidence file: %v", err)
+	}
+	if _, err := os.Stat(GetEvidencePath(other, "new")); !os.IsNotExist(err) {
+		t.Errorf("an evidence file was created for a PRD without one: %v", err)
+	}
+	if err := AppendEvidenceReview(dir, "ok", "maybe", ""); err == nil {
+		t.Error("AppendEvidenceReview() accepted an unknown verdict")
+	}
+}
@@ -0,0 +1,128 @@
+package prd
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats mil export can write
+const (
+	ExportHTML = "html"
+	ExportCSV  = "csv"
+)
+
+// ExportFormats lists every export format
+var ExportFormats = []string{ExportHTML, ExportCSV}
+
+// csvHeader names the columns of a CSV export
+var csvHeader = []string{"id", "status", "priority", "description", "owner", "dependsOn", "tags"}
+
+//go:embed report.html.tmpl
+var reportTemplate string
+
+var reportHTML = template.Must(template.New("report").Parse(reportTemplate))
+
+// Export writes the PRDs in the given format, without terminal styling
+// now is the report's generation time, shown in HTML reports.
+func Export(w io.Writer, format string, prdFile *PRDFileData, now time.Time) error {
+	switch format {
+	case ExportHTML:
+		return exportHTML(w, prdFile, now)
+	case ExportCSV:
+		return exportCSV(w, prdFile)
+	}
+	return fmt.Errorf("unknown export format %q (valid: %s)", format, strings.Join(ExportFormats, ", "))
+}
+
+// exportCSV writes one row per PRD in prd.json order
+func exportCSV(w io.Writer, prdFile *PRDFileData) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, p := range prdFile.PRDs {
+		row := []string{
+			p.ID,
+			p.Passes.String(),
+			strconv.Itoa(p.Priority),
+			p.Description,
+			p.Owner,
+			strings.Join(p.DependsOn, " "),
+			strings.Join(p.Tags, " "),
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// reportStatus is one status's count in the HTML summary
+type reportStatus struct {
+	Name  string
+	Count int
+}
+
+// reportPRD is a PRD as the HTML report shows it
+type reportPRD struct {
+	PRD
+	Status string
+	Meta   string // Owner, dependencies and subtask progress, when set
+}
+
+// reportMeta summarizes a PRD's owner, dependencies and subtasks in one line
+func reportMeta(p PRD) string {
+	var parts []string
+	if p.Owner != "" {
+		parts = append(parts, fmt.Sprintf("Owner: %s.", p.Owner))
+	}
+	if len(p.DependsOn) > 0 {
+		parts = append(parts, fmt.Sprintf("Depends on: %s.", strings.Join(p.DependsOn, ", ")))
+	}
+	if done, total := p.SubtaskProgress(); total > 0 {
+		parts = append(parts, fmt.Sprintf("Subtasks: %d/%d.", done, total))
+	}
+	return strings.Join(parts, " ")
+}
+
+// exportHTML writes a self-contained HTML status report
+func exportHTML(w io.Writer, prdFile *PRDFileData, now time.Time) error {
+	counts := make(map[string]int)
+	prds := make([]reportPRD, 0, len(prdFile.PRDs))
+	for _, p := range prdFile.PRDs {
+		status := p.Passes.String()
+		counts[status]++
+		prds = append(prds, reportPRD{PRD: p, Status: status, Meta: reportMeta(p)})
+	}
+
+	var summary []reportStatus
+	for _, status := range []string{StatusOpen, StatusActive, StatusPending, StatusBlocked, StatusComplete} {
+		summary = append(summary, reportStatus{Name: status, Count: counts[status]})
+	}
+
+	data := struct {
+		Generated string
+		Total     int
+		Summary   []reportStatus
+		PRDs      []reportPRD
+	}{
+		Generated: now.Format("2006-01-02 15:04 MST"),
+		Total:     len(prds),
+		Summary:   summary,
+		PRDs:      prds,
+	}
+	if err := reportHTML.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to write HTML: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package prd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+func TestExport(t *testing.T) {
+	data := &PRDFileData{PRDs: []PRD{
+		{ID: "auth-login", Description: `Login form with "remember me", and <script> escaping`, Priority: 1,
+			AcceptanceCriteria: []string{"Valid credentials log in", "Errors are shown inline"},
+			Owner:              "dana", Subtasks: []Subtask{{Description: "form", Done: true}, {Description: "api"}},
+			Tags: []string{"backend", "security"}},
+		{ID: "auth-logout", Description: "Logout button", Priority: 2, DependsOn: []string{"auth-login"}, Tags: []string{"<ui>"}},
+		{ID: "docs", Description: "Document the API\nwith examples", Priority: 3},
+	}}
+	data.PRDs[0].Passes.SetActive()
+	data.PRDs[1].Passes.SetFalse()
+	data.PRDs[2].Passes.SetTrue()
+	now := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	for _, format := range ExportFormats {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Export(&out, format, data, now); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if bytes.Contains(out.Bytes(), []byte("\x1b[")) {
+				t.Error("export contains ANSI escapes")
+			}
+
+			golden := filepath.Join("testdata", "export."+format+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(golden, out.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(out.Bytes(), want) {
+				t.Errorf("export differs from %s (run with -update to accept):\n%s", golden, out.String())
+			}
+		})
+	}
+
+	if err := Export(&bytes.Buffer{}, "pdf", data, now); err == nil {
+		t.Error("Export() accepted an unknown format")
+	}
+}
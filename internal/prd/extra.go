@@ -0,0 +1,143 @@
+package prd
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Fields Millhouse knows, by JSON name; anything else is kept in Extra
+var (
+	prdFields     = jsonFieldNames(reflect.TypeFor[PRD]())
+	prdFileFields = jsonFieldNames(reflect.TypeFor[PRDFileData]())
+)
+
+// UnmarshalJSON decodes a PRD, keeping fields Millhouse does not know in
+// Extra so saving the PRD writes them back
+func (p *PRD) UnmarshalJSON(data []byte) error {
+	type plain PRD // Same fields without the methods, so decoding doesn't recurse
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, prdFields)
+	if err != nil {
+		return err
+	}
+	decoded.Extra = extra
+	*p = PRD(decoded)
+	return nil
+}
+
+// MarshalJSON encodes a PRD with its Extra fields after the known ones
+func (p PRD) MarshalJSON() ([]byte, error) {
+	type plain PRD
+	data, err := json.Marshal(plain(p))
+	if err != nil {
+		return nil, err
+	}
+	return appendFields(data, p.Extra, prdFields)
+}
+
+// UnmarshalJSON decodes prd.json, keeping unknown top-level fields in Extra
+func (f *PRDFileData) UnmarshalJSON(data []byte) error {
+	type plain PRDFileData
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, prdFileFields)
+	if err != nil {
+		return err
+	}
+	decoded.Extra = extra
+	*f = PRDFileData(decoded)
+	return nil
+}
+
+// MarshalJSON encodes prd.json with its Extra fields after the PRDs
+func (f PRDFileData) MarshalJSON() ([]byte, error) {
+	type plain PRDFileData
+	data, err := json.Marshal(plain(f))
+	if err != nil {
+		return nil, err
+	}
+	return appendFields(data, f.Extra, prdFileFields)
+}
+
+// jsonFieldNames lists the JSON names of a struct's encoded fields
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// isKnown reports whether a JSON key decodes into one of the known fields
+// encoding/json matches keys case-insensitively, so this does too.
+func isKnown(key string, known []string) bool {
+	return slices.ContainsFunc(known, func(name string) bool { return strings.EqualFold(name, key) })
+}
+
+// unknownFields returns the fields of a JSON object that are not known, or
+// nil when there are none
+func unknownFields(data []byte, known []string) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key := range fields {
+		if isKnown(key, known) {
+			delete(fields, key)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// appendFields adds extra fields, sorted by name, to the end of an encoded
+// JSON object. Fields that collide with known ones are dropped.
+func appendFields(object []byte, extra map[string]json.RawMessage, known []string) ([]byte, error) {
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if !isKnown(key, known) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return object, nil
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(object[:len(object)-1]) // Drop the closing brace
+	for i, key := range keys {
+		if i > 0 || len(object) > 2 { // Not an empty object
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,92 @@
+package prd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	original := `{
+  "prds": [
+    {
+      "id": "auth-1",
+      "description": "Login",
+      "acceptanceCriteria": ["works"],
+      "priority": 1,
+      "passes": false,
+      "notes": "",
+      "metadata": {"jira": "AUTH-12", "points": 3, "links": ["https://example.com/a"]},
+      "estimate": "2d",
+      "reviewers": ["dana", "lee"]
+    }
+  ],
+  "project": {"name": "shop"}
+}`
+	if err := os.WriteFile(filepath.Join(dir, MillhouseDir, PRDFile), []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	p := data.FindByID("auth-1")
+	if p.Metadata["jira"] != "AUTH-12" {
+		t.Errorf("metadata = %v, want jira AUTH-12", p.Metadata)
+	}
+	if len(p.Extra) != 2 || string(p.Extra["estimate"]) != `"2d"` {
+		t.Errorf("PRD extra = %v, want estimate and reviewers", p.Extra)
+	}
+	if _, ok := data.Extra["project"]; !ok || len(data.Extra) != 1 {
+		t.Errorf("file extra = %v, want project", data.Extra)
+	}
+
+	// Edit a known field and save; everything else survives
+	p.Notes = "started"
+	if err := Save(dir, data); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, MillhouseDir, PRDFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]any
+	if err := json.Unmarshal(saved, &got); err != nil {
+		t.Fatalf("saved prd.json is not valid JSON: %v\n%s", err, saved)
+	}
+	if err := json.Unmarshal([]byte(strings.Replace(original, `"notes": ""`, `"notes": "started"`, 1)), &want); err != nil {
+		t.Fatal(err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("round trip changed prd.json:\ngot  %s\nwant %s", gotJSON, wantJSON)
+	}
+
+	// A second load sees the same data
+	again, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := again.FindByID("auth-1"); q.Notes != "started" || len(q.Extra) != 2 || q.Metadata["points"] != float64(3) {
+		t.Errorf("reloaded PRD = %+v", q)
+	}
+}
+
+func TestUnknownFieldsIgnoreCaseVariants(t *testing.T) {
+	// encoding/json decodes "Description" into Description, so it is not extra
+	var p PRD
+	if err := json.Unmarshal([]byte(`{"id":"a","Description":"Login"}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Description != "Login" || p.Extra != nil {
+		t.Errorf("PRD = %+v, want the description and no extra fields", p)
+	}
+}
@@ -0,0 +1,375 @@
+package prd
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter is a parsed --prd-filter expression such as
+// `priority<=2 && tag=backend && status=open`
+// Comparisons join with && (binds tighter), || and !, grouped by parentheses.
+type Filter struct {
+	expr  string
+	match filterFunc
+}
+
+// filterFunc reports whether a PRD satisfies part of a filter at time now
+type filterFunc func(p *PRD, now time.Time) bool
+
+// FilterFields lists the fields a filter can compare
+var FilterFields = []string{"priority", "status", "tag", "owner", "age", "id"}
+
+// filterStatuses maps status names in filters to their check
+var filterStatuses = map[string]func(*PassesStatus) bool{
+	StatusOpen:     (*PassesStatus).IsFalse,
+	StatusActive:   (*PassesStatus).IsActive,
+	StatusPending:  (*PassesStatus).IsPending,
+	StatusBlocked:  (*PassesStatus).IsBlocked,
+	StatusComplete: (*PassesStatus).IsTrue,
+}
+
+// ParseFilter compiles a filter expression
+func ParseFilter(expr string) (*Filter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRD filter: %w", err)
+	}
+	if len(tokens) == 1 {
+		return nil, fmt.Errorf("invalid PRD filter: empty expression")
+	}
+
+	fp := &filterParser{tokens: tokens}
+	match, err := fp.parseOr()
+	if err == nil && fp.peek().kind != tokEOF {
+		err = fp.errorf("unexpected %s", fp.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRD filter: %w", err)
+	}
+	return &Filter{expr: strings.TrimSpace(expr), match: match}, nil
+}
+
+// Match reports whether the PRD satisfies the filter; a nil filter matches
+// everything
+func (f *Filter) Match(p *PRD, now time.Time) bool {
+	return f == nil || f.match(p, now)
+}
+
+// String returns the expression the filter was parsed from
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// Apply returns the PRDs that satisfy the filter, in their original order
+func (f *Filter) Apply(prds []PRD, now time.Time) []PRD {
+	var matched []PRD
+	for i := range prds {
+		if f.Match(&prds[i], now) {
+			matched = append(matched, prds[i])
+		}
+	}
+	return matched
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp  // = == != < <= > >=
+	tokAnd // &&
+	tokOr  // ||
+	tokNot // !
+	tokLParen
+	tokRParen
+)
+
+type filterToken struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based column
+}
+
+func (t filterToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lexFilter splits an expression into tokens, ending with tokEOF
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c, pos := expr[i], i+1
+		two := ""
+		if i+1 < len(expr) {
+			two = expr[i : i+2]
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case two == "&&":
+			tokens = append(tokens, filterToken{tokAnd, two, pos})
+			i += 2
+		case two == "||":
+			tokens = append(tokens, filterToken{tokOr, two, pos})
+			i += 2
+		case two == "==" || two == "!=" || two == "<=" || two == ">=":
+			tokens = append(tokens, filterToken{tokOp, two, pos})
+			i += 2
+		case c == '=' || c == '<' || c == '>':
+			tokens = append(tokens, filterToken{tokOp, string(c), pos})
+			i++
+		case c == '!':
+			tokens = append(tokens, filterToken{tokNot, "!", pos})
+			i++
+		case c == '(':
+			tokens = append(tokens, filterToken{tokLParen, "(", pos})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{tokRParen, ")", pos})
+			i++
+		case c == '"':
+			end := strings.IndexByte(expr[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("column %d: unterminated string", pos)
+			}
+			tokens = append(tokens, filterToken{tokString, expr[i+1 : i+1+end], pos})
+			i += end + 2
+		case c == '&' || c == '|':
+			return nil, fmt.Errorf("column %d: %q is not an operator, use %c%c", pos, c, c, c)
+		default:
+			start := i
+			for i < len(expr) && !strings.ContainsRune(" \t\n&|=!<>()\"", rune(expr[i])) {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokWord, expr[start:i], pos})
+		}
+	}
+	return append(tokens, filterToken{kind: tokEOF, pos: len(expr) + 1}), nil
+}
+
+// filterParser is a recursive descent parser over:
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" or ")" | comparison
+//	comparison = field op value
+type filterParser struct {
+	tokens []filterToken
+	next   int
+}
+
+func (fp *filterParser) peek() filterToken {
+	return fp.tokens[fp.next]
+}
+
+func (fp *filterParser) take() filterToken {
+	t := fp.tokens[fp.next]
+	if t.kind != tokEOF {
+		fp.next++
+	}
+	return t
+}
+
+// errorf reports a problem at the next token's column
+func (fp *filterParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("column %d: %s", fp.peek().pos, fmt.Sprintf(format, args...))
+}
+
+func (fp *filterParser) parseOr() (filterFunc, error) {
+	left, err := fp.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for fp.peek().kind == tokOr {
+		fp.take()
+		right, err := fp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(p *PRD, now time.Time) bool { return l(p, now) || right(p, now) }
+	}
+	return left, nil
+}
+
+func (fp *filterParser) parseAnd() (filterFunc, error) {
+	left, err := fp.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for fp.peek().kind == tokAnd {
+		fp.take()
+		right, err := fp.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(p *PRD, now time.Time) bool { return l(p, now) && right(p, now) }
+	}
+	return left, nil
+}
+
+func (fp *filterParser) parseUnary() (filterFunc, error) {
+	switch fp.peek().kind {
+	case tokNot:
+		fp.take()
+		inner, err := fp.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(p *PRD, now time.Time) bool { return !inner(p, now) }, nil
+	case tokLParen:
+		fp.take()
+		inner, err := fp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if fp.peek().kind != tokRParen {
+			return nil, fp.errorf("expected ) but found %s", fp.peek())
+		}
+		fp.take()
+		return inner, nil
+	}
+	return fp.parseComparison()
+}
+
+func (fp *filterParser) parseComparison() (filterFunc, error) {
+	if fp.peek().kind != tokWord {
+		return nil, fp.errorf("expected a field but found %s", fp.peek())
+	}
+	fieldTok := fp.take()
+	field := strings.ToLower(fieldTok.text)
+	if field == "tags" {
+		field = "tag"
+	}
+	if !slices.Contains(FilterFields, field) {
+		return nil, fmt.Errorf("column %d: unknown field %q (fields: %s)", fieldTok.pos, fieldTok.text, strings.Join(FilterFields, ", "))
+	}
+
+	if fp.peek().kind != tokOp {
+		return nil, fp.errorf("expected a comparison after %s but found %s", field, fp.peek())
+	}
+	opTok := fp.take()
+	op := opTok.text
+	if op == "==" {
+		op = "="
+	}
+
+	if k := fp.peek().kind; k != tokWord && k != tokString {
+		return nil, fp.errorf("expected a value after %s%s but found %s", field, opTok.text, fp.peek())
+	}
+	valueTok := fp.take()
+
+	match, err := compileComparison(field, op, valueTok.text)
+	if err != nil {
+		return nil, fmt.Errorf("column %d: %w", fieldTok.pos, err)
+	}
+	return match, nil
+}
+
+// compileComparison builds the check for one field-op-value comparison
+func compileComparison(field, op, value string) (filterFunc, error) {
+	switch field {
+	case "priority":
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(value), "P"))
+		if err != nil {
+			return nil, fmt.Errorf("priority must be a number, got %q", value)
+		}
+		return func(p *PRD, _ time.Time) bool { return compareOrdered(p.Priority, op, n) }, nil
+
+	case "age":
+		if op == "=" || op == "!=" {
+			return nil, fmt.Errorf("age compares with <, <=, > or >=, not %s", op)
+		}
+		age, err := parseFilterAge(value)
+		if err != nil {
+			return nil, err
+		}
+		// PRDs without a creation time have no age to compare
+		return func(p *PRD, now time.Time) bool {
+			a, ok := p.Age(now)
+			return ok && compareOrdered(a, op, age)
+		}, nil
+	}
+
+	// The remaining fields are only equal or not
+	if op != "=" && op != "!=" {
+		return nil, fmt.Errorf("%s compares with = or !=, not %s", field, op)
+	}
+	want := op == "="
+
+	switch field {
+	case "status":
+		is, ok := filterStatuses[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("unknown status %q (open, active, pending, blocked or complete)", value)
+		}
+		return func(p *PRD, _ time.Time) bool { return is(&p.Passes) == want }, nil
+
+	case "tag":
+		return func(p *PRD, _ time.Time) bool {
+			return slices.ContainsFunc(p.Tags, func(t string) bool { return strings.EqualFold(t, value) }) == want
+		}, nil
+
+	case "owner":
+		return func(p *PRD, _ time.Time) bool { return (p.Owner == value) == want }, nil
+
+	default: // id, which may be a glob such as auth-*
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("invalid id pattern %q", value)
+		}
+		return func(p *PRD, _ time.Time) bool {
+			matched, _ := path.Match(value, p.ID)
+			return matched == want
+		}, nil
+	}
+}
+
+// compareOrdered applies a comparison operator to two values
+func compareOrdered[T int | time.Duration](a T, op string, b T) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+// parseFilterAge reads an age such as 3d, 2w or 36h
+func parseFilterAge(value string) (time.Duration, error) {
+	unit := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, scale := range unit {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			if count, err := strconv.Atoi(n); err == nil && count >= 0 {
+				return time.Duration(count) * scale, nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("age must be a duration such as 3d, 2w or 12h, got %q", value)
+}
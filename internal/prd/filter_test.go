@@ -0,0 +1,165 @@
+package prd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// filterPRDs is a fixture covering every field a filter can compare
+func filterPRDs(now time.Time) []PRD {
+	prds := []PRD{
+		{ID: "auth-1", Priority: 1, Owner: "alice", Tags: []string{"backend", "security"}, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+		{ID: "auth-2", Priority: 2, Tags: []string{"Backend"}, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "ui-1", Priority: 3, Owner: "bob", Tags: []string{"frontend"}, CreatedAt: now.Add(-3 * 24 * time.Hour)},
+		{ID: "ui-2", Priority: 2, Owner: "alice"},
+		{ID: "ops-1", Priority: 4, Tags: []string{"ops"}, CreatedAt: now.Add(-30 * 24 * time.Hour)},
+	}
+	prds[0].Passes.SetFalse()
+	prds[1].Passes.SetActive()
+	prds[2].Passes.SetPending()
+	prds[3].Passes.SetBlocked()
+	prds[4].Passes.SetTrue()
+	return prds
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	prds := filterPRDs(now)
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"priority<=2", []string{"auth-1", "auth-2", "ui-2"}},
+		{"priority = P2", []string{"auth-2", "ui-2"}},
+		{"priority==2", []string{"auth-2", "ui-2"}},
+		{"priority!=2", []string{"auth-1", "ui-1", "ops-1"}},
+		{"priority>3", []string{"ops-1"}},
+		{"priority >= 3", []string{"ui-1", "ops-1"}},
+		{"priority<2", []string{"auth-1"}},
+		{"status=open", []string{"auth-1"}},
+		{"status=ACTIVE", []string{"auth-2"}},
+		{"status!=complete", []string{"auth-1", "auth-2", "ui-1", "ui-2"}},
+		{"tag=backend", []string{"auth-1", "auth-2"}},
+		{"tags=security", []string{"auth-1"}},
+		{"tag!=backend", []string{"ui-1", "ui-2", "ops-1"}},
+		{"owner=alice", []string{"auth-1", "ui-2"}},
+		{`owner=""`, []string{"auth-2", "ops-1"}},
+		{"owner!=alice", []string{"auth-2", "ui-1", "ops-1"}},
+		{"age>7d", []string{"auth-1", "ops-1"}},
+		{"age<1d", []string{"auth-2"}},
+		{"age>=2w", []string{"ops-1"}},
+		{"age<=72h", []string{"auth-2", "ui-1"}},
+		{"id=auth-*", []string{"auth-1", "auth-2"}},
+		{"id!=ui-?", []string{"auth-1", "auth-2", "ops-1"}},
+		{`id="ops-1"`, []string{"ops-1"}},
+		{"priority<=2 && tag=backend && status=open", []string{"auth-1"}},
+		{"tag=frontend || tag=ops", []string{"ui-1", "ops-1"}},
+		{"!tag=backend", []string{"ui-1", "ui-2", "ops-1"}},
+		{"!!tag=backend", []string{"auth-1", "auth-2"}},
+		{"!(status=complete || status=blocked)", []string{"auth-1", "auth-2", "ui-1"}},
+
+		// && binds tighter than ||
+		{"tag=ops || tag=backend && owner=alice", []string{"auth-1", "ops-1"}},
+		{"tag=backend && owner=alice || tag=ops", []string{"auth-1", "ops-1"}},
+		{"(tag=ops || tag=backend) && owner=alice", []string{"auth-1"}},
+		{"owner=alice && (priority=1 || status=blocked)", []string{"auth-1", "ui-2"}},
+		// ! binds tighter than &&
+		{"!owner=alice && priority<=3", []string{"auth-2", "ui-1"}},
+		{"((priority=1))", []string{"auth-1"}},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseFilter(%q) error = %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, p := range f.Apply(prds, now) {
+			got = append(got, p.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "empty expression"},
+		{"   ", "empty expression"},
+		{"prio<=2", `column 1: unknown field "prio"`},
+		{"priority", "column 9: expected a comparison after priority but found end of expression"},
+		{"priority<=", "column 11: expected a value after priority<= but found end of expression"},
+		{"priority<=two", "priority must be a number"},
+		{"status<open", "status compares with = or !=, not <"},
+		{"status=done", `unknown status "done"`},
+		{"age=3d", "age compares with <, <=, > or >=, not ="},
+		{"age>3 days", "age must be a duration"},
+		{"age>-2d", "age must be a duration"},
+		{"id=auth-[", `invalid id pattern "auth-["`},
+		{"tag=backend & priority=1", `column 13: '&' is not an operator, use &&`},
+		{"tag=backend | tag=ops", `'|' is not an operator, use ||`},
+		{`owner="alice`, "column 7: unterminated string"},
+		{"tag=a && ", "column 10: expected a field but found end of expression"},
+		{"&& tag=a", `column 1: expected a field but found "&&"`},
+		{"(tag=a || tag=b", "column 16: expected ) but found end of expression"},
+		{"tag=a)", `column 6: unexpected ")"`},
+		{"tag=a tag=b", `column 7: unexpected "tag"`},
+		{"()", `column 2: expected a field but found ")"`},
+		{"=2", `column 1: expected a field but found "="`},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err == nil {
+			t.Errorf("ParseFilter(%q) = %v, want an error", tt.expr, f)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "invalid PRD filter: ") || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseFilter(%q) error = %q, want it to contain %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestScopeFilter(t *testing.T) {
+	f, err := ParseFilter("tag=backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := &PRDFileData{PRDs: []PRD{
+		{ID: "a-1", Priority: 1, Tags: []string{"backend"}},
+		{ID: "a-2", Priority: 3, Tags: []string{"backend"}},
+		{ID: "b-1", Priority: 1, Tags: []string{"frontend"}},
+	}}
+	for i := range data.PRDs {
+		data.PRDs[i].Passes.SetFalse()
+	}
+
+	// Shortcut flags and the filter must all allow a PRD
+	scope := Scope{MaxPriority: 2, Filter: f}
+	open := data.GetOpenPRDsIn(scope)
+	if len(open) != 1 || open[0].ID != "a-1" {
+		t.Errorf("GetOpenPRDsIn(%s) = %v, want only a-1", scope, open)
+	}
+	if got := scope.String(); got != `priority <= P2, filter "tag=backend"` {
+		t.Errorf("String() = %q", got)
+	}
+	if err := ValidateScope(data, "b-1", scope); err == nil || !strings.Contains(err.Error(), `does not match filter "tag=backend"`) {
+		t.Errorf("ValidateScope(b-1) error = %v", err)
+	}
+	if err := ValidateScope(data, "a-1", scope); err != nil {
+		t.Errorf("ValidateScope(a-1) error = %v", err)
+	}
+
+	var none *Filter
+	if !none.Match(&data.PRDs[2], time.Now()) || none.String() != "" {
+		t.Error("a nil filter should match everything")
+	}
+}
@@ -0,0 +1,220 @@
+package prd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph output formats
+const (
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+)
+
+// statusColors fills graph nodes by status
+var statusColors = map[string]string{
+	StatusOpen:     "#eeeeee",
+	StatusActive:   "#9ecbff",
+	StatusPending:  "#ffe08a",
+	StatusBlocked:  "#ff9e9e",
+	StatusComplete: "#a8e6a1",
+}
+
+// cycleColor marks dependency edges that form a cycle
+const cycleColor = "#d32f2f"
+
+// graphEdge is a dependency: From must be done before To
+type graphEdge struct {
+	From, To string
+}
+
+// DependencyCycles returns every group of PRDs that depend on each other in a
+// cycle, each sorted, including PRDs that depend on themselves
+func DependencyCycles(prdFile *PRDFileData) [][]string {
+	deps := make(map[string][]string)
+	var ids []string
+	for _, p := range prdFile.PRDs {
+		if _, seen := deps[p.ID]; !seen {
+			ids = append(ids, p.ID)
+		}
+		deps[p.ID] = append(deps[p.ID], p.DependsOn...)
+	}
+
+	// Tarjan's strongly connected components
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+
+		selfLoop := false
+		for _, dep := range deps[id] {
+			if dep == id {
+				selfLoop = true
+			}
+			if _, known := deps[dep]; !known {
+				continue
+			}
+			if _, visited := index[dep]; !visited {
+				visit(dep)
+				low[id] = min(low[id], low[dep])
+			} else if onStack[dep] {
+				low[id] = min(low[id], index[dep])
+			}
+		}
+
+		if low[id] != index[id] {
+			return
+		}
+		var group []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			group = append(group, top)
+			if top == id {
+				break
+			}
+		}
+		if len(group) > 1 || selfLoop {
+			sort.Strings(group)
+			cycles = append(cycles, group)
+		}
+	}
+
+	for _, id := range ids {
+		if _, visited := index[id]; !visited {
+			visit(id)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// Graph renders the PRD dependency graph as Graphviz DOT or Mermaid source
+// Nodes are filled by status; edges point from a dependency to the PRD that
+// needs it, and edges inside a cycle are drawn in red. Dependencies on
+// unknown IDs appear as dashed "missing" nodes.
+func Graph(prdFile *PRDFileData, format string) (string, error) {
+	inCycle := make(map[string]int) // PRD ID -> cycle group
+	for i, group := range DependencyCycles(prdFile) {
+		for _, id := range group {
+			inCycle[id] = i + 1
+		}
+	}
+	isCycleEdge := func(e graphEdge) bool {
+		return inCycle[e.From] != 0 && inCycle[e.From] == inCycle[e.To]
+	}
+
+	var edges []graphEdge
+	var missing []string
+	seenMissing := make(map[string]bool)
+	for _, p := range prdFile.PRDs {
+		for _, dep := range p.DependsOn {
+			edges = append(edges, graphEdge{From: dep, To: p.ID})
+			if prdFile.FindByID(dep) == nil && !seenMissing[dep] {
+				seenMissing[dep] = true
+				missing = append(missing, dep)
+			}
+		}
+	}
+
+	switch format {
+	case GraphDOT, "":
+		return graphDOT(prdFile, edges, missing, inCycle, isCycleEdge), nil
+	case GraphMermaid:
+		return graphMermaid(prdFile, edges, missing, inCycle, isCycleEdge), nil
+	default:
+		return "", fmt.Errorf("unknown graph format %q (use %s or %s)", format, GraphDOT, GraphMermaid)
+	}
+}
+
+func graphDOT(prdFile *PRDFileData, edges []graphEdge, missing []string, inCycle map[string]int, isCycleEdge func(graphEdge) bool) string {
+	var sb strings.Builder
+	sb.WriteString("digraph prds {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\"];\n")
+
+	for _, p := range prdFile.PRDs {
+		status := p.Passes.String()
+		attrs := fmt.Sprintf("label=%q, fillcolor=%q", fmt.Sprintf("%s\n%s", p.ID, status), statusColors[status])
+		if inCycle[p.ID] != 0 {
+			attrs += fmt.Sprintf(", color=%q, penwidth=2", cycleColor)
+		}
+		fmt.Fprintf(&sb, "  %q [%s];\n", p.ID, attrs)
+	}
+	for _, id := range missing {
+		fmt.Fprintf(&sb, "  %q [label=%q, style=dashed];\n", id, id+"\nmissing")
+	}
+
+	for _, e := range edges {
+		if isCycleEdge(e) {
+			fmt.Fprintf(&sb, "  %q -> %q [color=%q, penwidth=2];\n", e.From, e.To, cycleColor)
+		} else {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", e.From, e.To)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func graphMermaid(prdFile *PRDFileData, edges []graphEdge, missing []string, inCycle map[string]int, isCycleEdge func(graphEdge) bool) string {
+	// Mermaid node IDs are restricted, so PRDs get positional ones
+	nodeID := make(map[string]string)
+	node := func(id string) string {
+		if n, ok := nodeID[id]; ok {
+			return n
+		}
+		nodeID[id] = fmt.Sprintf("n%d", len(nodeID))
+		return nodeID[id]
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, status := range []string{StatusOpen, StatusActive, StatusPending, StatusBlocked, StatusComplete} {
+		fmt.Fprintf(&sb, "  classDef %s fill:%s,stroke:#555\n", status, statusColors[status])
+	}
+	fmt.Fprintf(&sb, "  classDef missing fill:#fff,stroke:#999,stroke-dasharray:4\n")
+	fmt.Fprintf(&sb, "  classDef cycle stroke:%s,stroke-width:3px\n", cycleColor)
+
+	for _, p := range prdFile.PRDs {
+		status := p.Passes.String()
+		fmt.Fprintf(&sb, "  %s[\"%s<br/>%s\"]:::%s\n", node(p.ID), mermaidText(p.ID), status, status)
+	}
+	for _, id := range missing {
+		fmt.Fprintf(&sb, "  %s[\"%s<br/>missing\"]:::missing\n", node(id), mermaidText(id))
+	}
+
+	var cycleLinks []string
+	for i, e := range edges {
+		fmt.Fprintf(&sb, "  %s --> %s\n", node(e.From), node(e.To))
+		if isCycleEdge(e) {
+			cycleLinks = append(cycleLinks, fmt.Sprint(i))
+		}
+	}
+
+	var cycleNodes []string
+	for _, p := range prdFile.PRDs {
+		if inCycle[p.ID] != 0 {
+			cycleNodes = append(cycleNodes, node(p.ID))
+		}
+	}
+	if len(cycleNodes) > 0 {
+		fmt.Fprintf(&sb, "  class %s cycle\n", strings.Join(cycleNodes, ","))
+		fmt.Fprintf(&sb, "  linkStyle %s stroke:%s,stroke-width:3px\n", strings.Join(cycleLinks, ","), cycleColor)
+	}
+	return sb.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,74 @@
+package prd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func graphFixture() *PRDFileData {
+	return &PRDFileData{PRDs: []PRD{
+		{ID: "schema", Passes: PassesStatus{Value: true}},
+		{ID: "api", Passes: PassesStatus{Value: "active"}, DependsOn: []string{"schema"}},
+		{ID: "ui", Passes: PassesStatus{Value: false}, DependsOn: []string{"api", "design"}},
+		{ID: "a", Passes: PassesStatus{Value: false}, DependsOn: []string{"b"}},
+		{ID: "b", Passes: PassesStatus{Value: false}, DependsOn: []string{"a"}},
+		{ID: "self", Passes: PassesStatus{Value: false}, DependsOn: []string{"self"}},
+	}}
+}
+
+func TestDependencyCycles(t *testing.T) {
+	got := DependencyCycles(graphFixture())
+	want := [][]string{{"a", "b"}, {"self"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cycles = %v, want %v", got, want)
+	}
+	if cycles := DependencyCycles(&PRDFileData{PRDs: graphFixture().PRDs[:3]}); len(cycles) != 0 {
+		t.Errorf("acyclic graph reported cycles: %v", cycles)
+	}
+}
+
+func TestGraphDOT(t *testing.T) {
+	out, err := Graph(graphFixture(), GraphDOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"digraph prds {",
+		`"api" [label="api\nactive", fillcolor="#9ecbff"];`,
+		`"schema" -> "api";`,
+		`"design" [label="design\nmissing", style=dashed];`,
+		`"a" [label="a\nopen", fillcolor="#eeeeee", color="#d32f2f", penwidth=2];`,
+		`"b" -> "a" [color="#d32f2f", penwidth=2];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"api" -> "ui" [color`) {
+		t.Error("edge outside a cycle drawn as a cycle")
+	}
+}
+
+func TestGraphMermaid(t *testing.T) {
+	out, err := Graph(graphFixture(), GraphMermaid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"flowchart LR",
+		`n0["schema<br/>complete"]:::complete`,
+		"n0 --> n1",
+		`["design<br/>missing"]:::missing`,
+		"class n3,n4,n5 cycle",
+		"linkStyle 3,4,5 stroke:#d32f2f",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := Graph(graphFixture(), "svg"); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
@@ -0,0 +1,83 @@
+package prd
+
+// PRDGroups holds a prd.json's PRDs split by status
+// The slices are copies taken when Group ran, so group again after changing
+// the PRDs rather than keeping a PRDGroups across a mutation.
+type PRDGroups struct {
+	Open     []PRD // passes=false
+	Active   []PRD // passes="active"
+	Pending  []PRD // passes="pending"
+	Blocked  []PRD // passes="blocked"
+	Complete []PRD // passes=true
+}
+
+// Group sorts every PRD into its status group
+// Use it where several of the Get*PRDs methods would otherwise each scan the
+// whole backlog and regrow their slices: statuses are classified once, and
+// each group is allocated at its final size before any PRD is copied.
+func (p *PRDFileData) Group() PRDGroups {
+	var g PRDGroups
+	groups := [...]*[]PRD{&g.Open, &g.Active, &g.Pending, &g.Blocked, &g.Complete}
+
+	kinds := make([]int, len(p.PRDs)) // Index into groups, -1 for none
+	var sizes [len(groups)]int
+	for i := range p.PRDs {
+		kinds[i] = statusGroup(&p.PRDs[i].Passes)
+		if kinds[i] >= 0 {
+			sizes[kinds[i]]++
+		}
+	}
+	for k, group := range groups {
+		*group = make([]PRD, 0, sizes[k])
+	}
+	for i, k := range kinds {
+		if k >= 0 {
+			*groups[k] = append(*groups[k], p.PRDs[i])
+		}
+	}
+	return g
+}
+
+// statusGroup returns the index of the PRDGroups field a status belongs in,
+// in field order, or -1 for an unrecognized status
+func statusGroup(passes *PassesStatus) int {
+	switch {
+	case passes.IsFalse():
+		return 0
+	case passes.IsActive():
+		return 1
+	case passes.IsPending():
+		return 2
+	case passes.IsBlocked():
+		return 3
+	case passes.IsTrue():
+		return 4
+	}
+	return -1
+}
+
+// OpenIn returns the open PRDs within scope
+func (g PRDGroups) OpenIn(scope Scope) []PRD {
+	return within(g.Open, scope)
+}
+
+// ActiveIn returns the active PRDs within scope
+func (g PRDGroups) ActiveIn(scope Scope) []PRD {
+	return within(g.Active, scope)
+}
+
+// PendingIn returns the pending PRDs within scope
+func (g PRDGroups) PendingIn(scope Scope) []PRD {
+	return within(g.Pending, scope)
+}
+
+// within returns the PRDs scope allows, in order
+func within(prds []PRD, scope Scope) []PRD {
+	var allowed []PRD
+	for i := range prds {
+		if scope.Allows(&prds[i]) {
+			allowed = append(allowed, prds[i])
+		}
+	}
+	return allowed
+}
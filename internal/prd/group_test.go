@@ -0,0 +1,82 @@
+package prd
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// largeBacklog returns n PRDs spread evenly over every status
+func largeBacklog(n int) *PRDFileData {
+	statuses := []interface{}{false, "active", "pending", "blocked", true}
+	data := &PRDFileData{PRDs: make([]PRD, n)}
+	for i := range data.PRDs {
+		data.PRDs[i] = PRD{
+			ID:       fmt.Sprintf("prd-%d", i),
+			Priority: i%4 + 1,
+			Passes:   PassesStatus{Value: statuses[i%len(statuses)]},
+		}
+		if i%3 == 0 {
+			data.PRDs[i].Owner = "alice"
+		}
+	}
+	return data
+}
+
+func TestGroupMatchesGetters(t *testing.T) {
+	data := largeBacklog(103)
+	g := data.Group()
+
+	checks := []struct {
+		name      string
+		got, want []PRD
+	}{
+		{"Open", g.Open, data.GetOpenPRDs()},
+		{"Active", g.Active, data.GetActivePRDs()},
+		{"Pending", g.Pending, data.GetPendingPRDs()},
+		{"Blocked", g.Blocked, data.GetBlockedPRDs()},
+		{"Complete", g.Complete, data.GetCompletePRDs()},
+	}
+	total := 0
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("Group().%s has %d PRDs, want the %d the getter returns", c.name, len(c.got), len(c.want))
+		}
+		total += len(c.got)
+	}
+	if total != len(data.PRDs) {
+		t.Errorf("groups hold %d PRDs, want all %d", total, len(data.PRDs))
+	}
+
+	scope := Scope{Owner: "bob", MaxPriority: 2}
+	if got, want := g.OpenIn(scope), data.GetOpenPRDsIn(scope); !reflect.DeepEqual(got, want) {
+		t.Errorf("OpenIn() = %d PRDs, want %d", len(got), len(want))
+	}
+	if got, want := g.ActiveIn(scope), data.GetActivePRDsIn(scope); !reflect.DeepEqual(got, want) {
+		t.Errorf("ActiveIn() = %d PRDs, want %d", len(got), len(want))
+	}
+	if got, want := g.PendingIn(scope), data.GetPendingPRDsIn(scope); !reflect.DeepEqual(got, want) {
+		t.Errorf("PendingIn() = %d PRDs, want %d", len(got), len(want))
+	}
+}
+
+// The run loop and status ask for several groups at once
+func BenchmarkStatusGetters(b *testing.B) {
+	data := largeBacklog(5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = data.GetOpenPRDs()
+		_ = data.GetActivePRDs()
+		_ = data.GetPendingPRDs()
+		_ = data.GetBlockedPRDs()
+		_ = data.GetCompletePRDs()
+	}
+}
+
+func BenchmarkGroup(b *testing.B) {
+	data := largeBacklog(5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = data.Group()
+	}
+}
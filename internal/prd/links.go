@@ -0,0 +1,127 @@
+package prd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MetadataLinks is the metadata key a PRD's external links are stored under
+const MetadataLinks = "links"
+
+// Link ties a PRD to an external ticket or issue
+type Link struct {
+	Type string `json:"type"` // e.g. jira, github, linear; "link" when unknown
+	URL  string `json:"url"`
+}
+
+// linkHosts infers a link type from well-known tracker hosts
+var linkHosts = []struct {
+	suffix, linkType string
+}{
+	{"atlassian.net", "jira"},
+	{"github.com", "github"},
+	{"gitlab.com", "gitlab"},
+	{"linear.app", "linear"},
+	{"shortcut.com", "shortcut"},
+}
+
+// ValidateLinkURL checks that raw is an absolute http or https URL
+func ValidateLinkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid URL %q: must start with http:// or https://", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL %q: no host", raw)
+	}
+	return nil
+}
+
+// LinkType guesses the tracker behind a URL from its host, or returns "link"
+func LinkType(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "link"
+	}
+	host := strings.ToLower(u.Hostname())
+	if strings.HasPrefix(host, "jira.") {
+		return "jira"
+	}
+	for _, h := range linkHosts {
+		if host == h.suffix || strings.HasSuffix(host, "."+h.suffix) {
+			return h.linkType
+		}
+	}
+	return "link"
+}
+
+// Links returns the PRD's external links
+// Malformed link metadata (e.g. hand-edited) yields no links rather than an error.
+func (p *PRD) Links() []Link {
+	raw, ok := p.Metadata[MetadataLinks]
+	if !ok {
+		return nil
+	}
+	if links, ok := raw.([]Link); ok {
+		return links
+	}
+	// Loaded from prd.json as generic JSON values
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var links []Link
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil
+	}
+	return links
+}
+
+// AddLink records an external link, returning false when the URL is already linked
+func (p *PRD) AddLink(link Link) bool {
+	links := p.Links()
+	for _, existing := range links {
+		if existing.URL == link.URL {
+			return false
+		}
+	}
+	p.setLinks(append(links, link))
+	return true
+}
+
+// RemoveLinks drops links whose URL or type matches target and returns how
+// many were removed
+func (p *PRD) RemoveLinks(target string) int {
+	links := p.Links()
+	kept := make([]Link, 0, len(links))
+	for _, link := range links {
+		if link.URL != target && !strings.EqualFold(link.Type, target) {
+			kept = append(kept, link)
+		}
+	}
+	if removed := len(links) - len(kept); removed > 0 {
+		p.setLinks(kept)
+		return removed
+	}
+	return 0
+}
+
+// setLinks stores links in metadata, removing the key (and an emptied map) when there are none
+func (p *PRD) setLinks(links []Link) {
+	if len(links) == 0 {
+		delete(p.Metadata, MetadataLinks)
+		if len(p.Metadata) == 0 {
+			p.Metadata = nil
+		}
+		return
+	}
+	if p.Metadata == nil {
+		p.Metadata = make(map[string]any)
+	}
+	p.Metadata[MetadataLinks] = links
+}
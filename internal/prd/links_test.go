@@ -0,0 +1,61 @@
+package prd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	for raw, wantErr := range map[string]bool{
+		"https://acme.atlassian.net/browse/AUTH-12": false,
+		"http://localhost:8080/issues/3":            false,
+		"ftp://example.com/x":                       true,
+		"example.com/issue/1":                       true,
+		"https://":                                  true,
+	} {
+		if err := ValidateLinkURL(raw); (err != nil) != wantErr {
+			t.Errorf("ValidateLinkURL(%q) = %v, want error %v", raw, err, wantErr)
+		}
+	}
+	for raw, want := range map[string]string{
+		"https://acme.atlassian.net/browse/AUTH-12": "jira",
+		"https://jira.acme.com/browse/AUTH-12":      "jira",
+		"https://github.com/acme/shop/issues/4":     "github",
+		"https://linear.app/acme/issue/ENG-9":       "linear",
+		"https://tracker.example.com/ticket/1":      "link",
+	} {
+		if got := LinkType(raw); got != want {
+			t.Errorf("LinkType(%q) = %q, want %q", raw, got, want)
+		}
+	}
+
+	p := PRD{ID: "auth-1", Metadata: map[string]any{"points": 3}}
+	jira := Link{Type: "jira", URL: "https://acme.atlassian.net/browse/AUTH-12"}
+	gh := Link{Type: "github", URL: "https://github.com/acme/shop/issues/4"}
+	if !p.AddLink(jira) || !p.AddLink(gh) || p.AddLink(jira) {
+		t.Fatal("AddLink should accept two new URLs and refuse a duplicate")
+	}
+
+	// Links survive a JSON round trip as generic metadata
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded PRD
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if links := loaded.Links(); len(links) != 2 || links[0] != jira || links[1] != gh {
+		t.Errorf("Links() after round trip = %+v", links)
+	}
+
+	if n := loaded.RemoveLinks("GitHub"); n != 1 || len(loaded.Links()) != 1 {
+		t.Errorf("RemoveLinks(type) removed %d, left %+v", n, loaded.Links())
+	}
+	if n := loaded.RemoveLinks(jira.URL); n != 1 || loaded.Metadata[MetadataLinks] != nil || loaded.Metadata["points"] == nil {
+		t.Errorf("RemoveLinks(url) removed %d, metadata %+v", n, loaded.Metadata)
+	}
+	if n := loaded.RemoveLinks("nothing"); n != 0 {
+		t.Errorf("RemoveLinks of an unknown target removed %d", n)
+	}
+}
@@ -0,0 +1,99 @@
+package prd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Severity ranks a lint issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // prd.json is inconsistent; agents will misbehave
+	SeverityWarning Severity = "warning" // Likely a mistake, but runs still work
+)
+
+// LintIssue is a single integrity problem in the PRD backlog
+type LintIssue struct {
+	Severity Severity
+	PRDID    string // Empty for file-level issues
+	Message  string
+}
+
+// String formats the issue as "<id>: <message>"
+func (i LintIssue) String() string {
+	if i.PRDID == "" {
+		return i.Message
+	}
+	return i.PRDID + ": " + i.Message
+}
+
+// validID matches IDs that are safe in file names and agent signals
+var validID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Lint runs every PRD integrity check and returns all problems found,
+// in prd.json order with file-level issues last
+func Lint(prdFile *PRDFileData, basePath string) []LintIssue {
+	var issues []LintIssue
+	add := func(sev Severity, id, format string, args ...any) {
+		issues = append(issues, LintIssue{Severity: sev, PRDID: id, Message: fmt.Sprintf(format, args...)})
+	}
+
+	seen := make(map[string]int)
+	for i, p := range prdFile.PRDs {
+		id := p.ID
+		switch {
+		case strings.TrimSpace(id) == "":
+			id = fmt.Sprintf("#%d", i+1)
+			add(SeverityError, id, "missing id")
+		case !validID.MatchString(id):
+			add(SeverityError, id, "id may only contain letters, digits, '.', '_' and '-'")
+		}
+
+		if p.ID != "" {
+			seen[p.ID]++
+			if seen[p.ID] == 2 {
+				add(SeverityError, id, "duplicate id")
+			}
+		}
+
+		if s, ok := p.Passes.Value.(string); ok && !p.Passes.IsKnown() {
+			add(SeverityError, id, "unknown status %q is never selected", s)
+		}
+
+		if strings.TrimSpace(p.Description) == "" {
+			add(SeverityError, id, "empty description")
+		}
+		if len(p.AcceptanceCriteria) == 0 {
+			add(SeverityWarning, id, "no acceptance criteria")
+		}
+
+		for _, dep := range p.DependsOn {
+			if prdFile.FindByID(dep) == nil {
+				add(SeverityError, id, "depends on unknown PRD %s", dep)
+			}
+		}
+
+		if p.Passes.IsPending() && p.ID != "" {
+			if _, err := os.Stat(GetEvidencePath(basePath, p.ID)); err != nil {
+				add(SeverityWarning, id, "pending without an evidence file")
+			}
+		}
+	}
+
+	for _, cycle := range DependencyCycles(prdFile) {
+		add(SeverityError, "", "dependency cycle: %s", strings.Join(cycle, ", "))
+	}
+
+	stale, err := prdFile.StalePlans(basePath)
+	if err != nil {
+		add(SeverityError, "", "plans: %v", err)
+	}
+	for _, id := range stale {
+		add(SeverityWarning, id, "orphaned plan file (run 'mil plan prune')")
+	}
+
+	return issues
+}
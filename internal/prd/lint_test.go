@@ -0,0 +1,56 @@
+package prd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsurePlansDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetPlanPath(dir, "gone"), []byte("plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	criteria := []string{"works"}
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "ok", Description: "fine", AcceptanceCriteria: criteria},
+		{ID: "ok", Description: "dup", AcceptanceCriteria: criteria},
+		{ID: "", Description: "no id", AcceptanceCriteria: criteria},
+		{ID: "bad id:1", Description: "spaces", AcceptanceCriteria: criteria},
+		{ID: "blank", Description: "  ", AcceptanceCriteria: criteria},
+		{ID: "vague", Description: "no criteria"},
+		{ID: "waiting", Description: "pending", AcceptanceCriteria: criteria, Passes: PassesStatus{Value: "pending"}},
+		{ID: "typo", Description: "typo", AcceptanceCriteria: criteria, Passes: PassesStatus{Value: "done"}},
+		{ID: "x", Description: "cycle", AcceptanceCriteria: criteria, DependsOn: []string{"y", "nowhere"}},
+		{ID: "y", Description: "cycle", AcceptanceCriteria: criteria, DependsOn: []string{"x"}},
+	}}
+
+	got := make(map[string]Severity)
+	for _, issue := range Lint(prdFile, dir) {
+		got[issue.String()] = issue.Severity
+	}
+
+	want := map[string]Severity{
+		"ok: duplicate id": SeverityError,
+		"#3: missing id":   SeverityError,
+		"bad id:1: id may only contain letters, digits, '.', '_' and '-'": SeverityError,
+		"blank: empty description":                        SeverityError,
+		"vague: no acceptance criteria":                   SeverityWarning,
+		"waiting: pending without an evidence file":       SeverityWarning,
+		`typo: unknown status "done" is never selected`:   SeverityError,
+		"gone: orphaned plan file (run 'mil plan prune')": SeverityWarning,
+		"x: depends on unknown PRD nowhere":               SeverityError,
+		"dependency cycle: x, y":                          SeverityError,
+	}
+	for msg, sev := range want {
+		if got[msg] != sev {
+			t.Errorf("missing %s issue %q", sev, msg)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Lint() = %v, want %d issues", got, len(want))
+	}
+}
@@ -6,16 +6,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 )
 
 const (
-	MillhouseDir = ".milhouse"
-	PRDFile      = "prd.json"
-	ProgressFile = "progress.md"
-	PromptFile   = "prompt.md"
-	EvidenceDir  = "evidence"
-	PlansDir     = "plans"
-	PromptsDir   = "prompts"
+	MillhouseDir   = ".milhouse"
+	PRDFile        = "prd.json"
+	ProgressFile   = "progress.md"
+	PromptFile     = "prompt.md"
+	EvidenceDir    = "evidence"
+	PlansDir       = "plans"
+	PromptsDir     = "prompts"
+	EventsFile     = "events.ndjson"
+	ArchiveDir     = "archive"
+	ChatHistoryDir = "chat-history"
+
+	// MaxHistory caps the number of transitions kept per PRD
+	MaxHistory = 50
+)
+
+// Status names used by Transition
+const (
+	StatusOpen     = "open"
+	StatusActive   = "active"
+	StatusPending  = "pending"
+	StatusComplete = "complete"
+	StatusBlocked  = "blocked"
 )
 
 // PassesStatus represents the quad-state passes field
@@ -23,8 +42,9 @@ const (
 // "active" = planner selected, has plan, builder working on it
 // "pending" = builder claims complete, awaiting reviewer
 // true = reviewer confirmed complete
+// "blocked" = needs refinement by a human before it can be selected
 type PassesStatus struct {
-	Value interface{} // bool or string "active"/"pending"
+	Value interface{} // bool or string "active"/"pending"/"blocked"
 }
 
 func (p *PassesStatus) IsFalse() bool {
@@ -48,6 +68,13 @@ func (p *PassesStatus) IsPending() bool {
 	return false
 }
 
+func (p *PassesStatus) IsBlocked() bool {
+	if s, ok := p.Value.(string); ok {
+		return s == "blocked"
+	}
+	return false
+}
+
 func (p *PassesStatus) IsTrue() bool {
 	if b, ok := p.Value.(bool); ok {
 		return b
@@ -55,6 +82,12 @@ func (p *PassesStatus) IsTrue() bool {
 	return false
 }
 
+// IsKnown reports whether the value is one of the five statuses
+func (p *PassesStatus) IsKnown() bool {
+	_, isBool := p.Value.(bool)
+	return isBool || p.IsActive() || p.IsPending() || p.IsBlocked()
+}
+
 func (p *PassesStatus) SetFalse() {
 	p.Value = false
 }
@@ -67,6 +100,10 @@ func (p *PassesStatus) SetPending() {
 	p.Value = "pending"
 }
 
+func (p *PassesStatus) SetBlocked() {
+	p.Value = "blocked"
+}
+
 func (p *PassesStatus) SetTrue() {
 	p.Value = true
 }
@@ -75,6 +112,12 @@ func (p PassesStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(p.Value)
 }
 
+// MarshalText renders the canonical status name, e.g. for logs and YAML
+// JSON encoding still uses MarshalJSON and keeps the raw bool/string value.
+func (p PassesStatus) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
 func (p *PassesStatus) UnmarshalJSON(data []byte) error {
 	// Try unmarshaling as bool first
 	var b bool
@@ -98,18 +141,269 @@ func (p *PassesStatus) UnmarshalJSON(data []byte) error {
 
 // PRD represents a single Product Requirements Document
 type PRD struct {
-	ID                 string       `json:"id"`
-	Description        string       `json:"description"`
-	AcceptanceCriteria []string     `json:"acceptanceCriteria"`
-	Priority           int          `json:"priority"`
-	Passes             PassesStatus `json:"passes"`
-	Notes              string       `json:"notes"`
-	ActivePlan         string       `json:"activePlan,omitempty"` // Path to plan file when active
+	ID                 string           `json:"id"`
+	Description        string           `json:"description"`
+	AcceptanceCriteria []string         `json:"acceptanceCriteria"`
+	Priority           int              `json:"priority"`
+	Passes             PassesStatus     `json:"passes"`
+	Notes              string           `json:"notes"`
+	Owner              string           `json:"owner,omitempty"`         // Who may run this PRD; empty means anyone
+	Tags               []string         `json:"tags,omitempty"`          // Labels for --prd-filter, e.g. backend
+	ModelOverride      string           `json:"modelOverride,omitempty"` // Builder model for this PRD (set by escalation)
+	ActivePlan         string           `json:"activePlan,omitempty"`    // Path to plan file when active
+	Subtasks           []Subtask        `json:"subtasks,omitempty"`      // Optional checklist for large PRDs
+	DependsOn          []string         `json:"dependsOn,omitempty"`     // IDs of PRDs this one builds on
+	SplitFrom          string           `json:"splitFrom,omitempty"`     // Parent PRD this one was split out of
+	Approach           *PlannedApproach `json:"approach,omitempty"`      // The latest plan's approach, files and risk, from PLAN_META
+	CreatedAt          time.Time        `json:"createdAt,omitzero"`      // When the PRD was added; zero for older PRDs
+	History            []Transition     `json:"history,omitempty"`       // Status changes, oldest first
+	Metadata           map[string]any   `json:"metadata,omitempty"`      // Free-form data for people and integrations, e.g. a ticket ID
+
+	// Extra holds fields Millhouse does not know, written back unchanged on save
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// Age returns how long the PRD has existed, or false when its creation time
+// is unknown
+func (p *PRD) Age(now time.Time) (time.Duration, bool) {
+	if p.CreatedAt.IsZero() {
+		return 0, false
+	}
+	return now.Sub(p.CreatedAt), true
+}
+
+// SortByAge orders PRDs oldest first; PRDs without CreatedAt go last,
+// keeping their relative order
+func SortByAge(prds []PRD) {
+	sort.SliceStable(prds, func(i, j int) bool {
+		a, b := prds[i].CreatedAt, prds[j].CreatedAt
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+}
+
+// Subtask is one trackable step of a large PRD
+type Subtask struct {
+	Description string `json:"description"`
+	Done        bool   `json:"done"`
+}
+
+// SubtaskProgress returns how many subtasks are done out of the total
+func (p *PRD) SubtaskProgress() (done, total int) {
+	for _, s := range p.Subtasks {
+		if s.Done {
+			done++
+		}
+	}
+	return done, len(p.Subtasks)
+}
+
+// CompleteSubtask marks the nth subtask (1-based) done, reporting whether
+// it changed
+func (p *PRD) CompleteSubtask(n int) (bool, error) {
+	if n < 1 || n > len(p.Subtasks) {
+		return false, fmt.Errorf("PRD %s has no subtask %d (has %d)", p.ID, n, len(p.Subtasks))
+	}
+	if p.Subtasks[n-1].Done {
+		return false, nil
+	}
+	p.Subtasks[n-1].Done = true
+	return true, nil
+}
+
+// Transition records a single status change of a PRD
+type Transition struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	At        time.Time `json:"at"`
+	Iteration int       `json:"iteration,omitempty"` // 0 when changed outside a run
+	Commit    string    `json:"commit,omitempty"`    // Checkpoint commit made after completion
+}
+
+// RecordCommit attaches a checkpoint commit SHA to the PRD's latest
+// transition to complete, reporting false when there is none
+func (p *PRD) RecordCommit(sha string) bool {
+	for i := len(p.History) - 1; i >= 0; i-- {
+		if p.History[i].To == StatusComplete {
+			p.History[i].Commit = sha
+			return true
+		}
+	}
+	return false
+}
+
+// validTransitions lists the allowed state changes for each status
+var validTransitions = map[string][]string{
+	StatusOpen:     {StatusActive, StatusBlocked},
+	StatusActive:   {StatusPending, StatusOpen, StatusBlocked},
+	StatusPending:  {StatusComplete, StatusOpen, StatusActive},
+	StatusComplete: {StatusOpen},
+	StatusBlocked:  {StatusOpen},
+}
+
+// String returns the canonical status name: open, active, pending, complete or blocked
+// Unrecognized values display as open, but selection skips them since only a
+// false value is open to agents; mil doctor reports unrecognized strings.
+func (p PassesStatus) String() string {
+	switch {
+	case p.IsTrue():
+		return StatusComplete
+	case p.IsPending():
+		return StatusPending
+	case p.IsActive():
+		return StatusActive
+	case p.IsBlocked():
+		return StatusBlocked
+	default:
+		return StatusOpen
+	}
+}
+
+// Transition moves the PRD to a new status, enforcing the state machine
+// Transitioning to the current status is a no-op. Moving to open or
+// complete (or blocked) clears the active plan reference. Each change is appended to
+// the PRD's history.
+func (p *PRD) Transition(to string, iteration int) error {
+	from := p.Passes.String()
+	if from == to {
+		return nil
+	}
+
+	allowed := false
+	for _, next := range validTransitions[from] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("invalid transition for PRD %s: %s -> %s", p.ID, from, to)
+	}
+
+	switch to {
+	case StatusOpen:
+		p.Passes.SetFalse()
+		p.ActivePlan = ""
+	case StatusActive:
+		p.Passes.SetActive()
+	case StatusPending:
+		p.Passes.SetPending()
+	case StatusComplete:
+		p.Passes.SetTrue()
+		p.ActivePlan = ""
+	case StatusBlocked:
+		p.Passes.SetBlocked()
+		p.ActivePlan = ""
+	}
+
+	p.appendHistory(from, to, iteration)
+	return nil
+}
+
+// appendHistory records a transition, dropping the oldest entries past MaxHistory
+func (p *PRD) appendHistory(from, to string, iteration int) {
+	p.History = append(p.History, Transition{
+		From:      from,
+		To:        to,
+		At:        time.Now(),
+		Iteration: iteration,
+	})
+	if len(p.History) > MaxHistory {
+		p.History = p.History[len(p.History)-MaxHistory:]
+	}
+}
+
+// AppendNote adds a line to the PRD's notes, keeping existing content
+func (p *PRD) AppendNote(note string) {
+	if p.Notes == "" {
+		p.Notes = note
+		return
+	}
+	p.Notes = strings.TrimRight(p.Notes, "\n") + "\n" + note
+}
+
+// CountTransitions returns how many recorded transitions went from one status to another
+func (p *PRD) CountTransitions(from, to string) int {
+	count := 0
+	for _, t := range p.History {
+		if t.From == from && t.To == to {
+			count++
+		}
+	}
+	return count
+}
+
+// Escalate sets model as the override for unfinished PRDs rejected at least
+// threshold times that have no override yet, returning the escalated IDs
+// A threshold of 0 disables escalation.
+func (p *PRDFileData) Escalate(threshold int, model string) []string {
+	if threshold <= 0 {
+		return nil
+	}
+
+	var escalated []string
+	for i := range p.PRDs {
+		prd := &p.PRDs[i]
+		if prd.Passes.IsTrue() || prd.ModelOverride != "" {
+			continue
+		}
+		rejections := prd.CountTransitions(StatusPending, StatusOpen)
+		if rejections < threshold {
+			continue
+		}
+		prd.ModelOverride = model
+		prd.AppendNote(fmt.Sprintf("[escalated] %d rejections; builder now uses %s", rejections, model))
+		escalated = append(escalated, prd.ID)
+	}
+	return escalated
+}
+
+// RecordChanges appends history entries for PRDs whose status differs from prev
+// and sets CreatedAt on PRDs added since prev
+// Agents edit prd.json directly, so this captures transitions they make.
+// Returns true if anything was recorded.
+func (p *PRDFileData) RecordChanges(prev *PRDFileData, iteration int) bool {
+	if prev == nil {
+		return false
+	}
+
+	changed := false
+	for i := range p.PRDs {
+		current := &p.PRDs[i]
+		before := prev.FindByID(current.ID)
+		if before == nil {
+			// Added by an agent; stamp it so its age can be reported
+			if current.CreatedAt.IsZero() {
+				current.CreatedAt = time.Now()
+				changed = true
+			}
+			continue
+		}
+		from, to := before.Passes.String(), current.Passes.String()
+		if from == to {
+			continue
+		}
+		// Skip changes already recorded via Transition
+		if n := len(current.History); n > 0 {
+			last := current.History[n-1]
+			if last.From == from && last.To == to && last.Iteration == iteration {
+				continue
+			}
+		}
+		current.appendHistory(from, to, iteration)
+		changed = true
+	}
+	return changed
 }
 
 // PRDFile represents the prd.json file structure
 type PRDFileData struct {
 	PRDs []PRD `json:"prds"`
+
+	// Extra holds top-level fields Millhouse does not know, written back unchanged on save
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Load reads and parses the prd.json file with resilient parsing
@@ -133,7 +427,7 @@ func Load(basePath string) (*PRDFileData, error) {
 	if len(trimmed) > 0 && trimmed[0] == '[' {
 		var prds []PRD
 		if err := json.Unmarshal(data, &prds); err != nil {
-			return nil, fmt.Errorf("prd.json appears to be an array but failed to parse: %w", err)
+			return nil, fmt.Errorf("prd.json appears to be an array but failed to parse: %w%s", err, restoreHint(basePath))
 		}
 
 		// Wrap in proper structure
@@ -150,25 +444,40 @@ func Load(basePath string) (*PRDFileData, error) {
 	}
 
 	// Check if it's an empty file
+	// An empty file is only a fresh start when there is nothing to lose;
+	// reading it as zero PRDs would let the next saves replace a good backup
 	if len(trimmed) == 0 {
+		if n := backupPRDCount(basePath); n > 0 {
+			return nil, fmt.Errorf("prd.json is empty but %s holds %d PRDs%s", BackupFile, n, restoreHint(basePath))
+		}
 		prdFile = PRDFileData{PRDs: []PRD{}}
 		fmt.Printf("Warning: prd.json was empty. Initialized with empty PRDs array.\n")
 		return &prdFile, nil
 	}
 
 	// Unknown format - return original error
-	return nil, fmt.Errorf("failed to parse prd.json: invalid JSON structure (expected object with 'prds' key)")
+	return nil, fmt.Errorf("failed to parse prd.json: invalid JSON structure (expected object with 'prds' key)%s", restoreHint(basePath))
 }
 
 // Save writes the prd.json file
+// Descriptions and notes over the SetTextLimits caps, if any, are truncated first.
+// The previous file is kept as BackupFile when it parses, and the new one is
+// written atomically, so neither a bad edit nor an interrupted write loses
+// the PRDs.
 func Save(basePath string, prdFile *PRDFileData) error {
-	path := filepath.Join(basePath, MillhouseDir, PRDFile)
+	for _, cut := range prdFile.enforceTextLimits() {
+		fmt.Printf("Warning: %s\n", cut)
+	}
+
 	data, err := json.MarshalIndent(prdFile, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal prd.json: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := backupBeforeSave(basePath); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(GetMillhousePath(basePath, PRDFile), data); err != nil {
 		return fmt.Errorf("failed to write prd.json: %w", err)
 	}
 
@@ -197,6 +506,17 @@ func (p *PRDFileData) GetPendingPRDs() []PRD {
 	return pending
 }
 
+// GetPendingPRDsIn returns pending PRDs within scope
+func (p *PRDFileData) GetPendingPRDsIn(scope Scope) []PRD {
+	var pending []PRD
+	for i := range p.PRDs {
+		if p.PRDs[i].Passes.IsPending() && scope.Allows(&p.PRDs[i]) {
+			pending = append(pending, p.PRDs[i])
+		}
+	}
+	return pending
+}
+
 // GetCompletePRDs returns PRDs where passes=true
 func (p *PRDFileData) GetCompletePRDs() []PRD {
 	var complete []PRD
@@ -232,7 +552,110 @@ func GetMillhousePath(basePath, filename string) string {
 
 // GetEvidencePath returns the path to an evidence file for a PRD
 func GetEvidencePath(basePath, prdID string) string {
-	return filepath.Join(basePath, MillhouseDir, EvidenceDir, prdID+"-evidence.md")
+	return GetArtifactPath(basePath, EvidenceDir, prdID+"-evidence.md")
+}
+
+// OwnedBy reports whether owner may work on the PRD
+// Unowned PRDs belong to everyone, and an empty owner matches every PRD.
+func (p *PRD) OwnedBy(owner string) bool {
+	return owner == "" || p.Owner == "" || p.Owner == owner
+}
+
+// GetPRDsByOwner returns the PRDs owned by owner plus unowned PRDs
+func (p *PRDFileData) GetPRDsByOwner(owner string) []PRD {
+	var owned []PRD
+	for _, prd := range p.PRDs {
+		if prd.OwnedBy(owner) {
+			owned = append(owned, prd)
+		}
+	}
+	return owned
+}
+
+// Scope limits which PRDs a run may plan, build and review
+type Scope struct {
+	Owner       string   // Only PRDs owned by Owner or unowned; empty means any owner
+	MaxPriority int      // Only PRDs with priority <= MaxPriority; 0 means any priority
+	Skip        []string // PRD IDs to pass over for now, e.g. rejections cooling down
+	Filter      *Filter  // Only PRDs matching this --prd-filter expression; nil means any
+
+	work bool // Match Filter against PRDs as they were when open (see Work)
+}
+
+// Allows reports whether the PRD falls within the scope
+func (s Scope) Allows(p *PRD) bool {
+	if !p.OwnedBy(s.Owner) || slices.Contains(s.Skip, p.ID) {
+		return false
+	}
+	if s.MaxPriority > 0 && p.Priority > s.MaxPriority {
+		return false
+	}
+	if s.work && s.Filter != nil {
+		open := *p
+		open.Passes.SetFalse()
+		p = &open
+	}
+	return s.Filter.Match(p, time.Now())
+}
+
+// Work returns the scope for PRDs already active or pending
+// Skip only delays picking up open PRDs, so it never holds back work in
+// progress. The filter sees each PRD as it was when open: status terms pick
+// which PRDs a run starts, and a PRD stays in scope once it has started.
+func (s Scope) Work() Scope {
+	s.Skip = nil
+	s.work = true
+	return s
+}
+
+// Limited reports whether the scope leaves out any PRDs
+func (s Scope) Limited() bool {
+	return s.Owner != "" || s.MaxPriority > 0 || len(s.Skip) > 0 || s.Filter != nil
+}
+
+// String describes the scope for messages, e.g. "owner alice, priority <= P2"
+func (s Scope) String() string {
+	var parts []string
+	if s.Owner != "" {
+		parts = append(parts, "owner "+s.Owner)
+	}
+	if s.MaxPriority > 0 {
+		parts = append(parts, fmt.Sprintf("priority <= P%d", s.MaxPriority))
+	}
+	if s.Filter != nil {
+		parts = append(parts, fmt.Sprintf("filter %q", s.Filter))
+	}
+	if len(parts) == 0 {
+		return "all PRDs"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GetOpenPRDsIn returns open PRDs within scope
+func (p *PRDFileData) GetOpenPRDsIn(scope Scope) []PRD {
+	var open []PRD
+	for i := range p.PRDs {
+		if p.PRDs[i].Passes.IsFalse() && scope.Allows(&p.PRDs[i]) {
+			open = append(open, p.PRDs[i])
+		}
+	}
+	return open
+}
+
+// GetBlockedPRDs returns PRDs where passes="blocked"
+func (p *PRDFileData) GetBlockedPRDs() []PRD {
+	var blocked []PRD
+	for _, prd := range p.PRDs {
+		if prd.Passes.IsBlocked() {
+			blocked = append(blocked, prd)
+		}
+	}
+	return blocked
+}
+
+// GetChecksPath returns the path to the post-build check results for a PRD
+func GetChecksPath(basePath, prdID string) string {
+	return GetArtifactPath(basePath, EvidenceDir, prdID+"-checks.json")
 }
 
 // GetActivePRDs returns PRDs where passes="active"
@@ -246,15 +669,25 @@ func (p *PRDFileData) GetActivePRDs() []PRD {
 	return active
 }
 
+// GetActivePRDsIn returns active PRDs within scope
+func (p *PRDFileData) GetActivePRDsIn(scope Scope) []PRD {
+	var active []PRD
+	for i := range p.PRDs {
+		if p.PRDs[i].Passes.IsActive() && scope.Allows(&p.PRDs[i]) {
+			active = append(active, p.PRDs[i])
+		}
+	}
+	return active
+}
+
 // GetPlanPath returns the path to a plan file for a PRD
 func GetPlanPath(basePath, prdID string) string {
-	return filepath.Join(basePath, MillhouseDir, PlansDir, prdID+"-plan.md")
+	return GetArtifactPath(basePath, PlansDir, prdID+"-plan.md")
 }
 
 // EnsurePlansDir creates the plans directory if it doesn't exist
 func EnsurePlansDir(basePath string) error {
-	plansPath := filepath.Join(basePath, MillhouseDir, PlansDir)
-	return os.MkdirAll(plansPath, 0755)
+	return os.MkdirAll(GetArtifactPath(basePath, PlansDir), 0755)
 }
 
 // DeletePlan removes a plan file for a PRD
@@ -272,3 +705,43 @@ func PlanExists(basePath, prdID string) bool {
 	_, err := os.Stat(planPath)
 	return err == nil
 }
+
+// ListPlans returns the IDs of PRDs that have a plan file, sorted
+func ListPlans(basePath string) ([]string, error) {
+	entries, err := os.ReadDir(GetArtifactPath(basePath, PlansDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plans directory: %w", err)
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if id, ok := strings.CutSuffix(e.Name(), "-plan.md"); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// StalePlans returns plan IDs whose PRD no longer exists or is no longer active/pending
+func (p *PRDFileData) StalePlans(basePath string) ([]string, error) {
+	ids, err := ListPlans(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, id := range ids {
+		found := p.FindByID(id)
+		if found == nil || !(found.Passes.IsActive() || found.Passes.IsPending()) {
+			stale = append(stale, id)
+		}
+	}
+	return stale, nil
+}
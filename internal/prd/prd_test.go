@@ -0,0 +1,495 @@
+package prd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    PassesStatus
+		to      string
+		wantErr bool
+	}{
+		{name: "open to active", from: PassesStatus{Value: false}, to: StatusActive},
+		{name: "active to pending", from: PassesStatus{Value: "active"}, to: StatusPending},
+		{name: "pending to complete", from: PassesStatus{Value: "pending"}, to: StatusComplete},
+		{name: "pending to open", from: PassesStatus{Value: "pending"}, to: StatusOpen},
+		{name: "open to complete", from: PassesStatus{Value: false}, to: StatusComplete, wantErr: true},
+		{name: "active to complete", from: PassesStatus{Value: "active"}, to: StatusComplete, wantErr: true},
+		{name: "open to blocked", from: PassesStatus{Value: false}, to: StatusBlocked},
+		{name: "blocked to open", from: PassesStatus{Value: "blocked"}, to: StatusOpen},
+		{name: "blocked to active", from: PassesStatus{Value: "blocked"}, to: StatusActive, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PRD{ID: "prd-1", Passes: tt.from}
+			err := p.Transition(tt.to, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if len(p.History) != 0 {
+					t.Errorf("History = %v, want empty after rejected transition", p.History)
+				}
+				return
+			}
+			if got := p.Passes.String(); got != tt.to {
+				t.Errorf("status = %s, want %s", got, tt.to)
+			}
+			if len(p.History) != 1 || p.History[0].To != tt.to || p.History[0].Iteration != 1 {
+				t.Errorf("History = %v, want single entry to %s", p.History, tt.to)
+			}
+		})
+	}
+}
+
+func TestTransitionSameStatusIsNoop(t *testing.T) {
+	p := PRD{ID: "prd-1", Passes: PassesStatus{Value: "pending"}}
+	if err := p.Transition(StatusPending, 1); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+	if len(p.History) != 0 {
+		t.Errorf("History = %v, want empty", p.History)
+	}
+}
+
+func TestHistoryCap(t *testing.T) {
+	p := PRD{ID: "prd-1", Passes: PassesStatus{Value: "active"}}
+	for i := 0; i < MaxHistory; i++ {
+		if err := p.Transition(StatusPending, i); err != nil {
+			t.Fatalf("Transition() error = %v", err)
+		}
+		if err := p.Transition(StatusActive, i); err != nil {
+			t.Fatalf("Transition() error = %v", err)
+		}
+	}
+
+	if len(p.History) != MaxHistory {
+		t.Errorf("len(History) = %d, want %d", len(p.History), MaxHistory)
+	}
+	if last := p.History[len(p.History)-1]; last.Iteration != MaxHistory-1 {
+		t.Errorf("last entry iteration = %d, want %d", last.Iteration, MaxHistory-1)
+	}
+}
+
+func TestRecordChanges(t *testing.T) {
+	prev := &PRDFileData{PRDs: []PRD{
+		{ID: "a", Passes: PassesStatus{Value: "pending"}},
+		{ID: "b", Passes: PassesStatus{Value: false}},
+	}}
+	current := &PRDFileData{PRDs: []PRD{
+		{ID: "a", Passes: PassesStatus{Value: false}},
+		{ID: "b", Passes: PassesStatus{Value: false}},
+	}}
+
+	if !current.RecordChanges(prev, 3) {
+		t.Fatal("RecordChanges() = false, want true")
+	}
+	if got := current.FindByID("a").CountTransitions(StatusPending, StatusOpen); got != 1 {
+		t.Errorf("CountTransitions(pending, open) = %d, want 1", got)
+	}
+	if len(current.FindByID("b").History) != 0 {
+		t.Errorf("unchanged PRD should have no history")
+	}
+
+	// A second pass over the same change must not duplicate the entry
+	if current.RecordChanges(prev, 3) {
+		t.Error("RecordChanges() recorded a duplicate entry")
+	}
+}
+
+func TestCreatedAt(t *testing.T) {
+	prdFile := &PRDFileData{}
+	if err := prdFile.Add(PRD{ID: "new", Description: "new work"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if prdFile.FindByID("new").CreatedAt.IsZero() {
+		t.Error("Add() should set CreatedAt")
+	}
+
+	// PRDs an agent writes into prd.json are stamped when changes are recorded
+	prev := &PRDFileData{PRDs: []PRD{{ID: "old"}}}
+	current := &PRDFileData{PRDs: []PRD{{ID: "old"}, {ID: "agent"}}}
+	if !current.RecordChanges(prev, 0) {
+		t.Error("RecordChanges() = false, want true for a new PRD")
+	}
+	if !current.FindByID("old").CreatedAt.IsZero() || current.FindByID("agent").CreatedAt.IsZero() {
+		t.Errorf("only the new PRD should be stamped: %+v", current.PRDs)
+	}
+
+	// PRDs without the field stay without it
+	data, err := json.Marshal(PRD{ID: "legacy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "createdAt") {
+		t.Errorf("zero CreatedAt should be omitted: %s", data)
+	}
+}
+
+func TestSortByAge(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC) }
+	prds := []PRD{
+		{ID: "unknown-1"},
+		{ID: "newer", CreatedAt: day(20)},
+		{ID: "unknown-2"},
+		{ID: "oldest", CreatedAt: day(2)},
+	}
+	SortByAge(prds)
+
+	var ids []string
+	for _, p := range prds {
+		ids = append(ids, p.ID)
+	}
+	if got := strings.Join(ids, ","); got != "oldest,newer,unknown-1,unknown-2" {
+		t.Errorf("SortByAge() = %s", got)
+	}
+
+	if age, ok := prds[0].Age(day(12)); !ok || age != 10*24*time.Hour {
+		t.Errorf("Age() = %v, %v; want 240h", age, ok)
+	}
+	if _, ok := prds[2].Age(day(12)); ok {
+		t.Error("Age() should be unknown without CreatedAt")
+	}
+}
+
+func TestSelectNextWith(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "low", Priority: 1, Passes: PassesStatus{Value: false}},
+		{ID: "high", Priority: 5, Passes: PassesStatus{Value: false}},
+		{ID: "done", Priority: 0, Passes: PassesStatus{Value: true}},
+	}}
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{name: "no target uses priority", target: "", want: "low"},
+		{name: "open target overrides priority", target: "high", want: "high"},
+		{name: "closed target selects nothing", target: "done", want: ""},
+		{name: "unknown target selects nothing", target: "missing", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SelectNextWith(prdFile, tt.target, Scope{})
+			gotID := ""
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.want {
+				t.Errorf("SelectNextWith(%q) = %q, want %q", tt.target, gotID, tt.want)
+			}
+		})
+	}
+
+	if err := ValidateSelection(prdFile, "high"); err != nil {
+		t.Errorf("ValidateSelection(high) error = %v", err)
+	}
+	if err := ValidateSelection(prdFile, "done"); err == nil {
+		t.Error("ValidateSelection(done) should fail for a complete PRD")
+	}
+	if err := ValidateSelection(prdFile, "missing"); err == nil {
+		t.Error("ValidateSelection(missing) should fail for an unknown PRD")
+	}
+}
+
+func TestOwnerFiltering(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "bob-1", Priority: 1, Owner: "bob", Passes: PassesStatus{Value: false}},
+		{ID: "alice-1", Priority: 3, Owner: "alice", Passes: PassesStatus{Value: false}},
+		{ID: "shared", Priority: 2, Passes: PassesStatus{Value: false}},
+	}}
+
+	var ids []string
+	for _, p := range prdFile.GetPRDsByOwner("alice") {
+		ids = append(ids, p.ID)
+	}
+	if len(ids) != 2 || ids[0] != "alice-1" || ids[1] != "shared" {
+		t.Errorf("GetPRDsByOwner(alice) = %v, want [alice-1 shared]", ids)
+	}
+	if n := len(prdFile.GetPRDsByOwner("")); n != 3 {
+		t.Errorf("empty owner should see every PRD, got %d", n)
+	}
+
+	tests := []struct {
+		owner, target, want string
+	}{
+		{"", "", "bob-1"},
+		{"alice", "", "shared"},
+		{"bob", "", "bob-1"},
+		{"alice", "bob-1", ""},
+		{"alice", "alice-1", "alice-1"},
+	}
+	for _, tt := range tests {
+		got := SelectNextWith(prdFile, tt.target, Scope{Owner: tt.owner})
+		gotID := ""
+		if got != nil {
+			gotID = got.ID
+		}
+		if gotID != tt.want {
+			t.Errorf("SelectNextWith(%q, owner %q) = %q, want %q", tt.target, tt.owner, gotID, tt.want)
+		}
+	}
+
+	if err := ValidateScope(prdFile, "bob-1", Scope{Owner: "alice"}); err == nil {
+		t.Error("ValidateScope should reject another owner's PRD")
+	}
+	if err := ValidateScope(prdFile, "shared", Scope{Owner: "alice"}); err != nil {
+		t.Errorf("ValidateScope should allow unowned PRDs: %v", err)
+	}
+}
+
+func TestStalePlans(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsurePlansDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"active", "pending", "open", "gone"} {
+		if err := os.WriteFile(GetPlanPath(dir, id), []byte("plan"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Unrelated files are ignored
+	os.WriteFile(filepath.Join(dir, MillhouseDir, PlansDir, "notes.txt"), []byte("x"), 0644)
+
+	ids, err := ListPlans(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "active,gone,open,pending" {
+		t.Errorf("ListPlans = %v", ids)
+	}
+
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "active", Passes: PassesStatus{Value: "active"}},
+		{ID: "pending", Passes: PassesStatus{Value: "pending"}},
+		{ID: "open", Passes: PassesStatus{Value: false}},
+	}}
+	stale, err := prdFile.StalePlans(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(stale, ",") != "gone,open" {
+		t.Errorf("StalePlans = %v, want [gone open]", stale)
+	}
+}
+
+func TestPassesStatusString(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{false, StatusOpen},
+		{"active", StatusActive},
+		{"pending", StatusPending},
+		{true, StatusComplete},
+		{"blocked", StatusBlocked},
+		{"bogus", StatusOpen},
+		{nil, StatusOpen},
+	}
+	for _, tt := range tests {
+		p := PassesStatus{Value: tt.value}
+		if got := p.String(); got != tt.want {
+			t.Errorf("PassesStatus{%v}.String() = %q, want %q", tt.value, got, tt.want)
+		}
+		text, err := p.MarshalText()
+		if err != nil || string(text) != tt.want {
+			t.Errorf("PassesStatus{%v}.MarshalText() = %q, %v", tt.value, text, err)
+		}
+	}
+
+	// JSON keeps the raw value so prd.json stays compatible
+	data, err := json.Marshal(PassesStatus{Value: "pending"})
+	if err != nil || string(data) != `"pending"` {
+		t.Errorf("MarshalJSON = %s, %v", data, err)
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	rejection := Transition{From: StatusPending, To: StatusOpen}
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "struggling", Passes: PassesStatus{Value: false}, History: []Transition{rejection, rejection}},
+		{ID: "once", Passes: PassesStatus{Value: false}, History: []Transition{rejection}},
+		{ID: "done", Passes: PassesStatus{Value: true}, History: []Transition{rejection, rejection}},
+		{ID: "already", Passes: PassesStatus{Value: false}, ModelOverride: "sonnet", History: []Transition{rejection, rejection}},
+	}}
+
+	if got := prdFile.Escalate(0, "opus"); got != nil {
+		t.Errorf("threshold 0 should disable escalation, got %v", got)
+	}
+
+	got := prdFile.Escalate(2, "opus")
+	if len(got) != 1 || got[0] != "struggling" {
+		t.Fatalf("Escalate = %v, want [struggling]", got)
+	}
+	p := prdFile.FindByID("struggling")
+	if p.ModelOverride != "opus" || !strings.Contains(p.Notes, "[escalated]") {
+		t.Errorf("struggling = %+v", p)
+	}
+	if prdFile.FindByID("already").ModelOverride != "sonnet" {
+		t.Error("existing override should be kept")
+	}
+
+	if again := prdFile.Escalate(2, "opus"); len(again) != 0 {
+		t.Errorf("escalation should happen once, got %v", again)
+	}
+}
+
+func TestMaxPriorityScope(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "p1", Priority: 1, Passes: PassesStatus{Value: false}},
+		{ID: "p2", Priority: 2, Passes: PassesStatus{Value: false}},
+		{ID: "p3", Priority: 3, Passes: PassesStatus{Value: false}},
+	}}
+
+	tests := []struct {
+		maxPriority int
+		wantOpen    int
+	}{
+		{0, 3}, // no floor
+		{1, 1},
+		{2, 2}, // boundary is inclusive
+		{3, 3},
+	}
+	for _, tt := range tests {
+		scope := Scope{MaxPriority: tt.maxPriority}
+		if got := len(prdFile.GetOpenPRDsIn(scope)); got != tt.wantOpen {
+			t.Errorf("GetOpenPRDsIn(max %d) = %d PRDs, want %d", tt.maxPriority, got, tt.wantOpen)
+		}
+	}
+
+	if p := SelectNextWith(prdFile, "p3", Scope{MaxPriority: 2}); p != nil {
+		t.Errorf("target outside the priority window should not be selected, got %s", p.ID)
+	}
+	if err := ValidateScope(prdFile, "p3", Scope{MaxPriority: 2}); err == nil {
+		t.Error("ValidateScope should reject a PRD below the priority floor")
+	}
+	if err := ValidateScope(prdFile, "p2", Scope{MaxPriority: 2}); err != nil {
+		t.Errorf("ValidateScope(p2) error = %v", err)
+	}
+
+	if got := (Scope{Owner: "alice", MaxPriority: 2}).String(); got != "owner alice, priority <= P2" {
+		t.Errorf("Scope.String() = %q", got)
+	}
+}
+
+func TestWorkScope(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "mine", Owner: "alice", Passes: PassesStatus{Value: "active"}},
+		{ID: "theirs", Owner: "bob", Passes: PassesStatus{Value: "active"}},
+		{ID: "cooling", Passes: PassesStatus{Value: "pending"}},
+		{ID: "review", Owner: "bob", Passes: PassesStatus{Value: "pending"}},
+	}}
+	scope := Scope{Owner: "alice", Skip: []string{"mine", "cooling"}}
+
+	// A cooldown holds back open PRDs only, never work in progress
+	work := scope.Work()
+	if active := prdFile.GetActivePRDsIn(work); len(active) != 1 || active[0].ID != "mine" {
+		t.Errorf("GetActivePRDsIn(%s) = %v, want only mine", work, active)
+	}
+	if pending := prdFile.GetPendingPRDsIn(work); len(pending) != 1 || pending[0].ID != "cooling" {
+		t.Errorf("GetPendingPRDsIn(%s) = %v, want only cooling", work, pending)
+	}
+	if len(scope.Skip) != 2 {
+		t.Errorf("Work() changed the original scope's Skip: %v", scope.Skip)
+	}
+
+	// Status terms in the filter only pick which open PRDs start
+	f, err := ParseFilter("status=open && owner!=bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	work = Scope{Filter: f}.Work()
+	if active := prdFile.GetActivePRDsIn(work); len(active) != 1 || active[0].ID != "mine" {
+		t.Errorf("GetActivePRDsIn(%s) = %v, want only mine", work, active)
+	}
+	if open := prdFile.GetOpenPRDsIn(Scope{Filter: f}); len(open) != 0 {
+		t.Errorf("GetOpenPRDsIn(%s) = %v, want none", f, open)
+	}
+
+	if (Scope{}).Limited() || (Scope{}).Work().Limited() {
+		t.Error("an empty scope should not be limited")
+	}
+	if !scope.Limited() || !(Scope{MaxPriority: 1}).Limited() || !(Scope{Skip: []string{"a"}}).Limited() {
+		t.Error("an owner, priority or skip should limit the scope")
+	}
+}
+
+func TestArtifactPaths(t *testing.T) {
+	base := "/project"
+	if got, want := GetPlanPath(base, "p1"), filepath.Join(base, MillhouseDir, PlansDir, "p1-plan.md"); got != want {
+		t.Errorf("GetPlanPath() = %q, want %q", got, want)
+	}
+	if got := AgentArtifactDir(PlansDir); got != ".milhouse/plans/" {
+		t.Errorf("AgentArtifactDir() = %q, want .milhouse/plans/", got)
+	}
+	if dirs := ExternalArtifactDirs(); dirs != nil {
+		t.Errorf("ExternalArtifactDirs() = %v, want none", dirs)
+	}
+
+	SetArtifactsDir("/tmp/out")
+	defer SetArtifactsDir("")
+
+	if got, want := GetPlanPath(base, "p1"), filepath.Join("/tmp/out", PlansDir, "p1-plan.md"); got != want {
+		t.Errorf("relocated GetPlanPath() = %q, want %q", got, want)
+	}
+	if got, want := GetMillhousePath(base, PRDFile), filepath.Join(base, MillhouseDir, PRDFile); got != want {
+		t.Errorf("GetMillhousePath() = %q, want %q; prd.json must not move", got, want)
+	}
+	if got, want := AgentArtifactDir(EvidenceDir), filepath.Join("/tmp/out", EvidenceDir)+string(filepath.Separator); got != want {
+		t.Errorf("relocated AgentArtifactDir() = %q, want %q", got, want)
+	}
+}
+
+func TestCompleteSubtask(t *testing.T) {
+	p := PRD{ID: "big-1", Subtasks: []Subtask{{Description: "schema"}, {Description: "api"}}}
+
+	if changed, err := p.CompleteSubtask(2); err != nil || !changed {
+		t.Fatalf("CompleteSubtask(2) = %v, %v", changed, err)
+	}
+	if changed, err := p.CompleteSubtask(2); err != nil || changed {
+		t.Errorf("repeat should be a no-op, got %v, %v", changed, err)
+	}
+	if _, err := p.CompleteSubtask(0); err == nil {
+		t.Error("index 0 should be rejected")
+	}
+	if _, err := p.CompleteSubtask(3); err == nil {
+		t.Error("out-of-range index should be rejected")
+	}
+	if done, total := p.SubtaskProgress(); done != 1 || total != 2 {
+		t.Errorf("progress = %d/%d, want 1/2", done, total)
+	}
+}
+
+func TestValidateResume(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsurePlansDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetPlanPath(dir, "planned"), []byte("plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "planned", Passes: PassesStatus{Value: "active"}},
+		{ID: "unplanned", Passes: PassesStatus{Value: "active"}},
+		{ID: "open", Passes: PassesStatus{Value: false}},
+	}}
+
+	if err := ValidateResume(dir, prdFile, "planned"); err != nil {
+		t.Errorf("active PRD with a plan rejected: %v", err)
+	}
+	for _, id := range []string{"unplanned", "open", "missing"} {
+		if err := ValidateResume(dir, prdFile, id); err == nil {
+			t.Errorf("ValidateResume(%s) should fail", id)
+		}
+	}
+}
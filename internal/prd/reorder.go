@@ -0,0 +1,198 @@
+package prd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const reorderDescWidth = 50
+
+// Reorderer is an interactive editor for the relative priority of open PRDs
+type Reorderer struct {
+	basePath string
+	items    []PRD // Open PRDs in their current order
+	cursor   int
+	err      error
+	saved    bool
+	message  string
+}
+
+// NewReorderer creates a reorderer listing open PRDs by priority
+func NewReorderer(basePath string, prdFile *PRDFileData) *Reorderer {
+	items := prdFile.GetOpenPRDs()
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Priority < items[j].Priority
+	})
+
+	return &Reorderer{
+		basePath: basePath,
+		items:    items,
+	}
+}
+
+// RunReorder starts the interactive priority editor
+func RunReorder(basePath string) error {
+	prdFile, err := Load(basePath)
+	if err != nil {
+		return err
+	}
+	if len(prdFile.GetOpenPRDs()) < 2 {
+		return fmt.Errorf("need at least two open PRDs to reorder")
+	}
+
+	p := tea.NewProgram(NewReorderer(basePath, prdFile), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("reorder error: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyOrder sets normalized priorities (1, 2, 3, ...) following ids
+// PRDs not listed keep their priority.
+func ApplyOrder(prdFile *PRDFileData, ids []string) error {
+	for i, id := range ids {
+		p := prdFile.FindByID(id)
+		if p == nil {
+			return fmt.Errorf("PRD %s not found", id)
+		}
+		p.Priority = i + 1
+	}
+	return nil
+}
+
+// Init implements the bubbletea Model interface
+func (r *Reorderer) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements the bubbletea Model interface
+func (r *Reorderer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return r, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyCtrlC, tea.KeyEscape:
+		return r, tea.Quit
+
+	case tea.KeyCtrlS:
+		if err := r.save(); err != nil {
+			r.err = err
+			r.message = fmt.Sprintf("Error: %v", err)
+		} else {
+			r.saved = true
+			r.message = "Priorities saved"
+		}
+		return r, nil
+
+	case tea.KeyUp:
+		if r.cursor > 0 {
+			r.cursor--
+		}
+		return r, nil
+
+	case tea.KeyDown:
+		if r.cursor < len(r.items)-1 {
+			r.cursor++
+		}
+		return r, nil
+
+	case tea.KeyShiftUp:
+		r.move(-1)
+		return r, nil
+
+	case tea.KeyShiftDown:
+		r.move(1)
+		return r, nil
+	}
+
+	// Fallbacks for terminals without shift+arrow support
+	switch keyMsg.String() {
+	case "K":
+		r.move(-1)
+	case "J":
+		r.move(1)
+	}
+	return r, nil
+}
+
+// move swaps the selected PRD with its neighbor, keeping it selected
+func (r *Reorderer) move(delta int) {
+	target := r.cursor + delta
+	if target < 0 || target >= len(r.items) {
+		return
+	}
+	r.items[r.cursor], r.items[target] = r.items[target], r.items[r.cursor]
+	r.cursor = target
+}
+
+// save writes the current order as normalized priorities
+func (r *Reorderer) save() error {
+	prdFile, err := Load(r.basePath)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(r.items))
+	for i, item := range r.items {
+		ids[i] = item.ID
+	}
+	if err := ApplyOrder(prdFile, ids); err != nil {
+		return err
+	}
+
+	return Save(r.basePath, prdFile)
+}
+
+// View implements the bubbletea Model interface
+func (r *Reorderer) View() string {
+	var s strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		MarginBottom(1)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("8")).
+		Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	s.WriteString(headerStyle.Render("Milhouse PRD Priorities") + "\n")
+	s.WriteString("Use ↑/↓ to select • Shift+↑/↓ (or K/J) to move • Ctrl+S to save • ESC to cancel\n\n")
+
+	for i, item := range r.items {
+		line := fmt.Sprintf("%3d. %-24s %s", i+1, item.ID, truncate(item.Description, reorderDescWidth))
+		if i == r.cursor {
+			s.WriteString(selectedStyle.Render("> "+line) + "\n")
+		} else {
+			s.WriteString("  " + line + "\n")
+		}
+	}
+
+	s.WriteString("\n")
+	if r.saved {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render("✓ "+r.message) + "\n")
+		r.saved = false
+	} else if r.err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✗ "+r.message) + "\n")
+		r.err = nil
+	}
+
+	s.WriteString(dimStyle.MarginTop(1).Render("[Ctrl+S] Save  [ESC] Cancel") + "\n")
+	return s.String()
+}
+
+// truncate shortens text to max runes with an ellipsis
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-3]) + "..."
+}
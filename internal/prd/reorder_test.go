@@ -0,0 +1,56 @@
+package prd
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func reorderFixture() *PRDFileData {
+	return &PRDFileData{PRDs: []PRD{
+		{ID: "c", Priority: 30, Passes: PassesStatus{Value: false}},
+		{ID: "a", Priority: 10, Passes: PassesStatus{Value: false}},
+		{ID: "done", Priority: 1, Passes: PassesStatus{Value: true}},
+		{ID: "b", Priority: 20, Passes: PassesStatus{Value: false}},
+	}}
+}
+
+func TestReordererMovesSelection(t *testing.T) {
+	r := NewReorderer(t.TempDir(), reorderFixture())
+
+	// Starts sorted by priority: a, b, c
+	r.Update(tea.KeyMsg{Type: tea.KeyDown})
+	r.Update(tea.KeyMsg{Type: tea.KeyShiftDown}) // b moves below c
+	r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")}) // b moves to the top
+
+	var got []string
+	for _, item := range r.items {
+		got = append(got, item.ID)
+	}
+	if len(got) != 3 || got[0] != "b" || got[1] != "a" || got[2] != "c" {
+		t.Errorf("order = %v, want [b a c]", got)
+	}
+	if r.cursor != 0 {
+		t.Errorf("cursor should follow the moved PRD, got %d", r.cursor)
+	}
+}
+
+func TestApplyOrder(t *testing.T) {
+	prdFile := reorderFixture()
+
+	if err := ApplyOrder(prdFile, []string{"c", "a", "b"}); err != nil {
+		t.Fatalf("ApplyOrder: %v", err)
+	}
+
+	want := map[string]int{"c": 1, "a": 2, "b": 3, "done": 1}
+	for _, p := range prdFile.PRDs {
+		if p.Priority != want[p.ID] {
+			t.Errorf("%s priority = %d, want %d", p.ID, p.Priority, want[p.ID])
+		}
+	}
+
+	if err := ApplyOrder(prdFile, []string{"missing"}); err == nil {
+		t.Error("expected error for unknown PRD")
+	}
+}
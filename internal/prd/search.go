@@ -0,0 +1,119 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SearchOptions narrows a PRD search
+type SearchOptions struct {
+	Status string // Only PRDs with this status; empty means any
+	Fuzzy  bool   // Also accept words within a typo or two of each query term
+}
+
+// SearchResult is a PRD that matched a search and the fields it matched in
+type SearchResult struct {
+	PRD    PRD      `json:"prd"`
+	Fields []string `json:"fields"` // id, description, acceptanceCriteria, notes
+}
+
+// Search finds PRDs whose ID, description, acceptance criteria or notes
+// contain query, ignoring case, in prd.json order
+// With Fuzzy set, a field also matches when every word of the query is close
+// to some word in it, so "authentcation" still finds "authentication".
+func Search(prdFile *PRDFileData, query string, opts SearchOptions) ([]SearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	switch opts.Status {
+	case "", StatusOpen, StatusActive, StatusPending, StatusComplete, StatusBlocked:
+	default:
+		return nil, fmt.Errorf("invalid status '%s': must be open, active, pending, complete, or blocked", opts.Status)
+	}
+
+	var results []SearchResult
+	for _, p := range prdFile.PRDs {
+		if opts.Status != "" && p.Passes.String() != opts.Status {
+			continue
+		}
+		fields := []struct{ name, text string }{
+			{"id", p.ID},
+			{"description", p.Description},
+			{"acceptanceCriteria", strings.Join(p.AcceptanceCriteria, "\n")},
+			{"notes", p.Notes},
+		}
+		var matched []string
+		for _, f := range fields {
+			if textMatches(strings.ToLower(f.text), query, opts.Fuzzy) {
+				matched = append(matched, f.name)
+			}
+		}
+		if len(matched) > 0 {
+			results = append(results, SearchResult{PRD: p, Fields: matched})
+		}
+	}
+	return results, nil
+}
+
+// textMatches reports whether lowercased text contains query, or with fuzzy
+// set, whether every query word is close to a word of text
+func textMatches(text, query string, fuzzy bool) bool {
+	if strings.Contains(text, query) {
+		return true
+	}
+	if !fuzzy || text == "" {
+		return false
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !(r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r > 127)
+	})
+	for _, term := range strings.Fields(query) {
+		found := false
+		for _, w := range words {
+			if strings.Contains(w, term) || editDistance(term, w) <= typoAllowance(term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// typoAllowance is how many edits a fuzzy term may be from a word; short
+// terms must match exactly to avoid matching everything
+func typoAllowance(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
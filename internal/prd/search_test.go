@@ -0,0 +1,57 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "auth-login", Description: "Add login endpoint", AcceptanceCriteria: []string{"Returns a session token"}},
+		{ID: "cache-layer", Description: "Cache API responses", Notes: "Blocked on AUTH decisions"},
+		{ID: "docs", Description: "Write the README", AcceptanceCriteria: []string{"Covers authentication setup"}, Passes: PassesStatus{Value: true}},
+	}}
+	prdFile.PRDs[0].Passes.SetFalse()
+	prdFile.PRDs[1].Passes.SetFalse()
+
+	ids := func(results []SearchResult) string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.PRD.ID+":"+strings.Join(r.Fields, "+"))
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		opts  SearchOptions
+		want  string
+	}{
+		{"across fields", "auth", SearchOptions{}, "auth-login:id,cache-layer:notes,docs:acceptanceCriteria"},
+		{"case-insensitive", "LOGIN", SearchOptions{}, "auth-login:id+description"},
+		{"status filter", "auth", SearchOptions{Status: StatusComplete}, "docs:acceptanceCriteria"},
+		{"no typo without fuzzy", "authentcation", SearchOptions{}, ""},
+		{"fuzzy typo", "authentcation", SearchOptions{Fuzzy: true}, "docs:acceptanceCriteria"},
+		{"fuzzy needs every word", "sesion tokn", SearchOptions{Fuzzy: true}, "auth-login:acceptanceCriteria"},
+		{"fuzzy keeps short words exact", "api", SearchOptions{Fuzzy: true}, "cache-layer:description"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Search(prdFile, tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if got := ids(results); got != tt.want {
+				t.Errorf("Search(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Search(prdFile, "  ", SearchOptions{}); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+	if _, err := Search(prdFile, "auth", SearchOptions{Status: "done"}); err == nil {
+		t.Error("expected an error for an unknown status")
+	}
+}
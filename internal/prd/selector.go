@@ -1,14 +1,17 @@
 package prd
 
 import (
+	"fmt"
+	"os"
 	"sort"
+	"time"
 )
 
-// SelectNext picks the best PRD to work on next
+// SelectNext picks the best PRD within scope to work on next
 // Returns the PRD with the lowest priority where passes=false
-// Returns nil if no open PRDs are available
-func SelectNext(prdFile *PRDFileData) *PRD {
-	open := prdFile.GetOpenPRDs()
+// Returns nil if no open PRDs are available within scope
+func SelectNext(prdFile *PRDFileData, scope Scope) *PRD {
+	open := prdFile.GetOpenPRDsIn(scope)
 	if len(open) == 0 {
 		return nil
 	}
@@ -28,6 +31,66 @@ func SelectNext(prdFile *PRDFileData) *PRD {
 	return nil
 }
 
+// SelectNextWith picks the target PRD if set and open, otherwise falls back to SelectNext
+// Returns nil if a target is given but is not currently open within scope.
+func SelectNextWith(prdFile *PRDFileData, target string, scope Scope) *PRD {
+	if target == "" {
+		return SelectNext(prdFile, scope)
+	}
+
+	p := prdFile.FindByID(target)
+	if p == nil || !p.Passes.IsFalse() || !scope.Allows(p) {
+		return nil
+	}
+	return p
+}
+
+// ValidateSelection checks that a PRD exists and is open so it can be selected
+func ValidateSelection(prdFile *PRDFileData, id string) error {
+	p := prdFile.FindByID(id)
+	if p == nil {
+		return fmt.Errorf("PRD not found: %s", id)
+	}
+	if !p.Passes.IsFalse() {
+		return fmt.Errorf("PRD %s is not open (status: %s)", id, p.Passes)
+	}
+	return nil
+}
+
+// ValidateResume checks that a PRD is active with a plan so interrupted
+// work on it can be continued
+func ValidateResume(basePath string, prdFile *PRDFileData, id string) error {
+	p := prdFile.FindByID(id)
+	if p == nil {
+		return fmt.Errorf("PRD not found: %s", id)
+	}
+	if !p.Passes.IsActive() {
+		return fmt.Errorf("PRD %s is not active (status: %s)", id, p.Passes)
+	}
+	if _, err := os.Stat(GetPlanPath(basePath, id)); err != nil {
+		return fmt.Errorf("PRD %s has no plan at %s", id, GetPlanPath(basePath, id))
+	}
+	return nil
+}
+
+// ValidateScope checks that the PRD with the given ID falls within scope
+func ValidateScope(prdFile *PRDFileData, id string, scope Scope) error {
+	p := prdFile.FindByID(id)
+	if p == nil {
+		return nil
+	}
+	if !p.OwnedBy(scope.Owner) {
+		return fmt.Errorf("PRD %s is owned by %s, not %s", id, p.Owner, scope.Owner)
+	}
+	if scope.MaxPriority > 0 && p.Priority > scope.MaxPriority {
+		return fmt.Errorf("PRD %s has priority P%d, outside priority <= P%d", id, p.Priority, scope.MaxPriority)
+	}
+	if !scope.Filter.Match(p, time.Now()) {
+		return fmt.Errorf("PRD %s does not match filter %q", id, scope.Filter)
+	}
+	return nil
+}
+
 // SelectNextPending picks a pending PRD for the reviewer to verify
 // Returns nil if no pending PRDs are available
 func SelectNextPending(prdFile *PRDFileData) *PRD {
@@ -0,0 +1,160 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split limits guard against an agent splitting work forever
+const (
+	MinSplitChildren = 2
+	MaxSplitChildren = 8
+	MaxSplitDepth    = 2 // A PRD created by a split may be split once more
+)
+
+// SplitChild is one proposed sub-PRD
+type SplitChild struct {
+	Description        string   `json:"description"`
+	AcceptanceCriteria []string `json:"acceptanceCriteria"`
+}
+
+// SplitProposal asks to replace an oversized PRD with smaller ones
+type SplitProposal struct {
+	PRDID    string       `json:"prdId"`
+	Reason   string       `json:"reason,omitempty"`
+	Children []SplitChild `json:"children"`
+}
+
+// SplitDepth returns how many splits produced the PRD, following SplitFrom
+func SplitDepth(prdFile *PRDFileData, id string) int {
+	depth := 0
+	seen := map[string]bool{id: true}
+	for p := prdFile.FindByID(id); p != nil && p.SplitFrom != ""; p = prdFile.FindByID(p.SplitFrom) {
+		if seen[p.SplitFrom] {
+			break
+		}
+		seen[p.SplitFrom] = true
+		depth++
+	}
+	return depth
+}
+
+// CheckSplit reports why a proposal cannot be applied, or nil if it can
+func (p *PRDFileData) CheckSplit(proposal SplitProposal) error {
+	parent := p.FindByID(proposal.PRDID)
+	if parent == nil {
+		return fmt.Errorf("PRD %s not found", proposal.PRDID)
+	}
+	if status := parent.Passes.String(); status != StatusOpen && status != StatusActive {
+		return fmt.Errorf("PRD %s is %s, only open or active PRDs can be split", parent.ID, status)
+	}
+	if !validID.MatchString(parent.ID) {
+		return fmt.Errorf("PRD ID %q cannot prefix child IDs", parent.ID)
+	}
+	if n := len(proposal.Children); n < MinSplitChildren || n > MaxSplitChildren {
+		return fmt.Errorf("split of %s proposes %d PRDs, want %d-%d", parent.ID, n, MinSplitChildren, MaxSplitChildren)
+	}
+	if depth := SplitDepth(p, parent.ID); depth >= MaxSplitDepth {
+		return fmt.Errorf("PRD %s was already split %d time(s), the limit is %d", parent.ID, depth, MaxSplitDepth)
+	}
+	for i, c := range proposal.Children {
+		if strings.TrimSpace(c.Description) == "" {
+			return fmt.Errorf("split child %d of %s has no description", i+1, parent.ID)
+		}
+		if len(c.AcceptanceCriteria) == 0 {
+			return fmt.Errorf("split child %d of %s has no acceptance criteria", i+1, parent.ID)
+		}
+	}
+	return nil
+}
+
+// Split creates the proposed children and blocks the parent behind them
+// Children are named <parent>-<n> and inherit the parent's priority, owner
+// and dependencies. The parent is kept as a record of the split rather than
+// removed, depending on every child, until CompleteSplitParents completes it.
+// Returns the new PRD IDs.
+func (p *PRDFileData) Split(proposal SplitProposal, iteration int) ([]string, error) {
+	if err := p.CheckSplit(proposal); err != nil {
+		return nil, err
+	}
+	parent := *p.FindByID(proposal.PRDID)
+
+	var ids []string
+	n := 1
+	for _, c := range proposal.Children {
+		id := fmt.Sprintf("%s-%d", parent.ID, n)
+		for p.FindByID(id) != nil {
+			n++
+			id = fmt.Sprintf("%s-%d", parent.ID, n)
+		}
+		n++
+
+		child := PRD{
+			ID:                 id,
+			Description:        strings.TrimSpace(c.Description),
+			AcceptanceCriteria: c.AcceptanceCriteria,
+			Priority:           parent.Priority,
+			Owner:              parent.Owner,
+			DependsOn:          append([]string(nil), parent.DependsOn...),
+			SplitFrom:          parent.ID,
+		}
+		if err := p.Add(child); err != nil {
+			return nil, fmt.Errorf("failed to add split child: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Add may have grown the slice, so look the parent up again
+	orig := p.FindByID(parent.ID)
+	if err := orig.Transition(StatusBlocked, iteration); err != nil {
+		return nil, err
+	}
+	orig.DependsOn = append(orig.DependsOn, ids...)
+	note := fmt.Sprintf("[split] into %s", strings.Join(ids, ", "))
+	if proposal.Reason != "" {
+		note += ": " + proposal.Reason
+	}
+	orig.AppendNote(note)
+	return ids, nil
+}
+
+// CompleteSplitParents completes each blocked PRD whose split children are all
+// complete, and returns their IDs
+// The parent's work was done by its children, so it moves straight from
+// blocked to complete, a step the state machine otherwise refuses. A parent
+// split from another completes in the same pass once it does.
+func (p *PRDFileData) CompleteSplitParents(iteration int) []string {
+	var completed []string
+	for changed := true; changed; {
+		changed = false
+		for i := range p.PRDs {
+			parent := &p.PRDs[i]
+			if !parent.Passes.IsBlocked() || !p.splitChildrenComplete(parent.ID) {
+				continue
+			}
+			parent.Passes.SetTrue()
+			parent.ActivePlan = ""
+			parent.appendHistory(StatusBlocked, StatusComplete, iteration)
+			parent.AppendNote("[split] every child is complete")
+			completed = append(completed, parent.ID)
+			changed = true
+		}
+	}
+	return completed
+}
+
+// splitChildrenComplete reports whether the PRD was split and every PRD split
+// from it is complete
+func (p *PRDFileData) splitChildrenComplete(id string) bool {
+	children := 0
+	for i := range p.PRDs {
+		if p.PRDs[i].SplitFrom != id {
+			continue
+		}
+		if !p.PRDs[i].Passes.IsTrue() {
+			return false
+		}
+		children++
+	}
+	return children > 0
+}
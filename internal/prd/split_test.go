@@ -0,0 +1,162 @@
+package prd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "big", Description: "everything", AcceptanceCriteria: []string{"a", "b"}, Priority: 2, Owner: "ana", DependsOn: []string{"base"}},
+		{ID: "big-1", Description: "taken", AcceptanceCriteria: []string{"x"}},
+		{ID: "base", Description: "base", AcceptanceCriteria: []string{"x"}, Passes: PassesStatus{Value: true}},
+	}}
+	prdFile.PRDs[0].Passes.SetFalse()
+	prdFile.PRDs[1].Passes.SetFalse()
+
+	ids, err := prdFile.Split(SplitProposal{
+		PRDID:  "big",
+		Reason: "too broad",
+		Children: []SplitChild{
+			{Description: "part a", AcceptanceCriteria: []string{"a"}},
+			{Description: "part b", AcceptanceCriteria: []string{"b"}},
+		},
+	}, 3)
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+	if strings.Join(ids, ",") != "big-2,big-3" {
+		t.Fatalf("Split() ids = %v, want big-2,big-3 (skipping the taken big-1)", ids)
+	}
+
+	for _, id := range ids {
+		c := prdFile.FindByID(id)
+		if c.Priority != 2 || c.Owner != "ana" || c.SplitFrom != "big" || !c.Passes.IsFalse() {
+			t.Errorf("child %s did not inherit from parent: %+v", id, c)
+		}
+		if len(c.DependsOn) != 1 || c.DependsOn[0] != "base" {
+			t.Errorf("child %s DependsOn = %v, want [base]", id, c.DependsOn)
+		}
+	}
+
+	parent := prdFile.FindByID("big")
+	if !parent.Passes.IsBlocked() {
+		t.Errorf("parent status = %s, want blocked", parent.Passes)
+	}
+	if strings.Join(parent.DependsOn, ",") != "base,big-2,big-3" {
+		t.Errorf("parent DependsOn = %v", parent.DependsOn)
+	}
+	if !strings.Contains(parent.Notes, "[split] into big-2, big-3: too broad") {
+		t.Errorf("parent notes = %q", parent.Notes)
+	}
+	if len(parent.History) != 1 || parent.History[0].Iteration != 3 {
+		t.Errorf("parent history = %+v", parent.History)
+	}
+}
+
+func TestSplitCycle(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "big", Description: "everything", AcceptanceCriteria: []string{"a", "b", "c"}},
+	}}
+	prdFile.PRDs[0].Passes.SetFalse()
+
+	three := []SplitChild{
+		{Description: "part a", AcceptanceCriteria: []string{"a"}},
+		{Description: "part b", AcceptanceCriteria: []string{"b"}},
+		{Description: "part c", AcceptanceCriteria: []string{"c"}},
+	}
+	if _, err := prdFile.Split(SplitProposal{PRDID: "big", Children: three}, 1); err != nil {
+		t.Fatalf("Split(big) error = %v", err)
+	}
+	// A child is split again, nesting the cycle
+	if _, err := prdFile.Split(SplitProposal{PRDID: "big-3", Children: three[:2]}, 2); err != nil {
+		t.Fatalf("Split(big-3) error = %v", err)
+	}
+
+	complete := func(id string, iteration int) {
+		t.Helper()
+		c := prdFile.FindByID(id)
+		for _, status := range []string{StatusActive, StatusPending, StatusComplete} {
+			if err := c.Transition(status, iteration); err != nil {
+				t.Fatalf("Transition(%s, %s) error = %v", id, status, err)
+			}
+		}
+	}
+	status := func(id string) string { return prdFile.FindByID(id).Passes.String() }
+
+	complete("big-1", 3)
+	complete("big-2", 3)
+	complete("big-3-1", 3)
+	if got := prdFile.CompleteSplitParents(3); len(got) != 0 {
+		t.Errorf("CompleteSplitParents() = %v with big-3-2 open", got)
+	}
+	if status("big") != StatusBlocked || status("big-3") != StatusBlocked {
+		t.Errorf("parents completed early: big %s, big-3 %s", status("big"), status("big-3"))
+	}
+
+	complete("big-3-2", 4)
+	if got := prdFile.CompleteSplitParents(4); strings.Join(got, ",") != "big-3,big" {
+		t.Errorf("CompleteSplitParents() = %v, want big-3,big", got)
+	}
+	for _, id := range []string{"big", "big-3"} {
+		p := prdFile.FindByID(id)
+		last := p.History[len(p.History)-1]
+		if !p.Passes.IsTrue() || last.From != StatusBlocked || last.To != StatusComplete || last.Iteration != 4 {
+			t.Errorf("%s = %s, last transition %+v", id, p.Passes, last)
+		}
+	}
+	if got := prdFile.CompleteSplitParents(5); len(got) != 0 {
+		t.Errorf("CompleteSplitParents() completed %v twice", got)
+	}
+
+	// A PRD blocked for another reason is left alone
+	blocked := &PRDFileData{PRDs: []PRD{{ID: "stuck"}}}
+	blocked.PRDs[0].Passes.SetBlocked()
+	if got := blocked.CompleteSplitParents(1); len(got) != 0 {
+		t.Errorf("CompleteSplitParents() = %v for a PRD that was never split", got)
+	}
+}
+
+func TestCheckSplitGuards(t *testing.T) {
+	two := []SplitChild{
+		{Description: "a", AcceptanceCriteria: []string{"a"}},
+		{Description: "b", AcceptanceCriteria: []string{"b"}},
+	}
+	prdFile := &PRDFileData{PRDs: []PRD{
+		{ID: "root", Description: "root"},
+		{ID: "root-1", Description: "child", SplitFrom: "root"},
+		{ID: "root-1-1", Description: "grandchild", SplitFrom: "root-1"},
+		{ID: "done", Description: "done", Passes: PassesStatus{Value: true}},
+	}}
+	for i := range prdFile.PRDs[:3] {
+		prdFile.PRDs[i].Passes.SetFalse()
+	}
+
+	tests := []struct {
+		name     string
+		proposal SplitProposal
+		wantErr  string
+	}{
+		{"ok", SplitProposal{PRDID: "root-1", Children: two}, ""},
+		{"missing", SplitProposal{PRDID: "nope", Children: two}, "not found"},
+		{"complete", SplitProposal{PRDID: "done", Children: two}, "only open or active"},
+		{"too few", SplitProposal{PRDID: "root", Children: two[:1]}, "proposes 1 PRDs"},
+		{"too many", SplitProposal{PRDID: "root", Children: make([]SplitChild, MaxSplitChildren+1)}, "proposes 9 PRDs"},
+		{"too deep", SplitProposal{PRDID: "root-1-1", Children: two}, "already split 2 time(s)"},
+		{"no criteria", SplitProposal{PRDID: "root", Children: []SplitChild{two[0], {Description: "c"}}}, "no acceptance criteria"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := prdFile.CheckSplit(tt.proposal)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckSplit() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckSplit() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package prd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReadTextFile reads a text file for embedding in prompts
+// Invalid UTF-8 bytes are replaced with U+FFFD and CRLF line endings are
+// normalized to LF, so binary pastes or files edited on Windows cannot
+// confuse line handling or the agents.
+func ReadTextFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return sanitizeText(data), nil
+}
+
+// sanitizeText replaces invalid UTF-8 and normalizes line endings
+func sanitizeText(data []byte) string {
+	text := string(data)
+	if !utf8.Valid(data) {
+		text = strings.ToValidUTF8(text, string(utf8.RuneError))
+	}
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
+
+// TextFileIssues reports encoding problems in a text file without changing it
+// An empty result means the file is valid UTF-8 with LF line endings.
+func TextFileIssues(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []string
+	if !utf8.Valid(data) {
+		invalid, firstLine := 0, 0
+		line := 1
+		for i := 0; i < len(data); {
+			r, size := utf8.DecodeRune(data[i:])
+			if r == utf8.RuneError && size == 1 {
+				invalid++
+				if firstLine == 0 {
+					firstLine = line
+				}
+			} else if r == '\n' {
+				line++
+			}
+			i += size
+		}
+		issues = append(issues, fmt.Sprintf("%d invalid UTF-8 byte(s), first on line %d", invalid, firstLine))
+	}
+	if n := bytes.Count(data, []byte("\r\n")); n > 0 {
+		issues = append(issues, fmt.Sprintf("%d CRLF line ending(s)", n))
+	}
+
+	return issues, nil
+}
+
+// TruncationMarker replaces text cut from a description or notes
+const TruncationMarker = "…[truncated]"
+
+// Caps Save applies to each PRD, in characters; 0 means no cap
+var maxDescription, maxNotes int
+
+// SetTextLimits caps the description and notes Save writes, in characters
+// Text over a cap is cut and marked, so runaway agent writes cannot bloat
+// prd.json and every prompt that embeds it. 0, the default, disables a cap.
+func SetTextLimits(description, notes int) {
+	maxDescription, maxNotes = description, notes
+}
+
+// enforceTextLimits truncates oversized descriptions and notes in place and
+// describes each cut. Descriptions keep their start; notes keep their end,
+// where the newest entries are.
+func (p *PRDFileData) enforceTextLimits() []string {
+	var cuts []string
+	for i := range p.PRDs {
+		prd := &p.PRDs[i]
+		if text, cut := truncateHead(prd.Description, maxDescription); cut > 0 {
+			prd.Description = text
+			cuts = append(cuts, fmt.Sprintf("%s: description truncated by %d characters", prd.ID, cut))
+		}
+		if text, cut := truncateTail(prd.Notes, maxNotes); cut > 0 {
+			prd.Notes = text
+			cuts = append(cuts, fmt.Sprintf("%s: notes truncated by %d characters", prd.ID, cut))
+		}
+	}
+	return cuts
+}
+
+// truncateHead keeps the first limit characters of s, marker included, and
+// returns how many were cut
+func truncateHead(s string, limit int) (string, int) {
+	runes := []rune(s)
+	if limit <= 0 || len(runes) <= limit {
+		return s, 0
+	}
+	keep := max(limit-utf8.RuneCountInString(TruncationMarker), 0)
+	return string(runes[:keep]) + TruncationMarker, len(runes) - keep
+}
+
+// truncateTail keeps the last limit characters of s, marker included, and
+// returns how many were cut
+func truncateTail(s string, limit int) (string, int) {
+	runes := []rune(s)
+	if limit <= 0 || len(runes) <= limit {
+		return s, 0
+	}
+	keep := max(limit-utf8.RuneCountInString(TruncationMarker), 0)
+	return TruncationMarker + string(runes[len(runes)-keep:]), len(runes) - keep
+}
@@ -0,0 +1,86 @@
+package prd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestReadTextFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.md")
+	if err := os.WriteFile(path, []byte("ok\r\nbad \xff\xfe byte\r\nend"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadTextFile(path)
+	if err != nil {
+		t.Fatalf("ReadTextFile: %v", err)
+	}
+	if want := "ok\nbad � byte\nend"; got != want {
+		t.Errorf("ReadTextFile() = %q, want %q", got, want)
+	}
+
+	issues, err := TextFileIssues(path)
+	if err != nil {
+		t.Fatalf("TextFileIssues: %v", err)
+	}
+	if len(issues) != 2 || issues[0] != "2 invalid UTF-8 byte(s), first on line 2" || issues[1] != "2 CRLF line ending(s)" {
+		t.Errorf("unexpected issues: %q", issues)
+	}
+}
+
+func TestTextFileIssuesClean(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.md")
+	if err := os.WriteFile(path, []byte("# Progress\nAll good ✓\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := TextFileIssues(path)
+	if err != nil {
+		t.Fatalf("TextFileIssues: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %q", issues)
+	}
+}
+
+func TestEnforceTextLimits(t *testing.T) {
+	SetTextLimits(20, 20)
+	defer SetTextLimits(0, 0)
+
+	marker := utf8.RuneCountInString(TruncationMarker)
+	data := &PRDFileData{PRDs: []PRD{
+		{ID: "fits", Description: strings.Repeat("d", 20), Notes: strings.Repeat("é", 20)},
+		{ID: "over", Description: strings.Repeat("d", 21), Notes: "old note\n" + strings.Repeat("n", 20)},
+	}}
+
+	cuts := data.enforceTextLimits()
+	if len(cuts) != 2 {
+		t.Fatalf("cuts = %v, want the description and notes of 'over'", cuts)
+	}
+
+	if fits := data.PRDs[0]; len(fits.Description) != 20 || utf8.RuneCountInString(fits.Notes) != 20 {
+		t.Errorf("text at the limit was changed: %+v", fits)
+	}
+
+	over := data.PRDs[1]
+	if want := strings.Repeat("d", 20-marker) + TruncationMarker; over.Description != want {
+		t.Errorf("description = %q, want %q", over.Description, want)
+	}
+	if want := TruncationMarker + strings.Repeat("n", 20-marker); over.Notes != want {
+		t.Errorf("notes = %q, want the newest text %q", over.Notes, want)
+	}
+	if want := fmt.Sprintf("over: description truncated by %d characters", 1+marker); cuts[0] != want {
+		t.Errorf("cuts[0] = %q, want %q", cuts[0], want)
+	}
+
+	// No caps, no cuts
+	SetTextLimits(0, 0)
+	data.PRDs[1].Description = strings.Repeat("d", 1000)
+	if cuts := data.enforceTextLimits(); len(cuts) != 0 {
+		t.Errorf("uncapped cuts = %v", cuts)
+	}
+}
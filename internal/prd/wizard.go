@@ -0,0 +1,291 @@
+package prd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Wizard steps, in order
+const (
+	stepDescription = iota
+	stepPriority
+	stepCriteria
+	stepOwner
+	stepConfirm
+)
+
+// idWords is how many words of the description go into a generated ID
+const idWords = 5
+
+// Wizard is an interactive form for authoring a new PRD without an agent
+type Wizard struct {
+	basePath string
+	existing *PRDFileData
+	step     int
+	inputs   [stepConfirm]textinput.Model // One input per step before confirm
+	criteria []string
+	err      error
+	created  *PRD // Set once the PRD is saved
+}
+
+// NewWizard creates a wizard for a PRD to be added to prdFile
+func NewWizard(basePath string, prdFile *PRDFileData) *Wizard {
+	w := &Wizard{basePath: basePath, existing: prdFile}
+
+	placeholders := [stepConfirm]string{
+		stepDescription: "What should be built, and why",
+		stepPriority:    strconv.Itoa(NextPriority(prdFile)),
+		stepCriteria:    "A testable condition (empty Enter when done)",
+		stepOwner:       "optional",
+	}
+	for i := range w.inputs {
+		ti := textinput.New()
+		ti.Placeholder = placeholders[i]
+		ti.Width = 60
+		w.inputs[i] = ti
+	}
+	w.inputs[stepDescription].Focus()
+	return w
+}
+
+// RunWizard starts the interactive PRD wizard and returns the created PRD,
+// or nil if the user cancelled
+func RunWizard(basePath string) (*PRD, error) {
+	prdFile, err := Load(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	w := NewWizard(basePath, prdFile)
+	if _, err := tea.NewProgram(w, tea.WithAltScreen()).Run(); err != nil {
+		return nil, fmt.Errorf("wizard error: %w", err)
+	}
+	return w.created, nil
+}
+
+// NextPriority returns a priority one past the lowest-ranked PRD
+func NextPriority(prdFile *PRDFileData) int {
+	next := 1
+	for _, p := range prdFile.PRDs {
+		if p.Priority >= next {
+			next = p.Priority + 1
+		}
+	}
+	return next
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// GenerateID derives a kebab-case ID from a description plus a random
+// suffix, e.g. "add-caching-layer-a1b2", avoiding IDs already in prdFile
+func GenerateID(description string, prdFile *PRDFileData) string {
+	words := strings.Fields(nonSlug.ReplaceAllString(strings.ToLower(description), " "))
+	if len(words) > idWords {
+		words = words[:idWords]
+	}
+	slug := strings.Join(words, "-")
+	if slug == "" {
+		slug = "prd"
+	}
+
+	for {
+		buf := make([]byte, 2)
+		rand.Read(buf)
+		id := slug + "-" + hex.EncodeToString(buf)
+		if prdFile.FindByID(id) == nil {
+			return id
+		}
+	}
+}
+
+// Add appends a new open PRD stamped with its creation time, rejecting
+// duplicate IDs and empty descriptions
+func (p *PRDFileData) Add(n PRD) error {
+	if strings.TrimSpace(n.Description) == "" {
+		return fmt.Errorf("description is required")
+	}
+	if !validID.MatchString(n.ID) {
+		return fmt.Errorf("invalid PRD ID %q", n.ID)
+	}
+	if p.FindByID(n.ID) != nil {
+		return fmt.Errorf("PRD %s already exists", n.ID)
+	}
+	n.Passes.SetFalse()
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	p.PRDs = append(p.PRDs, n)
+	return nil
+}
+
+// Init implements the bubbletea Model interface
+func (w *Wizard) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update implements the bubbletea Model interface
+func (w *Wizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyCtrlC, tea.KeyEscape:
+			return w, tea.Quit
+
+		case tea.KeyShiftTab, tea.KeyUp:
+			if w.step > stepDescription {
+				w.goTo(w.step - 1)
+			}
+			return w, nil
+
+		case tea.KeyEnter, tea.KeyTab:
+			if w.step == stepConfirm {
+				if err := w.save(); err != nil {
+					w.err = err
+					return w, nil
+				}
+				return w, tea.Quit
+			}
+			if err := w.advance(); err != nil {
+				w.err = err
+			}
+			return w, nil
+		}
+	}
+
+	if w.step == stepConfirm {
+		return w, nil
+	}
+	var cmd tea.Cmd
+	w.inputs[w.step], cmd = w.inputs[w.step].Update(msg)
+	return w, cmd
+}
+
+// advance validates the current step and moves to the next one
+// On the criteria step a non-empty entry is added and the step repeats.
+func (w *Wizard) advance() error {
+	value := strings.TrimSpace(w.inputs[w.step].Value())
+
+	switch w.step {
+	case stepDescription:
+		if value == "" {
+			return fmt.Errorf("description is required")
+		}
+	case stepPriority:
+		if value != "" {
+			if n, err := strconv.Atoi(value); err != nil || n < 1 {
+				return fmt.Errorf("priority must be a positive number")
+			}
+		}
+	case stepCriteria:
+		if value != "" {
+			w.criteria = append(w.criteria, value)
+			w.inputs[stepCriteria].SetValue("")
+			w.err = nil
+			return nil
+		}
+		if len(w.criteria) == 0 {
+			return fmt.Errorf("add at least one acceptance criterion")
+		}
+	}
+
+	w.goTo(w.step + 1)
+	return nil
+}
+
+// goTo moves focus to step
+func (w *Wizard) goTo(step int) {
+	if w.step < stepConfirm {
+		w.inputs[w.step].Blur()
+	}
+	w.step = step
+	if w.step < stepConfirm {
+		w.inputs[w.step].Focus()
+	}
+	w.err = nil
+}
+
+// build assembles the PRD from the wizard's fields
+func (w *Wizard) build() PRD {
+	description := strings.TrimSpace(w.inputs[stepDescription].Value())
+	priority := NextPriority(w.existing)
+	if n, err := strconv.Atoi(strings.TrimSpace(w.inputs[stepPriority].Value())); err == nil {
+		priority = n
+	}
+	return PRD{
+		ID:                 GenerateID(description, w.existing),
+		Description:        description,
+		AcceptanceCriteria: w.criteria,
+		Priority:           priority,
+		Owner:              strings.TrimSpace(w.inputs[stepOwner].Value()),
+	}
+}
+
+// save adds the PRD to a fresh copy of prd.json and writes it
+func (w *Wizard) save() error {
+	prdFile, err := Load(w.basePath)
+	if err != nil {
+		return err
+	}
+	w.existing = prdFile
+
+	n := w.build()
+	if err := prdFile.Add(n); err != nil {
+		return err
+	}
+	if err := Save(w.basePath, prdFile); err != nil {
+		return err
+	}
+	w.created = prdFile.FindByID(n.ID)
+	return nil
+}
+
+// View implements the bubbletea Model interface
+func (w *Wizard) View() string {
+	var s strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		MarginBottom(1)
+	labelStyle := lipgloss.NewStyle().
+		Width(15).
+		Align(lipgloss.Right).
+		Foreground(lipgloss.Color("8"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	s.WriteString(headerStyle.Render("New PRD") + "\n")
+	s.WriteString("Enter/Tab for next • Shift+Tab/↑ for previous • ESC to cancel\n\n")
+
+	labels := [stepConfirm]string{"Description", "Priority", "Criterion", "Owner"}
+	for i, label := range labels {
+		if i == stepCriteria {
+			for n, c := range w.criteria {
+				s.WriteString(labelStyle.Render(fmt.Sprintf("%d.", n+1)) + "  " + c + "\n")
+			}
+		}
+		field := w.inputs[i].View()
+		if i == w.step {
+			field = lipgloss.NewStyle().Background(lipgloss.Color("8")).Padding(0, 1).Render(field)
+		}
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Center, labelStyle.Render(label+":"), "  ", field) + "\n")
+	}
+
+	s.WriteString("\n")
+	if w.step == stepConfirm {
+		s.WriteString(lipgloss.NewStyle().Bold(true).Render("Press Enter to save, Shift+Tab to go back") + "\n")
+	}
+	if w.err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✗ "+w.err.Error()) + "\n")
+	}
+
+	s.WriteString(dimStyle.MarginTop(1).Render("[Enter] Next  [ESC] Cancel") + "\n")
+	return s.String()
+}
@@ -0,0 +1,92 @@
+package prd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeText(w *Wizard, text string) {
+	w.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+	w.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestWizardCreatesPRD(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := &PRDFileData{PRDs: []PRD{{ID: "old", Description: "old", Priority: 4}}}
+	if err := Save(dir, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWizard(dir, existing)
+	w.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if w.err == nil || w.step != stepDescription {
+		t.Fatal("empty description should be rejected")
+	}
+
+	typeText(w, "Add a caching layer to the API!")
+	typeText(w, "")                          // Default priority
+	w.Update(tea.KeyMsg{Type: tea.KeyEnter}) // No criteria yet
+	if w.err == nil || w.step != stepCriteria {
+		t.Fatal("at least one criterion should be required")
+	}
+	typeText(w, "Responses are cached for 60s")
+	typeText(w, "Cache can be disabled")
+	typeText(w, "") // Done with criteria
+	typeText(w, "alice")
+	if w.step != stepConfirm {
+		t.Fatalf("step = %d, want confirm", w.step)
+	}
+	w.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if w.created == nil {
+		t.Fatalf("PRD not created: %v", w.err)
+	}
+	reloaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := reloaded.FindByID(w.created.ID)
+	if p == nil {
+		t.Fatalf("%s not saved", w.created.ID)
+	}
+	if !regexp.MustCompile(`^add-a-caching-layer-to-[0-9a-f]{4}$`).MatchString(p.ID) {
+		t.Errorf("ID = %q", p.ID)
+	}
+	if p.Priority != 5 || len(p.AcceptanceCriteria) != 2 || p.Owner != "alice" || !p.Passes.IsFalse() {
+		t.Errorf("unexpected PRD %+v", p)
+	}
+}
+
+func TestWizardCancel(t *testing.T) {
+	w := NewWizard(t.TempDir(), &PRDFileData{})
+	typeText(w, "Something")
+	if _, cmd := w.Update(tea.KeyMsg{Type: tea.KeyEscape}); cmd == nil {
+		t.Error("ESC should quit")
+	}
+	if w.created != nil {
+		t.Error("cancelling must not create a PRD")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	prdFile := &PRDFileData{PRDs: []PRD{{ID: "a", Description: "a"}}}
+	if err := prdFile.Add(PRD{ID: "a", Description: "dup"}); err == nil {
+		t.Error("expected duplicate ID to be rejected")
+	}
+	if err := prdFile.Add(PRD{ID: "b"}); err == nil {
+		t.Error("expected empty description to be rejected")
+	}
+	if err := prdFile.Add(PRD{ID: "b", Description: "b", Passes: PassesStatus{Value: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if !prdFile.FindByID("b").Passes.IsFalse() {
+		t.Error("new PRDs should start open")
+	}
+}
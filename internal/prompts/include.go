@@ -0,0 +1,55 @@
+package prompts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/daydemir/milhouse/internal/display"
+)
+
+// includeDirective pulls a shared snippet into an augmentation file:
+// {{include "common.md"}}, with the path relative to .milhouse/prompts/
+var includeDirective = regexp.MustCompile(`\{\{\s*include\s+"([^"]+)"\s*\}\}`)
+
+// maxIncludeDepth bounds how deeply included files may include others
+const maxIncludeDepth = 5
+
+// resolveIncludes replaces the include directives in content, read from the
+// file chain ends with, with the named files from dir, resolved in turn
+// A missing file, a cycle or nesting past maxIncludeDepth drops that
+// directive with a warning and keeps the rest of the prompt.
+func resolveIncludes(dir, content string, chain []string) string {
+	return includeDirective.ReplaceAllStringFunc(content, func(directive string) string {
+		name := includeDirective.FindStringSubmatch(directive)[1]
+		text, err := loadInclude(dir, name, chain)
+		if err != nil {
+			display.Warning(fmt.Sprintf("Skipping include in %s: %v", chain[len(chain)-1], err))
+			return ""
+		}
+		return text
+	})
+}
+
+// loadInclude reads one included file and resolves its own includes
+func loadInclude(dir, name string, chain []string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%q is outside %s", name, dir)
+	}
+	name = filepath.ToSlash(filepath.Clean(name))
+	if slices.Contains(chain, name) {
+		return "", fmt.Errorf("include cycle %s", strings.Join(slices.Concat(chain, []string{name}), " -> "))
+	}
+	if len(chain) > maxIncludeDepth {
+		return "", fmt.Errorf("%q is nested more than %d includes deep", name, maxIncludeDepth)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return resolveIncludes(dir, strings.TrimSpace(string(content)), slices.Concat(chain, []string{name})), nil
+}
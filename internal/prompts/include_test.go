@@ -0,0 +1,84 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// writePrompts creates .milhouse/prompts/ files from name -> content
+func writePrompts(t *testing.T, files map[string]string) string {
+	t.Helper()
+	base := t.TempDir()
+	dir := filepath.Join(base, prd.MillhouseDir, prd.PromptsDir)
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return base
+}
+
+func TestLoadAugmentationIncludes(t *testing.T) {
+	base := writePrompts(t, map[string]string{
+		"builder.md":      "# Builder\n{{include \"common.md\"}}\nRun make test.\n",
+		"common.md":       "Use tabs.\n{{ include \"shared/style.md\" }}\n",
+		"shared/style.md": "Wrap errors.\n",
+		"planner.md":      "Plan small.\n",
+		"reviewer.md":     "{{include \"missing.md\"}}\nCheck tests.\n",
+		"chat.md":         "{{include \"../outside.md\"}}Be brief.",
+		"../outside.md":   "secret",
+	})
+
+	if got, want := LoadAugmentation(base, "builder"), "# Builder\nUse tabs.\nWrap errors.\nRun make test."; got != want {
+		t.Errorf("nested includes = %q, want %q", got, want)
+	}
+	if got := LoadAugmentation(base, "planner"); got != "Plan small." {
+		t.Errorf("plain file = %q, want it unchanged", got)
+	}
+	if got := LoadAugmentation(base, "reviewer"); got != "Check tests." {
+		t.Errorf("missing include = %q, want it skipped", got)
+	}
+	if got := LoadAugmentation(base, "chat"); got != "Be brief." {
+		t.Errorf("include outside prompts/ = %q, want it skipped", got)
+	}
+	if got := LoadAugmentationSource(base, "builder"); !strings.Contains(got, `{{include "common.md"}}`) {
+		t.Errorf("LoadAugmentationSource() = %q, want the directive kept", got)
+	}
+}
+
+func TestLoadAugmentationIncludeCycle(t *testing.T) {
+	base := writePrompts(t, map[string]string{
+		"builder.md": "top\n{{include \"a.md\"}}",
+		"a.md":       "a\n{{include \"b.md\"}}",
+		"b.md":       "b\n{{include \"a.md\"}}{{include \"builder.md\"}}",
+	})
+	if got, want := LoadAugmentation(base, "builder"), "top\na\nb"; got != want {
+		t.Errorf("cyclic includes = %q, want %q", got, want)
+	}
+
+	dir := filepath.Join(base, prd.MillhouseDir, prd.PromptsDir)
+	if _, err := loadInclude(dir, "a.md", []string{"builder.md", "a.md", "b.md"}); err == nil || !strings.Contains(err.Error(), "builder.md -> a.md -> b.md -> a.md") {
+		t.Errorf("cycle error = %v", err)
+	}
+}
+
+func TestLoadAugmentationIncludeDepth(t *testing.T) {
+	files := map[string]string{"builder.md": `{{include "1.md"}}`}
+	for i := 1; i <= maxIncludeDepth+2; i++ {
+		files[string(rune('0'+i))+".md"] = string(rune('0'+i)) + `{{include "` + string(rune('0'+i+1)) + `.md"}}`
+	}
+	base := writePrompts(t, files)
+
+	got := LoadAugmentation(base, "builder")
+	if want := "12345"; got != want {
+		t.Errorf("deep includes = %q, want them cut at depth %d: %q", got, maxIncludeDepth, want)
+	}
+}
@@ -3,6 +3,7 @@ package prompts
 import (
 	"bytes"
 	"embed"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,9 @@ import (
 	"github.com/daydemir/milhouse/internal/prd"
 )
 
+// CustomDir holds mil x prompts under .milhouse/prompts/
+const CustomDir = "custom"
+
 //go:embed *.tmpl
 var templates embed.FS
 
@@ -20,17 +24,61 @@ var (
 	builderTmpl  *template.Template
 	reviewerTmpl *template.Template
 	chatTmpl     *template.Template
+	summaryTmpl  *template.Template
+	customTmpl   *template.Template
 )
 
+// templateFuncs resolve artifact locations at render time, since the
+// artifacts directory can be relocated per run
+var templateFuncs = template.FuncMap{
+	"plansDir":    func() string { return prd.AgentArtifactDir(prd.PlansDir) },
+	"evidenceDir": func() string { return prd.AgentArtifactDir(prd.EvidenceDir) },
+}
+
 func init() {
 	// Parse shared components first
-	sharedTmpl = template.Must(template.ParseFS(templates, "shared.tmpl"))
+	sharedTmpl = template.Must(template.New("shared.tmpl").Funcs(templateFuncs).ParseFS(templates, "shared.tmpl"))
 
 	// Parse each agent template with shared components
 	plannerTmpl = template.Must(template.Must(sharedTmpl.Clone()).ParseFS(templates, "planner.tmpl"))
 	builderTmpl = template.Must(template.Must(sharedTmpl.Clone()).ParseFS(templates, "builder.tmpl"))
 	reviewerTmpl = template.Must(template.Must(sharedTmpl.Clone()).ParseFS(templates, "reviewer.tmpl"))
 	chatTmpl = template.Must(template.ParseFS(templates, "chat.tmpl"))
+	summaryTmpl = template.Must(template.ParseFS(templates, "summarizer.tmpl"))
+	customTmpl = template.Must(template.Must(sharedTmpl.Clone()).ParseFS(templates, "custom.tmpl"))
+}
+
+// PhasePrompt is an agent prompt split into the fixed role and rules, passed
+// as the system prompt, and the data for this run, passed as the user prompt
+type PhasePrompt struct {
+	System string
+	User   string
+}
+
+// String joins both parts for callers that send a single prompt
+func (p PhasePrompt) String() string {
+	if p.System == "" {
+		return p.User
+	}
+	return p.System + "\n" + p.User
+}
+
+// renderPhase renders the <name>_system and <name>_user templates
+// A part that fails to render is left empty, like the single-prompt builders.
+func renderPhase(tmpl *template.Template, name string, data any) PhasePrompt {
+	render := func(part string) string {
+		var buf bytes.Buffer
+		// Must use Lookup to get the specific template, not the cloned base
+		t := tmpl.Lookup(name + "_" + part)
+		if t == nil {
+			return ""
+		}
+		if err := t.Execute(&buf, data); err != nil {
+			return ""
+		}
+		return buf.String()
+	}
+	return PhasePrompt{System: render("system"), User: render("user")}
 }
 
 // PlannerData contains data for the planner prompt template
@@ -40,20 +88,13 @@ type PlannerData struct {
 	ProgressContent     string // Last lines of progress.md
 	Timestamp           string // Current timestamp
 	PlannerAugmentation string // Optional project-specific planner guidance
+	SelectedPRD         string // PRD ID forced by --select (skips normal selection)
+	RunDirective        string // One-time instruction for this run (--seed-prompt)
 }
 
-// BuildPlannerPrompt renders the planner prompt template
-func BuildPlannerPrompt(data PlannerData) string {
-	var buf bytes.Buffer
-	// Must use Lookup to get the specific template, not the cloned base
-	tmpl := plannerTmpl.Lookup("planner.tmpl")
-	if tmpl == nil {
-		return ""
-	}
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return ""
-	}
-	return buf.String()
+// BuildPlannerPrompt renders the planner prompt templates
+func BuildPlannerPrompt(data PlannerData) PhasePrompt {
+	return renderPhase(plannerTmpl, "planner", data)
 }
 
 // BuilderData contains data for the builder prompt template
@@ -64,20 +105,16 @@ type BuilderData struct {
 	ProgressContent     string // Last lines of progress.md
 	Timestamp           string // Current timestamp
 	BuilderAugmentation string // Optional project-specific builder guidance
+	CriteriaChecklist   string // Numbered acceptance criteria of the active PRD
+	SubtaskChecklist    string // Numbered subtasks of the active PRD, with done state
+	ReadOnly            bool   // Preview mode: describe changes without making them
+	Resuming            bool   // Continuing work interrupted mid-build (--resume-prd)
+	RunDirective        string // One-time instruction for this run (--seed-prompt)
 }
 
-// BuildBuilderPrompt renders the builder prompt template
-func BuildBuilderPrompt(data BuilderData) string {
-	var buf bytes.Buffer
-	// Must use Lookup to get the specific template, not the cloned base
-	tmpl := builderTmpl.Lookup("builder.tmpl")
-	if tmpl == nil {
-		return ""
-	}
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return ""
-	}
-	return buf.String()
+// BuildBuilderPrompt renders the builder prompt templates
+func BuildBuilderPrompt(data BuilderData) PhasePrompt {
+	return renderPhase(builderTmpl, "builder", data)
 }
 
 // ReviewerData contains data for the reviewer prompt template
@@ -92,20 +129,23 @@ type ReviewerData struct {
 	PlannerPrompt        string            // Content of .milhouse/prompts/planner.md
 	BuilderPrompt        string            // Content of .milhouse/prompts/builder.md
 	ReviewerPrompt       string            // Content of .milhouse/prompts/reviewer.md
+	// Batch verification fields
+	BatchMode            bool              // True for a dedicated sweep over all pending PRDs
+	PendingIDs           []string          // IDs of every pending PRD within the run's scope
+	// Map of pending PRD ID to its numbered acceptance criteria
+	PendingCriteria      map[string]string
+	// Remote sync fields
+	RemoteStatus         string            // "up-to-date", "ahead-N" or "no-upstream"
+	RequirePushed        bool              // Reject work that is not pushed to upstream
+	// Map of pending PRD ID to post-build check summaries (test/build hooks)
+	CheckResults         map[string]string
+	RunDirective         string            // One-time instruction for this run (--seed-prompt)
+	Scope                string            // The run's PRD scope, e.g. "owner alice"; empty for all PRDs
 }
 
-// BuildReviewerPrompt renders the reviewer prompt template
-func BuildReviewerPrompt(data ReviewerData) string {
-	var buf bytes.Buffer
-	// Must use Lookup to get the specific template, not the cloned base
-	tmpl := reviewerTmpl.Lookup("reviewer.tmpl")
-	if tmpl == nil {
-		return ""
-	}
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return ""
-	}
-	return buf.String()
+// BuildReviewerPrompt renders the reviewer prompt templates
+func BuildReviewerPrompt(data ReviewerData) PhasePrompt {
+	return renderPhase(reviewerTmpl, "reviewer", data)
 }
 
 // ChatData contains data for the chat prompt template
@@ -129,9 +169,88 @@ func BuildChatPrompt(data ChatData) string {
 	return buf.String()
 }
 
+// SummarizerData contains data for the progress summarizer prompt template
+type SummarizerData struct {
+	Learnings   string // Patterns and learnings sections already in progress.md
+	Entries     string // Older progress entries to condense
+	EntryCount  int    // Number of entries in Entries
+	RecentCount int    // Newer entries kept verbatim after the summary
+}
+
+// BuildSummarizerPrompt renders the progress summarizer prompt template
+func BuildSummarizerPrompt(data SummarizerData) string {
+	var buf bytes.Buffer
+	if err := summaryTmpl.Execute(&buf, data); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// CustomData contains data for a mil x custom prompt
+type CustomData struct {
+	Name        string // Custom prompt name (file name without .md)
+	Task        string // Prompt file contents with arguments substituted
+	Timestamp   string // Current timestamp
+	AllowWrites bool   // phases.custom.allowWrites grants write tools
+}
+
+// BuildCustomPrompt renders the custom prompt template
+func BuildCustomPrompt(data CustomData) string {
+	var buf bytes.Buffer
+	// Must use Lookup to get the specific template, not the cloned base
+	tmpl := customTmpl.Lookup("custom.tmpl")
+	if tmpl == nil {
+		return ""
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// FormatCriteria renders acceptance criteria as a numbered checklist
+// Numbering lets agents reference individual criteria in evidence and verdicts.
+func FormatCriteria(criteria []string) string {
+	if len(criteria) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, c := range criteria {
+		fmt.Fprintf(&sb, "%d. [ ] %s\n", i+1, strings.TrimSpace(c))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// FormatSubtasks renders subtasks as a numbered checklist with done state
+func FormatSubtasks(subtasks []prd.Subtask) string {
+	var sb strings.Builder
+	for i, s := range subtasks {
+		mark := " "
+		if s.Done {
+			mark = "x"
+		}
+		fmt.Fprintf(&sb, "%d. [%s] %s\n", i+1, mark, strings.TrimSpace(s.Description))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
 // LoadAugmentation reads a phase-specific augmentation file
 // Returns empty string if file doesn't exist (augmentations are optional)
+// {{include "file.md"}} directives are replaced with the named files from
+// the prompts directory.
 func LoadAugmentation(basePath, phase string) string {
+	content := LoadAugmentationSource(basePath, phase)
+	if content == "" {
+		return ""
+	}
+	dir := filepath.Dir(GetAugmentationPath(basePath, phase))
+	return strings.TrimSpace(resolveIncludes(dir, content, []string{phase + ".md"}))
+}
+
+// LoadAugmentationSource reads a phase's augmentation file as written,
+// leaving include directives in place for whoever edits it
+func LoadAugmentationSource(basePath, phase string) string {
 	augPath := GetAugmentationPath(basePath, phase)
 	content, err := os.ReadFile(augPath)
 	if err != nil {
@@ -153,6 +272,11 @@ func GetAugmentationPath(basePath, phase string) string {
 	return filepath.Join(basePath, prd.MillhouseDir, prd.PromptsDir, phase+".md")
 }
 
+// GetCustomPromptPath returns the path to a mil x custom prompt file
+func GetCustomPromptPath(basePath, name string) string {
+	return filepath.Join(basePath, prd.MillhouseDir, prd.PromptsDir, CustomDir, name+".md")
+}
+
 // EnsurePromptsDir creates the prompts directory if it doesn't exist
 func EnsurePromptsDir(basePath string) error {
 	promptsPath := filepath.Join(basePath, prd.MillhouseDir, prd.PromptsDir)
@@ -0,0 +1,94 @@
+package reviewer
+
+import (
+	"fmt"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// EvaluateCompletion runs the definition-of-done checks in cfg.Completion
+// against a PRD the reviewer verified, and returns whether it may become
+// complete with a reason for each failed check
+// phases.reviewer.requirePushed and requireEvidence also enable their checks.
+func EvaluateCompletion(p *prd.PRD, basePath string, cfg *config.Config) (bool, []string) {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	policy := cfg.Completion
+	requires := func(check string) bool {
+		switch check {
+		case config.CompletionPushed:
+			return policy.Requires(check) || cfg.GetPhaseConfig("reviewer").RequirePushed
+		case config.CompletionEvidence:
+			return policy.Requires(check) || cfg.RequireEvidence
+		}
+		return policy.Requires(check)
+	}
+
+	var reasons []string
+	for _, check := range config.AllCompletionChecks {
+		if !requires(check) {
+			continue
+		}
+		if reason := evaluateCheck(check, p, basePath); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return len(reasons) == 0, reasons
+}
+
+// evaluateCheck runs a single completion check, returning why it failed or ""
+func evaluateCheck(check string, p *prd.PRD, basePath string) string {
+	switch check {
+	case config.CompletionCriteria:
+		if len(p.AcceptanceCriteria) == 0 {
+			return "no acceptance criteria"
+		}
+
+	case config.CompletionChecks:
+		if !checksPassed(basePath, p.ID) {
+			return "post-build checks failed"
+		}
+
+	case config.CompletionPushed:
+		remote := &git.VerificationResult{RequirePushed: true}
+		remote.RemoteStatus, _ = git.CheckRemoteStatus(basePath)
+		if remote.PushPending() {
+			if remote.RemoteStatus == git.RemoteNoUpstream {
+				return "no upstream branch to push to"
+			}
+			return fmt.Sprintf("%d commit(s) not pushed to upstream", git.UnpushedCommits(remote.RemoteStatus))
+		}
+
+	case config.CompletionEvidence:
+		if _, err := prd.LoadEvidence(basePath, p.ID); err != nil {
+			return err.Error()
+		}
+
+	case config.CompletionCommit:
+		ev, err := prd.LoadEvidence(basePath, p.ID)
+		if ev == nil || len(ev.Commits) == 0 {
+			if err == nil {
+				err = fmt.Errorf("evidence names no commit")
+			}
+			return fmt.Sprintf("no commit to check: %v", err)
+		}
+		for _, sha := range ev.Commits {
+			if exists, _ := git.VerifyCommitExists(basePath, sha); !exists {
+				return fmt.Sprintf("commit %s named in evidence does not exist", sha)
+			}
+		}
+
+	case config.CompletionCleanTree:
+		changes, err := git.UncommittedChanges(basePath, prd.MillhouseDir)
+		if err != nil {
+			return err.Error()
+		}
+		if len(changes) > 0 {
+			return fmt.Sprintf("%d uncommitted change(s) outside %s/", len(changes), prd.MillhouseDir)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,216 @@
+package reviewer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/config"
+	milexec "github.com/daydemir/milhouse/internal/exec"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// completionRepo is a git repository with one commit and a .milhouse/ folder
+type completionRepo struct {
+	t    *testing.T
+	dir  string
+	head string
+}
+
+func newCompletionRepo(t *testing.T) *completionRepo {
+	t.Helper()
+	r := &completionRepo{t: t, dir: t.TempDir()}
+	r.git("init")
+	r.git("config", "user.email", "test@example.com")
+	r.git("config", "user.name", "Test")
+	r.write("main.go", "package main")
+	r.git("add", "main.go")
+	r.git("commit", "-m", "init")
+	r.head = r.git("rev-parse", "HEAD")
+	if err := os.MkdirAll(filepath.Join(r.dir, prd.MillhouseDir, prd.EvidenceDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func (r *completionRepo) git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %v: %v: %s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func (r *completionRepo) write(name, content string) {
+	r.t.Helper()
+	if err := os.WriteFile(filepath.Join(r.dir, name), []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// evidence writes the PRD's evidence file naming commits
+func (r *completionRepo) evidence(commits ...string) {
+	r.t.Helper()
+	content := "# Evidence: feat\n\n## Git Commits\n"
+	for _, sha := range commits {
+		content += "- " + sha + " Add feature\n"
+	}
+	if err := os.WriteFile(prd.GetEvidencePath(r.dir, "feat"), []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// checks records post-build check results with the given exit codes,
+// run against the current code
+func (r *completionRepo) checks(exitCodes ...int) {
+	r.t.Helper()
+	state, err := git.CaptureTree(r.dir, prd.MillhouseDir)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	tree, err := state.Fingerprint()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	var results []*milexec.Result
+	for _, code := range exitCodes {
+		results = append(results, &milexec.Result{Name: "test", Command: "go test ./...", ExitCode: code, Tree: tree})
+	}
+	if err := milexec.SaveResults(prd.GetChecksPath(r.dir, "feat"), results); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// push gives the repository an upstream holding its current HEAD
+func (r *completionRepo) push() {
+	r.t.Helper()
+	remote := r.t.TempDir()
+	r.git("init", "--bare", remote)
+	r.git("remote", "add", "origin", remote)
+	r.git("push", "-u", "origin", "HEAD")
+}
+
+func TestEvaluateCompletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    string
+		criteria bool
+		setup    func(r *completionRepo)
+		want     string // Expected reason, "" when the check passes
+	}{
+		{name: "criteria present", check: config.CompletionCriteria, criteria: true},
+		{name: "criteria missing", check: config.CompletionCriteria, want: "no acceptance criteria"},
+
+		{name: "checks not recorded", check: config.CompletionChecks},
+		{name: "checks passed", check: config.CompletionChecks, setup: func(r *completionRepo) { r.checks(0, 0) }},
+		{name: "checks failed", check: config.CompletionChecks, setup: func(r *completionRepo) { r.checks(0, 1) },
+			want: "post-build checks failed"},
+		{name: "checks failed then committed", check: config.CompletionChecks, setup: func(r *completionRepo) {
+			r.write("main.go", "package broken")
+			r.checks(1)
+			r.git("commit", "-am", "broken")
+		}, want: "post-build checks failed"},
+		{name: "checks failed then fixed", check: config.CompletionChecks, setup: func(r *completionRepo) {
+			r.checks(1)
+			r.write("main.go", "package fixed")
+		}},
+		{name: "checks failed then fixed with a new file", check: config.CompletionChecks, setup: func(r *completionRepo) {
+			r.checks(1)
+			r.write("fix.go", "package main")
+		}},
+		// Changes under .milhouse/ leave the code under test alone
+		{name: "checks failed then noted", check: config.CompletionChecks, setup: func(r *completionRepo) {
+			r.evidence()
+			r.git("add", prd.MillhouseDir)
+			r.git("commit", "-m", "evidence")
+			r.checks(1)
+			r.evidence(r.head)
+		}, want: "post-build checks failed"},
+
+		{name: "no upstream", check: config.CompletionPushed, want: "no upstream branch to push to"},
+		{name: "pushed", check: config.CompletionPushed, setup: func(r *completionRepo) { r.push() }},
+		{name: "not pushed", check: config.CompletionPushed, setup: func(r *completionRepo) {
+			r.push()
+			r.write("next.go", "package main")
+			r.git("add", "next.go")
+			r.git("commit", "-m", "next")
+		}, want: "1 commit(s) not pushed to upstream"},
+
+		{name: "evidence missing", check: config.CompletionEvidence, want: "no evidence file at"},
+		{name: "evidence without commit", check: config.CompletionEvidence, setup: func(r *completionRepo) { r.evidence() },
+			want: "names no commit SHA"},
+		{name: "evidence with commit", check: config.CompletionEvidence, setup: func(r *completionRepo) { r.evidence(r.head) }},
+
+		{name: "commit without evidence", check: config.CompletionCommit, want: "no commit to check: no evidence file at"},
+		{name: "commit not named", check: config.CompletionCommit, setup: func(r *completionRepo) { r.evidence() },
+			want: "no commit to check: evidence file"},
+		{name: "commit exists", check: config.CompletionCommit, setup: func(r *completionRepo) { r.evidence(r.head[:7]) }},
+		{name: "commit missing", check: config.CompletionCommit, setup: func(r *completionRepo) { r.evidence(r.head, "deadbeef") },
+			want: "commit deadbeef named in evidence does not exist"},
+
+		// Evidence lives in .milhouse/, which does not count as a change
+		{name: "clean tree", check: config.CompletionCleanTree, setup: func(r *completionRepo) { r.evidence(r.head) }},
+		{name: "dirty tree", check: config.CompletionCleanTree, setup: func(r *completionRepo) { r.write("main.go", "package changed") },
+			want: "1 uncommitted change(s) outside .milhouse/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newCompletionRepo(t)
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+			p := &prd.PRD{ID: "feat"}
+			if tt.criteria {
+				p.AcceptanceCriteria = []string{"works"}
+			}
+			cfg := config.DefaultConfig()
+			cfg.Completion.Require = []string{tt.check}
+
+			ok, reasons := EvaluateCompletion(p, r.dir, cfg)
+			if tt.want == "" {
+				if !ok || len(reasons) != 0 {
+					t.Errorf("EvaluateCompletion() = %v %q, want it to pass", ok, reasons)
+				}
+				return
+			}
+			if ok || len(reasons) != 1 || !strings.Contains(reasons[0], tt.want) {
+				t.Errorf("EvaluateCompletion() = %v %q, want one reason containing %q", ok, reasons, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCompletionPolicy(t *testing.T) {
+	r := newCompletionRepo(t)
+	r.checks(1)
+	p := &prd.PRD{ID: "feat"}
+
+	// The default policy requires criteria and passing checks
+	_, reasons := EvaluateCompletion(p, r.dir, nil)
+	if want := []string{"no acceptance criteria", "post-build checks failed"}; !slices.Equal(reasons, want) {
+		t.Errorf("default policy reasons = %q, want %q", reasons, want)
+	}
+
+	// An empty policy requires nothing
+	cfg := config.DefaultConfig()
+	cfg.Completion.Require = []string{}
+	if ok, reasons := EvaluateCompletion(p, r.dir, cfg); !ok {
+		t.Errorf("empty policy = %q, want it to pass", reasons)
+	}
+
+	// requireEvidence and requirePushed turn on their checks
+	cfg.RequireEvidence = true
+	cfg.Phases.Reviewer.RequirePushed = true
+	_, reasons = EvaluateCompletion(p, r.dir, cfg)
+	if len(reasons) != 2 || reasons[0] != "no upstream branch to push to" || !strings.HasPrefix(reasons[1], "no evidence file at") {
+		t.Errorf("requirePushed and requireEvidence reasons = %q", reasons)
+	}
+}
@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/daydemir/milhouse/internal/config"
 	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/exec"
+	"github.com/daydemir/milhouse/internal/git"
 	"github.com/daydemir/milhouse/internal/llm"
 	"github.com/daydemir/milhouse/internal/prd"
 	"github.com/daydemir/milhouse/internal/prompts"
@@ -21,11 +22,149 @@ type ReviewerResult struct {
 	LoopRisk      []string // PRD IDs at risk of looping
 	PlanUpdated   []string // PRD IDs whose plans were updated (bailout handling)
 	PromptUpdated []string // Phase names whose prompts were updated
+	Unreviewed    []string // Pending PRD IDs that received no verdict (batch mode)
+	Held          []string // Verified PRD IDs kept pending by the completion policy
+	NoCriteria    []string // Verified PRD IDs without acceptance criteria, blocked for refinement
+	TotalTokens   int
 	Error         error
+	RateLimited   bool   // The API rate-limited the reviewer
+	Summary       string // One-line recap of the agent's work
+
+	Rejections  []RejectionDetail   // Reason for each rejection, in signal order
+	HeldReasons map[string][]string // Failed completion checks for each held PRD
+}
+
+// RejectionDetail is why the reviewer rejected a PRD
+type RejectionDetail struct {
+	ID     string
+	Reason string
 }
 
 // Run executes the reviewer agent
 func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, iteration int, cfg *config.Config) (*ReviewerResult, error) {
+	return run(ctx, basePath, prdFile, iteration, cfg, false)
+}
+
+// RunBatch executes the reviewer as a dedicated verification sweep over every
+// pending PRD. The agent only emits verdicts; the resulting state transitions
+// are applied in Go via ApplyVerdicts.
+func RunBatch(ctx context.Context, basePath string, prdFile *prd.PRDFileData, iteration int, cfg *config.Config) (*ReviewerResult, error) {
+	result, err := run(ctx, basePath, prdFile, iteration, cfg, true)
+	if err != nil {
+		return result, err
+	}
+
+	// Track pending PRDs the agent never ruled on
+	decided := make(map[string]bool)
+	for _, id := range result.Verified {
+		decided[id] = true
+	}
+	for _, id := range result.Rejected {
+		decided[id] = true
+	}
+	for _, id := range result.Held {
+		decided[id] = true
+	}
+	for _, id := range result.NoCriteria {
+		decided[id] = true
+	}
+	for _, p := range prdFile.GetPendingPRDsIn(cfg.Run.Scope().Work()) {
+		if !decided[p.ID] {
+			result.Unreviewed = append(result.Unreviewed, p.ID)
+		}
+	}
+
+	if err := ApplyVerdicts(basePath, result, iteration); err != nil {
+		result.Error = err
+		return result, err
+	}
+
+	return result, nil
+}
+
+// ApplyVerdicts applies reviewer verdicts to prd.json
+// Verified PRDs are promoted to complete and rejected PRDs are reverted to
+// open; plan files are deleted in both cases. A split PRD completes with the
+// last of its children.
+func ApplyVerdicts(basePath string, result *ReviewerResult, iteration int) error {
+	if len(result.Verified) == 0 && len(result.Rejected) == 0 {
+		return nil
+	}
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	apply := func(ids []string, status string) error {
+		for _, id := range ids {
+			p := prdFile.FindByID(id)
+			if p == nil {
+				display.Warning(fmt.Sprintf("Reviewer referenced unknown PRD: %s", id))
+				continue
+			}
+			if err := p.Transition(status, iteration); err != nil {
+				return err
+			}
+			if err := prd.DeletePlan(basePath, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := apply(result.Verified, prd.StatusComplete); err != nil {
+		return err
+	}
+	if err := apply(result.Rejected, prd.StatusOpen); err != nil {
+		return err
+	}
+	for _, id := range prdFile.CompleteSplitParents(iteration) {
+		display.Success(fmt.Sprintf("%s complete: every PRD split from it is done", id))
+	}
+
+	return prd.Save(basePath, prdFile)
+}
+
+// RecordRejections appends each rejection reason to the PRD's notes so the
+// next builder attempt knows what was wrong. Completed PRDs and rejections
+// without a reason are skipped. Returns the IDs that were noted.
+// iteration is 0 outside a run, as with mil review and mil approve, and is
+// then left out of the note.
+func RecordRejections(basePath string, rejections []RejectionDetail, iteration int) ([]string, error) {
+	if len(rejections) == 0 {
+		return nil, nil
+	}
+
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	var noted []string
+	for _, r := range rejections {
+		p := prdFile.FindByID(r.ID)
+		if p == nil || p.Passes.IsTrue() || r.Reason == "" {
+			continue
+		}
+		if iteration > 0 {
+			p.AppendNote(fmt.Sprintf("[rejected] iteration %d: %s", iteration, r.Reason))
+		} else {
+			p.AppendNote("[rejected] " + r.Reason)
+		}
+		noted = append(noted, p.ID)
+	}
+	if len(noted) == 0 {
+		return nil, nil
+	}
+
+	if err := prd.Save(basePath, prdFile); err != nil {
+		return nil, err
+	}
+	return noted, nil
+}
+
+func run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, iteration int, cfg *config.Config, batch bool) (*ReviewerResult, error) {
 	// Nil guard - use default config if none provided
 	if cfg == nil {
 		cfg = config.DefaultConfig()
@@ -33,23 +172,46 @@ func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, iterati
 
 	result := &ReviewerResult{}
 
-	prompt := buildReviewerPrompt(basePath, prdFile, iteration, cfg)
+	remote := checkRemote(basePath, cfg)
+	prompt := buildReviewerPrompt(basePath, prdFile, iteration, cfg, batch, remote)
+
+	if batch {
+		display.AgentHeader("reviewer", "batch verification")
+	} else {
+		display.AgentHeader("reviewer", "review")
+	}
 
-	display.AgentHeader("reviewer", "review")
+	model := reviewerModel(prdFile, cfg)
+	if model != cfg.GetPhaseConfig("reviewer").Model {
+		display.Info(fmt.Sprintf("Using model %s for the pending PRDs (prdModels)", model))
+	}
 
-	execResult, err := runClaude(ctx, basePath, prompt, cfg)
+	execResult, err := runClaude(ctx, basePath, prompt, model, cfg)
 	if err != nil {
 		result.Error = err
 		return result, err
 	}
 
-	// Process signals from the reviewer output
+	result.TotalTokens = execResult.GetTokenStats().TotalTokens
+	result.RateLimited = execResult.RateLimited()
+	result.Summary = execResult.PhaseSummary("reviewer")
+
+	// Process signals from the reviewer output, leaving verdicts on PRDs
+	// outside the run's scope to the runs that own them
+	scope := cfg.Run.Scope().Work()
 	for _, signal := range execResult.GetSignals() {
+		if signal.Type == llm.SignalVerified || signal.Type == llm.SignalRejected {
+			if p := prdFile.FindByID(signal.PRDID); p != nil && !scope.Allows(p) {
+				display.Warning(fmt.Sprintf("Ignoring verdict on %s: outside %s", signal.PRDID, scope))
+				continue
+			}
+		}
 		switch signal.Type {
 		case llm.SignalVerified:
 			result.Verified = append(result.Verified, signal.PRDID)
 		case llm.SignalRejected:
 			result.Rejected = append(result.Rejected, signal.PRDID)
+			result.Rejections = append(result.Rejections, RejectionDetail{ID: signal.PRDID, Reason: signal.Details})
 		case llm.SignalLoopRisk:
 			result.LoopRisk = append(result.LoopRisk, signal.PRDID)
 		case llm.SignalPlanUpdated:
@@ -59,42 +221,246 @@ func Run(ctx context.Context, basePath string, prdFile *prd.PRDFileData, iterati
 		}
 	}
 
+	// Definition of done: a verified PRD only reaches complete when the
+	// completion policy passes too. Without criteria there is nothing to
+	// verify against, so the PRD is blocked; other failures keep it pending.
+	var verified []string
+	for _, id := range result.Verified {
+		p := prdFile.FindByID(id)
+		if p == nil {
+			verified = append(verified, id) // ApplyVerdicts reports unknown IDs
+			continue
+		}
+		if ok, reasons := EvaluateCompletion(p, basePath, cfg); ok {
+			verified = append(verified, id)
+		} else if cfg.Completion.Requires(config.CompletionCriteria) && len(p.AcceptanceCriteria) == 0 {
+			result.NoCriteria = append(result.NoCriteria, id)
+		} else {
+			result.Held = append(result.Held, id)
+			if result.HeldReasons == nil {
+				result.HeldReasons = make(map[string][]string)
+			}
+			result.HeldReasons[id] = reasons
+		}
+	}
+	result.Verified = verified
+
+	if cfg.GetPhaseConfig("reviewer").ReviewNotes {
+		recordReviews(basePath, result)
+	}
+
+	if len(result.Held) > 0 && !batch {
+		if err := holdPending(basePath, result.Held); err != nil {
+			result.Error = err
+			return result, err
+		}
+	}
+	if len(result.NoCriteria) > 0 {
+		if err := blockWithoutCriteria(basePath, result.NoCriteria); err != nil {
+			result.Error = err
+			return result, err
+		}
+	}
+
 	return result, nil
 }
 
+// recordReviews appends the reviewer's verdicts to the PRDs' evidence files
+// PRDs verified but held back by the completion policy are recorded as
+// verified with the reason they did not complete. PRDs without evidence and
+// unchanged verdicts are skipped by AppendEvidenceReview.
+func recordReviews(basePath string, result *ReviewerResult) {
+	record := func(id, verdict, reason string) {
+		if id == "" {
+			return
+		}
+		if err := prd.AppendEvidenceReview(basePath, id, verdict, reason); err != nil {
+			display.Warning(fmt.Sprintf("Failed to record review of %s: %v", id, err))
+		}
+	}
+	for _, id := range result.Verified {
+		record(id, prd.ReviewVerified, "")
+	}
+	for _, id := range result.Held {
+		record(id, prd.ReviewVerified, "held at pending: "+strings.Join(result.HeldReasons[id], "; "))
+	}
+	for _, id := range result.NoCriteria {
+		record(id, prd.ReviewVerified, "blocked: no acceptance criteria to verify against")
+	}
+	reasons := make(map[string]string)
+	for _, r := range result.Rejections {
+		reasons[r.ID] = r.Reason
+	}
+	for _, id := range result.Rejected {
+		record(id, prd.ReviewRejected, reasons[id])
+	}
+}
+
+// reviewerModel returns the model for a review: the prdModels pin shared by
+// every pending PRD within scope, or the reviewer's configured model when
+// they differ
+func reviewerModel(prdFile *prd.PRDFileData, cfg *config.Config) string {
+	model := ""
+	for _, p := range prdFile.GetPendingPRDsIn(cfg.Run.Scope().Work()) {
+		m := cfg.ModelForPRD("reviewer", p.ID)
+		if model != "" && m != model {
+			return cfg.GetPhaseConfig("reviewer").Model
+		}
+		model = m
+	}
+	if model == "" {
+		return cfg.GetPhaseConfig("reviewer").Model
+	}
+	return model
+}
+
+// CurrentChecks returns a PRD's recorded post-build check results, or none
+// when they are missing, unreadable or stale
+// Results are stale once the code outside .milhouse/, untracked files
+// included, differs from the code they ran against, e.g. after a fix made by
+// hand.
+func CurrentChecks(basePath, prdID string) []*exec.Result {
+	results, err := exec.LoadResults(prd.GetChecksPath(basePath, prdID))
+	if err != nil || len(results) == 0 {
+		return nil
+	}
+	// Results recorded without a tree, or outside git, cannot be told stale
+	if tree := results[0].Tree; tree != "" {
+		if state, err := git.CaptureTree(basePath, prd.MillhouseDir); err == nil {
+			if current, err := state.Fingerprint(); err == nil && current != tree {
+				return nil
+			}
+		}
+	}
+	return results
+}
+
+// checksPassed reports whether a PRD's current post-build checks all passed
+// PRDs without current checks pass.
+func checksPassed(basePath, prdID string) bool {
+	return exec.AllPassed(CurrentChecks(basePath, prdID))
+}
+
+// loadCheckSummaries returns current check result summaries for PRDs that
+// have them
+func loadCheckSummaries(basePath string, prds []prd.PRD) map[string]string {
+	summaries := make(map[string]string)
+	for _, p := range prds {
+		results := CurrentChecks(basePath, p.ID)
+		if len(results) == 0 {
+			continue
+		}
+		var parts []string
+		for _, r := range results {
+			parts = append(parts, r.Summary())
+		}
+		summaries[p.ID] = strings.Join(parts, "\n\n")
+	}
+	return summaries
+}
+
+// checkRemote reports the upstream status of HEAD for the reviewer context
+func checkRemote(basePath string, cfg *config.Config) *git.VerificationResult {
+	status, _ := git.CheckRemoteStatus(basePath)
+	return &git.VerificationResult{
+		RemoteStatus:  status,
+		RequirePushed: cfg.GetPhaseConfig("reviewer").RequirePushed,
+	}
+}
+
+// holdPending reverts PRDs the reviewer marked complete back to pending
+func holdPending(basePath string, ids []string) error {
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	for _, id := range ids {
+		if p := prdFile.FindByID(id); p != nil && p.Passes.IsTrue() {
+			p.Passes.SetPending()
+		}
+	}
+
+	return prd.Save(basePath, prdFile)
+}
+
+// blockWithoutCriteria blocks verified PRDs that have no acceptance criteria,
+// whatever state the reviewer left them in, so a human defines "done" first
+// A PRD that has gained criteria since the review is left alone.
+func blockWithoutCriteria(basePath string, ids []string) error {
+	prdFile, err := prd.Load(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to load PRDs: %w", err)
+	}
+
+	for _, id := range ids {
+		p := prdFile.FindByID(id)
+		if p == nil || len(p.AcceptanceCriteria) > 0 {
+			continue
+		}
+		p.Passes.SetBlocked()
+		p.ActivePlan = ""
+		p.AppendNote("[needs refinement] no acceptance criteria to verify against; add criteria and set passes back to false")
+		if err := prd.DeletePlan(basePath, id); err != nil {
+			return err
+		}
+	}
+
+	return prd.Save(basePath, prdFile)
+}
+
 // ShouldRunReviewer determines if the reviewer should run
-// It should run if there are pending PRDs, active PRDs (for bailout handling), or open PRDs
-func ShouldRunReviewer(prdFile *prd.PRDFileData) bool {
+// It should run if there are pending PRDs, active PRDs (for bailout handling),
+// or open PRDs within scope
+func ShouldRunReviewer(prdFile *prd.PRDFileData, scope prd.Scope) bool {
+	run, _ := ShouldRunReviewerWithReason(prdFile, scope)
+	return run
+}
+
+// ShouldRunReviewerWithReason is ShouldRunReviewer plus the rationale for the
+// decision
+func ShouldRunReviewerWithReason(prdFile *prd.PRDFileData, scope prd.Scope) (bool, string) {
+	groups := prdFile.Group()
+	scope = scope.Work()
+
 	// Always run if there are pending PRDs
-	if len(prdFile.GetPendingPRDs()) > 0 {
-		return true
+	if n := len(groups.PendingIn(scope)); n > 0 {
+		return true, fmt.Sprintf("%d pending PRD(s) to verify", n)
 	}
 
 	// Also run if there are active PRDs (to handle bailouts)
-	if len(prdFile.GetActivePRDs()) > 0 {
-		return true
+	if n := len(groups.ActiveIn(scope)); n > 0 {
+		return true, fmt.Sprintf("%d active PRD(s) to check for bailouts", n)
 	}
 
 	// Also run if there are open PRDs (to cross-pollinate observations)
-	if len(prdFile.GetOpenPRDs()) > 0 {
-		return true
+	if n := len(groups.OpenIn(scope)); n > 0 {
+		return true, fmt.Sprintf("%d open PRD(s) to cross-pollinate", n)
 	}
 
-	return false
+	if scope.Limited() {
+		return false, fmt.Sprintf("no pending, active or open PRDs within %s", scope)
+	}
+	return false, "no pending, active or open PRDs"
 }
 
-func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config) (*llm.ConsoleHandler, error) {
+func runClaude(ctx context.Context, basePath string, prompt prompts.PhasePrompt, model string, cfg *config.Config) (*llm.ConsoleHandler, error) {
 	phaseConfig := cfg.GetPhaseConfig("reviewer")
 
-	claude := llm.NewClaude("")
+	apiKey, err := cfg.Auth.APIKey()
+	if err != nil {
+		return nil, err
+	}
+	claude := llm.NewClaude("", apiKey)
 
 	// Create a cancellable context for this execution
 	execCtx, cancelExec := context.WithCancel(ctx)
 	defer cancelExec()
 
 	opts := llm.ExecuteOptions{
-		Prompt:       prompt,
-		Model:        phaseConfig.Model,
+		SystemPrompt: prompt.System,
+		Prompt:       prompt.User,
+		Model:        model,
 		AllowedTools: []string{
 			"Read", "Write", "Edit", "Bash", "Glob", "Grep",
 			"Task", "TodoWrite", "WebSearch", "WebFetch",
@@ -104,7 +470,10 @@ func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config)
 			prd.GetMillhousePath(basePath, prd.ProgressFile),
 			prd.GetMillhousePath(basePath, prd.PromptFile),
 		},
-		WorkDir: basePath,
+		WorkDir:    basePath,
+		AddDirs:    prd.ExternalArtifactDirs(),
+		ExtraArgs:  phaseConfig.ExtraArgs,
+		MCPServers: phaseConfig.MCPServers,
 	}
 
 	reader, err := claude.Execute(execCtx, opts)
@@ -113,36 +482,50 @@ func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config)
 	}
 
 	// Create handler with termination support
-	handler := llm.NewConsoleHandlerWithTerminate(phaseConfig.MaxTokens, cancelExec)
+	handler := llm.NewConsoleHandlerWithLimits(phaseConfig.MaxTokens, phaseConfig.MaxOutputTokens, cancelExec)
+	handler.SetMaxRetainedOutput(phaseConfig.RetainedOutput())
+	defer handler.Flush() // Flush buffered output on every exit path
+
+	// Cancel the phase if the agent goes quiet for too long
+	stopWatchdog := handler.WatchIdle(phaseConfig.IdleTimeoutDuration(), cancelExec)
+	defer stopWatchdog()
 
 	// Parse the stream
 	if err := llm.ParseStream(reader, handler, cancelExec); err != nil {
 		reader.Close()
 		return nil, fmt.Errorf("stream parsing failed: %w", err)
 	}
+	stopWatchdog()
 
 	// Close reader and check for process exit errors (e.g., Claude CLI failure)
 	// Note: "signal: killed" is expected when we intentionally terminate after a signal
 	closeErr := reader.Close()
 	if closeErr != nil && !handler.ShouldTerminate() {
+		if handler.RateLimited() {
+			return nil, fmt.Errorf("claude execution failed: %w: %w", closeErr, llm.ErrRateLimited)
+		}
 		return nil, fmt.Errorf("claude execution failed: %w", closeErr)
 	}
 
+	handler.Flush()
 	fmt.Println() // Ensure newline after output
 	handler.DisplayFinalTokenUsage()
+	handler.DisplayToolSummary()
 
 	return handler, nil
 }
 
-func buildReviewerPrompt(basePath string, prdFile *prd.PRDFileData, iteration int, cfg *config.Config) string {
+func buildReviewerPrompt(basePath string, prdFile *prd.PRDFileData, iteration int, cfg *config.Config, batch bool, remote *git.VerificationResult) prompts.PhasePrompt {
 	phaseConfig := cfg.GetPhaseConfig("reviewer")
 
 	allPRDsJSON, _ := json.MarshalIndent(prdFile.PRDs, "", "  ")
 	progressContent := readLastLines(prd.GetMillhousePath(basePath, prd.ProgressFile), phaseConfig.ProgressLines)
 
-	// Collect active plans
+	// Collect active plans within the run's scope
+	scope := cfg.Run.Scope().Work()
+	pending := prdFile.GetPendingPRDsIn(scope)
 	activePlans := make(map[string]string)
-	for _, p := range prdFile.GetActivePRDs() {
+	for _, p := range prdFile.GetActivePRDsIn(scope) {
 		planPath := prd.GetPlanPath(basePath, p.ID)
 		if content := readFileContent(planPath); content != "" {
 			activePlans[p.ID] = content
@@ -150,19 +533,34 @@ func buildReviewerPrompt(basePath string, prdFile *prd.PRDFileData, iteration in
 	}
 
 	// Also include plans for pending PRDs (they still have plans until verified/rejected)
-	for _, p := range prdFile.GetPendingPRDs() {
+	for _, p := range pending {
 		planPath := prd.GetPlanPath(basePath, p.ID)
 		if content := readFileContent(planPath); content != "" {
 			activePlans[p.ID] = content
 		}
 	}
 
-	reviewerAugmentation := prompts.LoadAugmentation(basePath, "reviewer")
+	var pendingIDs []string
+	pendingCriteria := make(map[string]string)
+	for _, p := range pending {
+		pendingIDs = append(pendingIDs, p.ID)
+		pendingCriteria[p.ID] = prompts.FormatCriteria(p.AcceptanceCriteria)
+	}
+
+	scopeDescription := ""
+	if scope.Limited() {
+		scopeDescription = scope.String()
+	}
+
+	reviewerAugmentation := ""
+	if !cfg.Run.NoAugmentation {
+		reviewerAugmentation = prompts.LoadAugmentation(basePath, "reviewer")
+	}
 
 	// Load prompt files for self-improvement capability
-	plannerPrompt := prompts.LoadAugmentation(basePath, "planner")
-	builderPrompt := prompts.LoadAugmentation(basePath, "builder")
-	reviewerPrompt := prompts.LoadAugmentation(basePath, "reviewer")
+	plannerPrompt := prompts.LoadAugmentationSource(basePath, "planner")
+	builderPrompt := prompts.LoadAugmentationSource(basePath, "builder")
+	reviewerPrompt := prompts.LoadAugmentationSource(basePath, "reviewer")
 
 	return prompts.BuildReviewerPrompt(prompts.ReviewerData{
 		AllPRDsJSON:          string(allPRDsJSON),
@@ -174,26 +572,34 @@ func buildReviewerPrompt(basePath string, prdFile *prd.PRDFileData, iteration in
 		PlannerPrompt:        plannerPrompt,
 		BuilderPrompt:        builderPrompt,
 		ReviewerPrompt:       reviewerPrompt,
+		BatchMode:            batch,
+		PendingIDs:           pendingIDs,
+		PendingCriteria:      pendingCriteria,
+		CheckResults:         loadCheckSummaries(basePath, pending),
+		RemoteStatus:         remote.RemoteStatus,
+		RequirePushed:        remote.RequirePushed,
+		RunDirective:         cfg.Run.RunDirective,
+		Scope:                scopeDescription,
 	})
 }
 
 func readFileContent(path string) string {
-	content, err := os.ReadFile(path)
+	content, err := prd.ReadTextFile(path)
 	if err != nil {
 		return ""
 	}
-	return string(content)
+	return content
 }
 
 func readLastLines(path string, n int) string {
-	content, err := os.ReadFile(path)
+	content, err := prd.ReadTextFile(path)
 	if err != nil {
 		return ""
 	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(content, "\n")
 	if len(lines) <= n {
-		return string(content)
+		return content
 	}
 
 	return strings.Join(lines[len(lines)-n:], "\n")
@@ -0,0 +1,139 @@
+package reviewer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/git"
+	"github.com/daydemir/milhouse/internal/prd"
+)
+
+// savePRDs writes prds to a fresh project's prd.json and returns its path
+func savePRDs(t *testing.T, prds ...prd.PRD) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, prd.MillhouseDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := prd.Save(dir, &prd.PRDFileData{PRDs: prds}); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestBlockWithoutCriteria(t *testing.T) {
+	vague := prd.PRD{ID: "vague", Description: "no criteria", ActivePlan: "vague.md"}
+	vague.Passes.SetTrue()
+	defined := prd.PRD{ID: "defined", Description: "criteria added since", AcceptanceCriteria: []string{"works"}}
+	defined.Passes.SetTrue()
+	dir := savePRDs(t, vague, defined)
+	if err := prd.EnsurePlansDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prd.GetPlanPath(dir, "vague"), []byte("plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := blockWithoutCriteria(dir, []string{"vague", "defined", "missing"}); err != nil {
+		t.Fatalf("blockWithoutCriteria() error = %v", err)
+	}
+
+	prdFile, err := prd.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := prdFile.FindByID("vague")
+	if !got.Passes.IsBlocked() || got.ActivePlan != "" {
+		t.Errorf("vague = %s with plan %q, want blocked without a plan", got.Passes, got.ActivePlan)
+	}
+	if !strings.Contains(got.Notes, "[needs refinement] no acceptance criteria") {
+		t.Errorf("vague notes = %q, want the refinement note", got.Notes)
+	}
+	if _, err := os.Stat(prd.GetPlanPath(dir, "vague")); !os.IsNotExist(err) {
+		t.Errorf("plan file still exists: %v", err)
+	}
+
+	if kept := prdFile.FindByID("defined"); !kept.Passes.IsTrue() || kept.Notes != "" {
+		t.Errorf("defined = %s with notes %q, want it left alone", kept.Passes, kept.Notes)
+	}
+}
+
+func TestRecordRejections(t *testing.T) {
+	var prds []prd.PRD
+	for _, id := range []string{"run", "manual", "silent"} {
+		p := prd.PRD{ID: id, Description: id}
+		p.Passes.SetFalse()
+		prds = append(prds, p)
+	}
+	done := prd.PRD{ID: "done", Description: "done"}
+	done.Passes.SetTrue()
+	dir := savePRDs(t, append(prds, done)...)
+
+	noted, err := RecordRejections(dir, []RejectionDetail{
+		{ID: "run", Reason: "tests fail"},
+		{ID: "silent"},
+		{ID: "done", Reason: "too late"},
+		{ID: "missing", Reason: "nowhere"},
+	}, 3)
+	if err != nil {
+		t.Fatalf("RecordRejections() error = %v", err)
+	}
+	if len(noted) != 1 || noted[0] != "run" {
+		t.Errorf("noted = %v, want only run", noted)
+	}
+
+	// Outside a run there is no iteration to name
+	if _, err := RecordRejections(dir, []RejectionDetail{{ID: "manual", Reason: "wrong color"}}, 0); err != nil {
+		t.Fatalf("RecordRejections() error = %v", err)
+	}
+
+	prdFile, err := prd.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := func(id string) string { return prdFile.FindByID(id).Notes }
+	if !strings.Contains(notes("run"), "[rejected] iteration 3: tests fail") {
+		t.Errorf("run notes = %q", notes("run"))
+	}
+	if got := notes("manual"); !strings.Contains(got, "[rejected] wrong color") || strings.Contains(got, "iteration") {
+		t.Errorf("manual notes = %q, want the reason without an iteration", got)
+	}
+	if notes("silent") != "" || notes("done") != "" {
+		t.Errorf("notes written for a rejection without a reason or a complete PRD: %q, %q", notes("silent"), notes("done"))
+	}
+}
+
+func TestReviewScope(t *testing.T) {
+	mine := prd.PRD{ID: "mine", Owner: "alice"}
+	mine.Passes.SetPending()
+	theirs := prd.PRD{ID: "theirs", Owner: "bob"}
+	theirs.Passes.SetPending()
+	prdFile := &prd.PRDFileData{PRDs: []prd.PRD{mine, theirs}}
+
+	cfg := config.DefaultConfig()
+	cfg.Run.Owner = "bob"
+	cfg.Run.Cooldown = []string{"theirs"} // Never holds back a review
+	if run, reason := ShouldRunReviewerWithReason(prdFile, cfg.Run.Scope()); !run || reason != "1 pending PRD(s) to verify" {
+		t.Errorf("ShouldRunReviewerWithReason(bob) = %v, %q", run, reason)
+	}
+	if run, reason := ShouldRunReviewerWithReason(prdFile, prd.Scope{Owner: "carol"}); run || reason != "no pending, active or open PRDs within owner carol" {
+		t.Errorf("ShouldRunReviewerWithReason(carol) = %v, %q", run, reason)
+	}
+
+	prompt := buildReviewerPrompt(t.TempDir(), prdFile, 1, cfg, false, &git.VerificationResult{})
+	if !strings.Contains(prompt.User+prompt.System, "This run covers owner bob. Verify only these pending PRDs") {
+		t.Error("a scoped review should name its scope")
+	}
+	if got := strings.Count(prompt.User+prompt.System, "- theirs\n"); got != 1 || strings.Contains(prompt.User+prompt.System, "- mine\n") {
+		t.Errorf("a scoped review should list only theirs, listed %d time(s)", got)
+	}
+
+	cfg.Run.Owner = ""
+	cfg.Run.Cooldown = nil
+	if prompt := buildReviewerPrompt(t.TempDir(), prdFile, 1, cfg, false, &git.VerificationResult{}); strings.Contains(prompt.User+prompt.System, "This run covers") {
+		t.Error("an unscoped review should not name a scope")
+	}
+}
@@ -0,0 +1,93 @@
+package summarizer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LearningsHeading titles the section a summary writes into progress.md
+const LearningsHeading = "## Codebase Patterns and Learnings"
+
+var (
+	// entryHeading starts a progress entry, e.g. "## [2026-01-02 15:04] - auth-1"
+	entryHeading = regexp.MustCompile(`^## \[`)
+	learningsTag = regexp.MustCompile(`(?s)<learnings>\s*(.*?)\s*</learnings>`)
+)
+
+// progressLog is progress.md split into its parts
+type progressLog struct {
+	title     string   // Text before the first "## " section
+	learnings string   // Sections between the title and the first entry
+	entries   []string // Dated entries, oldest first
+}
+
+// parseProgress splits progress.md into its title, learnings and entries
+func parseProgress(content string) progressLog {
+	var log progressLog
+	var title, learnings, entry []string
+	inLearnings := false
+
+	flush := func() {
+		if len(entry) > 0 {
+			log.entries = append(log.entries, trimSection(strings.Join(entry, "\n")))
+			entry = nil
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case entryHeading.MatchString(line):
+			flush()
+			entry = []string{line}
+		case entry != nil:
+			entry = append(entry, line)
+		case inLearnings || strings.HasPrefix(line, "## "):
+			inLearnings = true
+			learnings = append(learnings, line)
+		default:
+			title = append(title, line)
+		}
+	}
+	flush()
+
+	log.title = strings.TrimSpace(strings.Join(title, "\n"))
+	log.learnings = trimSection(strings.Join(learnings, "\n"))
+	return log
+}
+
+// trimSection strips surrounding blank lines and a trailing "---" separator
+func trimSection(s string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "---"))
+}
+
+// split divides the entries into those to condense and the newest keep
+func (l progressLog) split(keep int) (older, recent []string) {
+	if len(l.entries) <= keep {
+		return nil, l.entries
+	}
+	cut := len(l.entries) - keep
+	return l.entries[:cut], l.entries[cut:]
+}
+
+// compose rebuilds progress.md with learnings in place of the older entries
+func (l progressLog) compose(learnings string, recent []string) string {
+	var sb strings.Builder
+	if l.title != "" {
+		sb.WriteString(l.title + "\n\n")
+	}
+	sb.WriteString(LearningsHeading + "\n\n")
+	sb.WriteString(strings.TrimSpace(learnings) + "\n\n---\n\n")
+	for _, e := range recent {
+		sb.WriteString(e + "\n\n")
+	}
+	return sb.String()
+}
+
+// extractLearnings returns the last <learnings> block in the agent output
+func extractLearnings(output string) string {
+	matches := learningsTag.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1][1]
+}
@@ -0,0 +1,92 @@
+package summarizer
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleProgress = `# Milhouse Progress Log
+
+Initialized: 2026-01-01 09:00:00
+
+## Codebase Patterns
+- Errors are wrapped with %w
+
+---
+
+## [2026-01-02 10:00] - auth-1
+- Added login handler
+
+## [2026-01-03 11:00] - auth-2
+- Added logout
+---
+
+## [2026-01-04 12:00] - auth-3
+- Added sessions
+`
+
+func TestParseProgress(t *testing.T) {
+	log := parseProgress(sampleProgress)
+
+	if log.title != "# Milhouse Progress Log\n\nInitialized: 2026-01-01 09:00:00" {
+		t.Errorf("title = %q", log.title)
+	}
+	if log.learnings != "## Codebase Patterns\n- Errors are wrapped with %w" {
+		t.Errorf("learnings = %q", log.learnings)
+	}
+	if len(log.entries) != 3 {
+		t.Fatalf("got %d entries, want 3: %q", len(log.entries), log.entries)
+	}
+	if log.entries[1] != "## [2026-01-03 11:00] - auth-2\n- Added logout" {
+		t.Errorf("separator not trimmed from entry: %q", log.entries[1])
+	}
+
+	older, recent := log.split(2)
+	if len(older) != 1 || len(recent) != 2 || !strings.Contains(older[0], "auth-1") {
+		t.Errorf("split(2) = %q / %q", older, recent)
+	}
+	if older, recent := log.split(5); older != nil || len(recent) != 3 {
+		t.Errorf("nothing should be condensed when keeping all entries, got %q", older)
+	}
+}
+
+func TestComposeProgress(t *testing.T) {
+	log := parseProgress(sampleProgress)
+	_, recent := log.split(1)
+
+	got := log.compose("### Errors\n- Wrap with %w\n", recent)
+	want := `# Milhouse Progress Log
+
+Initialized: 2026-01-01 09:00:00
+
+## Codebase Patterns and Learnings
+
+### Errors
+- Wrap with %w
+
+---
+
+## [2026-01-04 12:00] - auth-3
+- Added sessions
+
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A summarized file parses back into the same shape
+	again := parseProgress(got)
+	if !strings.HasPrefix(again.learnings, LearningsHeading) || len(again.entries) != 1 {
+		t.Errorf("re-parse: learnings %q, %d entries", again.learnings, len(again.entries))
+	}
+}
+
+func TestExtractLearnings(t *testing.T) {
+	output := "Reading the log.\n<learnings>\n### Draft\n</learnings>\nRevised:\n<learnings>\n### Build\n- go test ./...\n</learnings>\n"
+	if got := extractLearnings(output); got != "### Build\n- go test ./..." {
+		t.Errorf("got %q", got)
+	}
+	if got := extractLearnings("no tags here"); got != "" {
+		t.Errorf("expected empty, got %q", got)
+	}
+}
@@ -0,0 +1,162 @@
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/daydemir/milhouse/internal/config"
+	"github.com/daydemir/milhouse/internal/display"
+	"github.com/daydemir/milhouse/internal/llm"
+	"github.com/daydemir/milhouse/internal/prd"
+	"github.com/daydemir/milhouse/internal/prompts"
+)
+
+// SummarizerResult contains the result of a summarizer run
+type SummarizerResult struct {
+	Summarized  int    // Entries condensed into the learnings section
+	Kept        int    // Recent entries kept verbatim
+	BytesBefore int    // Size of progress.md before the summary
+	BytesAfter  int    // Size of progress.md after the summary
+	ArchivePath string // Copy of the original progress.md
+	TotalTokens int
+	RateLimited bool // The API rate-limited the summarizer
+}
+
+// ShouldSummarize reports whether progress.md has outgrown summarize.maxBytes
+func ShouldSummarize(basePath string, cfg *config.Config) bool {
+	if cfg == nil || cfg.Summarize.MaxBytes <= 0 {
+		return false
+	}
+	info, err := os.Stat(prd.GetMillhousePath(basePath, prd.ProgressFile))
+	return err == nil && info.Size() > int64(cfg.Summarize.MaxBytes)
+}
+
+// Run condenses older progress.md entries into a learnings section, keeping
+// the newest entries verbatim and archiving the original file
+// Nothing is changed when there are no more entries than would be kept.
+func Run(ctx context.Context, basePath string, cfg *config.Config) (*SummarizerResult, error) {
+	// Nil guard - use default config if none provided
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	path := prd.GetMillhousePath(basePath, prd.ProgressFile)
+	original, err := prd.ReadTextFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read progress: %w", err)
+	}
+
+	log := parseProgress(original)
+	older, recent := log.split(cfg.Summarize.Keep())
+	result := &SummarizerResult{Kept: len(recent), BytesBefore: len(original), BytesAfter: len(original)}
+	if len(older) == 0 {
+		return result, nil
+	}
+
+	display.AgentHeader("summarizer", fmt.Sprintf("condensing %d progress entries", len(older)))
+
+	prompt := prompts.BuildSummarizerPrompt(prompts.SummarizerData{
+		Learnings:   log.learnings,
+		Entries:     strings.Join(older, "\n\n"),
+		EntryCount:  len(older),
+		RecentCount: len(recent),
+	})
+
+	handler, err := runClaude(ctx, basePath, prompt, cfg)
+	if handler != nil {
+		result.TotalTokens = handler.GetTokenStats().TotalTokens
+		result.RateLimited = handler.RateLimited()
+	}
+	if err != nil {
+		return result, err
+	}
+
+	learnings := extractLearnings(handler.GetOutput())
+	if learnings == "" {
+		return result, fmt.Errorf("summarizer output had no <learnings> section; progress.md left unchanged")
+	}
+
+	archive := prd.GetArtifactPath(basePath, prd.ArchiveDir, "progress-"+time.Now().Format("20060102-150405")+".md")
+	if err := os.MkdirAll(filepath.Dir(archive), 0755); err != nil {
+		return result, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := os.WriteFile(archive, []byte(original), 0644); err != nil {
+		return result, fmt.Errorf("failed to archive progress: %w", err)
+	}
+
+	summarized := log.compose(learnings, recent)
+	if err := os.WriteFile(path, []byte(summarized), 0644); err != nil {
+		return result, fmt.Errorf("failed to write progress: %w", err)
+	}
+
+	result.Summarized = len(older)
+	result.BytesAfter = len(summarized)
+	result.ArchivePath = archive
+	return result, nil
+}
+
+func runClaude(ctx context.Context, basePath, prompt string, cfg *config.Config) (*llm.ConsoleHandler, error) {
+	model := cfg.Summarize.Model
+	if model == "" {
+		model = cfg.Global.Model
+	}
+
+	apiKey, err := cfg.Auth.APIKey()
+	if err != nil {
+		return nil, err
+	}
+	claude := llm.NewClaude("", apiKey)
+
+	// Create a cancellable context for this execution
+	execCtx, cancelExec := context.WithCancel(ctx)
+	defer cancelExec()
+
+	// Everything the summarizer needs is in the prompt; it must not edit files
+	opts := llm.ExecuteOptions{
+		Prompt:          prompt,
+		Model:           model,
+		AllowedTools:    []string{"Read"},
+		DisallowedTools: []string{"Write", "Edit", "MultiEdit", "NotebookEdit", "Bash", "Task"},
+		WorkDir:         basePath,
+	}
+
+	reader, err := claude.Execute(execCtx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := llm.NewConsoleHandlerWithLimits(cfg.Global.MaxTokens, 0, cancelExec)
+	// Keep the full output: the learnings block is parsed from it afterward
+	handler.SetMaxRetainedOutput(0)
+	defer handler.Flush() // Flush buffered output on every exit path
+
+	// Cancel the call if the agent goes quiet for too long
+	stopWatchdog := handler.WatchIdle(time.Duration(cfg.Global.IdleTimeout)*time.Second, cancelExec)
+	defer stopWatchdog()
+
+	// Parse the stream
+	if err := llm.ParseStream(reader, handler, cancelExec); err != nil {
+		reader.Close()
+		return handler, fmt.Errorf("stream parsing failed: %w", err)
+	}
+	stopWatchdog()
+
+	closeErr := reader.Close()
+	if closeErr != nil && !handler.ShouldTerminate() {
+		if handler.RateLimited() {
+			return handler, fmt.Errorf("claude execution failed: %w: %w", closeErr, llm.ErrRateLimited)
+		}
+		return handler, fmt.Errorf("claude execution failed: %w", closeErr)
+	}
+
+	handler.Flush()
+	fmt.Println() // Ensure newline after output
+	handler.DisplayFinalTokenUsage()
+	handler.DisplayToolSummary()
+
+	return handler, nil
+}
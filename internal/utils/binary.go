@@ -31,13 +31,7 @@ func ResolveBinaryPath(binaryPath string) string {
 	// Check common locations
 	home, err := os.UserHomeDir()
 	if err == nil {
-		commonPaths := []string{
-			filepath.Join(home, ".claude", "local", "claude"),
-			"/usr/local/bin/claude",
-			"/opt/homebrew/bin/claude",
-		}
-
-		for _, p := range commonPaths {
+		for _, p := range commonClaudePaths(home) {
 			if _, err := os.Stat(p); err == nil {
 				return p
 			}
@@ -52,13 +46,9 @@ func ResolveBinaryPath(binaryPath string) string {
 func ClaudeNotFoundError() error {
 	return fmt.Errorf(`claude not found in PATH
 
-To fix, add to your ~/.zshrc or ~/.bashrc:
-  export PATH="$HOME/.claude/local:$PATH"
-
-Then restart your terminal, or run:
-  source ~/.zshrc
+To fix, %s
 
 Alternatively, set the full path in .milhouse/config.yaml:
   claude:
-    binary: /path/to/claude`)
+    binary: %s`, pathHint, examplePath)
 }
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package utils
+
+import "path/filepath"
+
+// pathHint explains how to put claude on PATH
+const pathHint = `add to your ~/.zshrc or ~/.bashrc:
+  export PATH="$HOME/.claude/local:$PATH"
+
+Then restart your terminal, or run:
+  source ~/.zshrc`
+
+// examplePath is a placeholder binary path for error messages
+const examplePath = "/path/to/claude"
+
+// commonClaudePaths lists where claude is usually installed
+func commonClaudePaths(home string) []string {
+	return []string{
+		filepath.Join(home, ".claude", "local", "claude"),
+		"/usr/local/bin/claude",
+		"/opt/homebrew/bin/claude",
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// pathHint explains how to put claude on PATH
+const pathHint = `add its folder to your user PATH in PowerShell:
+  [Environment]::SetEnvironmentVariable("Path", $env:Path + ";$HOME\.claude\local", "User")
+
+Then open a new terminal.`
+
+// examplePath is a placeholder binary path for error messages
+const examplePath = `C:\path\to\claude.exe`
+
+// commonClaudePaths lists where claude is usually installed
+// npm installs a claude.cmd shim into %APPDATA%\npm.
+func commonClaudePaths(home string) []string {
+	paths := []string{
+		filepath.Join(home, ".claude", "local", "claude.exe"),
+		filepath.Join(home, ".claude", "local", "claude.cmd"),
+	}
+	if appData := os.Getenv("APPDATA"); appData != "" {
+		paths = append(paths, filepath.Join(appData, "npm", "claude.cmd"))
+	}
+	return paths
+}